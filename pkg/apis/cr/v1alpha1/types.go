@@ -337,6 +337,156 @@ type Profile struct {
 	// is allowed when operating with the Location.
 	// If omitted from the CR definition, it defaults to false
 	SkipSSLVerify bool `json:"skipSSLVerify"`
+	// Encryption enables client-side encryption of the artifacts written to
+	// the Location.
+	Encryption *Encryption `json:"encryption,omitempty"`
+	// Compression is the codec that artifacts written to the Location are
+	// compressed with. Artifacts are not compressed if it is omitted.
+	Compression CompressionType `json:"compression,omitempty"`
+	// ObjectLock retains the artifacts written to the Location so that they
+	// cannot be deleted or overwritten until their retention expires. The
+	// bucket must have S3 Object Lock enabled.
+	ObjectLock *ObjectLock `json:"objectLock,omitempty"`
+	// ServerSideEncryption configures how the object store encrypts the
+	// artifacts written to the Location at rest.
+	ServerSideEncryption *ServerSideEncryption `json:"serverSideEncryption,omitempty"`
+	// StorageClass is the storage class, or access tier of azure locations,
+	// that artifacts written to the Location are stored in, e.g. GLACIER_IR,
+	// NEARLINE or Cool. The default of the bucket is used if it is omitted.
+	StorageClass string `json:"storageClass,omitempty"`
+	// RateLimit throttles the bandwidth of data moved to and from the
+	// Location. ActionSets can override it with the rateLimit and
+	// rateLimitBurst options.
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// SecondaryLocations are the locations that are used after Location,
+	// in order, as configured by LocationPolicy. They must be of the same
+	// type as Location and are accessed with the same Credential.
+	SecondaryLocations []Location `json:"secondaryLocations,omitempty"`
+	// LocationPolicy is how artifacts are written to the locations of the
+	// Profile. It defaults to failover.
+	LocationPolicy LocationPolicy `json:"locationPolicy,omitempty"`
+	// Status is the health of the Profile as last checked by the controller.
+	Status *ProfileStatus `json:"status,omitempty"`
+}
+
+// ProfileStatus is the health of a Profile. This should only be updated by the controller.
+type ProfileStatus struct {
+	// Conditions are the results of the checks of the Profile. Checks that
+	// depend on a check that failed have the status Unknown.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastChecked is when the Profile was last checked.
+	LastChecked *metav1.Time `json:"lastChecked,omitempty"`
+}
+
+const (
+	// ProfileCredentialsValid indicates whether the Profile is valid and its
+	// credential can be read.
+	ProfileCredentialsValid string = "CredentialsValid"
+
+	// ProfileBucketReachable indicates whether the buckets of the Profile
+	// can be reached with its credential.
+	ProfileBucketReachable string = "BucketReachable"
+
+	// ProfileReadAccess indicates whether the buckets of the Profile can be
+	// read from.
+	ProfileReadAccess string = "ReadAccess"
+
+	// ProfileWriteAccess indicates whether the buckets of the Profile can be
+	// written to.
+	ProfileWriteAccess string = "WriteAccess"
+)
+
+// LocationPolicy is how artifacts are written to the locations of a Profile.
+type LocationPolicy string
+
+const (
+	// LocationPolicyFailover writes artifacts to the first of the locations
+	// that they can be written to.
+	LocationPolicyFailover LocationPolicy = "failover"
+	// LocationPolicyWriteAll writes artifacts to all the locations. Writes
+	// only fail if none of the locations can be written to.
+	LocationPolicyWriteAll LocationPolicy = "writeAll"
+)
+
+// ServerSideEncryptionType is a kind of S3 server-side encryption.
+type ServerSideEncryptionType string
+
+const (
+	// ServerSideEncryptionTypeS3 encrypts artifacts with keys managed by S3.
+	ServerSideEncryptionTypeS3 ServerSideEncryptionType = "sse-s3"
+	// ServerSideEncryptionTypeKMS encrypts artifacts with a KMS key.
+	ServerSideEncryptionTypeKMS ServerSideEncryptionType = "sse-kms"
+	// ServerSideEncryptionTypeCustomer encrypts artifacts with a key that
+	// is sent with every request and is not stored by S3.
+	ServerSideEncryptionTypeCustomer ServerSideEncryptionType = "sse-c"
+)
+
+// ServerSideEncryption configures the encryption of artifacts by the object
+// store. Type applies to s3Compliant locations and EncryptionScope to azure
+// locations.
+type ServerSideEncryption struct {
+	// Type is the kind of S3 server-side encryption.
+	Type ServerSideEncryptionType `json:"type,omitempty"`
+	// KMSKeyID is the ID, ARN or alias of the KMS key of sse-kms. The AWS
+	// managed key of the account is used if it is omitted.
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+	// CustomerKeySecret references the Secret that holds the key of sse-c.
+	CustomerKeySecret *ObjectReference `json:"customerKeySecret,omitempty"`
+	// CustomerKeyField is the entry of CustomerKeySecret that holds the
+	// key. The key is 32 bytes long, raw or base64 encoded.
+	CustomerKeyField string `json:"customerKeyField,omitempty"`
+	// EncryptionScope is the Azure encryption scope of the blobs written
+	// to the Location.
+	EncryptionScope string `json:"encryptionScope,omitempty"`
+}
+
+// ObjectLockMode is the S3 Object Lock mode of retained artifacts.
+type ObjectLockMode string
+
+const (
+	// ObjectLockModeGovernance retains artifacts unless they are deleted by
+	// users with permission to bypass governance retention.
+	ObjectLockModeGovernance ObjectLockMode = "governance"
+	// ObjectLockModeCompliance retains artifacts regardless of permissions.
+	ObjectLockModeCompliance ObjectLockMode = "compliance"
+)
+
+// ObjectLock configures how long artifacts are retained after they are
+// written.
+type ObjectLock struct {
+	Mode      ObjectLockMode  `json:"mode"`
+	Retention metav1.Duration `json:"retention"`
+}
+
+// RateLimit is a token bucket that limits the bandwidth of data movement.
+type RateLimit struct {
+	// BytesPerSecond is the sustained rate data is moved at.
+	BytesPerSecond int64 `json:"bytesPerSecond"`
+	// Burst is the number of bytes that can be moved at once. It defaults
+	// to BytesPerSecond.
+	Burst int64 `json:"burst,omitempty"`
+}
+
+// CompressionType is a codec that artifacts can be compressed with.
+type CompressionType string
+
+const (
+	CompressionTypeNone CompressionType = "none"
+	CompressionTypeGzip CompressionType = "gzip"
+	CompressionTypeZstd CompressionType = "zstd"
+)
+
+// Encryption configures client-side envelope encryption. Each artifact is
+// encrypted with its own data key, which is wrapped by a key from a Secret.
+type Encryption struct {
+	// KeySecret references the Secret whose entries are the wrapping keys.
+	// Keys are 32 bytes long, raw or base64 encoded.
+	KeySecret ObjectReference `json:"keySecret"`
+	// KeyID is the entry of the Secret whose key wraps the data keys of new
+	// artifacts. Artifacts record the ID of the key that wrapped their data
+	// key, so keys are rotated by adding a new entry to the Secret and
+	// changing KeyID. Previous keys must be kept while artifacts use them.
+	KeyID string `json:"keyID"`
 }
 
 type LocationType string
@@ -346,17 +496,30 @@ const (
 	LocationTypeS3Compliant LocationType = "s3Compliant"
 	LocationTypeAzure       LocationType = "azure"
 	LocationTypeKopia       LocationType = "kopia"
+	// LocationTypeFilesystem stores backups in a filesystem, such as an NFS
+	// share, that is provided by a PersistentVolumeClaim.
+	LocationTypeFilesystem LocationType = "filesystem"
+	// LocationTypeSFTP stores backups on an SFTP server. The Endpoint is
+	// `[sftp://]host[:port][/path]` and the Bucket is a directory in the
+	// path. The credential is a Secret of type `secrets.kanister.io/sftp`.
+	LocationTypeSFTP LocationType = "sftp"
 )
 
 type Location struct {
 	// Type specifies the kind of object storage that would be used to upload the
 	// backup objects. Currently supported values are: "GCS", "S3Compliant",
-	// and "Azure".
+	// "Azure", "Filesystem" and "SFTP".
 	Type LocationType `json:"type"`
 	// Bucket represents the bucket on the object storage where the backup is uploaded.
+	// For filesystem locations it is a directory in the filesystem.
 	Bucket string `json:"bucket"`
 	// Endpoint specifies the endpoint where the object storage is accessible at.
+	// For filesystem locations it is the path the filesystem is mounted at.
 	Endpoint string `json:"endpoint"`
+	// ClaimName is the name of the PersistentVolumeClaim that provides the
+	// filesystem of a filesystem location. Pods created by Kanister functions
+	// mount it at Endpoint. The claim must be in the namespace of those pods.
+	ClaimName string `json:"claimName,omitempty"`
 	// Prefix is the string that would be prepended to the object path in the
 	// bucket where the backup objects are uploaded.
 	Prefix string `json:"prefix"`
@@ -370,6 +533,9 @@ const (
 	CredentialTypeKeyPair CredentialType = "keyPair"
 	CredentialTypeSecret  CredentialType = "secret"
 	CredentialTypeKopia   CredentialType = "kopia"
+	// CredentialTypeWorkloadIdentity exchanges Kubernetes service account
+	// tokens for short-lived cloud provider credentials.
+	CredentialTypeWorkloadIdentity CredentialType = "workloadIdentity"
 )
 
 type Credential struct {
@@ -381,6 +547,83 @@ type Credential struct {
 	Secret *ObjectReference `json:"secret,omitempty"`
 	// KopiaServerSecret represents the secret being used by Credential of Type Kopia.
 	KopiaServerSecret *KopiaServerSecret `json:"kopiaServerSecret,omitempty"`
+	// WorkloadIdentity represents the identity used by the Credential of Type WorkloadIdentity.
+	WorkloadIdentity *WorkloadIdentity `json:"workloadIdentity,omitempty"`
+	// Source represents credentials stored outside of Kubernetes Secrets. If set,
+	// the credential data of Type KeyPair or Secret is read from the source instead
+	// of the referenced Kubernetes Secret.
+	Source *CredentialSource `json:"source,omitempty"`
+}
+
+// WorkloadIdentity configures credentials that are obtained by exchanging
+// tokens of a Kubernetes ServiceAccount with the cloud provider, using AWS web
+// identity federation, Azure federated credentials or GCP workload identity
+// federation.
+type WorkloadIdentity struct {
+	// ServiceAccount references the Kubernetes ServiceAccount whose tokens are
+	// exchanged for cloud provider credentials. It must be in the namespace of
+	// the Profile.
+	ServiceAccount ObjectReference `json:"serviceAccount"`
+	// Audience of the ServiceAccount tokens. Defaults to `sts.amazonaws.com` for
+	// S3 compliant locations and `api://AzureADTokenExchange` for Azure locations.
+	// For GCS locations it is the full resource name of the workload identity
+	// pool provider and is required.
+	Audience string `json:"audience,omitempty"`
+	// RoleARN is the AWS IAM role that is assumed, for S3 compliant locations.
+	RoleARN string `json:"roleARN,omitempty"`
+	// TenantID is the Azure AD tenant, for Azure locations.
+	TenantID string `json:"tenantID,omitempty"`
+	// ClientID is the client ID of the Azure AD application or managed identity
+	// with the federated credential, for Azure locations.
+	ClientID string `json:"clientID,omitempty"`
+	// StorageAccount is the Azure storage account, for Azure locations.
+	StorageAccount string `json:"storageAccount,omitempty"`
+	// ServiceAccountEmail is the GCP service account that is impersonated, for
+	// GCS locations. If empty, the federated identity is used directly.
+	ServiceAccountEmail string `json:"serviceAccountEmail,omitempty"`
+	// ProjectID is the GCP project, for GCS locations.
+	ProjectID string `json:"projectID,omitempty"`
+}
+
+// CredentialSource references credential data stored outside of Kubernetes.
+// Exactly one of File or Vault must be set.
+type CredentialSource struct {
+	// File reads the credential data from files mounted into the controller pod.
+	File *FileCredentialSource `json:"file,omitempty"`
+	// Vault reads the credential data from a HashiCorp Vault KV secret.
+	Vault *VaultCredentialSource `json:"vault,omitempty"`
+	// SecretType is the type of the secret, e.g. `secrets.kanister.io/aws`, that
+	// the data is validated as for Credentials of Type Secret.
+	SecretType string `json:"secretType,omitempty"`
+}
+
+// FileCredentialSource references a directory, such as a volume mounted by the
+// Secrets Store CSI driver, where each file holds the value of the key with the
+// same name.
+type FileCredentialSource struct {
+	// Path is the directory in the controller pod where the files are mounted.
+	// It must be in the directory set by KANISTER_CREDENTIAL_FILE_ROOT.
+	Path string `json:"path"`
+}
+
+// VaultCredentialSource references a secret of a HashiCorp Vault KV secrets engine.
+type VaultCredentialSource struct {
+	// Address is the URL of the Vault server.
+	Address string `json:"address"`
+	// Namespace is the Vault Enterprise namespace of the secret.
+	Namespace string `json:"namespace,omitempty"`
+	// Mount is the path where the KV secrets engine is mounted. Defaults to `secret`.
+	Mount string `json:"mount,omitempty"`
+	// Path is the path of the secret within the KV secrets engine.
+	Path string `json:"path"`
+	// KVVersion is the version of the KV secrets engine, 1 or 2. Defaults to 2.
+	KVVersion int `json:"kvVersion,omitempty"`
+	// TokenSecret references the Kubernetes Secret that stores the Vault token
+	// in the `token` key.
+	TokenSecret ObjectReference `json:"tokenSecret"`
+	// CABundle is the PEM-encoded CA bundle used to verify the certificate of
+	// the Vault server instead of the system trust store.
+	CABundle string `json:"caBundle,omitempty"`
 }
 
 type KeyPair struct {
@@ -488,6 +488,16 @@ func (in *Credential) DeepCopyInto(out *Credential) {
 		*out = new(KopiaServerSecret)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentity)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(CredentialSource)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -501,6 +511,49 @@ func (in *Credential) DeepCopy() *Credential {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialSource) DeepCopyInto(out *CredentialSource) {
+	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileCredentialSource)
+		**out = **in
+	}
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultCredentialSource)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialSource.
+func (in *CredentialSource) DeepCopy() *CredentialSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Encryption) DeepCopyInto(out *Encryption) {
+	*out = *in
+	out.KeySecret = in.KeySecret
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Encryption.
+func (in *Encryption) DeepCopy() *Encryption {
+	if in == nil {
+		return nil
+	}
+	out := new(Encryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Error) DeepCopyInto(out *Error) {
 	*out = *in
@@ -517,6 +570,22 @@ func (in *Error) DeepCopy() *Error {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileCredentialSource) DeepCopyInto(out *FileCredentialSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileCredentialSource.
+func (in *FileCredentialSource) DeepCopy() *FileCredentialSource {
+	if in == nil {
+		return nil
+	}
+	out := new(FileCredentialSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONMap.
 func (in JSONMap) DeepCopy() JSONMap {
 	if in == nil {
@@ -614,6 +683,23 @@ func (in *Location) DeepCopy() *Location {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectLock) DeepCopyInto(out *ObjectLock) {
+	*out = *in
+	out.Retention = in.Retention
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectLock.
+func (in *ObjectLock) DeepCopy() *ObjectLock {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectLock)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 	*out = *in
@@ -667,6 +753,36 @@ func (in *Profile) DeepCopyInto(out *Profile) {
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Location = in.Location
 	in.Credential.DeepCopyInto(&out.Credential)
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(Encryption)
+		**out = **in
+	}
+	if in.ObjectLock != nil {
+		in, out := &in.ObjectLock, &out.ObjectLock
+		*out = new(ObjectLock)
+		**out = **in
+	}
+	if in.ServerSideEncryption != nil {
+		in, out := &in.ServerSideEncryption, &out.ServerSideEncryption
+		*out = new(ServerSideEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		**out = **in
+	}
+	if in.SecondaryLocations != nil {
+		in, out := &in.SecondaryLocations, &out.SecondaryLocations
+		*out = make([]Location, len(*in))
+		copy(*out, *in)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ProfileStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -725,6 +841,49 @@ func (in *ProfileList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastChecked != nil {
+		in, out := &in.LastChecked, &out.LastChecked
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileStatus.
+func (in *ProfileStatus) DeepCopy() *ProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Repository) DeepCopyInto(out *Repository) {
 	*out = *in
@@ -883,6 +1042,27 @@ func (in *ServerInfo) DeepCopy() *ServerInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSideEncryption) DeepCopyInto(out *ServerSideEncryption) {
+	*out = *in
+	if in.CustomerKeySecret != nil {
+		in, out := &in.CustomerKeySecret, &out.CustomerKeySecret
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSideEncryption.
+func (in *ServerSideEncryption) DeepCopy() *ServerSideEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSideEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Storage) DeepCopyInto(out *Storage) {
 	*out = *in
@@ -917,3 +1097,35 @@ func (in *UserAccess) DeepCopy() *UserAccess {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultCredentialSource) DeepCopyInto(out *VaultCredentialSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultCredentialSource.
+func (in *VaultCredentialSource) DeepCopy() *VaultCredentialSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultCredentialSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentity) DeepCopyInto(out *WorkloadIdentity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentity.
+func (in *WorkloadIdentity) DeepCopy() *WorkloadIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
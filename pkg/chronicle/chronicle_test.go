@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/location"
 	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/testutil"
@@ -52,6 +53,28 @@ func (s *ChronicleSuite) SetUpSuite(c *check.C) {
 	s.profile = *testutil.ObjectStoreProfileOrSkip(c, osType, loc)
 }
 
+// MemoryChronicleSuite runs the ChronicleSuite tests against an in-memory
+// object store.
+type MemoryChronicleSuite struct {
+	ChronicleSuite
+}
+
+var _ = check.Suite(&MemoryChronicleSuite{})
+
+func (s *MemoryChronicleSuite) SetUpSuite(c *check.C) {
+	s.profile = param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     location.LocationTypeMemory,
+			Endpoint: "MemoryChronicleSuite",
+			Bucket:   "chronicle",
+		},
+	}
+}
+
+func (s *MemoryChronicleSuite) TearDownSuite(c *check.C) {
+	objectstore.DeleteMemoryStore(s.profile.Location.Endpoint)
+}
+
 func (s *ChronicleSuite) TestPushPull(c *check.C) {
 	pp := filepath.Join(c.MkDir(), "profile.json")
 	err := writeProfile(pp, s.profile)
@@ -28,6 +28,13 @@ const (
 	LabelValueKanister       = "kanister"
 	LabelPrefix              = "kanister.io/"
 	LabelSuffixJobID         = "JobID"
+
+	// WorkloadIdentityTokenFilePath is where ServiceAccount tokens of workload
+	// identity credentials are written in data mover pods.
+	WorkloadIdentityTokenFilePath = "/tmp/kanister-workload-identity-token"
+	// FilesystemLocationMountPath is where the PersistentVolumeClaim of a
+	// filesystem location is mounted if the location has no endpoint.
+	FilesystemLocationMountPath = "/mnt/kanister-location"
 )
 
 // These names are used to query ActionSet API objects.
@@ -60,3 +67,10 @@ const (
 
 // KanisterToolsImageEnvName is used to set up a custom kanister-tools image
 const KanisterToolsImageEnvName = "KANISTER_TOOLS"
+
+// These env vars are set in the pods that functions create to the rate limit
+// of the action, in bytes per second and bytes, and throttle kando commands.
+const (
+	RateLimitEnvName      = "KANDO_RATE_LIMIT"
+	RateLimitBurstEnvName = "KANDO_RATE_LIMIT_BURST"
+)
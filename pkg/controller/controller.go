@@ -76,7 +76,9 @@ type Controller struct {
 	osClient         osversioned.Interface
 	recorder         record.EventRecorder
 	actionSetTombMap sync.Map
-	metrics          *metrics
+	// profileRechecks holds the Profiles that are being checked again.
+	profileRechecks sync.Map
+	metrics         *metrics
 }
 
 // New create controller for watching kanister custom resources created
@@ -91,7 +93,8 @@ func New(c *rest.Config, reg prometheus.Registerer) *Controller {
 	}
 }
 
-// StartWatch watches for instances of ActionSets and Blueprints acts on them.
+// StartWatch watches for instances of ActionSets and Blueprints acts on them
+// and periodically checks the health of Profiles.
 func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
 	crClient, err := versioned.NewForConfig(c.config)
 	if err != nil {
@@ -138,6 +141,9 @@ func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
 		}()
 		go watcher.Watch(o, chTmp)
 	}
+	if interval := profileCheckInterval(); interval > 0 {
+		go c.watchProfiles(ctx, namespace, interval)
+	}
 	return nil
 }
 
@@ -449,7 +455,11 @@ func (c *Controller) LoadOrStoreTomb(ctx context.Context, asName string) (*tomb.
 func (c *Controller) runAction(ctx context.Context, t *tomb.Tomb, as *crv1alpha1.ActionSet, aIDX int, bp *crv1alpha1.Blueprint) error {
 	action := as.Spec.Actions[aIDX]
 	c.logAndSuccessEvent(ctx, fmt.Sprintf("Executing action %s", action.Name), "Started Action", as)
-	tp, err := param.New(ctx, c.clientset, c.dynClient, c.crClient, c.osClient, action)
+	if err := c.checkActionProfile(ctx, action); err != nil {
+		c.incrementActionSetResolutionCounterVec(ActionSetCounterVecLabelResFailure)
+		return err
+	}
+	tp, err := param.New(ctx, c.clientset, c.dynClient, c.crClient, c.osClient, action, as.Namespace)
 	if err != nil {
 		c.incrementActionSetResolutionCounterVec(ActionSetCounterVecLabelResFailure)
 		return err
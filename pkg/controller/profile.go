@@ -0,0 +1,268 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kanisterio/errkit"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+const (
+	// profileCheckIntervalEnv is the environment variable that sets how often
+	// the controller checks the health of Profiles, e.g. 10m. Profiles are
+	// not checked periodically if it is 0.
+	profileCheckIntervalEnv = "KANISTER_PROFILE_CHECK_INTERVAL"
+	// profileWriteCheckEnv is the environment variable that enables the
+	// WriteAccess check, e.g. true. Profiles are only checked with reads by
+	// default, since the sample objects that are written cost requests and
+	// are kept as noncurrent versions in versioned buckets.
+	profileWriteCheckEnv = "KANISTER_PROFILE_WRITE_CHECK"
+
+	defaultProfileCheckInterval = 10 * time.Minute
+	// profileCheckTimeout bounds each check, so that an object store that
+	// does not respond does not hold up the checks of the other Profiles.
+	profileCheckTimeout = time.Minute
+
+	profileCheckSucceeded  = "CheckSucceeded"
+	profileCheckFailed     = "CheckFailed"
+	profileCheckSkipped    = "PreviousCheckFailed"
+	profileCheckNotChecked = "NotChecked"
+)
+
+type profileCheck struct {
+	condition string
+	check     func(context.Context, *crv1alpha1.Profile, kubernetes.Interface) error
+}
+
+// profileChecks are the checks of the health of Profiles. A check is only
+// run if the ones before it succeeded.
+var profileChecks = []profileCheck{
+	{
+		condition: crv1alpha1.ProfileCredentialsValid,
+		check: func(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
+			if err := validate.ProfileSchema(p); err != nil {
+				return err
+			}
+			return validate.ProfileCredential(ctx, p, cli)
+		},
+	},
+	{condition: crv1alpha1.ProfileBucketReachable, check: validate.ProfileBucket},
+	{condition: crv1alpha1.ProfileReadAccess, check: validate.ReadAccess},
+	{condition: crv1alpha1.ProfileWriteAccess, check: validate.WriteAccess},
+}
+
+// profileCheckInterval returns how often Profiles are checked.
+func profileCheckInterval() time.Duration {
+	v, ok := os.LookupEnv(profileCheckIntervalEnv)
+	if !ok || v == "" {
+		return defaultProfileCheckInterval
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Error().Print("Invalid profile check interval, using the default", field.M{"interval": v, "default": defaultProfileCheckInterval})
+		return defaultProfileCheckInterval
+	}
+	return d
+}
+
+// profileWriteCheck returns whether Profiles are checked for write access.
+func profileWriteCheck() bool {
+	v, ok := os.LookupEnv(profileWriteCheckEnv)
+	if !ok || v == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Error().Print("Invalid profile write check setting, not checking write access", field.M{"value": v})
+		return false
+	}
+	return enabled
+}
+
+// watchProfiles checks the health of the Profiles in `namespace` every
+// `interval` until the context is canceled.
+func (c *Controller) watchProfiles(ctx context.Context, namespace string, interval time.Duration) {
+	for {
+		c.checkProfiles(ctx, namespace)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *Controller) checkProfiles(ctx context.Context, namespace string) {
+	ps, err := c.crClient.CrV1alpha1().Profiles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Error().WithContext(ctx).WithError(err).Print("Failed to list Profiles")
+		return
+	}
+	for _, p := range ps.Items {
+		c.updateProfileStatus(ctx, p)
+	}
+}
+
+// updateProfileStatus checks the health of `p` and stores it in its status.
+// It returns the Profile with the new status.
+func (c *Controller) updateProfileStatus(ctx context.Context, p *crv1alpha1.Profile) *crv1alpha1.Profile {
+	status := profileStatus(ctx, p, c.clientset, profileWriteCheck(), metav1.Now())
+	if status == nil {
+		return p
+	}
+	wasHealthy := profileHealthError(p) == nil
+	p = p.DeepCopy()
+	p.Status = status
+	err := reconcile.Profile(ctx, c.crClient.CrV1alpha1(), p.GetNamespace(), p.GetName(), func(rp *crv1alpha1.Profile) error {
+		rp.Status = status
+		return nil
+	})
+	if err != nil {
+		log.Error().WithContext(ctx).WithError(err).Print("Failed to update Profile status", field.M{"Profile": p.GetName()})
+	}
+	if herr := profileHealthError(p); herr != nil && wasHealthy {
+		c.logAndErrorEvent(ctx, "Profile health check failed:", "Unhealthy", herr, p)
+	}
+	return p
+}
+
+// checkActionProfile returns an error if the status of the Profile of the
+// action is unhealthy. Unhealthy Profiles are checked again in the
+// background, so that fixed credentials are noticed before the next periodic
+// check without holding up the action. The claims of filesystem locations are
+// checked in the namespace of the object of the action, where the pods that
+// mount them are run.
+func (c *Controller) checkActionProfile(ctx context.Context, action crv1alpha1.ActionSpec) error {
+	if action.Profile == nil {
+		return nil
+	}
+	p, err := c.crClient.CrV1alpha1().Profiles(action.Profile.Namespace).Get(ctx, action.Profile.Name, metav1.GetOptions{})
+	if err != nil {
+		// Profiles that cannot be read are reported when the action is run
+		return nil
+	}
+	if err := profileHealthError(p); err != nil {
+		c.recheckProfile(ctx, p)
+		return err
+	}
+	if action.Object.Namespace == "" {
+		return nil
+	}
+	return validate.FilesystemClaims(ctx, p, action.Object.Namespace, c.clientset)
+}
+
+// recheckProfile checks the health of `p` again in the background, unless
+// it is already being checked again.
+func (c *Controller) recheckProfile(ctx context.Context, p *crv1alpha1.Profile) {
+	key := p.GetNamespace() + "/" + p.GetName()
+	if _, running := c.profileRechecks.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	// The check outlives the action that triggered it
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer c.profileRechecks.Delete(key)
+		c.updateProfileStatus(ctx, p)
+	}()
+}
+
+// profileStatus runs the checks of the health of `p` and returns its new
+// status, or nil if its location type cannot be checked. Write access is only
+// checked if writeCheck is set.
+func profileStatus(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface, writeCheck bool, now metav1.Time) *crv1alpha1.ProfileStatus {
+	if p.Location.Type == crv1alpha1.LocationTypeKopia {
+		// Kopia repository servers are checked by their own controller
+		return nil
+	}
+	status := &crv1alpha1.ProfileStatus{LastChecked: &now}
+	if p.Status != nil {
+		status.Conditions = p.Status.DeepCopy().Conditions
+	}
+	checks := make([]profileCheck, 0, len(profileChecks))
+	for i, pc := range profileChecks {
+		switch {
+		case i > 0 && p.Location.Bucket == "":
+			// Like kanctl validate profile, only the schema can be checked
+		case pc.condition == crv1alpha1.ProfileWriteAccess && !writeCheck:
+		default:
+			checks = append(checks, pc)
+			continue
+		}
+		apimeta.RemoveStatusCondition(&status.Conditions, pc.condition)
+	}
+	failed := false
+	for _, pc := range checks {
+		cond := metav1.Condition{
+			Type:   pc.condition,
+			Status: metav1.ConditionUnknown,
+			Reason: profileCheckSkipped,
+		}
+		if !failed {
+			err := runProfileCheck(ctx, pc, p, cli)
+			switch {
+			case validate.IsNotChecked(err):
+				cond.Reason, cond.Message = profileCheckNotChecked, err.Error()
+			case err != nil:
+				failed = true
+				cond.Status, cond.Reason, cond.Message = metav1.ConditionFalse, profileCheckFailed, err.Error()
+			default:
+				cond.Status, cond.Reason = metav1.ConditionTrue, profileCheckSucceeded
+			}
+		}
+		cond.LastTransitionTime = now
+		apimeta.SetStatusCondition(&status.Conditions, cond)
+	}
+	return status
+}
+
+// runProfileCheck runs a check of `p` with a timeout.
+func runProfileCheck(ctx context.Context, pc profileCheck, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
+	ctx, cancel := context.WithTimeout(ctx, profileCheckTimeout)
+	defer cancel()
+	return pc.check(ctx, p, cli)
+}
+
+// profileHealthError returns an error that describes the failed checks of
+// `p`, or nil if none failed.
+func profileHealthError(p *crv1alpha1.Profile) error {
+	if p.Status == nil {
+		return nil
+	}
+	var msgs []string
+	for _, cond := range p.Status.Conditions {
+		if cond.Status == metav1.ConditionFalse {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", cond.Type, cond.Message))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errkit.New(fmt.Sprintf("Profile %s/%s is unhealthy: %s", p.GetNamespace(), p.GetName(), strings.Join(msgs, "; ")))
+}
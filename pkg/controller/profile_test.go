@@ -0,0 +1,195 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"gopkg.in/check.v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
+)
+
+type ProfileHealthSuite struct{}
+
+var _ = check.Suite(&ProfileHealthSuite{})
+
+func (s *ProfileHealthSuite) TestProfileStatus(c *check.C) {
+	ctx := context.Background()
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+	p := &crv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "ns"},
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeFilesystem,
+			Bucket: "backups",
+		},
+	}
+
+	// The claim of the filesystem location is not set
+	cli := fake.NewSimpleClientset()
+	p.Status = profileStatus(ctx, p, cli, true, now)
+	c.Assert(p.Status.LastChecked, check.DeepEquals, &now)
+	c.Assert(p.Status.Conditions, check.HasLen, 4)
+	for cond, status := range map[string]metav1.ConditionStatus{
+		crv1alpha1.ProfileCredentialsValid: metav1.ConditionFalse,
+		crv1alpha1.ProfileBucketReachable:  metav1.ConditionUnknown,
+		crv1alpha1.ProfileReadAccess:       metav1.ConditionUnknown,
+		crv1alpha1.ProfileWriteAccess:      metav1.ConditionUnknown,
+	} {
+		c.Assert(apimeta.FindStatusCondition(p.Status.Conditions, cond).Status, check.Equals, status, check.Commentf(cond))
+	}
+	err := profileHealthError(p)
+	c.Assert(err, check.ErrorMatches, "Profile ns/profile is unhealthy: CredentialsValid: .*PersistentVolumeClaim.*")
+
+	// Conditions keep the time of their last transition
+	later := metav1.NewTime(now.Add(time.Hour))
+	p.Location.ClaimName = "backup-pvc"
+	p.Status = profileStatus(ctx, p, cli, true, later)
+	c.Assert(profileHealthError(p), check.IsNil)
+	c.Assert(p.Status.LastChecked, check.DeepEquals, &later)
+	cond := apimeta.FindStatusCondition(p.Status.Conditions, crv1alpha1.ProfileCredentialsValid)
+	c.Assert(cond.LastTransitionTime, check.Equals, later)
+	p.Status = profileStatus(ctx, p, cli, true, metav1.NewTime(later.Add(time.Hour)))
+	cond = apimeta.FindStatusCondition(p.Status.Conditions, crv1alpha1.ProfileCredentialsValid)
+	c.Assert(cond.Status, check.Equals, metav1.ConditionTrue)
+	c.Assert(cond.LastTransitionTime, check.Equals, later)
+	// Filesystems are only mounted in pods, so their access is unknown
+	for _, t := range []string{crv1alpha1.ProfileBucketReachable, crv1alpha1.ProfileReadAccess, crv1alpha1.ProfileWriteAccess} {
+		cond = apimeta.FindStatusCondition(p.Status.Conditions, t)
+		c.Assert(cond.Status, check.Equals, metav1.ConditionUnknown, check.Commentf(t))
+		c.Assert(cond.Reason, check.Equals, profileCheckNotChecked, check.Commentf(t))
+	}
+
+	// Write access is only checked if it is enabled
+	p.Status = profileStatus(ctx, p, cli, false, later)
+	c.Assert(p.Status.Conditions, check.HasLen, 3)
+	c.Assert(apimeta.FindStatusCondition(p.Status.Conditions, crv1alpha1.ProfileWriteAccess), check.IsNil)
+
+	// Only the schema is checked if there is no bucket
+	p.Location.Bucket = ""
+	p.Status = profileStatus(ctx, p, cli, true, later)
+	c.Assert(p.Status.Conditions, check.HasLen, 1)
+}
+
+func (s *ProfileHealthSuite) TestCredentialsInvalid(c *check.C) {
+	p := &crv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "ns"},
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Bucket: "backups",
+			Region: "us-west-2",
+		},
+		Credential: crv1alpha1.Credential{
+			Type:   crv1alpha1.CredentialTypeSecret,
+			Secret: &crv1alpha1.ObjectReference{Name: "rotated", Namespace: "ns"},
+		},
+	}
+	p.Status = profileStatus(context.Background(), p, fake.NewSimpleClientset(), true, metav1.Now())
+	cond := apimeta.FindStatusCondition(p.Status.Conditions, crv1alpha1.ProfileCredentialsValid)
+	c.Assert(cond.Status, check.Equals, metav1.ConditionFalse)
+	c.Assert(cond.Reason, check.Equals, profileCheckFailed)
+	cond = apimeta.FindStatusCondition(p.Status.Conditions, crv1alpha1.ProfileWriteAccess)
+	c.Assert(cond.Status, check.Equals, metav1.ConditionUnknown)
+	c.Assert(profileHealthError(p), check.ErrorMatches, "Profile ns/profile is unhealthy: CredentialsValid: .*")
+
+	// Kopia profiles are not checked
+	p.Location.Type = crv1alpha1.LocationTypeKopia
+	c.Assert(profileStatus(context.Background(), p, fake.NewSimpleClientset(), true, metav1.Now()), check.IsNil)
+}
+
+func (s *ProfileHealthSuite) TestProfileCheckInterval(c *check.C) {
+	defer os.Unsetenv(profileCheckIntervalEnv) //nolint:errcheck
+	for v, expected := range map[string]time.Duration{
+		"":        defaultProfileCheckInterval,
+		"1h":      time.Hour,
+		"0":       0,
+		"invalid": defaultProfileCheckInterval,
+		"-1m":     defaultProfileCheckInterval,
+	} {
+		c.Assert(os.Setenv(profileCheckIntervalEnv, v), check.IsNil)
+		c.Assert(profileCheckInterval(), check.Equals, expected, check.Commentf(v))
+	}
+}
+
+func (s *ProfileHealthSuite) TestProfileWriteCheck(c *check.C) {
+	defer os.Unsetenv(profileWriteCheckEnv) //nolint:errcheck
+	for v, expected := range map[string]bool{
+		"":        false,
+		"true":    true,
+		"false":   false,
+		"invalid": false,
+	} {
+		c.Assert(os.Setenv(profileWriteCheckEnv, v), check.IsNil)
+		c.Assert(profileWriteCheck(), check.Equals, expected, check.Commentf(v))
+	}
+}
+
+func (s *ProfileHealthSuite) TestCheckActionProfile(c *check.C) {
+	ctx := context.Background()
+	p := &crv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "ns"},
+		Location: crv1alpha1.Location{
+			Type:      crv1alpha1.LocationTypeFilesystem,
+			Bucket:    "backups",
+			ClaimName: "backup-pvc",
+		},
+		Status: &crv1alpha1.ProfileStatus{
+			Conditions: []metav1.Condition{{
+				Type:    crv1alpha1.ProfileCredentialsValid,
+				Status:  metav1.ConditionFalse,
+				Reason:  profileCheckFailed,
+				Message: "rotated",
+			}},
+		},
+	}
+	crCli := crfake.NewSimpleClientset(p)
+	ctlr := &Controller{crClient: crCli, clientset: fake.NewSimpleClientset()}
+	action := crv1alpha1.ActionSpec{Profile: &crv1alpha1.ObjectReference{Name: "profile", Namespace: "ns"}}
+
+	// Actions fail from the status of the Profile, which is checked again in
+	// the background
+	err := ctlr.checkActionProfile(ctx, action)
+	c.Assert(err, check.ErrorMatches, "Profile ns/profile is unhealthy: CredentialsValid: rotated")
+	for i := 0; ; i++ {
+		rp, err := crCli.CrV1alpha1().Profiles("ns").Get(ctx, "profile", metav1.GetOptions{})
+		c.Assert(err, check.IsNil)
+		if profileHealthError(rp) == nil {
+			break
+		}
+		c.Assert(i < 100, check.Equals, true)
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(ctlr.checkActionProfile(ctx, action), check.IsNil)
+}
+
+func (s *ProfileHealthSuite) TestProfileCheckTimeout(c *check.C) {
+	pc := profileCheck{
+		condition: crv1alpha1.ProfileBucketReachable,
+		check: func(ctx context.Context, _ *crv1alpha1.Profile, _ kubernetes.Interface) error {
+			deadline, ok := ctx.Deadline()
+			c.Assert(ok, check.Equals, true)
+			c.Assert(time.Until(deadline) <= profileCheckTimeout, check.Equals, true)
+			return nil
+		},
+	}
+	c.Assert(runProfileCheck(context.Background(), pc, &crv1alpha1.Profile{}, nil), check.IsNil)
+}
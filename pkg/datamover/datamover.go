@@ -16,12 +16,18 @@
 // operations such as pulling, pushing, and deleting data from object storage.
 package datamover
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 type DataMover interface {
 	// Pull is used to download the data from object storage
 	// using the preferred data-mover
 	Pull(ctx context.Context, sourcePath, destinationPath string) error
+	// PullTo is used to download the data from object storage
+	// into a writer using the preferred data-mover
+	PullTo(ctx context.Context, w io.Writer, destinationPath string) error
 	// Push is used to upload the data to object storage
 	// using the preferred data-mover
 	Push(ctx context.Context, sourcePath, destinationPath string) error
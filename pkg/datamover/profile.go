@@ -16,6 +16,7 @@ package datamover
 
 import (
 	"context"
+	"io"
 
 	"github.com/kanisterio/errkit"
 
@@ -23,7 +24,9 @@ import (
 	"github.com/kanisterio/kanister/pkg/kopia"
 	"github.com/kanisterio/kanister/pkg/kopia/repository"
 	"github.com/kanisterio/kanister/pkg/kopia/snapshot"
+	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/ratelimit"
 )
 
 // Check that Profile implements DataMover interface
@@ -33,6 +36,7 @@ type Profile struct {
 	outputName string
 	profile    *param.Profile
 	snapJSON   string
+	transfer   objectstore.TransferOptions
 }
 
 func (p *Profile) Pull(ctx context.Context, sourcePath, destinationPath string) error {
@@ -44,13 +48,28 @@ func (p *Profile) Pull(ctx context.Context, sourcePath, destinationPath string)
 		if err := p.connectToKopiaRepositoryServer(ctx, repository.ReadOnlyAccess); err != nil {
 			return err
 		}
-		return kopiaLocationPull(ctx, kopiaSnap.ID, destinationPath, sourcePath, p.profile.Credential.KopiaServerSecret.Password)
+		return kopiaLocationPull(ctx, kopiaSnap.ID, destinationPath, sourcePath, p.profile.Credential.KopiaServerSecret.Password, ratelimit.NewLimiter(p.profile.RateLimit))
 	}
 	target, err := targetWriter(sourcePath)
 	if err != nil {
 		return err
 	}
-	return locationPull(ctx, p.profile, destinationPath, target)
+	return locationPull(ctx, p.profile, destinationPath, target, p.transfer)
+}
+
+func (p *Profile) PullTo(ctx context.Context, w io.Writer, destinationPath string) error {
+	if p.profile.Location.Type == crv1alpha1.LocationTypeKopia {
+		kopiaSnap, err := p.unmarshalKopiaSnapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if err := p.connectToKopiaRepositoryServer(ctx, repository.ReadOnlyAccess); err != nil {
+			return err
+		}
+		w = ratelimit.Writer(ctx, w, ratelimit.NewLimiter(p.profile.RateLimit))
+		return snapshot.Read(ctx, w, kopiaSnap.ID, destinationPath, p.profile.Credential.KopiaServerSecret.Password)
+	}
+	return locationPull(ctx, p.profile, destinationPath, w, p.transfer)
 }
 
 func (p *Profile) Push(ctx context.Context, sourcePath, destinationPath string) error {
@@ -58,14 +77,14 @@ func (p *Profile) Push(ctx context.Context, sourcePath, destinationPath string)
 		if err := p.connectToKopiaRepositoryServer(ctx, repository.WriteAccess); err != nil {
 			return err
 		}
-		_, err := kopiaLocationPush(ctx, destinationPath, p.outputName, sourcePath, p.profile.Credential.KopiaServerSecret.Password)
+		_, err := kopiaLocationPush(ctx, destinationPath, p.outputName, sourcePath, p.profile.Credential.KopiaServerSecret.Password, ratelimit.NewLimiter(p.profile.RateLimit))
 		return err
 	}
 	source, err := sourceReader(sourcePath)
 	if err != nil {
 		return err
 	}
-	return locationPush(ctx, p.profile, destinationPath, source)
+	return locationPush(ctx, p.profile, destinationPath, p.outputName, source, p.transfer)
 }
 
 func (p *Profile) Delete(ctx context.Context, destinationPath string) error {
@@ -109,10 +128,11 @@ func (p *Profile) unmarshalKopiaSnapshot(ctx context.Context) (*snapshot.Snapsho
 	return &kopiaSnap, nil
 }
 
-func NewProfileDataMover(prof *param.Profile, outputName, snapJSON string) *Profile {
+func NewProfileDataMover(prof *param.Profile, outputName, snapJSON string, transfer objectstore.TransferOptions) *Profile {
 	return &Profile{
 		outputName: outputName,
 		profile:    prof,
 		snapJSON:   snapJSON,
+		transfer:   transfer,
 	}
 }
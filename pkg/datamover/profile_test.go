@@ -52,11 +52,11 @@ func (ps *ProfileSuite) TestLocationOperationsForProfileDataMover(c *check.C) {
 	path := filepath.Join(dir, "test-object1.txt")
 
 	source := bytes.NewBufferString(testContent)
-	err := locationPush(ps.ctx, p, path, source)
+	err := locationPush(ps.ctx, p, path, "", source, objectstore.TransferOptions{})
 	c.Assert(err, check.IsNil)
 
 	target := bytes.NewBuffer(nil)
-	err = locationPull(ps.ctx, p, path, target)
+	err = locationPull(ps.ctx, p, path, target, objectstore.TransferOptions{})
 	c.Assert(err, check.IsNil)
 	c.Assert(target.String(), check.Equals, testContent)
 
@@ -66,13 +66,13 @@ func (ps *ProfileSuite) TestLocationOperationsForProfileDataMover(c *check.C) {
 
 	// test deleting dir with multiple artifacts
 	source = bytes.NewBufferString(testContent)
-	err = locationPush(ps.ctx, p, path, source)
+	err = locationPush(ps.ctx, p, path, "", source, objectstore.TransferOptions{})
 	c.Assert(err, check.IsNil)
 
 	path = filepath.Join(dir, "test-object2.txt")
 
 	source = bytes.NewBufferString(testContent)
-	err = locationPush(ps.ctx, p, path, source)
+	err = locationPush(ps.ctx, p, path, "", source, objectstore.TransferOptions{})
 	c.Assert(err, check.IsNil)
 
 	err = locationDelete(ps.ctx, p, dir)
@@ -16,13 +16,16 @@ package datamover
 
 import (
 	"context"
+	"io"
 
 	"github.com/kanisterio/errkit"
 
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/kopia"
 	"github.com/kanisterio/kanister/pkg/kopia/repository"
 	"github.com/kanisterio/kanister/pkg/kopia/snapshot"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/ratelimit"
 )
 
 // Check that RepositoryServer implements DataMover interface
@@ -33,6 +36,7 @@ type RepositoryServer struct {
 	repositoryServer *param.RepositoryServer
 	snapJSON         string
 	hostName         string
+	rateLimit        *crv1alpha1.RateLimit
 }
 
 func (rs *RepositoryServer) Pull(ctx context.Context, sourcePath, destinationPath string) error {
@@ -44,7 +48,20 @@ func (rs *RepositoryServer) Pull(ctx context.Context, sourcePath, destinationPat
 	if err != nil {
 		return err
 	}
-	return kopiaLocationPull(ctx, kopiaSnap.ID, destinationPath, sourcePath, password)
+	return kopiaLocationPull(ctx, kopiaSnap.ID, destinationPath, sourcePath, password, ratelimit.NewLimiter(rs.rateLimit))
+}
+
+func (rs *RepositoryServer) PullTo(ctx context.Context, w io.Writer, destinationPath string) error {
+	kopiaSnap, err := rs.unmarshalKopiaSnapshot()
+	if err != nil {
+		return err
+	}
+	password, err := rs.connectToKopiaRepositoryServer(ctx, repository.ReadOnlyAccess)
+	if err != nil {
+		return err
+	}
+	w = ratelimit.Writer(ctx, w, ratelimit.NewLimiter(rs.rateLimit))
+	return snapshot.Read(ctx, w, kopiaSnap.ID, destinationPath, password)
 }
 
 func (rs *RepositoryServer) Push(ctx context.Context, sourcePath, destinationPath string) error {
@@ -52,7 +69,7 @@ func (rs *RepositoryServer) Push(ctx context.Context, sourcePath, destinationPat
 	if err != nil {
 		return err
 	}
-	_, err = kopiaLocationPush(ctx, destinationPath, rs.outputName, sourcePath, password)
+	_, err = kopiaLocationPush(ctx, destinationPath, rs.outputName, sourcePath, password, ratelimit.NewLimiter(rs.rateLimit))
 	return err
 }
 
@@ -132,11 +149,12 @@ func (rs *RepositoryServer) checkHostnameExistsInUserAccessMap(userAccessMap map
 	return nil
 }
 
-func NewRepositoryServerDataMover(repoServer *param.RepositoryServer, outputName, snapJSON, userHostname string) *RepositoryServer {
+func NewRepositoryServerDataMover(repoServer *param.RepositoryServer, outputName, snapJSON, userHostname string, rateLimit *crv1alpha1.RateLimit) *RepositoryServer {
 	return &RepositoryServer{
 		outputName:       outputName,
 		repositoryServer: repoServer,
 		snapJSON:         snapJSON,
 		hostName:         userHostname,
+		rateLimit:        rateLimit,
 	}
 }
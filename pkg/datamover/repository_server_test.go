@@ -234,11 +234,11 @@ func (rss *RepositoryServerSuite) TestLocationOperationsForRepositoryServerDataM
 	c.Assert(err, check.IsNil)
 
 	// Test Kopia Repository Server Location Push
-	snapInfo, err := kopiaLocationPush(rss.ctx, rss.repoPathPrefix, "kandoOutput", sourceDir, rss.testUserPassword)
+	snapInfo, err := kopiaLocationPush(rss.ctx, rss.repoPathPrefix, "kandoOutput", sourceDir, rss.testUserPassword, nil)
 	c.Assert(err, check.IsNil)
 
 	// Test Kopia Repository Server Location Pull
-	err = kopiaLocationPull(rss.ctx, snapInfo.ID, rss.repoPathPrefix, targetDir, rss.testUserPassword)
+	err = kopiaLocationPull(rss.ctx, snapInfo.ID, rss.repoPathPrefix, targetDir, rss.testUserPassword, nil)
 	c.Assert(err, check.IsNil)
 
 	// TODO : Verify Data is Pulled from the Location (Issue #2230)
@@ -249,6 +249,6 @@ func (rss *RepositoryServerSuite) TestLocationOperationsForRepositoryServerDataM
 
 	// Verify Data is Deleted from the Location
 	// Expect an Error while Pulling Data
-	err = kopiaLocationPull(rss.ctx, snapInfo.ID, rss.repoPathPrefix, targetDir, rss.testUserPassword)
+	err = kopiaLocationPull(rss.ctx, snapInfo.ID, rss.repoPathPrefix, targetDir, rss.testUserPassword, nil)
 	c.Assert(err, check.NotNil)
 }
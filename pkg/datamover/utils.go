@@ -16,6 +16,7 @@ package datamover
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"os"
 
@@ -23,8 +24,10 @@ import (
 
 	"github.com/kanisterio/kanister/pkg/kopia/snapshot"
 	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/output"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/ratelimit"
 )
 
 const (
@@ -38,27 +41,33 @@ func targetWriter(target string) (io.Writer, error) {
 	return os.Stdout, nil
 }
 
-func locationPull(ctx context.Context, p *param.Profile, path string, target io.Writer) error {
-	return location.Read(ctx, target, *p, path)
+func locationPull(ctx context.Context, p *param.Profile, path string, target io.Writer, transfer objectstore.TransferOptions) error {
+	return location.ReadWithOptions(ctx, target, *p, path, transfer)
 }
 
-// kopiaLocationPull pulls the data from a kopia snapshot into the given target
-func kopiaLocationPull(ctx context.Context, backupID, path, targetPath, password string) error {
+// kopiaLocationPull pulls the data from a kopia snapshot into the given target.
+// Streams are throttled by lim.
+func kopiaLocationPull(ctx context.Context, backupID, path, targetPath, password string, lim ratelimit.Limiter) error {
 	switch targetPath {
 	case usePipeParam:
-		return snapshot.Read(ctx, os.Stdout, backupID, path, password)
+		return snapshot.Read(ctx, ratelimit.Writer(ctx, os.Stdout, lim), backupID, path, password)
 	default:
 		return snapshot.ReadFile(ctx, backupID, targetPath, password)
 	}
 }
 
-// kopiaLocationPush pushes the data from the source using a kopia snapshot
-func kopiaLocationPush(ctx context.Context, path, outputName, sourcePath, password string) (*snapshot.SnapshotInfo, error) {
+// kopiaLocationPush pushes the data from the source using a kopia snapshot.
+// Streams are throttled by lim.
+func kopiaLocationPush(ctx context.Context, path, outputName, sourcePath, password string, lim ratelimit.Limiter) (*snapshot.SnapshotInfo, error) {
 	var snapInfo *snapshot.SnapshotInfo
 	var err error
 	switch sourcePath {
 	case usePipeParam:
-		snapInfo, err = snapshot.Write(ctx, os.Stdin, path, password)
+		source := struct {
+			io.Reader
+			io.Closer
+		}{ratelimit.Reader(ctx, os.Stdin, lim), os.Stdin}
+		snapInfo, err = snapshot.Write(ctx, source, path, password)
 	default:
 		snapInfo, err = snapshot.WriteFile(ctx, path, sourcePath, password)
 	}
@@ -87,8 +96,22 @@ func sourceReader(source string) (io.Reader, error) {
 	return os.Stdin, nil
 }
 
-func locationPush(ctx context.Context, p *param.Profile, path string, source io.Reader) error {
-	return location.Write(ctx, source, *p, path)
+// locationPush pushes the data from the source to the locations of the
+// profile. The locations it was written to are printed as the output named
+// outputName if the profile has secondary locations.
+func locationPush(ctx context.Context, p *param.Profile, path, outputName string, source io.Reader, transfer objectstore.TransferOptions) error {
+	locs, err := location.WriteToLocations(ctx, source, *p, path, transfer)
+	if err != nil {
+		return err
+	}
+	if len(p.SecondaryLocations) == 0 {
+		return nil
+	}
+	out, err := json.Marshal(locs)
+	if err != nil {
+		return errkit.Wrap(err, "Failed to marshal locations")
+	}
+	return output.PrintOutput(outputName, string(out))
 }
 
 // kopiaLocationDelete deletes the kopia snapshot with given backupID
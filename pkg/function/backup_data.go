@@ -109,7 +109,7 @@ func (b *backupDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 		return nil, err
 	}
 
-	if err = ValidateProfile(tp.Profile); err != nil {
+	if err = validateProfileInPod(tp.Profile); err != nil {
 		return nil, errkit.Wrap(err, "Failed to validate Profile")
 	}
 
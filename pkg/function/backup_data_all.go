@@ -105,7 +105,7 @@ func (b *backupDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 		return nil, err
 	}
 
-	if err = ValidateProfile(tp.Profile); err != nil {
+	if err = validateProfileInPod(tp.Profile); err != nil {
 		return nil, errkit.Wrap(err, "Failed to validate Profile")
 	}
 
@@ -122,6 +122,16 @@ func (b *backupDataAllFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 			ps = tp.Deployment.Pods
 		case tp.StatefulSet != nil:
 			ps = tp.StatefulSet.Pods
+		case tp.DaemonSet != nil:
+			ps = tp.DaemonSet.Pods
+		case tp.ReplicaSet != nil:
+			ps = tp.ReplicaSet.Pods
+		case tp.Job != nil:
+			ps = tp.Job.Pods
+		case tp.CronJob != nil:
+			ps = tp.CronJob.Pods
+		case tp.Workload != nil:
+			ps = tp.Workload.Pods
 		default:
 			return nil, errkit.New("Failed to get pods")
 		}
@@ -0,0 +1,373 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/kanisterio/errkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/ephemeral"
+	"github.com/kanisterio/kanister/pkg/kopia/snapshot"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+	"github.com/kanisterio/kanister/pkg/utils"
+)
+
+const (
+	// CopyArtifactFuncName gives the function name
+	CopyArtifactFuncName = "CopyArtifact"
+	// CopyArtifactNamespaceArg is the namespace of the pod that copies the
+	// artifact. It defaults to the namespace of the controller.
+	CopyArtifactNamespaceArg = "namespace"
+	// CopyArtifactImageArg is the image of the pod that copies the artifact
+	CopyArtifactImageArg = "image"
+	// CopyArtifactSourceProfileArg is the reference to the Profile of the
+	// artifact. It defaults to the Profile of the ActionSet.
+	CopyArtifactSourceProfileArg = "sourceProfile"
+	// CopyArtifactDestinationProfileArg is the reference to the Profile the
+	// artifact is copied to
+	CopyArtifactDestinationProfileArg = "destinationProfile"
+	// CopyArtifactArtifactArg is the path of the artifact in the source
+	// Profile
+	CopyArtifactArtifactArg = "artifact"
+	// CopyArtifactDestinationArtifactArg is the path of the copy in the
+	// destination Profile. It defaults to the path of the artifact.
+	CopyArtifactDestinationArtifactArg = "destinationArtifact"
+	// CopyArtifactKopiaSnapshotArg is the ID or the information of the kopia
+	// snapshot that is copied, as produced by `kando location push`
+	CopyArtifactKopiaSnapshotArg = "kopiaSnapshot"
+	// CopyArtifactPodOverrideArg contains pod specs to override default pod specs
+	CopyArtifactPodOverrideArg = "podOverride"
+	// CopyArtifactOutputArtifact is the path of the copy
+	CopyArtifactOutputArtifact = "artifact"
+	// CopyArtifactOutputChecksum is the SHA-256 of the copy
+	CopyArtifactOutputChecksum = "checksum"
+	copyArtifactJobPrefix      = "copy-artifact-"
+)
+
+func init() {
+	_ = kanister.Register(&copyArtifactFunc{})
+}
+
+var _ kanister.Func = (*copyArtifactFunc)(nil)
+
+type copyArtifactFunc struct {
+	progressPercent string
+}
+
+func (*copyArtifactFunc) Name() string {
+	return CopyArtifactFuncName
+}
+
+func (f *copyArtifactFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	f.progressPercent = progress.StartedPercent
+	defer func() { f.progressPercent = progress.CompletedPercent }()
+
+	var namespace, image, artifact, dstArtifact, kopiaSnapshot string
+	var srcRef, dstRef *crv1alpha1.ObjectReference
+	var bpAnnotations, bpLabels map[string]string
+	if err := OptArg(args, CopyArtifactNamespaceArg, &namespace, ""); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyArtifactImageArg, &image, consts.GetKanisterToolsImage()); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, CopyArtifactArtifactArg, &artifact); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyArtifactDestinationArtifactArg, &dstArtifact, artifact); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyArtifactSourceProfileArg, &srcRef, nil); err != nil {
+		return nil, err
+	}
+	if err := Arg(args, CopyArtifactDestinationProfileArg, &dstRef); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyArtifactKopiaSnapshotArg, &kopiaSnapshot, ""); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, PodAnnotationsArg, &bpAnnotations, nil); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, PodLabelsArg, &bpLabels, nil); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, CopyArtifactPodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := bpAnnotations
+	labels := bpLabels
+	if tp.PodAnnotations != nil {
+		// merge the actionset annotations with blueprint annotations
+		var actionSetAnn ActionSetAnnotations = tp.PodAnnotations
+		annotations = actionSetAnn.MergeBPAnnotations(bpAnnotations)
+	}
+
+	if tp.PodLabels != nil {
+		// merge the actionset labels with blueprint labels
+		var actionSetLabels ActionSetLabels = tp.PodLabels
+		labels = actionSetLabels.MergeBPLabels(bpLabels)
+	}
+
+	src := tp.Profile
+	if srcRef != nil {
+		if src, err = fetchProfileRef(ctx, tp, *srcRef); err != nil {
+			return nil, errkit.Wrap(err, "Failed to get source Profile")
+		}
+	}
+	dst, err := fetchProfileRef(ctx, tp, *dstRef)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to get destination Profile")
+	}
+	if src == nil && (kopiaSnapshot == "" || tp.RepositoryServer == nil) {
+		return nil, errkit.New("Profile must be non-nil")
+	}
+	for _, p := range []*param.Profile{src, dst} {
+		if p == nil {
+			continue
+		}
+		if err := ValidateProfile(p); err != nil {
+			return nil, errkit.Wrap(err, "Failed to validate Profile")
+		}
+	}
+	if src != nil && dst.Credential.Type == param.CredentialTypeWorkloadIdentity && src.Credential.Type == param.CredentialTypeWorkloadIdentity {
+		return nil, errkit.New("Artifacts cannot be copied between two Profiles that use workload identity")
+	}
+
+	cmd, err := copyArtifactCommand(src, tp.RepositoryServer, artifact, dst, dstArtifact, kopiaSnapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &kube.PodOptions{
+		Namespace:            namespace,
+		GenerateName:         copyArtifactJobPrefix,
+		Image:                image,
+		Command:              []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:          podOverride,
+		Annotations:          annotations,
+		Labels:               labels,
+		EnvironmentVariables: rateLimitEnv(dst.RateLimit),
+	}
+	for _, p := range []*param.Profile{src, dst} {
+		if err := mountProfileVolume(options, p); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply the registered ephemeral pod changes.
+	if err := ephemeral.PodOptions.Apply(options); err != nil {
+		return nil, errkit.Wrap(err, "Failed to apply ephemeral pod options")
+	}
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create Kubernetes client")
+	}
+	return copyArtifact(ctx, cli, options, src, dst, cmd)
+}
+
+func copyArtifact(ctx context.Context, cli kubernetes.Interface, options *kube.PodOptions, src, dst *param.Profile, cmd []string) (map[string]interface{}, error) {
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := copyArtifactPodFunc(src, dst, cmd)
+	return pr.Run(ctx, podFunc)
+}
+
+func copyArtifactPodFunc(src, dst *param.Profile, cmd []string) func(ctx context.Context, pc kube.PodController) (map[string]interface{}, error) {
+	return func(ctx context.Context, pc kube.PodController) (map[string]interface{}, error) {
+		pod := pc.Pod()
+
+		// Wait for pod to reach running state
+		if err := pc.WaitForPodReady(ctx); err != nil {
+			return nil, errkit.Wrap(err, "Failed while waiting for Pod to be ready", "pod", pod.Name)
+		}
+
+		// kando reads key pairs from the Profiles it is given, only workload
+		// identity tokens have to be written to the pod
+		for _, p := range []*param.Profile{src, dst} {
+			if p == nil || p.Credential.Type != param.CredentialTypeWorkloadIdentity {
+				continue
+			}
+			remover, err := MaybeWriteProfileCredentials(ctx, pc, p)
+			if err != nil {
+				return nil, err
+			}
+			// Parent context could already be dead, so removing file within new context
+			defer remover.Remove(context.Background()) //nolint:errcheck
+		}
+
+		// Get command executor
+		podCommandExecutor, err := pc.GetCommandExecutor()
+		if err != nil {
+			return nil, err
+		}
+
+		stdout, _, err := ExecAndLog(ctx, podCommandExecutor, cmd, pod)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to copy artifact")
+		}
+		out, err := parseLogAndCreateOutput(stdout)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to parse output of kando")
+		}
+		replica := struct {
+			Path     string `json:"path"`
+			Checksum string `json:"checksum"`
+		}{}
+		s, ok := out[CopyArtifactOutputArtifact].(string)
+		if !ok {
+			return nil, errkit.New("kando did not output the copied artifact")
+		}
+		if err := json.Unmarshal([]byte(s), &replica); err != nil {
+			return nil, errkit.Wrap(err, "Failed to unmarshal copied artifact")
+		}
+		return map[string]interface{}{
+			CopyArtifactOutputArtifact: replica.Path,
+			CopyArtifactOutputChecksum: replica.Checksum,
+			FunctionOutputVersion:      kanister.DefaultVersion,
+		}, nil
+	}
+}
+
+// copyArtifactCommand returns the `kando location replicate` command that
+// copies artifact of src, or the kopia snapshot of src or rs, to dstArtifact
+// of dst.
+func copyArtifactCommand(src *param.Profile, rs *param.RepositoryServer, artifact string, dst *param.Profile, dstArtifact, kopiaSnapshot string) ([]string, error) {
+	if dst.Location.Type == crv1alpha1.LocationTypeKopia {
+		return nil, errkit.New("Artifacts cannot be copied to kopia locations")
+	}
+	dstJSON, err := json.Marshal(dst)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to marshal destination Profile")
+	}
+	cmd := []string{"kando", "location", "replicate"}
+	switch {
+	case kopiaSnapshot == "":
+		if src.Location.Type == crv1alpha1.LocationTypeKopia {
+			return nil, errkit.New("Require argument for kopia locations", "arg", CopyArtifactKopiaSnapshotArg)
+		}
+		srcJSON, err := json.Marshal(src)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to marshal source Profile")
+		}
+		artifact = strings.TrimPrefix(artifact, src.Location.Bucket)
+		cmd = append(cmd, "--profile", string(srcJSON))
+	case src != nil && src.Location.Type == crv1alpha1.LocationTypeKopia:
+		srcJSON, err := json.Marshal(src)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to marshal source Profile")
+		}
+		cmd = append(cmd, "--profile", string(srcJSON))
+	case rs != nil:
+		rsJSON, err := json.Marshal(rs)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to marshal Repository Server")
+		}
+		cmd = append(cmd, "--repository-server", string(rsJSON))
+	default:
+		return nil, errkit.New("Kopia snapshots can only be copied from kopia Profiles or the Repository Server of the ActionSet")
+	}
+	info, err := kopiaSnapshotInfo(kopiaSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	if info != "" {
+		cmd = append(cmd, "--kopia-snapshot", info)
+	}
+	return append(cmd,
+		"--path", artifact,
+		"--destination-profile", string(dstJSON),
+		"--destination-path", strings.TrimPrefix(dstArtifact, dst.Location.Bucket),
+		"--output-name", CopyArtifactOutputArtifact,
+	), nil
+}
+
+// kopiaSnapshotInfo returns the snapshot information that kando expects for
+// s, which is either a snapshot ID or the output of `kando location push`.
+func kopiaSnapshotInfo(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.HasPrefix(s, "{") {
+		return s, nil
+	}
+	info, err := json.Marshal(snapshot.SnapshotInfo{ID: s})
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to marshal kopia snapshot information")
+	}
+	return string(info), nil
+}
+
+// fetchProfileRef returns the Profile that ref refers to.
+func fetchProfileRef(ctx context.Context, tp param.TemplateParams, ref crv1alpha1.ObjectReference) (*param.Profile, error) {
+	if ref.Name == "" || ref.Namespace == "" {
+		return nil, errkit.New("Profile reference must have a name and a namespace")
+	}
+	return tp.FetchProfile(ctx, &ref)
+}
+
+func (*copyArtifactFunc) RequiredArgs() []string {
+	return []string{
+		CopyArtifactArtifactArg,
+		CopyArtifactDestinationProfileArg,
+	}
+}
+
+func (*copyArtifactFunc) Arguments() []string {
+	return []string{
+		CopyArtifactNamespaceArg,
+		CopyArtifactImageArg,
+		CopyArtifactArtifactArg,
+		CopyArtifactDestinationArtifactArg,
+		CopyArtifactSourceProfileArg,
+		CopyArtifactDestinationProfileArg,
+		CopyArtifactKopiaSnapshotArg,
+		CopyArtifactPodOverrideArg,
+		PodAnnotationsArg,
+		PodLabelsArg,
+	}
+}
+
+func (f *copyArtifactFunc) Validate(args map[string]any) error {
+	if err := ValidatePodLabelsAndAnnotations(f.Name(), args); err != nil {
+		return err
+	}
+
+	if err := utils.CheckSupportedArgs(f.Arguments(), args); err != nil {
+		return err
+	}
+
+	return utils.CheckRequiredArgs(f.RequiredArgs(), args)
+}
+
+func (f *copyArtifactFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    f.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
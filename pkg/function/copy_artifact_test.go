@@ -0,0 +1,146 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type CopyArtifactSuite struct{}
+
+var _ = check.Suite(&CopyArtifactSuite{})
+
+func (s *CopyArtifactSuite) TestValidate(c *check.C) {
+	f := &copyArtifactFunc{}
+	for _, tc := range []struct {
+		args    map[string]any
+		checker check.Checker
+	}{
+		{
+			args: map[string]any{
+				CopyArtifactArtifactArg:           "backups/dump.sql",
+				CopyArtifactDestinationProfileArg: map[string]any{"name": "dr", "namespace": "kanister"},
+			},
+			checker: check.IsNil,
+		},
+		{
+			args: map[string]any{
+				CopyArtifactArtifactArg:            "backups/dump.sql",
+				CopyArtifactDestinationArtifactArg: "replicas/dump.sql",
+				CopyArtifactSourceProfileArg:       map[string]any{"name": "primary", "namespace": "kanister"},
+				CopyArtifactDestinationProfileArg:  map[string]any{"name": "dr", "namespace": "kanister"},
+			},
+			checker: check.IsNil,
+		},
+		{
+			args:    map[string]any{CopyArtifactArtifactArg: "backups/dump.sql"},
+			checker: check.NotNil,
+		},
+		{
+			args: map[string]any{
+				CopyArtifactArtifactArg:           "backups/dump.sql",
+				CopyArtifactDestinationProfileArg: map[string]any{"name": "dr", "namespace": "kanister"},
+				"snapshotID":                      "k1",
+			},
+			checker: check.NotNil,
+		},
+	} {
+		c.Check(f.Validate(tc.args), tc.checker, check.Commentf("%v", tc.args))
+	}
+}
+
+func (s *CopyArtifactSuite) TestProfileReference(c *check.C) {
+	f := &copyArtifactFunc{}
+	_, err := f.Exec(context.Background(), param.TemplateParams{}, map[string]any{
+		CopyArtifactArtifactArg:           "backups/dump.sql",
+		CopyArtifactDestinationProfileArg: map[string]any{"name": "dr"},
+	})
+	c.Assert(err, check.ErrorMatches, ".*Profile reference must have a name and a namespace.*")
+}
+
+func (s *CopyArtifactSuite) TestCommand(c *check.C) {
+	src := &param.Profile{Location: crv1alpha1.Location{Type: crv1alpha1.LocationTypeS3Compliant, Bucket: "primary"}}
+	kopia := &param.Profile{Location: crv1alpha1.Location{Type: crv1alpha1.LocationTypeKopia}}
+	dst := &param.Profile{Location: crv1alpha1.Location{Type: crv1alpha1.LocationTypeGCS, Bucket: "dr"}}
+	rs := &param.RepositoryServer{Name: "repo"}
+	for _, tc := range []struct {
+		src      *param.Profile
+		rs       *param.RepositoryServer
+		dst      *param.Profile
+		snapshot string
+		flags    map[string]string
+		errMatch string
+	}{
+		{
+			src:   src,
+			dst:   dst,
+			flags: map[string]string{"--profile": "", "--path": "/backups/dump.sql", "--destination-path": "/replicas/dump.sql"},
+		},
+		{
+			src:      kopia,
+			dst:      dst,
+			snapshot: "k1",
+			flags:    map[string]string{"--profile": "", "--kopia-snapshot": `{"id":"k1","logicalSize":0,"physicalSize":0}`},
+		},
+		{
+			rs:       rs,
+			dst:      dst,
+			snapshot: `{"id":"k1"}`,
+			flags:    map[string]string{"--repository-server": "", "--kopia-snapshot": `{"id":"k1"}`},
+		},
+		{
+			src:      kopia,
+			dst:      dst,
+			errMatch: ".*Require argument for kopia locations.*",
+		},
+		{
+			src:      src,
+			dst:      dst,
+			snapshot: "k1",
+			errMatch: ".*Kopia snapshots can only be copied from kopia Profiles.*",
+		},
+		{
+			src:      src,
+			dst:      kopia,
+			errMatch: ".*cannot be copied to kopia locations.*",
+		},
+	} {
+		cmd, err := copyArtifactCommand(tc.src, tc.rs, "primary/backups/dump.sql", tc.dst, "dr/replicas/dump.sql", tc.snapshot)
+		if tc.errMatch != "" {
+			c.Check(err, check.ErrorMatches, tc.errMatch)
+			continue
+		}
+		c.Assert(err, check.IsNil)
+		c.Assert(cmd[:3], check.DeepEquals, []string{"kando", "location", "replicate"})
+		flags := make(map[string]string)
+		for i := 3; i+1 < len(cmd); i += 2 {
+			flags[cmd[i]] = cmd[i+1]
+		}
+		c.Check(flags["--destination-profile"], check.Not(check.Equals), "")
+		c.Check(flags["--output-name"], check.Equals, CopyArtifactOutputArtifact)
+		for k, v := range tc.flags {
+			got, ok := flags[k]
+			c.Check(ok, check.Equals, true, check.Commentf("%s", k))
+			if v != "" {
+				c.Check(got, check.Equals, v, check.Commentf("%s", k))
+			}
+		}
+	}
+}
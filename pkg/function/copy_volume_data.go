@@ -110,6 +110,9 @@ func copyVolumeData(
 		Annotations: annotations,
 		Labels:      labels,
 	}
+	if err := mountProfileVolume(options, tp.Profile); err != nil {
+		return nil, err
+	}
 
 	// Apply the registered ephemeral pod changes.
 	if err := ephemeral.PodOptions.Apply(options); err != nil {
@@ -308,7 +308,7 @@ func (s *DataSuite) getTemplateParamsAndPVCName(c *check.C, replicas int32) (*pa
 		},
 	}
 
-	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as)
+	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 	tp.Profile = s.profile
 
@@ -677,7 +677,7 @@ func (s *DataSuite) initPVCTemplateParams(c *check.C, pvc *corev1.PersistentVolu
 		},
 		Options: options,
 	}
-	tp, err := param.New(context.Background(), s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, pvc), s.crCli, s.osCli, as)
+	tp, err := param.New(context.Background(), s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, pvc), s.crCli, s.osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 	tp.Profile = s.profile
 	return tp
@@ -16,7 +16,9 @@ package function
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
@@ -30,6 +32,8 @@ import (
 	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/ephemeral"
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/progress"
 	"github.com/kanisterio/kanister/pkg/restic"
@@ -104,6 +108,9 @@ func deleteData(
 		Annotations:  annotations,
 		Labels:       labels,
 	}
+	if err := mountProfileVolume(options, tp.Profile); err != nil {
+		return nil, err
+	}
 
 	// Apply the registered ephemeral pod changes.
 	if err := ephemeral.PodOptions.Apply(options); err != nil {
@@ -170,8 +177,12 @@ func deleteDataPodFunc(
 				return nil, err
 			}
 
-			_, _, err = ExecAndLog(ctx, podCommandExecutor, cmd, pod)
+			_, stderr, err := ExecAndLog(ctx, podCommandExecutor, cmd, pod)
 			if err != nil {
+				var re *objectstore.RetentionError
+				if restic.IsAccessDenied(stderr) && errors.As(snapshotRetention(ctx, tp.Profile, targetPaths[i], deleteIdentifier), &re) {
+					return nil, errkit.Wrap(err, "Failed to forget data, data is retained by object lock", "object", re.Name, "mode", re.Mode, "retainUntil", re.RetainUntil.Format(time.RFC3339))
+				}
 				return nil, errkit.Wrap(err, "Failed to forget data")
 			}
 			if reclaimSpace {
@@ -189,6 +200,20 @@ func deleteDataPodFunc(
 	}
 }
 
+// snapshotRetention returns an objectstore.RetentionError if the object of
+// the restic snapshot with the ID id in repository is retained by an object
+// lock. Filesystem locations are only mounted in pods and do not retain
+// objects.
+func snapshotRetention(ctx context.Context, profile *param.Profile, repository, id string) error {
+	if profile.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		return nil
+	}
+	p := *profile
+	p.Location.Prefix = ""
+	key := strings.TrimPrefix(repository, profile.Location.Bucket+"/")
+	return location.CheckRetention(ctx, p, path.Join(key, "snapshots", id))
+}
+
 func pruneData(
 	tp param.TemplateParams,
 	pod *corev1.Pod,
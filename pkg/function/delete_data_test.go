@@ -0,0 +1,65 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type DeleteDataSuite struct{}
+
+var _ = check.Suite(&DeleteDataSuite{})
+
+func (s *DeleteDataSuite) TestSnapshotRetention(c *check.C) {
+	ctx := context.Background()
+	profile := &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     location.LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+			Prefix:   "kanister",
+		},
+		ObjectLock: &crv1alpha1.ObjectLock{
+			Mode:      crv1alpha1.ObjectLockModeCompliance,
+			Retention: metav1.Duration{Duration: time.Hour},
+		},
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+	// Restic writes the repository at the artifact prefix, which does not
+	// need to be under the prefix of the location
+	repo := ResolveArtifactPrefix("restic/app", profile)
+	p := *profile
+	p.Location.Prefix = ""
+	err := location.Write(ctx, bytes.NewBufferString("snapshot"), p, "restic/app/snapshots/0123abcd")
+	c.Assert(err, check.IsNil)
+
+	var re *objectstore.RetentionError
+	err = snapshotRetention(ctx, profile, repo, "0123")
+	c.Assert(errors.As(err, &re), check.Equals, true)
+	c.Assert(re.Name, check.Equals, "restic/app/snapshots/0123abcd")
+	c.Assert(re.Mode, check.Equals, objectstore.ObjectLockModeCompliance)
+	c.Assert(snapshotRetention(ctx, profile, repo, "4567"), check.IsNil)
+}
@@ -130,7 +130,7 @@ func (s *KubeExecAllTest) TestKubeExecAllDeployment(c *check.C) {
 			Namespace: s.namespace,
 		},
 	}
-	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, d), s.crCli, s.osCli, as)
+	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, d), s.crCli, s.osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 
 	action := "echo"
@@ -164,7 +164,7 @@ func (s *KubeExecAllTest) TestKubeExecAllStatefulSet(c *check.C) {
 			Namespace: s.namespace,
 		},
 	}
-	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as)
+	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 
 	action := "echo"
@@ -159,7 +159,7 @@ func (s *KubeExecTest) TestKubeExec(c *check.C) {
 			Namespace: s.namespace,
 		},
 	}
-	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as)
+	tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 
 	action := "echo"
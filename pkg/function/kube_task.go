@@ -69,15 +69,21 @@ func kubeTask(
 	podOverride crv1alpha1.JSONMap,
 	annotations,
 	labels map[string]string,
+	profile *param.Profile,
+	rateLimit *crv1alpha1.RateLimit,
 ) (map[string]interface{}, error) {
 	options := &kube.PodOptions{
-		Namespace:    namespace,
-		GenerateName: jobPrefix,
-		Image:        image,
-		Command:      command,
-		PodOverride:  podOverride,
-		Annotations:  annotations,
-		Labels:       labels,
+		Namespace:            namespace,
+		GenerateName:         jobPrefix,
+		Image:                image,
+		Command:              command,
+		PodOverride:          podOverride,
+		Annotations:          annotations,
+		Labels:               labels,
+		EnvironmentVariables: rateLimitEnv(rateLimit),
+	}
+	if err := mountProfileVolume(options, profile); err != nil {
+		return nil, err
 	}
 
 	// Apply the registered ephemeral pod changes.
@@ -159,6 +165,12 @@ func (ktf *kubeTaskFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 		labels = actionSetLabels.MergeBPLabels(bpLabels)
 	}
 
+	// Actions without a profile can still limit the bandwidth of kando
+	rateLimit, err := param.RateLimit(tp.Profile, tp.Options)
+	if err != nil {
+		return nil, err
+	}
+
 	cli, err := kube.NewClient()
 	if err != nil {
 		return nil, errkit.Wrap(err, "Failed to create Kubernetes client")
@@ -172,6 +184,8 @@ func (ktf *kubeTaskFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 		podOverride,
 		annotations,
 		labels,
+		tp.Profile,
+		rateLimit,
 	)
 }
 
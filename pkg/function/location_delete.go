@@ -16,14 +16,20 @@ package function
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 
 	"github.com/kanisterio/errkit"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
 
 	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/ephemeral"
+	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/location"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/progress"
@@ -35,6 +41,16 @@ const (
 	LocationDeleteFuncName = "LocationDelete"
 	// LocationDeleteArtifactArg provides the path to the artifacts on the object store
 	LocationDeleteArtifactArg = "artifact"
+	// LocationDeleteNamespaceArg is the namespace of the pod that deletes
+	// artifacts of filesystem locations. It defaults to the namespace of the
+	// object of the action.
+	LocationDeleteNamespaceArg = "namespace"
+	// LocationDeleteImageArg is the image of the pod that deletes artifacts
+	// of filesystem locations
+	LocationDeleteImageArg = "image"
+	// LocationDeletePodOverrideArg contains pod specs to override default pod specs
+	LocationDeletePodOverrideArg = "podOverride"
+	locationDeleteJobPrefix      = "location-delete-"
 )
 
 func init() {
@@ -64,7 +80,114 @@ func (l *locationDeleteFunc) Exec(ctx context.Context, tp param.TemplateParams,
 	if err = ValidateProfile(tp.Profile); err != nil {
 		return nil, errkit.Wrap(err, "Failed to validate Profile")
 	}
-	return nil, location.Delete(ctx, *tp.Profile, strings.TrimPrefix(artifact, tp.Profile.Location.Bucket))
+	suffix := strings.TrimPrefix(artifact, tp.Profile.Location.Bucket)
+	if tp.Profile.Location.Type != crv1alpha1.LocationTypeFilesystem {
+		return nil, location.Delete(ctx, *tp.Profile, suffix)
+	}
+
+	// Filesystem locations can only be accessed from pods that mount them
+	var namespace, image string
+	var bpAnnotations, bpLabels map[string]string
+	if err = OptArg(args, LocationDeleteNamespaceArg, &namespace, objectNamespace(tp)); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, LocationDeleteImageArg, &image, consts.GetKanisterToolsImage()); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, PodAnnotationsArg, &bpAnnotations, nil); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, PodLabelsArg, &bpLabels, nil); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, LocationDeletePodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := bpAnnotations
+	labels := bpLabels
+	if tp.PodAnnotations != nil {
+		// merge the actionset annotations with blueprint annotations
+		var actionSetAnn ActionSetAnnotations = tp.PodAnnotations
+		annotations = actionSetAnn.MergeBPAnnotations(bpAnnotations)
+	}
+
+	if tp.PodLabels != nil {
+		// merge the actionset labels with blueprint labels
+		var actionSetLabels ActionSetLabels = tp.PodLabels
+		labels = actionSetLabels.MergeBPLabels(bpLabels)
+	}
+
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: locationDeleteJobPrefix,
+		Image:        image,
+		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		PodOverride:  podOverride,
+		Annotations:  annotations,
+		Labels:       labels,
+	}
+	if err := mountProfileVolume(options, tp.Profile); err != nil {
+		return nil, err
+	}
+
+	// Apply the registered ephemeral pod changes.
+	if err := ephemeral.PodOptions.Apply(options); err != nil {
+		return nil, errkit.Wrap(err, "Failed to apply ephemeral pod options")
+	}
+
+	cmd, err := locationDeleteCommand(tp.Profile, suffix)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create Kubernetes client")
+	}
+	return locationDelete(ctx, cli, options, cmd)
+}
+
+func locationDelete(ctx context.Context, cli kubernetes.Interface, options *kube.PodOptions, cmd []string) (map[string]interface{}, error) {
+	pr := kube.NewPodRunner(cli, options)
+	return pr.Run(ctx, func(ctx context.Context, pc kube.PodController) (map[string]interface{}, error) {
+		pod := pc.Pod()
+
+		// Wait for pod to reach running state
+		if err := pc.WaitForPodReady(ctx); err != nil {
+			return nil, errkit.Wrap(err, "Failed while waiting for Pod to be ready", "pod", pod.Name)
+		}
+
+		// Get command executor
+		podCommandExecutor, err := pc.GetCommandExecutor()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, _, err := ExecAndLog(ctx, podCommandExecutor, cmd, pod); err != nil {
+			return nil, errkit.Wrap(err, "Failed to delete artifact")
+		}
+		return nil, nil
+	})
+}
+
+// locationDeleteCommand returns the `kando location delete` command that
+// deletes the artifacts at suffix of the location of profile.
+func locationDeleteCommand(profile *param.Profile, suffix string) ([]string, error) {
+	profileJSON, err := json.Marshal(profile)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to marshal Profile")
+	}
+	return []string{"kando", "location", "delete", "--profile", string(profileJSON), "--path", suffix}, nil
+}
+
+// objectNamespace returns the namespace of the object of the action, in
+// which the volumes of filesystem locations are mounted by default.
+func objectNamespace(tp param.TemplateParams) string {
+	if tp.Namespace != nil {
+		return tp.Namespace.Name
+	}
+	return (&unstructured.Unstructured{Object: tp.Object}).GetNamespace()
 }
 
 func (*locationDeleteFunc) RequiredArgs() []string {
@@ -72,10 +195,21 @@ func (*locationDeleteFunc) RequiredArgs() []string {
 }
 
 func (*locationDeleteFunc) Arguments() []string {
-	return []string{LocationDeleteArtifactArg}
+	return []string{
+		LocationDeleteArtifactArg,
+		LocationDeleteNamespaceArg,
+		LocationDeleteImageArg,
+		LocationDeletePodOverrideArg,
+		PodAnnotationsArg,
+		PodLabelsArg,
+	}
 }
 
 func (l *locationDeleteFunc) Validate(args map[string]any) error {
+	if err := ValidatePodLabelsAndAnnotations(l.Name(), args); err != nil {
+		return err
+	}
+
 	if err := utils.CheckSupportedArgs(l.Arguments(), args); err != nil {
 		return err
 	}
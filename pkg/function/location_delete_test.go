@@ -0,0 +1,48 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"encoding/json"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type LocationDeleteSuite struct{}
+
+var _ = check.Suite(&LocationDeleteSuite{})
+
+func (s *LocationDeleteSuite) TestCommand(c *check.C) {
+	cmd, err := locationDeleteCommand(newFilesystemProfile(), "backups/dump.sql")
+	c.Assert(err, check.IsNil)
+	c.Assert(cmd, check.HasLen, 7)
+	c.Assert(cmd[:4], check.DeepEquals, []string{"kando", "location", "delete", "--profile"})
+	c.Assert(cmd[5:], check.DeepEquals, []string{"--path", "backups/dump.sql"})
+	p := &param.Profile{}
+	c.Assert(json.Unmarshal([]byte(cmd[4]), p), check.IsNil)
+	c.Assert(p.Location.Type, check.Equals, crv1alpha1.LocationTypeFilesystem)
+}
+
+func (s *LocationDeleteSuite) TestObjectNamespace(c *check.C) {
+	c.Assert(objectNamespace(param.TemplateParams{}), check.Equals, "")
+	c.Assert(objectNamespace(param.TemplateParams{Namespace: &param.NamespaceParams{Name: "app"}}), check.Equals, "app")
+	tp := param.TemplateParams{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "db", "namespace": "app"},
+	}}
+	c.Assert(objectNamespace(tp), check.Equals, "app")
+}
@@ -72,6 +72,16 @@ func getVolumes(tp param.TemplateParams) (map[string]string, error) {
 		podsToPvcs = tp.Deployment.PersistentVolumeClaims
 	case tp.StatefulSet != nil:
 		podsToPvcs = tp.StatefulSet.PersistentVolumeClaims
+	case tp.DaemonSet != nil:
+		podsToPvcs = tp.DaemonSet.PersistentVolumeClaims
+	case tp.ReplicaSet != nil:
+		podsToPvcs = tp.ReplicaSet.PersistentVolumeClaims
+	case tp.Job != nil:
+		podsToPvcs = tp.Job.PersistentVolumeClaims
+	case tp.CronJob != nil:
+		podsToPvcs = tp.CronJob.PersistentVolumeClaims
+	case tp.Workload != nil:
+		podsToPvcs = tp.Workload.PersistentVolumeClaims
 	default:
 		return nil, errkit.New("Failed to get volumes")
 	}
@@ -111,6 +111,13 @@ func getAWSConfigFromProfile(ctx context.Context, profile *param.Profile, region
 		config[aws.SecretAccessKey] = string(profile.Credential.Secret.Data[secrets.AWSSecretAccessKey])
 		config[aws.ConfigRole] = string(profile.Credential.Secret.Data[secrets.ConfigRole])
 		config[aws.SessionToken] = string(profile.Credential.Secret.Data[secrets.AWSSessionToken])
+	case param.CredentialTypeWorkloadIdentity:
+		token, err := profile.Credential.WorkloadIdentity.TokenSource.Token(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		config[aws.ConfigRole] = profile.Credential.WorkloadIdentity.RoleARN
+		config[aws.ConfigWebIdentityToken] = token
 	}
 	if region == "" {
 		config[aws.ConfigRegion] = profile.Location.Region
@@ -140,6 +140,10 @@ func restoreData(
 	annotations,
 	labels map[string]string,
 ) (map[string]any, error) {
+	vols, err := withProfileVolume(vols, tp.Profile)
+	if err != nil {
+		return nil, err
+	}
 	podFunc := restoreDataPodFunc(tp, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID, backupPath, insecureTLS)
 	return PrepareAndRunPod(
 		ctx,
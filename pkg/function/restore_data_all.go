@@ -101,6 +101,16 @@ func validateAndGetRestoreAllOptArgs(args map[string]interface{}, tp param.Templ
 			ps = tp.Deployment.Pods
 		case tp.StatefulSet != nil:
 			ps = tp.StatefulSet.Pods
+		case tp.DaemonSet != nil:
+			ps = tp.DaemonSet.Pods
+		case tp.ReplicaSet != nil:
+			ps = tp.ReplicaSet.Pods
+		case tp.Job != nil:
+			ps = tp.Job.Pods
+		case tp.CronJob != nil:
+			ps = tp.CronJob.Pods
+		case tp.Workload != nil:
+			ps = tp.Workload.Pods
 		default:
 			return restorePath, encryptionKey, ps, insecureTLS, podOverride, errkit.New("Unsupported workload type")
 		}
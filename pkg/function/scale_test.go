@@ -163,7 +163,7 @@ func (s *ScaleSuite) TestScaleDeployment(c *check.C) {
 	}
 	var scaleUpToReplicas int32 = 2
 	for _, action := range []string{"scaleUp", "echoHello", "scaleDown"} {
-		tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, d), s.crCli, s.osCli, as)
+		tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, d), s.crCli, s.osCli, as, s.namespace)
 		c.Assert(err, check.IsNil)
 		bp := newScaleBlueprint(kind, fmt.Sprintf("%d", scaleUpToReplicas))
 		phases, err := kanister.GetPhases(*bp, action, kanister.DefaultVersion, *tp)
@@ -225,7 +225,7 @@ func (s *ScaleSuite) TestScaleStatefulSet(c *check.C) {
 
 	var scaleUpToReplicas int32 = 2
 	for _, action := range []string{"scaleUp", "echoHello", "scaleDown"} {
-		tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as)
+		tp, err := param.New(ctx, s.cli, fake.NewSimpleDynamicClient(k8sscheme.Scheme, ss), s.crCli, s.osCli, as, s.namespace)
 		c.Assert(err, check.IsNil)
 		bp := newScaleBlueprint(kind, fmt.Sprintf("%d", scaleUpToReplicas))
 		phases, err := kanister.GetPhases(*bp, action, kanister.DefaultVersion, *tp)
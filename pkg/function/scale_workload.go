@@ -24,7 +24,10 @@ import (
 	"github.com/kanisterio/errkit"
 	osversioned "github.com/openshift/client-go/apps/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 
 	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
@@ -42,6 +45,9 @@ const (
 	ScaleWorkloadKindArg      = "kind"
 	ScaleWorkloadReplicas     = "replicas"
 	ScaleWorkloadWaitArg      = "waitForReady"
+	ScaleWorkloadGroupArg     = "group"
+	ScaleWorkloadVersionArg   = "version"
+	ScaleWorkloadResourceArg  = "resource"
 
 	outputArtifactOriginalReplicaCount = "originalReplicaCount"
 )
@@ -61,6 +67,8 @@ type scaleWorkloadFunc struct {
 	name            string
 	replicas        int32
 	waitForReady    bool
+	// gvr is set for workloads that are scaled through their scale subresource
+	gvr schema.GroupVersionResource
 }
 
 func (*scaleWorkloadFunc) Name() string {
@@ -85,6 +93,9 @@ func (s *scaleWorkloadFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err != nil {
 		return nil, errkit.Wrap(err, "Failed to create Kubernetes client")
 	}
+	if s.gvr.Resource != "" {
+		return s.scaleResource(ctx, cfg, cli)
+	}
 	switch strings.ToLower(s.kind) {
 	case param.StatefulSetKind:
 		count, err := kube.StatefulSetReplicas(ctx, cli, s.namespace, s.name)
@@ -114,10 +125,58 @@ func (s *scaleWorkloadFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 		return map[string]interface{}{
 			outputArtifactOriginalReplicaCount: count,
 		}, kube.ScaleDeploymentConfig(ctx, cli, osCli, s.namespace, s.name, s.replicas, s.waitForReady)
+	case param.DaemonSetKind:
+		count, err := kube.DaemonSetReplicas(ctx, cli, s.namespace, s.name)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			outputArtifactOriginalReplicaCount: count,
+		}, kube.ScaleDaemonSet(ctx, cli, s.namespace, s.name, s.replicas, s.waitForReady)
+	case param.ReplicaSetKind:
+		count, err := kube.ReplicaSetReplicas(ctx, cli, s.namespace, s.name)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			outputArtifactOriginalReplicaCount: count,
+		}, kube.ScaleReplicaSet(ctx, cli, s.namespace, s.name, s.replicas, s.waitForReady)
+	case param.JobKind:
+		count, err := kube.JobReplicas(ctx, cli, s.namespace, s.name)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			outputArtifactOriginalReplicaCount: count,
+		}, kube.ScaleJob(ctx, cli, s.namespace, s.name, s.replicas, s.waitForReady)
+	case param.CronJobKind:
+		count, err := kube.CronJobReplicas(ctx, cli, s.namespace, s.name)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			outputArtifactOriginalReplicaCount: count,
+		}, kube.ScaleCronJob(ctx, cli, s.namespace, s.name, s.replicas)
 	}
 	return nil, errkit.New("Workload type not supported " + s.kind)
 }
 
+// scaleResource scales any workload, including custom resources, through
+// its scale subresource.
+func (s *scaleWorkloadFunc) scaleResource(ctx context.Context, cfg *rest.Config, cli kubernetes.Interface) (map[string]interface{}, error) {
+	dynCli, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create dynamic Kubernetes client")
+	}
+	count, err := kube.ScaleResourceReplicas(ctx, dynCli, s.gvr, s.namespace, s.name)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		outputArtifactOriginalReplicaCount: count,
+	}, kube.ScaleResource(ctx, cli, dynCli, s.gvr, s.namespace, s.name, s.replicas, s.waitForReady)
+}
+
 func (*scaleWorkloadFunc) RequiredArgs() []string {
 	return []string{ScaleWorkloadReplicas}
 }
@@ -129,6 +188,9 @@ func (*scaleWorkloadFunc) Arguments() []string {
 		ScaleWorkloadNameArg,
 		ScaleWorkloadKindArg,
 		ScaleWorkloadWaitArg,
+		ScaleWorkloadGroupArg,
+		ScaleWorkloadVersionArg,
+		ScaleWorkloadResourceArg,
 	}
 }
 
@@ -176,21 +238,13 @@ func (s *scaleWorkloadFunc) setArgs(tp param.TemplateParams, args map[string]int
 		return errkit.New(fmt.Sprintf("Invalid arg type %T for Arg %s ", rep, ScaleWorkloadReplicas))
 	}
 	// Populate default values for optional arguments from template parameters
-	switch {
-	case tp.StatefulSet != nil:
-		kind = param.StatefulSetKind
-		name = tp.StatefulSet.Name
-		namespace = tp.StatefulSet.Namespace
-	case tp.Deployment != nil:
-		kind = param.DeploymentKind
-		name = tp.Deployment.Name
-		namespace = tp.Deployment.Namespace
-	case tp.DeploymentConfig != nil:
-		kind = param.DeploymentConfigKind
-		name = tp.DeploymentConfig.Name
-		namespace = tp.DeploymentConfig.Namespace
-	default:
-		if !ArgExists(args, ScaleWorkloadNamespaceArg) || !ArgExists(args, ScaleWorkloadNameArg) || !ArgExists(args, ScaleWorkloadKindArg) {
+	kind, name, namespace = workloadFromTemplateParams(tp)
+	var gvr schema.GroupVersionResource
+	if tp.Workload != nil {
+		gvr = schema.GroupVersionResource{Group: tp.Workload.Group, Version: tp.Workload.Version, Resource: tp.Workload.Resource}
+	}
+	if kind == "" && gvr.Resource == "" {
+		if !ArgExists(args, ScaleWorkloadNamespaceArg) || !ArgExists(args, ScaleWorkloadNameArg) || !(ArgExists(args, ScaleWorkloadKindArg) || ArgExists(args, ScaleWorkloadResourceArg)) {
 			return errkit.New("Workload information not available via defaults or namespace/name/kind parameters")
 		}
 	}
@@ -211,6 +265,20 @@ func (s *scaleWorkloadFunc) setArgs(tp param.TemplateParams, args map[string]int
 	if err != nil {
 		return err
 	}
+	if err = OptArg(args, ScaleWorkloadGroupArg, &gvr.Group, gvr.Group); err != nil {
+		return err
+	}
+	if err = OptArg(args, ScaleWorkloadVersionArg, &gvr.Version, gvr.Version); err != nil {
+		return err
+	}
+	if err = OptArg(args, ScaleWorkloadResourceArg, &gvr.Resource, gvr.Resource); err != nil {
+		return err
+	}
+	if ArgExists(args, ScaleWorkloadKindArg) && !ArgExists(args, ScaleWorkloadResourceArg) {
+		// An explicit kind takes precedence over the defaulted resource
+		gvr = schema.GroupVersionResource{}
+	}
+	s.gvr = gvr
 	s.kind = kind
 	s.name = name
 	s.namespace = namespace
@@ -218,3 +286,29 @@ func (s *scaleWorkloadFunc) setArgs(tp param.TemplateParams, args map[string]int
 	s.waitForReady = waitForReady
 	return nil
 }
+
+// workloadFromTemplateParams returns the kind, name and namespace of the
+// workload the action is performed on, if it is a scalable workload. The kind
+// is empty for custom workloads, which are scaled through their scale
+// subresource.
+func workloadFromTemplateParams(tp param.TemplateParams) (kind, name, namespace string) {
+	switch {
+	case tp.StatefulSet != nil:
+		return param.StatefulSetKind, tp.StatefulSet.Name, tp.StatefulSet.Namespace
+	case tp.Deployment != nil:
+		return param.DeploymentKind, tp.Deployment.Name, tp.Deployment.Namespace
+	case tp.DeploymentConfig != nil:
+		return param.DeploymentConfigKind, tp.DeploymentConfig.Name, tp.DeploymentConfig.Namespace
+	case tp.DaemonSet != nil:
+		return param.DaemonSetKind, tp.DaemonSet.Name, tp.DaemonSet.Namespace
+	case tp.ReplicaSet != nil:
+		return param.ReplicaSetKind, tp.ReplicaSet.Name, tp.ReplicaSet.Namespace
+	case tp.Job != nil:
+		return param.JobKind, tp.Job.Name, tp.Job.Namespace
+	case tp.CronJob != nil:
+		return param.CronJobKind, tp.CronJob.Name, tp.CronJob.Namespace
+	case tp.Workload != nil:
+		return "", tp.Workload.Name, tp.Workload.Namespace
+	}
+	return "", "", ""
+}
@@ -2,6 +2,7 @@ package function
 
 import (
 	"gopkg.in/check.v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/kanisterio/kanister/pkg/param"
 )
@@ -44,3 +45,72 @@ func (s *ScaleWorkloadSuite) TestSetArgs(c *check.C) {
 		c.Assert(s.replicas, check.Equals, tc.expectedReplicas)
 	}
 }
+
+func (s *ScaleWorkloadSuite) TestSetArgsFromTemplateParams(c *check.C) {
+	for _, tc := range []struct {
+		tp   param.TemplateParams
+		kind string
+	}{
+		{
+			tp:   param.TemplateParams{DaemonSet: &param.DaemonSetParams{Name: "ds", Namespace: "ns"}},
+			kind: param.DaemonSetKind,
+		},
+		{
+			tp:   param.TemplateParams{ReplicaSet: &param.ReplicaSetParams{Name: "rs", Namespace: "ns"}},
+			kind: param.ReplicaSetKind,
+		},
+		{
+			tp:   param.TemplateParams{Job: &param.JobParams{Name: "job", Namespace: "ns"}},
+			kind: param.JobKind,
+		},
+		{
+			tp:   param.TemplateParams{CronJob: &param.JobParams{Name: "cj", Namespace: "ns"}},
+			kind: param.CronJobKind,
+		},
+	} {
+		swf := scaleWorkloadFunc{}
+		err := swf.setArgs(tc.tp, map[string]interface{}{
+			"replicas": 0,
+		})
+		c.Assert(err, check.IsNil)
+		c.Assert(swf.kind, check.Equals, tc.kind)
+		c.Assert(swf.namespace, check.Equals, "ns")
+	}
+
+	swf := scaleWorkloadFunc{}
+	err := swf.setArgs(param.TemplateParams{}, map[string]interface{}{
+		"replicas": 0,
+	})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *ScaleWorkloadSuite) TestSetArgsCustomWorkload(c *check.C) {
+	tp := param.TemplateParams{
+		Workload: &param.WorkloadParams{
+			Name:      "rollout",
+			Namespace: "ns",
+			Group:     "argoproj.io",
+			Version:   "v1alpha1",
+			Resource:  "rollouts",
+		},
+	}
+	swf := scaleWorkloadFunc{}
+	err := swf.setArgs(tp, map[string]interface{}{
+		"replicas": 0,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(swf.name, check.Equals, "rollout")
+	c.Assert(swf.namespace, check.Equals, "ns")
+	c.Assert(swf.gvr, check.DeepEquals, schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"})
+
+	// An explicit kind overrides the custom workload
+	swf = scaleWorkloadFunc{}
+	err = swf.setArgs(tp, map[string]interface{}{
+		"replicas": 0,
+		"kind":     param.DeploymentKind,
+		"name":     "dep",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(swf.kind, check.Equals, param.DeploymentKind)
+	c.Assert(swf.gvr, check.DeepEquals, schema.GroupVersionResource{})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/kanisterio/errkit"
@@ -18,6 +19,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/ephemeral"
 	"github.com/kanisterio/kanister/pkg/format"
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/location"
 	"github.com/kanisterio/kanister/pkg/log"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/secrets"
@@ -137,6 +139,11 @@ func ValidateCredentials(creds *param.Credential) error {
 			return errkit.New("Kopia TLSCert is not set")
 		}
 		return nil
+	case param.CredentialTypeWorkloadIdentity:
+		if creds.WorkloadIdentity == nil {
+			return errkit.New("Empty WorkloadIdentity field")
+		}
+		return nil
 	default:
 		return errkit.New(fmt.Sprintf("Unsupported type '%s' for credentials", creds.Type))
 	}
@@ -147,6 +154,10 @@ func ValidateProfile(profile *param.Profile) error {
 	if profile == nil {
 		return errkit.New("Profile must be non-nil")
 	}
+	if profile.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		// Filesystem locations are mounted and do not need credentials
+		return nil
+	}
 	if err := ValidateCredentials(&profile.Credential); err != nil {
 		return err
 	}
@@ -154,6 +165,7 @@ func ValidateProfile(profile *param.Profile) error {
 	case crv1alpha1.LocationTypeS3Compliant:
 	case crv1alpha1.LocationTypeGCS:
 	case crv1alpha1.LocationTypeAzure:
+	case crv1alpha1.LocationTypeSFTP:
 	case crv1alpha1.LocationTypeKopia:
 	default:
 		return errkit.New("Location type not supported")
@@ -161,6 +173,77 @@ func ValidateProfile(profile *param.Profile) error {
 	return nil
 }
 
+// validateProfileInPod verifies that the location of the profile can be
+// accessed from running pods, which cannot mount the volume of filesystem
+// locations.
+func validateProfileInPod(profile *param.Profile) error {
+	if err := ValidateProfile(profile); err != nil {
+		return err
+	}
+	if profile.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		return errkit.New("Filesystem locations can only be accessed from pods created by Kanister functions")
+	}
+	return nil
+}
+
+// filesystemLocationVolume returns the PersistentVolumeClaim of a filesystem
+// location and the path it is mounted at in pods that access the location.
+func filesystemLocationVolume(profile *param.Profile) (claimName, mountPath string, ok bool) {
+	if profile == nil || profile.Location.Type != crv1alpha1.LocationTypeFilesystem || profile.Location.ClaimName == "" {
+		return "", "", false
+	}
+	return profile.Location.ClaimName, location.FilesystemRoot(profile.Location), true
+}
+
+// mountProfileVolume mounts the PersistentVolumeClaim of a filesystem location
+// in pods created with options, so that they can access the location.
+func mountProfileVolume(options *kube.PodOptions, profile *param.Profile) error {
+	claimName, mountPath, ok := filesystemLocationVolume(profile)
+	if !ok {
+		return nil
+	}
+	if _, ok := options.Volumes[claimName]; ok {
+		return errkit.New("Volume of filesystem location is already mounted", "claimName", claimName)
+	}
+	if options.Volumes == nil {
+		options.Volumes = make(map[string]kube.VolumeMountOptions)
+	}
+	options.Volumes[claimName] = kube.VolumeMountOptions{MountPath: mountPath}
+	return nil
+}
+
+// rateLimitEnv returns the environment variables that limit the bandwidth of
+// kando commands in pods created by functions, which may not be given the
+// profile of the action.
+func rateLimitEnv(l *crv1alpha1.RateLimit) []corev1.EnvVar {
+	if l == nil || l.BytesPerSecond <= 0 {
+		return nil
+	}
+	env := []corev1.EnvVar{{Name: consts.RateLimitEnvName, Value: strconv.FormatInt(l.BytesPerSecond, 10)}}
+	if l.Burst > 0 {
+		env = append(env, corev1.EnvVar{Name: consts.RateLimitBurstEnvName, Value: strconv.FormatInt(l.Burst, 10)})
+	}
+	return env
+}
+
+// withProfileVolume returns vols with the PersistentVolumeClaim of a filesystem
+// location added, for functions that create pods with PrepareAndRunPod.
+func withProfileVolume(vols map[string]string, profile *param.Profile) (map[string]string, error) {
+	claimName, mountPath, ok := filesystemLocationVolume(profile)
+	if !ok {
+		return vols, nil
+	}
+	if _, ok := vols[claimName]; ok {
+		return nil, errkit.New("Volume of filesystem location is already mounted", "claimName", claimName)
+	}
+	withProfile := make(map[string]string, len(vols)+1)
+	for pvc, path := range vols {
+		withProfile[pvc] = path
+	}
+	withProfile[claimName] = mountPath
+	return withProfile, nil
+}
+
 type nopRemover struct {
 }
 
@@ -174,8 +257,12 @@ func (nr nopRemover) Path() string {
 	return ""
 }
 
-// MaybeWriteProfileCredentials creates a file with Google credentials if the given profile points to a GCS location, otherwise does nothing
+// MaybeWriteProfileCredentials creates a file with Google credentials if the given profile points to a GCS location,
+// or with the ServiceAccount token if the profile uses workload identity, otherwise does nothing
 func MaybeWriteProfileCredentials(ctx context.Context, pc kube.PodController, profile *param.Profile) (kube.PodFileRemover, error) {
+	if profile.Credential.Type == param.CredentialTypeWorkloadIdentity {
+		return writeWorkloadIdentityFiles(ctx, pc, profile)
+	}
 	if profile.Location.Type == crv1alpha1.LocationTypeGCS {
 		pfw, err := pc.GetFileWriter()
 		if err != nil {
@@ -197,6 +284,13 @@ func MaybeWriteProfileCredentials(ctx context.Context, pc kube.PodController, pr
 //
 //nolint:revive // context-as-argument: maintaining backward compatibility for public API
 func GetPodWriter(cli kubernetes.Interface, ctx context.Context, namespace, podName, containerName string, profile *param.Profile) (kube.PodWriter, error) {
+	if profile.Credential.Type == param.CredentialTypeWorkloadIdentity {
+		pw := &workloadIdentityPodWriter{cli: cli, profile: profile}
+		if err := pw.Write(ctx, namespace, podName, containerName); err != nil {
+			return nil, err
+		}
+		return pw, nil
+	}
 	if profile.Location.Type == crv1alpha1.LocationTypeGCS {
 		pw := kube.NewPodWriter(cli, consts.GoogleCloudCredsFilePath, bytes.NewBufferString(profile.Credential.KeyPair.Secret))
 		if err := pw.Write(ctx, namespace, podName, containerName); err != nil {
@@ -229,6 +323,31 @@ func FetchPodVolumes(pod string, tp param.TemplateParams) (map[string]string, er
 			return pvcToMountPath, nil
 		}
 		return nil, errkit.New("Failed to find volumes for the Pod: " + pod)
+	case tp.DaemonSet != nil:
+		if pvcToMountPath, ok := tp.DaemonSet.PersistentVolumeClaims[pod]; ok {
+			return pvcToMountPath, nil
+		}
+		return nil, errkit.New("Failed to find volumes for the Pod: " + pod)
+	case tp.ReplicaSet != nil:
+		if pvcToMountPath, ok := tp.ReplicaSet.PersistentVolumeClaims[pod]; ok {
+			return pvcToMountPath, nil
+		}
+		return nil, errkit.New("Failed to find volumes for the Pod: " + pod)
+	case tp.Job != nil:
+		if pvcToMountPath, ok := tp.Job.PersistentVolumeClaims[pod]; ok {
+			return pvcToMountPath, nil
+		}
+		return nil, errkit.New("Failed to find volumes for the Pod: " + pod)
+	case tp.CronJob != nil:
+		if pvcToMountPath, ok := tp.CronJob.PersistentVolumeClaims[pod]; ok {
+			return pvcToMountPath, nil
+		}
+		return nil, errkit.New("Failed to find volumes for the Pod: " + pod)
+	case tp.Workload != nil:
+		if pvcToMountPath, ok := tp.Workload.PersistentVolumeClaims[pod]; ok {
+			return pvcToMountPath, nil
+		}
+		return nil, errkit.New("Failed to find volumes for the Pod: " + pod)
 	default:
 		return nil, errkit.New("Invalid Template Params")
 	}
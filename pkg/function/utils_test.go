@@ -19,6 +19,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/secrets"
 )
@@ -39,6 +41,8 @@ func (s *UtilsTestSuite) TestValidateProfile(c *check.C) {
 		{"Invalid Profile", newInvalidProfile(), check.NotNil},
 		{"Invalid Profile with Secret Credentials", newInvalidProfileWithSecretCredentials(), check.NotNil},
 		{"Nil Profile", nil, check.NotNil},
+		{"Filesystem Profile", newFilesystemProfile(), check.IsNil},
+		{"SFTP Profile", newSFTPProfile(), check.IsNil},
 	}
 	for _, tc := range testCases {
 		err := ValidateProfile(tc.profile)
@@ -46,6 +50,42 @@ func (s *UtilsTestSuite) TestValidateProfile(c *check.C) {
 	}
 }
 
+func (s *UtilsTestSuite) TestValidateProfileInPod(c *check.C) {
+	c.Assert(validateProfileInPod(newValidProfile()), check.IsNil)
+	c.Assert(validateProfileInPod(newFilesystemProfile()), check.NotNil)
+	c.Assert(validateProfileInPod(newSFTPProfile()), check.IsNil)
+}
+
+func (s *UtilsTestSuite) TestMountProfileVolume(c *check.C) {
+	options := &kube.PodOptions{}
+	c.Assert(mountProfileVolume(options, newValidProfile()), check.IsNil)
+	c.Assert(options.Volumes, check.HasLen, 0)
+
+	c.Assert(mountProfileVolume(options, newFilesystemProfile()), check.IsNil)
+	c.Assert(options.Volumes, check.DeepEquals, map[string]kube.VolumeMountOptions{
+		"backup-pvc": {MountPath: consts.FilesystemLocationMountPath},
+	})
+	// The volume cannot be mounted twice
+	c.Assert(mountProfileVolume(options, newFilesystemProfile()), check.NotNil)
+
+	vols := map[string]string{"pvc1": "/data"}
+	withProfile, err := withProfileVolume(vols, newFilesystemProfile())
+	c.Assert(err, check.IsNil)
+	c.Assert(withProfile, check.DeepEquals, map[string]string{"pvc1": "/data", "backup-pvc": consts.FilesystemLocationMountPath})
+	c.Assert(vols, check.HasLen, 1)
+}
+
+func (s *UtilsTestSuite) TestRateLimitEnv(c *check.C) {
+	c.Assert(rateLimitEnv(nil), check.HasLen, 0)
+	c.Assert(rateLimitEnv(&crv1alpha1.RateLimit{BytesPerSecond: 1 << 20}), check.DeepEquals, []corev1.EnvVar{
+		{Name: consts.RateLimitEnvName, Value: "1048576"},
+	})
+	c.Assert(rateLimitEnv(&crv1alpha1.RateLimit{BytesPerSecond: 1 << 20, Burst: 1 << 10}), check.DeepEquals, []corev1.EnvVar{
+		{Name: consts.RateLimitEnvName, Value: "1048576"},
+		{Name: consts.RateLimitBurstEnvName, Value: "1024"},
+	})
+}
+
 func (s *UtilsTestSuite) TestFetchPodVolumes(c *check.C) {
 	testCases := []struct {
 		name       string
@@ -315,6 +355,37 @@ func newValidProfile() *param.Profile {
 	}
 }
 
+func newFilesystemProfile() *param.Profile {
+	return &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:      crv1alpha1.LocationTypeFilesystem,
+			Bucket:    "test-bucket",
+			ClaimName: "backup-pvc",
+		},
+	}
+}
+
+func newSFTPProfile() *param.Profile {
+	return &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeSFTP,
+			Bucket:   "test-bucket",
+			Endpoint: "sftp.example.com",
+		},
+		Credential: param.Credential{
+			Type: param.CredentialTypeSecret,
+			Secret: &corev1.Secret{
+				Type: corev1.SecretType(secrets.SFTPSecretType),
+				Data: map[string][]byte{
+					secrets.SFTPUsername: []byte("user"),
+					secrets.SFTPPassword: []byte("password"),
+					secrets.SFTPHostKey:  []byte("ssh-ed25519 AAAA"),
+				},
+			},
+		},
+	}
+}
+
 func newValidProfileWithSecretCredentials() *param.Profile {
 	return &param.Profile{
 		Location: crv1alpha1.Location{
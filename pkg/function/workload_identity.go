@@ -0,0 +1,219 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kanisterio/errkit"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/secrets"
+)
+
+// workloadIdentityRefreshInterval is how often the ServiceAccount token is
+// rewritten to data mover pods. It must be shorter than the time between a
+// token being refreshed and expiring, which is a fifth of
+// secrets.WorkloadIdentityTokenExpiration.
+var workloadIdentityRefreshInterval = 5 * time.Minute
+
+// writeFileFunc writes content to a file in a data mover pod.
+type writeFileFunc func(ctx context.Context, path string, content io.Reader) error
+
+// workloadIdentityWriter writes the ServiceAccount token of a workload
+// identity, and for GCS locations the credential configuration that
+// references it, to a data mover pod. The token is rewritten periodically so
+// that it stays valid during long transfers.
+type workloadIdentityWriter struct {
+	wi       *param.WorkloadIdentity
+	location crv1alpha1.LocationType
+	write    writeFileFunc
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWorkloadIdentityWriter(profile *param.Profile, write writeFileFunc) (*workloadIdentityWriter, error) {
+	wi := profile.Credential.WorkloadIdentity
+	if wi == nil || wi.TokenSource == nil {
+		return nil, errkit.New("Workload identity credentials are not initialized")
+	}
+	return &workloadIdentityWriter{
+		wi:       wi,
+		location: profile.Location.Type,
+		write:    write,
+	}, nil
+}
+
+// start writes the files and starts refreshing the token until stop is called
+// or ctx is done.
+func (w *workloadIdentityWriter) start(ctx context.Context) error {
+	if w.location == crv1alpha1.LocationTypeGCS {
+		cfg, err := secrets.WorkloadIdentityGCPCredentialsJSON(&w.wi.WorkloadIdentity, consts.WorkloadIdentityTokenFilePath)
+		if err != nil {
+			return err
+		}
+		if err := w.write(ctx, consts.GoogleCloudCredsFilePath, bytes.NewBufferString(cfg)); err != nil {
+			return errkit.Wrap(err, "Unable to write Google credentials")
+		}
+	}
+	if err := w.writeToken(ctx); err != nil {
+		return err
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		t := time.NewTicker(workloadIdentityRefreshInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if err := w.writeToken(ctx); err != nil && ctx.Err() == nil {
+					log.Error().WithContext(ctx).WithError(err).Print("Failed to refresh workload identity token", field.M{"path": consts.WorkloadIdentityTokenFilePath})
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *workloadIdentityWriter) writeToken(ctx context.Context) error {
+	token, err := w.wi.TokenSource.Token(ctx)
+	if err != nil {
+		return err
+	}
+	return errkit.Wrap(w.write(ctx, consts.WorkloadIdentityTokenFilePath, bytes.NewBufferString(token)), "Unable to write workload identity token")
+}
+
+// stop stops refreshing the token.
+func (w *workloadIdentityWriter) stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// paths returns the files written to the pod.
+func (w *workloadIdentityWriter) paths() []string {
+	if w.location == crv1alpha1.LocationTypeGCS {
+		return []string{consts.WorkloadIdentityTokenFilePath, consts.GoogleCloudCredsFilePath}
+	}
+	return []string{consts.WorkloadIdentityTokenFilePath}
+}
+
+// workloadIdentityFileRemover implements kube.PodFileRemover for pods that are
+// controlled by a kube.PodController.
+type workloadIdentityFileRemover struct {
+	w        *workloadIdentityWriter
+	mu       sync.Mutex
+	removers map[string]kube.PodFileRemover
+}
+
+var _ kube.PodFileRemover = (*workloadIdentityFileRemover)(nil)
+
+func writeWorkloadIdentityFiles(ctx context.Context, pc kube.PodController, profile *param.Profile) (kube.PodFileRemover, error) {
+	pfw, err := pc.GetFileWriter()
+	if err != nil {
+		return nil, errkit.Wrap(err, "Unable to write workload identity credentials")
+	}
+	r := &workloadIdentityFileRemover{removers: make(map[string]kube.PodFileRemover)}
+	r.w, err = newWorkloadIdentityWriter(profile, func(ctx context.Context, path string, content io.Reader) error {
+		remover, err := pfw.Write(ctx, path, content)
+		if err != nil {
+			return err
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.removers[path] = remover
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := r.w.start(ctx); err != nil {
+		_ = r.Remove(context.Background())
+		return nil, err
+	}
+	return r, nil
+}
+
+// Remove stops refreshing the token and deletes the files from the pod.
+func (r *workloadIdentityFileRemover) Remove(ctx context.Context) error {
+	r.w.stop()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	for _, remover := range r.removers {
+		if rerr := remover.Remove(ctx); rerr != nil {
+			err = errkit.Append(err, rerr)
+		}
+	}
+	return err
+}
+
+// Path returns the path of the token file.
+func (r *workloadIdentityFileRemover) Path() string {
+	return consts.WorkloadIdentityTokenFilePath
+}
+
+// workloadIdentityPodWriter implements kube.PodWriter for pods that are not
+// created by Kanister.
+type workloadIdentityPodWriter struct {
+	cli     kubernetes.Interface
+	profile *param.Profile
+	w       *workloadIdentityWriter
+}
+
+var _ kube.PodWriter = (*workloadIdentityPodWriter)(nil)
+
+// Write writes the files to the pod and starts refreshing the token.
+func (p *workloadIdentityPodWriter) Write(ctx context.Context, namespace, podName, containerName string) error {
+	var err error
+	p.w, err = newWorkloadIdentityWriter(p.profile, func(ctx context.Context, path string, content io.Reader) error {
+		return kube.NewPodWriter(p.cli, path, content).Write(ctx, namespace, podName, containerName)
+	})
+	if err != nil {
+		return err
+	}
+	return p.w.start(ctx)
+}
+
+// Remove stops refreshing the token and deletes the files from the pod.
+func (p *workloadIdentityPodWriter) Remove(ctx context.Context, namespace, podName, containerName string) error {
+	if p.w == nil {
+		return nil
+	}
+	p.w.stop()
+	var err error
+	for _, path := range p.w.paths() {
+		if rerr := kube.NewPodWriter(p.cli, path, nil).Remove(ctx, namespace, podName, containerName); rerr != nil {
+			err = errkit.Append(err, rerr)
+		}
+	}
+	return err
+}
@@ -19,10 +19,13 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
 
 	"github.com/go-logr/logr"
 	"github.com/kanisterio/errkit"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -31,12 +34,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 	"github.com/kanisterio/kanister/pkg/validatingwebhook"
 )
 
 const (
-	metricsPath  = "/metrics"
-	whHandlePath = "/validate/v1alpha1/blueprint"
+	metricsPath         = "/metrics"
+	whHandlePath        = "/validate/v1alpha1/blueprint"
+	profileWHHandlePath = "/validate/v1alpha1/profile"
+	asWHHandlePath      = "/validate/v1alpha1/actionset"
+
+	// profileWHCheckSecretsEnv is the environment variable that enables the
+	// checks of the Secrets referenced by Profiles in the validating webhook.
+	profileWHCheckSecretsEnv = "KANISTER_PROFILE_WEBHOOK_CHECK_SECRETS"
 )
 
 // Info provides information about kanister controller
@@ -45,7 +55,7 @@ type Info struct {
 	Version string `json:"version"`
 }
 
-// RunWebhookServer starts the validating webhook resources for blueprint kanister resources
+// RunWebhookServer starts the validating webhook resources for blueprint, profile and actionset kanister resources
 func RunWebhookServer(c *rest.Config) error {
 	log.SetLogger(logr.New(log.NullLogSink{}))
 	mgr, err := manager.New(c, manager.Options{
@@ -74,9 +84,29 @@ func RunWebhookServer(c *rest.Config) error {
 		return errkit.Wrap(err, "Failed to inject decoder")
 	}
 
+	profileValidator := &validatingwebhook.ProfileValidator{}
+	if err = profileValidator.InjectDecoder(&decoder); err != nil {
+		return errkit.Wrap(err, "Failed to inject decoder")
+	}
+	if checkSecrets, _ := strconv.ParseBool(os.Getenv(profileWHCheckSecretsEnv)); checkSecrets {
+		if profileValidator.Client, err = kubernetes.NewForConfig(c); err != nil {
+			return errkit.Wrap(err, "Failed to create kubernetes client")
+		}
+	}
+
+	asValidator := &validatingwebhook.ActionSetValidator{}
+	if err = asValidator.InjectDecoder(&decoder); err != nil {
+		return errkit.Wrap(err, "Failed to inject decoder")
+	}
+	if asValidator.Client, err = versioned.NewForConfig(c); err != nil {
+		return errkit.Wrap(err, "Failed to create CR client")
+	}
+
 	hookServerOptions := webhook.Options{CertDir: validatingwebhook.WHCertsDir}
 	hookServer := webhook.NewServer(hookServerOptions)
 	hookServer.Register(whHandlePath, &webhook.Admission{Handler: bpValidator})
+	hookServer.Register(profileWHHandlePath, &webhook.Admission{Handler: profileValidator})
+	hookServer.Register(asWHHandlePath, &webhook.Admission{Handler: asValidator})
 	hookServer.Register(metricsPath, promhttp.Handler())
 
 	if err := mgr.Add(hookServer); err != nil {
@@ -435,6 +435,12 @@ func validateProfile(ctx context.Context, profile *crv1alpha1.Profile, cli kuber
 			case writeAccessValidation:
 				err = validate.WriteAccess(ctx, profile, cli)
 			}
+			if validate.IsNotChecked(err) {
+				if !printFailStageOnly {
+					utils.PrintStage(d, utils.Skip)
+				}
+				continue
+			}
 			if err != nil {
 				utils.PrintStage(d, utils.Fail)
 				return err
@@ -28,8 +28,10 @@ import (
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/kopia/command/storage"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/poll"
+	"github.com/kanisterio/kanister/pkg/secrets/repositoryserver"
 )
 
 const (
@@ -56,6 +58,7 @@ type repositoryServerParams struct {
 	prefix                          string
 	location                        string
 	locationCreds                   string
+	profile                         string
 	namespace                       string
 }
 
@@ -76,6 +79,7 @@ func newRepositoryServerCommand() *cobra.Command {
 	cmd.PersistentFlags().StringP(kopiaRepoUserFlag, "k", "", "name of the user for accessing the kopia repository")
 	cmd.PersistentFlags().StringP(locationSecretFlag, "l", "", "name of the secret containing kopia repository storage location details")
 	cmd.PersistentFlags().StringP(locationCredsSecretFlag, "c", "", "name of the secret containing kopia repository storage credentials")
+	cmd.PersistentFlags().String(profileFlagName, "", "name of the profile to create the kopia repository storage location secret from, instead of --location-secret and --location-creds-secret")
 	cmd.PersistentFlags().BoolP(waitFlag, "w", false, "wait for the kopia repository server to be in ready state after creation")
 
 	_ = cmd.MarkFlagRequired(tlsSecretFlag)
@@ -84,8 +88,6 @@ func newRepositoryServerCommand() *cobra.Command {
 	_ = cmd.MarkFlagRequired(repoServerAdminUserAccessSecretFlag)
 	_ = cmd.MarkFlagRequired(kopiaRepoPasswordSecretFlag)
 	_ = cmd.MarkFlagRequired(prefixFlag)
-	_ = cmd.MarkFlagRequired(locationSecretFlag)
-	_ = cmd.MarkFlagRequired(locationCredsSecretFlag)
 	return cmd
 }
 
@@ -100,11 +102,6 @@ func createNewRepositoryServer(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	repositoryServer, err := validateSecretsAndConstructRepositoryServer(rsParams)
-	if err != nil {
-		return err
-	}
-
 	config, err := kube.LoadConfig()
 	if err != nil {
 		return err
@@ -119,6 +116,17 @@ func createNewRepositoryServer(cmd *cobra.Command, args []string) error {
 	}
 
 	ctx := context.Background()
+	if rsParams.profile != "" {
+		if err := createLocationSecretFromProfile(ctx, cli, crCli, rsParams); err != nil {
+			return err
+		}
+	}
+
+	repositoryServer, err := validateSecretsAndConstructRepositoryServer(rsParams)
+	if err != nil {
+		return err
+	}
+
 	rs, err := crCli.CrV1alpha1().RepositoryServers(rsParams.namespace).Create(ctx, repositoryServer, metav1.CreateOptions{})
 	if err != nil {
 		return err
@@ -175,6 +183,14 @@ func generateRepositoryServerParams(cmd *cobra.Command) (*repositoryServerParams
 		return nil, errkit.New(fmt.Sprintf("Invalid secret name %s, it should not be of the form namespace/name )", locationCreds))
 	}
 
+	profile, _ := cmd.Flags().GetString(profileFlagName)
+	if profile != "" && (location != "" || locationCreds != "") {
+		return nil, errkit.New(fmt.Sprintf("--%s cannot be used with --%s or --%s", profileFlagName, locationSecretFlag, locationCredsSecretFlag))
+	}
+	if profile == "" && (location == "" || locationCreds == "") {
+		return nil, errkit.New(fmt.Sprintf("--%s and --%s, or --%s, are required", locationSecretFlag, locationCredsSecretFlag, profileFlagName))
+	}
+
 	ns, err := resolveNamespace(cmd)
 	if err != nil {
 		return nil, err
@@ -190,10 +206,60 @@ func generateRepositoryServerParams(cmd *cobra.Command) (*repositoryServerParams
 		prefix:                          prefix,
 		location:                        location,
 		locationCreds:                   locationCreds,
+		profile:                         profile,
 		namespace:                       ns,
 	}, nil
 }
 
+// createLocationSecretFromProfile creates the location secret of the
+// repository server from the location of the profile and uses the credential
+// secret of the profile as the location credentials secret.
+func createLocationSecretFromProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, rsParams *repositoryServerParams) error {
+	p, err := crCli.CrV1alpha1().Profiles(rsParams.namespace).Get(ctx, rsParams.profile, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	secret, err := locationSecretFromProfile(p)
+	if err != nil {
+		return err
+	}
+	secret, err = cli.CoreV1().Secrets(rsParams.namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("secret/%s created\n", secret.GetName())
+	rsParams.location = secret.GetName()
+	rsParams.locationCreds = p.Credential.Secret.Name
+	return nil
+}
+
+// locationSecretFromProfile returns the location secret of a repository
+// server that stores kopia repositories in the location of the profile.
+// Only profiles with credentials in a secret in their namespace can be used,
+// since the location credentials secret is in the namespace of the
+// repository server.
+func locationSecretFromProfile(p *crv1alpha1.Profile) (*corev1.Secret, error) {
+	cred := p.Credential
+	if cred.Type != crv1alpha1.CredentialTypeSecret || cred.Secret == nil || cred.Source != nil {
+		return nil, errkit.New(fmt.Sprintf("Profile %s should have credentials of type %s", p.GetName(), crv1alpha1.CredentialTypeSecret))
+	}
+	if cred.Secret.Namespace != "" && cred.Secret.Namespace != p.GetNamespace() {
+		return nil, errkit.New(fmt.Sprintf("Credential secret of profile %s should be in namespace %s", p.GetName(), p.GetNamespace()))
+	}
+	data, err := storage.LocationFromProfile(p)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: p.GetName() + "-location-",
+			Namespace:    p.GetNamespace(),
+		},
+		Type: repositoryserver.Location,
+		Data: data,
+	}, nil
+}
+
 func validateSecretsAndConstructRepositoryServer(rsParams *repositoryServerParams) (*crv1alpha1.RepositoryServer, error) {
 	// Fetch and Validate Secrets
 	ctx := context.Background()
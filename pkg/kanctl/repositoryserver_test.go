@@ -0,0 +1,83 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanctl
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
+	"github.com/kanisterio/kanister/pkg/kopia/command/storage"
+	"github.com/kanisterio/kanister/pkg/secrets/repositoryserver"
+)
+
+func (k *KanctlTestSuite) TestCreateLocationSecretFromProfile(c *check.C) {
+	ctx := context.Background()
+	p := &crv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "profile",
+			Namespace: "ns",
+		},
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Bucket: "bucket",
+			Region: "us-west-2",
+		},
+		Credential: crv1alpha1.Credential{
+			Type:   crv1alpha1.CredentialTypeSecret,
+			Secret: &crv1alpha1.ObjectReference{Name: "creds", Namespace: "ns"},
+		},
+		ObjectLock: &crv1alpha1.ObjectLock{
+			Mode:      crv1alpha1.ObjectLockModeCompliance,
+			Retention: metav1.Duration{Duration: time.Hour},
+		},
+		RateLimit: &crv1alpha1.RateLimit{BytesPerSecond: 1 << 20},
+	}
+	cli := fake.NewSimpleClientset()
+	rsParams := &repositoryServerParams{profile: "profile", namespace: "ns"}
+	err := createLocationSecretFromProfile(ctx, cli, crfake.NewSimpleClientset(p), rsParams)
+	c.Assert(err, check.IsNil)
+	c.Assert(rsParams.locationCreds, check.Equals, "creds")
+
+	secrets, err := cli.CoreV1().Secrets("ns").List(ctx, metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(secrets.Items, check.HasLen, 1)
+	secret := secrets.Items[0]
+	c.Assert(secret.Type, check.Equals, repositoryserver.Location)
+	c.Assert(string(secret.Data[repositoryserver.TypeKey]), check.Equals, string(repositoryserver.LocTypeS3))
+	c.Assert(string(secret.Data[repositoryserver.BucketKey]), check.Equals, "bucket")
+	mode, period, err := storage.RetentionFromMap(secret.Data)
+	c.Assert(err, check.IsNil)
+	c.Assert(mode, check.Equals, "COMPLIANCE")
+	c.Assert(period, check.Equals, time.Hour)
+	c.Assert(string(secret.Data[repositoryserver.RateLimitKey]), check.Equals, "1048576")
+
+	// The location credentials secret must be in the namespace of the
+	// repository server
+	p.Credential.Secret.Namespace = "other"
+	_, err = locationSecretFromProfile(p)
+	c.Assert(err, check.ErrorMatches, "Credential secret of profile profile should be in namespace ns")
+	p.Credential = crv1alpha1.Credential{
+		Type:             crv1alpha1.CredentialTypeWorkloadIdentity,
+		WorkloadIdentity: &crv1alpha1.WorkloadIdentity{},
+	}
+	_, err = locationSecretFromProfile(p)
+	c.Assert(err, check.ErrorMatches, "Profile profile should have credentials of type secret")
+}
@@ -16,11 +16,17 @@ package kando
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
 
 	"github.com/kanisterio/errkit"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/datamover"
+	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
 )
 
@@ -29,6 +35,10 @@ const (
 	profileFlagName                      = "profile"
 	repositoryServerFlagName             = "repository-server"
 	repositoryServerUserHostnameFlagName = "repository-server-user-hostname"
+	partSizeFlagName                     = "part-size"
+	concurrencyFlagName                  = "concurrency"
+	rateLimitFlagName                    = "rate-limit"
+	rateLimitBurstFlagName               = "rate-limit-burst"
 
 	// DataMoverTypeProfile is used to specify that the DataMover is of type Profile
 	DataMoverTypeProfile DataMoverType = "profile"
@@ -41,18 +51,133 @@ type DataMoverType string
 func newLocationCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "location <command>",
-		Short: "Push, pull and delete from object storage",
+		Short: "Push, pull, copy, verify and delete from object storage",
 	}
 	cmd.AddCommand(newLocationPushCommand())
 	cmd.AddCommand(newLocationPullCommand())
 	cmd.AddCommand(newLocationDeleteCommand())
+	cmd.AddCommand(newLocationStatCommand())
+	cmd.AddCommand(newLocationCopyCommand())
+	cmd.AddCommand(newLocationReplicateCommand())
+	cmd.AddCommand(newLocationVerifyCommand())
 	cmd.PersistentFlags().StringP(pathFlagName, "s", "", "Specify a path suffix (optional)")
 	cmd.PersistentFlags().StringP(profileFlagName, "p", "", "Pass a Profile as a JSON string (required)")
 	cmd.PersistentFlags().StringP(repositoryServerFlagName, "r", "", "Pass a Repository Server CR as a JSON string (required for kopia based blueprints)")
 	cmd.PersistentFlags().StringP(repositoryServerUserHostnameFlagName, "c", "", "Pass the Repository Server Client Hostname (applicable if --repository-server is passed)")
+	cmd.PersistentFlags().String(partSizeFlagName, "", "Size of the parts objects are uploaded and downloaded in, e.g. 64Mi (optional, applicable if --profile is passed)")
+	cmd.PersistentFlags().Int(concurrencyFlagName, 0, "Number of parts uploaded or downloaded in parallel (optional, applicable if --profile is passed)")
+	cmd.PersistentFlags().String(rateLimitFlagName, "", "Limit the bandwidth of data movement in bytes per second, e.g. 10Mi. Overrides the rate limit of the Profile. Defaults to $"+consts.RateLimitEnvName+" (optional)")
+	cmd.PersistentFlags().String(rateLimitBurstFlagName, "", "Number of bytes that can be moved at once when the bandwidth is limited, e.g. 1Mi. Defaults to $"+consts.RateLimitBurstEnvName+" (optional)")
 	return cmd
 }
 
+// transferOptionsFromCMD returns the TransferOptions configured by the
+// --part-size and --concurrency flags.
+func transferOptionsFromCMD(cmd *cobra.Command) (objectstore.TransferOptions, error) {
+	var opts objectstore.TransferOptions
+	if ps := cmd.Flag(partSizeFlagName).Value.String(); ps != "" {
+		q, err := resource.ParseQuantity(ps)
+		if err != nil {
+			return opts, errkit.Wrap(err, "failed to parse part size", "partSize", ps)
+		}
+		if q.Value() < objectstore.MinPartSize {
+			return opts, errkit.New(fmt.Sprintf("part size must be at least %d bytes", objectstore.MinPartSize), "partSize", ps)
+		}
+		opts.PartSize = q.Value()
+	}
+	concurrency, err := cmd.Flags().GetInt(concurrencyFlagName)
+	if err != nil {
+		return opts, err
+	}
+	if concurrency < 0 {
+		return opts, errkit.New("concurrency must not be negative", "concurrency", concurrency)
+	}
+	opts.Concurrency = concurrency
+	return opts, nil
+}
+
+// rateLimitFromCMD returns the rate limit configured by the --rate-limit and
+// --rate-limit-burst flags, or the environment variables that functions set
+// in the pods they create. It returns nil if neither is set.
+func rateLimitFromCMD(cmd *cobra.Command) (*crv1alpha1.RateLimit, error) {
+	rate := flagOrEnv(cmd, rateLimitFlagName, consts.RateLimitEnvName)
+	burst := flagOrEnv(cmd, rateLimitBurstFlagName, consts.RateLimitBurstEnvName)
+	if rate == "" {
+		if burst != "" {
+			return nil, errkit.New("--rate-limit-burst requires --rate-limit")
+		}
+		return nil, nil
+	}
+	var l crv1alpha1.RateLimit
+	for _, v := range []struct {
+		name  string
+		value string
+		dst   *int64
+	}{
+		{name: rateLimitFlagName, value: rate, dst: &l.BytesPerSecond},
+		{name: rateLimitBurstFlagName, value: burst, dst: &l.Burst},
+	} {
+		if v.value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(v.value)
+		if err != nil {
+			return nil, errkit.Wrap(err, "failed to parse "+v.name, v.name, v.value)
+		}
+		if q.Sign() < 0 {
+			return nil, errkit.New(v.name+" must not be negative", v.name, v.value)
+		}
+		*v.dst = q.Value()
+	}
+	if l.BytesPerSecond == 0 {
+		// A rate of 0 disables the limit of the profile
+		return &crv1alpha1.RateLimit{}, nil
+	}
+	return &l, nil
+}
+
+func flagOrEnv(cmd *cobra.Command, flagName, envName string) string {
+	if v := cmd.Flag(flagName).Value.String(); v != "" {
+		return v
+	}
+	return os.Getenv(envName)
+}
+
+// rateLimitToProfile overrides the rate limit of the profile with the one
+// configured for the command, if any.
+func rateLimitToProfile(cmd *cobra.Command, p *param.Profile) error {
+	l, err := rateLimitFromCMD(cmd)
+	if err != nil || l == nil {
+		return err
+	}
+	p.RateLimit = l
+	return nil
+}
+
+// compressionFromCMD overrides the compression of the profile with the
+// --compression flag of commands that have it.
+func compressionFromCMD(cmd *cobra.Command, p *param.Profile) error {
+	f := cmd.Flags().Lookup(compressionFlagName)
+	if f == nil || f.Value.String() == "" {
+		return nil
+	}
+	switch c := crv1alpha1.CompressionType(f.Value.String()); c {
+	case crv1alpha1.CompressionTypeNone, crv1alpha1.CompressionTypeGzip, crv1alpha1.CompressionTypeZstd:
+		p.Compression = c
+		return nil
+	default:
+		return errkit.New("unsupported compression", "compression", c)
+	}
+}
+
+// storageClassFromCMD overrides the storage class of the profile with the
+// --storage-class flag of commands that have it.
+func storageClassFromCMD(cmd *cobra.Command, p *param.Profile) {
+	if f := cmd.Flags().Lookup(storageClassFlagName); f != nil && f.Value.String() != "" {
+		p.StorageClass = f.Value.String()
+	}
+}
+
 func pathFlag(cmd *cobra.Command) string {
 	return cmd.Flag(pathFlagName).Value.String()
 }
@@ -71,6 +196,22 @@ func validateCommandArgs(cmd *cobra.Command) error {
 	return nil
 }
 
+// profileFromCMD returns the Profile of commands that only support
+// object storage locations.
+func profileFromCMD(cmd *cobra.Command) (*param.Profile, error) {
+	if cmd.Flags().Lookup(repositoryServerFlagName).Value.String() != "" {
+		return nil, errkit.New("--repository-server is not supported, please provide --profile")
+	}
+	if cmd.Flags().Lookup(profileFlagName).Value.String() == "" {
+		return nil, errkit.New("Please provide --profile")
+	}
+	p, err := unmarshalProfileFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return p, rateLimitToProfile(cmd, p)
+}
+
 // dataMoverForKopiaSnapshotFlag returns a DataMover based on the --kopia-snapshot flag
 func dataMoverForKopiaSnapshotFlag(cmd *cobra.Command) (datamover.DataMover, error) {
 	return dataMoverFromCMD(cmd, cmd.Flag(kopiaSnapshotFlagName).Value.String(), "")
@@ -88,13 +229,28 @@ func dataMoverFromCMD(cmd *cobra.Command, kopiaSnapshot, outputName string) (dat
 		if err != nil {
 			return nil, err
 		}
-		return datamover.NewProfileDataMover(profileRef, outputName, kopiaSnapshot), nil
+		if err := compressionFromCMD(cmd, profileRef); err != nil {
+			return nil, err
+		}
+		storageClassFromCMD(cmd, profileRef)
+		if err := rateLimitToProfile(cmd, profileRef); err != nil {
+			return nil, err
+		}
+		transfer, err := transferOptionsFromCMD(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return datamover.NewProfileDataMover(profileRef, outputName, kopiaSnapshot, transfer), nil
 	case DataMoverTypeRepositoryServer:
 		repositoryServerRef, err := unmarshalRepositoryServerFlag(cmd)
 		if err != nil {
 			return nil, err
 		}
-		return datamover.NewRepositoryServerDataMover(repositoryServerRef, outputName, kopiaSnapshot, cmd.Flag(repositoryServerUserHostnameFlagName).Value.String()), nil
+		rateLimit, err := rateLimitFromCMD(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return datamover.NewRepositoryServerDataMover(repositoryServerRef, outputName, kopiaSnapshot, cmd.Flag(repositoryServerUserHostnameFlagName).Value.String(), rateLimit), nil
 	default:
 		return nil, errkit.New("Could not initialize DataMover.")
 	}
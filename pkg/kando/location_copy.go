@@ -0,0 +1,59 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kando
+
+import (
+	"encoding/json"
+
+	"github.com/kanisterio/errkit"
+	"github.com/spf13/cobra"
+
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+const (
+	destinationPathFlagName    = "destination-path"
+	destinationProfileFlagName = "destination-profile"
+)
+
+func newLocationCopyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy",
+		Short: "Copy an artifact in object storage, within the object store if possible",
+		RunE: func(c *cobra.Command, args []string) error {
+			src, err := profileFromCMD(c)
+			if err != nil {
+				return err
+			}
+			dst := src
+			if dstJSON := c.Flag(destinationProfileFlagName).Value.String(); dstJSON != "" {
+				dst = &param.Profile{}
+				if err := json.Unmarshal([]byte(dstJSON), dst); err != nil {
+					return errkit.Wrap(err, "failed to unmarshal destination profile")
+				}
+			}
+			transfer, err := transferOptionsFromCMD(c)
+			if err != nil {
+				return err
+			}
+			return location.Copy(c.Context(), *src, pathFlag(c), *dst, c.Flag(destinationPathFlagName).Value.String(), transfer)
+		},
+	}
+	cmd.Flags().String(destinationPathFlagName, "", "Specify the path suffix of the copy (required)")
+	cmd.Flags().String(destinationProfileFlagName, "", "Pass the Profile of the copy as a JSON string. Set to --profile by default (optional)")
+	_ = cmd.MarkFlagRequired(destinationPathFlagName)
+	return cmd
+}
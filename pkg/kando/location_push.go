@@ -20,6 +20,8 @@ import (
 
 const (
 	outputNameFlagName    = "output-name"
+	compressionFlagName   = "compression"
+	storageClassFlagName  = "storage-class"
 	defaultKandoOutputKey = "kandoOutput"
 )
 
@@ -41,6 +43,8 @@ func newLocationPushCommand() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringP(outputNameFlagName, "o", defaultKandoOutputKey, "Specify a name to be used for the output produced by kando. Set to `kandoOutput` by default")
+	cmd.Flags().String(compressionFlagName, "", "Compress the data with none, gzip or zstd. Overrides the compression of the Profile (optional, applicable if --profile is passed)")
+	cmd.Flags().String(storageClassFlagName, "", "Store the data in a storage class of the object store, e.g. GLACIER_IR, NEARLINE or Cool. Overrides the storage class of the Profile (optional, applicable if --profile is passed)")
 
 	return cmd
 }
@@ -0,0 +1,118 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kando
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/kanisterio/errkit"
+	"github.com/spf13/cobra"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/output"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// replicatedArtifact is the output of the location replicate command.
+type replicatedArtifact struct {
+	Path     string `json:"path"`
+	Checksum string `json:"checksum"`
+}
+
+func newLocationReplicateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replicate",
+		Short: "Replicate an artifact or kopia snapshot to the location of another Profile and verify its checksum",
+		RunE:  runLocationReplicate,
+	}
+	cmd.Flags().String(destinationPathFlagName, "", "Specify the path suffix of the replica. Set to --path by default (optional)")
+	cmd.Flags().String(destinationProfileFlagName, "", "Pass the Profile of the replica as a JSON string (required)")
+	cmd.Flags().StringP(kopiaSnapshotFlagName, "k", "", "Pass the kopia snapshot information from the location push command to replicate the snapshot (optional)")
+	cmd.Flags().StringP(outputNameFlagName, "o", defaultKandoOutputKey, "Specify a name to be used for the output produced by kando. Set to `kandoOutput` by default")
+	_ = cmd.MarkFlagRequired(destinationProfileFlagName)
+	return cmd
+}
+
+func runLocationReplicate(c *cobra.Command, args []string) error {
+	if err := validateCommandArgs(c); err != nil {
+		return err
+	}
+	dst := &param.Profile{}
+	if err := json.Unmarshal([]byte(c.Flag(destinationProfileFlagName).Value.String()), dst); err != nil {
+		return errkit.Wrap(err, "failed to unmarshal destination profile")
+	}
+	if dst.Location.Type == crv1alpha1.LocationTypeKopia {
+		return errkit.New("Replicating to kopia locations is not supported")
+	}
+	transfer, err := transferOptionsFromCMD(c)
+	if err != nil {
+		return err
+	}
+	path := pathFlag(c)
+	dstPath := c.Flag(destinationPathFlagName).Value.String()
+	if dstPath == "" {
+		dstPath = path
+	}
+
+	var sum string
+	if src, ok, err := objectStoreProfileFromCMD(c); err != nil {
+		return err
+	} else if ok {
+		if c.Flag(kopiaSnapshotFlagName).Value.String() != "" {
+			return errkit.New("--kopia-snapshot requires a kopia profile or --repository-server")
+		}
+		// Artifacts are copied as they are stored, by the object store if
+		// possible
+		sum, err = location.Replicate(c.Context(), *src, path, *dst, dstPath, transfer)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Kopia snapshots are streamed from the repository
+		dataMover, err := dataMoverForKopiaSnapshotFlag(c)
+		if err != nil {
+			return err
+		}
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(dataMover.PullTo(c.Context(), pw, path))
+		}()
+		sum, err = location.ReplicateFrom(c.Context(), pr, *dst, dstPath, transfer)
+		pr.CloseWithError(err)
+		if err != nil {
+			return err
+		}
+	}
+	out, err := json.Marshal(replicatedArtifact{Path: dstPath, Checksum: sum})
+	if err != nil {
+		return err
+	}
+	return output.PrintOutputTo(c.OutOrStdout(), c.Flag(outputNameFlagName).Value.String(), string(out))
+}
+
+// objectStoreProfileFromCMD returns the Profile of the --profile flag if it
+// stores artifacts in an object store rather than in a kopia repository.
+func objectStoreProfileFromCMD(c *cobra.Command) (*param.Profile, bool, error) {
+	if dataMoverTypeFromCMD(c) != DataMoverTypeProfile {
+		return nil, false, nil
+	}
+	p, err := unmarshalProfileFlag(c)
+	if err != nil {
+		return nil, false, err
+	}
+	return p, p.Location.Type != crv1alpha1.LocationTypeKopia, nil
+}
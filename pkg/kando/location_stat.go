@@ -0,0 +1,56 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kando
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/output"
+)
+
+// locationStat is the output of the location stat command.
+type locationStat struct {
+	Size    int64             `json:"size"`
+	ModTime time.Time         `json:"modTime"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+func newLocationStatCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stat",
+		Short: "Output the size, modification time and tags of an artifact in object storage without downloading it",
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := profileFromCMD(c)
+			if err != nil {
+				return err
+			}
+			info, err := location.Stat(c.Context(), *profile, pathFlag(c))
+			if err != nil {
+				return err
+			}
+			stat, err := json.Marshal(locationStat{Size: info.Size, ModTime: info.ModTime, Tags: info.Tags})
+			if err != nil {
+				return err
+			}
+			return output.PrintOutputTo(c.OutOrStdout(), c.Flag(outputNameFlagName).Value.String(), string(stat))
+		},
+	}
+	cmd.Flags().StringP(outputNameFlagName, "o", defaultKandoOutputKey, "Specify a name to be used for the output produced by kando. Set to `kandoOutput` by default")
+	return cmd
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kando
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/output"
+)
+
+// verifiedArtifact is the output of the location verify command.
+type verifiedArtifact struct {
+	Checksum string `json:"checksum"`
+}
+
+func newLocationVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the checksums of an artifact in object storage without writing it anywhere",
+		RunE: func(c *cobra.Command, args []string) error {
+			profile, err := profileFromCMD(c)
+			if err != nil {
+				return err
+			}
+			sum, err := location.Verify(c.Context(), *profile, pathFlag(c))
+			if err != nil {
+				return err
+			}
+			out, err := json.Marshal(verifiedArtifact{Checksum: sum})
+			if err != nil {
+				return err
+			}
+			return output.PrintOutputTo(c.OutOrStdout(), c.Flag(outputNameFlagName).Value.String(), string(out))
+		},
+	}
+	cmd.Flags().StringP(outputNameFlagName, "o", defaultKandoOutputKey, "Specify a name to be used for the output produced by kando. Set to `kandoOutput` by default")
+	return cmd
+}
@@ -93,6 +93,15 @@ func RepositoryCreateCommand(cmdArgs RepositoryCommandArgs) ([]string, error) {
 		command = command.AppendLoggableKV(overrideUsernameFlag, cmdArgs.Username)
 	}
 
+	// Locations with object lock enabled retain the blobs of the repository
+	if cmdArgs.RetentionMode == "" {
+		mode, period, err := storage.RetentionFromMap(cmdArgs.Location)
+		if err != nil {
+			return nil, err
+		}
+		cmdArgs.RetentionMode, cmdArgs.RetentionPeriod = mode, period
+	}
+
 	// During creation, both should be set. Technically RetentionPeriod should be >= 24 * time.Hour
 	if cmdArgs.RetentionMode != "" && cmdArgs.RetentionPeriod > 0 {
 		command = command.AppendLoggableKV(retentionModeFlag, cmdArgs.RetentionMode)
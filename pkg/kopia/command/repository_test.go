@@ -140,6 +140,41 @@ func (s *RepositoryUtilsSuite) TestRepositoryCreateUtil(c *check.C) {
 				"--path=/mnt/data/test-prefix/test-path/prefix/",
 			},
 		},
+		{
+			cmdArg: RepositoryCommandArgs{
+				CommandArgs: &CommandArgs{
+					RepoPassword:   "pass123",
+					ConfigFilePath: "/tmp/config.file",
+					LogDirectory:   "/tmp/log.dir",
+				},
+				CacheDirectory: "/tmp/cache.dir",
+				RepoPathPrefix: "test-path/prefix",
+				Location: map[string][]byte{
+					"type":            []byte("s3"),
+					"bucket":          []byte("test-bucket"),
+					"objectLockMode":  []byte("compliance"),
+					"retentionPeriod": []byte("720h"),
+				},
+			},
+			Checker: check.IsNil,
+			expectedCmd: []string{"kopia",
+				"--log-level=error",
+				"--config-file=/tmp/config.file",
+				"--log-dir=/tmp/log.dir",
+				"--password=pass123",
+				"repository",
+				"create",
+				"--no-check-for-updates",
+				"--cache-directory=/tmp/cache.dir",
+				"--content-cache-size-limit-mb=0",
+				"--metadata-cache-size-limit-mb=0",
+				"--retention-mode=COMPLIANCE",
+				"--retention-period=720h0m0s",
+				"s3",
+				"--bucket=test-bucket",
+				"--prefix=test-path/prefix",
+			},
+		},
 		{
 			cmdArg: RepositoryCommandArgs{
 				CommandArgs: &CommandArgs{
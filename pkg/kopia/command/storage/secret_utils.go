@@ -16,6 +16,8 @@ package storage
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kanisterio/errkit"
@@ -56,10 +58,61 @@ func checkSkipSSLVerifyFromMap(m map[string][]byte) bool {
 	return v == "true"
 }
 
+func getRateLimitFromMap(m map[string][]byte) string {
+	return string(m[repositoryserver.RateLimitKey])
+}
+
 func locationType(m map[string][]byte) repositoryserver.LocType {
 	return repositoryserver.LocType(m[repositoryserver.TypeKey])
 }
 
+// serverSideEncryptionFromMap returns the server-side encryption type or
+// encryption scope of a location, if it has one that requires headers.
+// Objects are encrypted with sse-s3 by default.
+func serverSideEncryptionFromMap(m map[string][]byte) (string, bool) {
+	if v := string(m[repositoryserver.EncryptionScopeKey]); v != "" {
+		return v, true
+	}
+	v := string(m[repositoryserver.ServerSideEncryptionKey])
+	if v == "" || (v == string(crv1alpha1.ServerSideEncryptionTypeS3) && len(m[repositoryserver.KMSKeyIDKey]) == 0) {
+		return "", false
+	}
+	return v, true
+}
+
+// RetentionFromMap returns the kopia blob retention mode and period of a
+// location with object lock enabled. The mode is empty if it is not set.
+func RetentionFromMap(m map[string][]byte) (string, time.Duration, error) {
+	mode := strings.ToUpper(string(m[repositoryserver.ObjectLockModeKey]))
+	if mode == "" {
+		return "", 0, nil
+	}
+	period, err := time.ParseDuration(string(m[repositoryserver.RetentionPeriodKey]))
+	if err != nil {
+		return "", 0, errkit.Wrap(err, "Failed to parse retention period of location")
+	}
+	return mode, period, nil
+}
+
+// SetObjectLockLocationValues adds the object lock settings of a Profile to
+// a location map.
+func SetObjectLockLocationValues(m map[string][]byte, lock *crv1alpha1.ObjectLock) {
+	if lock == nil {
+		return
+	}
+	m[repositoryserver.ObjectLockModeKey] = []byte(lock.Mode)
+	m[repositoryserver.RetentionPeriodKey] = []byte(lock.Retention.Duration.String())
+}
+
+// SetRateLimitLocationValues adds the rate limit of a Profile to a location
+// map. Kopia does not support bursts, so only the rate is added.
+func SetRateLimitLocationValues(m map[string][]byte, l *crv1alpha1.RateLimit) {
+	if l == nil || l.BytesPerSecond <= 0 {
+		return
+	}
+	m[repositoryserver.RateLimitKey] = []byte(strconv.FormatInt(l.BytesPerSecond, 10))
+}
+
 // GenerateEnvSpecFromCredentialSecret parses the secret and returns
 // list of EnvVar based on secret type
 func GenerateEnvSpecFromCredentialSecret(s *corev1.Secret, assumeRoleDurationS3 time.Duration) ([]corev1.EnvVar, error) {
@@ -141,6 +194,34 @@ func getEnvVar(varName, value string) corev1.EnvVar {
 	}
 }
 
+// LocationFromProfile returns the location map of a kopia repository in the
+// Location of a Profile, e.g. to create the location Secret of a
+// RepositoryServer from the Profile.
+func LocationFromProfile(p *crv1alpha1.Profile) (map[string][]byte, error) {
+	locType := repositoryserver.LocType(p.Location.Type)
+	switch p.Location.Type {
+	case crv1alpha1.LocationTypeS3Compliant, crv1alpha1.LocationTypeGCS, crv1alpha1.LocationTypeAzure:
+	case crv1alpha1.LocationTypeFilesystem:
+		locType = repositoryserver.LocTypeFilestore
+	default:
+		return nil, errkit.New("Unsupported location type", "type", p.Location.Type)
+	}
+	m := GetMapForLocationValues(
+		locType,
+		p.Location.Prefix,
+		p.Location.Region,
+		p.Location.Bucket,
+		p.Location.Endpoint,
+		strconv.FormatBool(p.SkipSSLVerify),
+	)
+	if p.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		m[repositoryserver.ClaimNameKey] = []byte(p.Location.ClaimName)
+	}
+	SetObjectLockLocationValues(m, p.ObjectLock)
+	SetRateLimitLocationValues(m, p.RateLimit)
+	return m, nil
+}
+
 // GetMapForLocationValues return a map with valid keys
 // for different location values
 func GetMapForLocationValues(
@@ -298,3 +298,61 @@ func (s *StorageUtilsSuite) TestGetMapForLocationValues(c *check.C) {
 		c.Assert(op, check.DeepEquals, tc.expectedOutput)
 	}
 }
+
+func (s *StorageUtilsSuite) TestLocationFromProfile(c *check.C) {
+	m, err := LocationFromProfile(&crv1alpha1.Profile{
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Bucket: "test-bucket",
+			Prefix: "test-prefix",
+			Region: "test-region",
+		},
+		ObjectLock: &crv1alpha1.ObjectLock{
+			Mode:      crv1alpha1.ObjectLockModeGovernance,
+			Retention: metav1.Duration{Duration: 24 * time.Hour},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(locationType(m), check.Equals, repositoryserver.LocTypeS3)
+	c.Assert(getBucketNameFromMap(m), check.Equals, "test-bucket")
+	c.Assert(getPrefixFromMap(m), check.Equals, "test-prefix")
+	c.Assert(getRegionFromMap(m), check.Equals, "test-region")
+	c.Assert(checkSkipSSLVerifyFromMap(m), check.Equals, false)
+	mode, period, err := RetentionFromMap(m)
+	c.Assert(err, check.IsNil)
+	c.Assert(mode, check.Equals, "GOVERNANCE")
+	c.Assert(period, check.Equals, 24*time.Hour)
+	c.Assert(getRateLimitFromMap(m), check.Equals, "")
+
+	// Repositories in the location are throttled by the rate limit of the
+	// profile
+	m, err = LocationFromProfile(&crv1alpha1.Profile{
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Bucket: "test-bucket",
+		},
+		RateLimit: &crv1alpha1.RateLimit{BytesPerSecond: 1 << 20, Burst: 4 << 20},
+	})
+	c.Assert(err, check.IsNil)
+	args, err := KopiaStorageArgs(&StorageCommandParams{Location: m})
+	c.Assert(err, check.IsNil)
+	c.Assert(args.String(), check.Matches, ".* --max-upload-speed=1048576 --max-download-speed=1048576")
+
+	m, err = LocationFromProfile(&crv1alpha1.Profile{
+		Location: crv1alpha1.Location{
+			Type:      crv1alpha1.LocationTypeFilesystem,
+			ClaimName: "backups",
+		},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(locationType(m), check.Equals, repositoryserver.LocTypeFilestore)
+	c.Assert(string(m[repositoryserver.ClaimNameKey]), check.Equals, "backups")
+	mode, _, err = RetentionFromMap(m)
+	c.Assert(err, check.IsNil)
+	c.Assert(mode, check.Equals, "")
+
+	_, err = LocationFromProfile(&crv1alpha1.Profile{
+		Location: crv1alpha1.Location{Type: crv1alpha1.LocationTypeSFTP},
+	})
+	c.Assert(err, check.NotNil)
+}
@@ -23,8 +23,10 @@ import (
 )
 
 const (
-	prefixFlag = "--prefix"
-	bucketFlag = "--bucket"
+	prefixFlag           = "--prefix"
+	bucketFlag           = "--bucket"
+	maxDownloadSpeedFlag = "--max-download-speed"
+	maxUploadSpeedFlag   = "--max-upload-speed"
 )
 
 type StorageCommandParams struct {
@@ -36,18 +38,33 @@ type StorageCommandParams struct {
 // KopiaStorageArgs returns kopia command arguments for specific storage
 func KopiaStorageArgs(params *StorageCommandParams) (logsafe.Cmd, error) {
 	LocType := locationType(params.Location)
+	// Kopia does not set server-side encryption headers on the blobs it
+	// writes, so they are encrypted with the default encryption of the
+	// bucket. Fail instead of writing blobs that are not encrypted as the
+	// location requires.
+	if sse, ok := serverSideEncryptionFromMap(params.Location); ok {
+		return nil, errkit.New("Kopia only supports sse-s3 server-side encryption of a location, use the default encryption of the bucket instead", "serverSideEncryption", sse)
+	}
+	var args logsafe.Cmd
 	switch locationType(params.Location) {
 	case repositoryserver.LocTypeFilestore:
-		return filesystemArgs(params.Location, params.RepoPathPrefix), nil
+		args = filesystemArgs(params.Location, params.RepoPathPrefix)
 	case repositoryserver.LocTypeS3:
-		return s3Args(params.Location, params.RepoPathPrefix), nil
+		args = s3Args(params.Location, params.RepoPathPrefix)
 	case repositoryserver.LocTypes3Compliant:
-		return s3Args(params.Location, params.RepoPathPrefix), nil
+		args = s3Args(params.Location, params.RepoPathPrefix)
 	case repositoryserver.LocTypeGCS:
-		return gcsArgs(params.Location, params.RepoPathPrefix), nil
+		args = gcsArgs(params.Location, params.RepoPathPrefix)
 	case repositoryserver.LocTypeAzure:
-		return azureArgs(params.Location, params.RepoPathPrefix), nil
+		args = azureArgs(params.Location, params.RepoPathPrefix)
 	default:
 		return nil, errkit.New("unsupported type for the location", "locationType", LocType)
 	}
+	// Kopia stores the limits in its config file, so that snapshots created
+	// and restored through the connection are throttled
+	if rl := getRateLimitFromMap(params.Location); rl != "" {
+		args = args.AppendLoggableKV(maxUploadSpeedFlag, rl)
+		args = args.AppendLoggableKV(maxDownloadSpeedFlag, rl)
+	}
+	return args, nil
 }
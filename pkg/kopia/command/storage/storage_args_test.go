@@ -115,6 +115,25 @@ func (s *StorageUtilsSuite) TestStorageArgsUtil(c *check.C) {
 				fmt.Sprintf(" %s=test-prefix/dir/subdir/", prefixFlag),
 			),
 		},
+		{
+			params: &StorageCommandParams{
+				Location: map[string][]byte{
+					repositoryserver.BucketKey:    []byte("test-bucket"),
+					repositoryserver.PrefixKey:    []byte("test-prefix"),
+					repositoryserver.TypeKey:      []byte("gcs"),
+					repositoryserver.RateLimitKey: []byte("1048576"),
+				},
+			},
+			Checker: check.IsNil,
+			expectedCmd: fmt.Sprint(
+				gcsSubCommand,
+				fmt.Sprintf(" %s=test-bucket", bucketFlag),
+				fmt.Sprintf(" %s=/tmp/creds.txt", credentialsFileFlag),
+				fmt.Sprintf(" %s=test-prefix/", prefixFlag),
+				fmt.Sprintf(" %s=1048576", maxUploadSpeedFlag),
+				fmt.Sprintf(" %s=1048576", maxDownloadSpeedFlag),
+			),
+		},
 		{
 			params: &StorageCommandParams{
 				Location: map[string][]byte{
@@ -123,6 +142,42 @@ func (s *StorageUtilsSuite) TestStorageArgsUtil(c *check.C) {
 			},
 			Checker: check.NotNil,
 		},
+		{
+			params: &StorageCommandParams{
+				Location: map[string][]byte{
+					repositoryserver.BucketKey:               []byte("test-bucket"),
+					repositoryserver.ServerSideEncryptionKey: []byte("sse-kms"),
+					repositoryserver.KMSKeyIDKey:             []byte("alias/backups"),
+					repositoryserver.TypeKey:                 []byte("s3"),
+				},
+			},
+			Checker: check.NotNil,
+		},
+		{
+			params: &StorageCommandParams{
+				Location: map[string][]byte{
+					repositoryserver.BucketKey:               []byte("test-bucket"),
+					repositoryserver.ServerSideEncryptionKey: []byte("sse-s3"),
+					repositoryserver.TypeKey:                 []byte("s3"),
+				},
+			},
+			Checker: check.IsNil,
+			expectedCmd: fmt.Sprint(
+				s3SubCommand,
+				fmt.Sprintf(" %s=test-bucket", bucketFlag),
+				fmt.Sprintf(" %s=", prefixFlag),
+			),
+		},
+		{
+			params: &StorageCommandParams{
+				Location: map[string][]byte{
+					repositoryserver.BucketKey:          []byte("test-bucket"),
+					repositoryserver.EncryptionScopeKey: []byte("backups"),
+					repositoryserver.TypeKey:            []byte("azure"),
+				},
+			},
+			Checker: check.NotNil,
+		},
 	} {
 		cmd, err := KopiaStorageArgs(tc.params)
 		c.Assert(err, tc.Checker)
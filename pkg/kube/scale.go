@@ -0,0 +1,201 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kanisterio/errkit"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+const scaleSubresource = "scale"
+
+// Scale is the state of the `scale` subresource of an API object.
+type Scale struct {
+	SpecReplicas   int32
+	StatusReplicas int32
+	// Selector is the label selector of the pods managed by the object, in
+	// its string form.
+	Selector string
+}
+
+// FetchScale returns the `scale` subresource of the referenced object.
+func FetchScale(ctx context.Context, dynCli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (*Scale, error) {
+	u, err := dynCli.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}, scaleSubresource)
+	if err != nil {
+		return nil, err
+	}
+	specReplicas, _, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to read spec.replicas of scale subresource", "resource", gvr.String(), "namespace", namespace, "name", name)
+	}
+	statusReplicas, _, err := unstructured.NestedInt64(u.Object, "status", "replicas")
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to read status.replicas of scale subresource", "resource", gvr.String(), "namespace", namespace, "name", name)
+	}
+	selector, _, err := unstructured.NestedString(u.Object, "status", "selector")
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to read status.selector of scale subresource", "resource", gvr.String(), "namespace", namespace, "name", name)
+	}
+	return &Scale{
+		SpecReplicas:   int32(specReplicas),
+		StatusReplicas: int32(statusReplicas),
+		Selector:       selector,
+	}, nil
+}
+
+// WorkloadSelector returns the label selector of the pods managed by an
+// arbitrary workload object. The selector is read from `spec.selector` if it
+// is a label selector, otherwise from the `scale` subresource of the object.
+// A nil selector is returned if the object does not expose either.
+func WorkloadSelector(ctx context.Context, dynCli dynamic.Interface, gvr schema.GroupVersionResource, u *unstructured.Unstructured) (labels.Selector, error) {
+	if raw, ok, _ := unstructured.NestedMap(u.Object, "spec", "selector"); ok {
+		ls := &metav1.LabelSelector{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, ls); err == nil && (len(ls.MatchLabels) > 0 || len(ls.MatchExpressions) > 0) {
+			return metav1.LabelSelectorAsSelector(ls)
+		}
+	}
+	scale, err := FetchScale(ctx, dynCli, gvr, u.GetNamespace(), u.GetName())
+	switch {
+	case apierrors.IsNotFound(err), apierrors.IsMethodNotSupported(err):
+		// The resource doesn't have a scale subresource
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	if scale.Selector == "" {
+		return nil, nil
+	}
+	return labels.Parse(scale.Selector)
+}
+
+// FetchPodsBySelector fetches the pods matching the label selector and splits
+// them into 2 groups (running/not-running)
+func FetchPodsBySelector(ctx context.Context, cli kubernetes.Interface, namespace string, selector labels.Selector) (runningPods []corev1.Pod, notRunningPods []corev1.Pod, err error) {
+	pods, err := cli.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, nil, errkit.Wrap(err, "Could not list Pods", "selector", selector.String())
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			notRunningPods = append(notRunningPods, pod)
+			continue
+		}
+		runningPods = append(runningPods, pod)
+	}
+	return runningPods, notRunningPods, nil
+}
+
+// PodVolumes returns the PVCs referenced by the pod as a [pod spec volume name]->[PVC name] map
+func PodVolumes(pod *corev1.Pod) (volNameToPvc map[string]string) {
+	return podSpecVolumes(pod.Spec)
+}
+
+// ScaleResourceReplicas returns the desired replica count of an object with a
+// `scale` subresource.
+func ScaleResourceReplicas(ctx context.Context, dynCli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (int32, error) {
+	scale, err := FetchScale(ctx, dynCli, gvr, namespace, name)
+	if err != nil {
+		return 0, errkit.Wrap(err, "Could not get scale subresource, to figure out replicas", "Resource", gvr.String(), "Namespace", namespace, "Name", name)
+	}
+	return scale.SpecReplicas, nil
+}
+
+// ScaleResource sets the replica count of an object through its `scale`
+// subresource. This works for any workload, including custom resources, that
+// implements the scale subresource.
+func ScaleResource(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, replicas int32, waitForReady bool) error {
+	ri := dynCli.Resource(gvr).Namespace(namespace)
+	u, err := ri.Get(ctx, name, metav1.GetOptions{}, scaleSubresource)
+	if err != nil {
+		return errkit.Wrap(err, "Could not get scale subresource", "resource", gvr.String(), "namespace", namespace, "name", name)
+	}
+	if err := unstructured.SetNestedField(u.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return errkit.Wrap(err, "Could not set replicas of scale subresource", "resource", gvr.String(), "namespace", namespace, "name", name)
+	}
+	if _, err := ri.Update(ctx, u, metav1.UpdateOptions{}, scaleSubresource); err != nil {
+		return errkit.Wrap(err, "Could not update scale subresource", "resource", gvr.String(), "namespace", namespace, "name", name)
+	}
+	if !waitForReady {
+		return nil
+	}
+	return WaitOnScaleResourceReady(ctx, cli, dynCli, gvr, namespace, name)
+}
+
+// ScaleResourceReady checks if an object with a `scale` subresource has the
+// desired number of replicas, and that the pods matching its selector are
+// running.
+func ScaleResourceReady(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (bool, string, error) {
+	scale, err := FetchScale(ctx, dynCli, gvr, namespace, name)
+	if err != nil {
+		return false, "", errkit.Wrap(err, "could not get scale subresource", "resource", gvr.String(), "namespace", namespace, "name", name)
+	}
+	if scale.StatusReplicas != scale.SpecReplicas {
+		status := fmt.Sprintf(
+			"Specified %d replicas and only have %d", scale.SpecReplicas, scale.StatusReplicas,
+		)
+		return false, status, nil
+	}
+	if scale.Selector == "" {
+		return true, "", nil
+	}
+	selector, err := labels.Parse(scale.Selector)
+	if err != nil {
+		return false, "", errkit.Wrap(err, "Failed to parse selector of scale subresource", "selector", scale.Selector)
+	}
+	runningPods, notRunningPods, err := FetchPodsBySelector(ctx, cli, namespace, selector)
+	if err != nil {
+		return false, "", err
+	}
+	if len(runningPods) != int(scale.SpecReplicas) || len(notRunningPods) != 0 {
+		status := fmt.Sprintf(
+			"Specified %d replicas and %d out of %d pods are running", scale.SpecReplicas, len(runningPods), len(runningPods)+len(notRunningPods),
+		)
+		return false, status, nil
+	}
+	return true, "", nil
+}
+
+// WaitOnScaleResourceReady waits for an object with a `scale` subresource to
+// be ready
+func WaitOnScaleResourceReady(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) error {
+	var status string
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		ok, s, err := ScaleResourceReady(ctx, cli, dynCli, gvr, namespace, name)
+		if s != "" {
+			status = s
+		}
+		if apierrors.IsNotFound(errkit.Unwrap(err)) {
+			return false, nil
+		}
+		return ok, err
+	})
+	if err != nil && status != "" {
+		return errkit.Wrap(err, status)
+	}
+	return err
+}
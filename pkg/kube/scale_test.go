@@ -0,0 +1,124 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+
+	"gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type ScaleSuite struct{}
+
+var _ = check.Suite(&ScaleSuite{})
+
+var rolloutGVR = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+
+func newRollout(spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"name":      "rollout",
+			"namespace": "default",
+		},
+		"spec": spec,
+	}}
+}
+
+// newScaleDynamicClient returns a fake dynamic client that serves the scale
+// subresource of rollouts from the given scale object, and records updates to it.
+func newScaleDynamicClient(scale map[string]interface{}, objects ...runtime.Object) *dynfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	dynCli := dynfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{rolloutGVR: "RolloutList"}, objects...)
+	dynCli.PrependReactor("get", "rollouts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		return true, &unstructured.Unstructured{Object: runtime.DeepCopyJSON(scale)}, nil
+	})
+	dynCli.PrependReactor("update", "rollouts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		u := action.(k8stesting.UpdateAction).GetObject().(*unstructured.Unstructured)
+		scale["spec"] = u.Object["spec"]
+		return true, u, nil
+	})
+	return dynCli
+}
+
+func (s *ScaleSuite) TestWorkloadSelectorFromSpec(c *check.C) {
+	u := newRollout(map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{"app": "web"},
+		},
+	})
+	sel, err := WorkloadSelector(context.Background(), newScaleDynamicClient(nil, u), rolloutGVR, u)
+	c.Assert(err, check.IsNil)
+	c.Assert(sel.String(), check.Equals, "app=web")
+}
+
+func (s *ScaleSuite) TestWorkloadSelectorFromScale(c *check.C) {
+	u := newRollout(map[string]interface{}{})
+	dynCli := newScaleDynamicClient(map[string]interface{}{
+		"spec":   map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{"replicas": int64(2), "selector": "app=db"},
+	}, u)
+	sel, err := WorkloadSelector(context.Background(), dynCli, rolloutGVR, u)
+	c.Assert(err, check.IsNil)
+	c.Assert(sel.String(), check.Equals, "app=db")
+}
+
+func (s *ScaleSuite) TestScaleResource(c *check.C) {
+	ctx := context.Background()
+	scale := map[string]interface{}{
+		"spec":   map[string]interface{}{"replicas": int64(1)},
+		"status": map[string]interface{}{"replicas": int64(1), "selector": "app=db"},
+	}
+	dynCli := newScaleDynamicClient(scale, newRollout(map[string]interface{}{}))
+	cli := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default", Labels: map[string]string{"app": "db"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	})
+
+	replicas, err := ScaleResourceReplicas(ctx, dynCli, rolloutGVR, "default", "rollout")
+	c.Assert(err, check.IsNil)
+	c.Assert(replicas, check.Equals, int32(1))
+
+	ready, status, err := ScaleResourceReady(ctx, cli, dynCli, rolloutGVR, "default", "rollout")
+	c.Assert(err, check.IsNil)
+	c.Assert(status, check.Equals, "")
+	c.Assert(ready, check.Equals, true)
+
+	err = ScaleResource(ctx, cli, dynCli, rolloutGVR, "default", "rollout", 3, false)
+	c.Assert(err, check.IsNil)
+	replicas, err = ScaleResourceReplicas(ctx, dynCli, rolloutGVR, "default", "rollout")
+	c.Assert(err, check.IsNil)
+	c.Assert(replicas, check.Equals, int32(3))
+
+	ready, status, err = ScaleResourceReady(ctx, cli, dynCli, rolloutGVR, "default", "rollout")
+	c.Assert(err, check.IsNil)
+	c.Assert(status, check.Equals, "Specified 3 replicas and only have 1")
+	c.Assert(ready, check.Equals, false)
+}
@@ -24,6 +24,7 @@ import (
 	osAppsv1 "github.com/openshift/api/apps/v1"
 	osversioned "github.com/openshift/client-go/apps/clientset/versioned"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/poll"
 )
 
@@ -527,3 +529,408 @@ func DeploymentConfigReplicas(ctx context.Context, osCli osversioned.Interface,
 	}
 	return dc.Spec.Replicas, nil
 }
+
+// DaemonSetScaledDownLabel is the node selector key that is added to a
+// daemonset's pod template to scale it down. No node is expected to have this
+// label, so the daemonset controller removes all of its pods.
+const DaemonSetScaledDownLabel = consts.LabelPrefix + "daemonset-scaled-down"
+
+// DaemonSetReady checks if a daemonset has the desired number of pods
+// scheduled, updated and ready.
+func DaemonSetReady(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) (bool, string, error) {
+	ds, err := kubeCli.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, "", errkit.Wrap(err, "could not get DaemonSet", "namespace", namespace, "name", name)
+	}
+	var status string
+	switch {
+	case ds.Status.ObservedGeneration < ds.Generation:
+		status = fmt.Sprintf(
+			"Need generation of at least %d and observed %d", ds.Generation, ds.Status.ObservedGeneration,
+		)
+	case ds.Status.UpdatedNumberScheduled != ds.Status.DesiredNumberScheduled:
+		status = fmt.Sprintf(
+			"Desired %d scheduled pods and only have %d updated pods", ds.Status.DesiredNumberScheduled, ds.Status.UpdatedNumberScheduled,
+		)
+	case ds.Status.NumberReady != ds.Status.DesiredNumberScheduled:
+		status = fmt.Sprintf(
+			"Desired %d scheduled pods and only %d are ready", ds.Status.DesiredNumberScheduled, ds.Status.NumberReady,
+		)
+	}
+	if status != "" {
+		return false, status, nil
+	}
+	runningPods, _, err := FetchPods(kubeCli, namespace, ds.GetUID())
+	if err != nil {
+		return false, "", err
+	}
+	if len(runningPods) != int(ds.Status.DesiredNumberScheduled) {
+		status = fmt.Sprintf(
+			"Desired %d scheduled pods and %d are running", ds.Status.DesiredNumberScheduled, len(runningPods),
+		)
+		return false, status, nil
+	}
+	return true, "", nil
+}
+
+// WaitOnDaemonSetReady waits for the daemonset to be ready
+func WaitOnDaemonSetReady(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) error {
+	var status string
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		ok, s, err := DaemonSetReady(ctx, kubeCli, namespace, name)
+		if s != "" {
+			status = s
+		}
+		if apierrors.IsNotFound(errkit.Unwrap(err)) {
+			return false, nil
+		}
+		return ok, err
+	})
+	if err != nil && status != "" {
+		return errkit.Wrap(err, status)
+	}
+	return err
+}
+
+// DaemonSetPods returns list of running and notrunning pods created by the daemonset.
+func DaemonSetPods(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) ([]corev1.Pod, []corev1.Pod, error) {
+	ds, err := kubeCli.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, errkit.Wrap(err, "could not get DaemonSet", "namespace", namespace, "name", name)
+	}
+	return FetchPods(kubeCli, namespace, ds.GetUID())
+}
+
+// ScaleDaemonSet scales a daemonset down by adding a node selector that no
+// node matches, and scales it back up by removing that node selector. Any
+// non-zero replica count is treated as "scheduled on all eligible nodes".
+func ScaleDaemonSet(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string, replicas int32, waitForReady bool) error {
+	ds, err := kubeCli.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not get DaemonSet", "namespace", namespace, "name", name)
+	}
+	if replicas == 0 {
+		if ds.Spec.Template.Spec.NodeSelector == nil {
+			ds.Spec.Template.Spec.NodeSelector = make(map[string]string)
+		}
+		ds.Spec.Template.Spec.NodeSelector[DaemonSetScaledDownLabel] = "true"
+	} else {
+		delete(ds.Spec.Template.Spec.NodeSelector, DaemonSetScaledDownLabel)
+	}
+	_, err = kubeCli.AppsV1().DaemonSets(namespace).Update(ctx, ds, metav1.UpdateOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not update DaemonSet", "namespace", namespace, "name", name)
+	}
+	if !waitForReady {
+		return nil
+	}
+	return WaitOnDaemonSetReady(ctx, kubeCli, namespace, name)
+}
+
+// DaemonSetReplicas returns the number of nodes the daemonset is scheduled on,
+// or zero if the daemonset was scaled down using ScaleDaemonSet.
+func DaemonSetReplicas(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) (int32, error) {
+	ds, err := kubeCli.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, errkit.Wrap(err, "Could not get DaemonSet, to figure out replicas", "Namespace", namespace, "DaemonSet", name)
+	}
+	if _, ok := ds.Spec.Template.Spec.NodeSelector[DaemonSetScaledDownLabel]; ok {
+		return 0, nil
+	}
+	return ds.Status.DesiredNumberScheduled, nil
+}
+
+// DaemonSetVolumes returns the PVCs referenced by this daemonset as a [pods spec volume name]->[PVC name] map
+func DaemonSetVolumes(cli kubernetes.Interface, ds *appsv1.DaemonSet) (volNameToPvc map[string]string) {
+	return podSpecVolumes(ds.Spec.Template.Spec)
+}
+
+// ReplicaSetReady checks if a replicaset has the desired number of ready
+// replicas.
+func ReplicaSetReady(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) (bool, string, error) {
+	rs, err := kubeCli.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, "", errkit.Wrap(err, "could not get ReplicaSet", "namespace", namespace, "name", name)
+	}
+	replicas := replicaSetSpecReplicas(rs)
+	var status string
+	switch {
+	case rs.Status.ObservedGeneration < rs.Generation:
+		status = fmt.Sprintf(
+			"Need generation of at least %d and observed %d", rs.Generation, rs.Status.ObservedGeneration,
+		)
+	case rs.Status.ReadyReplicas != replicas:
+		status = fmt.Sprintf(
+			"Specified %d replicas and only %d are ready", replicas, rs.Status.ReadyReplicas,
+		)
+	}
+	if status != "" {
+		return false, status, nil
+	}
+	runningPods, _, err := FetchPods(kubeCli, namespace, rs.GetUID())
+	if err != nil {
+		return false, "", err
+	}
+	if len(runningPods) != int(replicas) {
+		status = fmt.Sprintf(
+			"Specified %d replicas and only %d are running", replicas, len(runningPods),
+		)
+		return false, status, nil
+	}
+	return true, "", nil
+}
+
+// WaitOnReplicaSetReady waits for the replicaset to be ready
+func WaitOnReplicaSetReady(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) error {
+	var status string
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		ok, s, err := ReplicaSetReady(ctx, kubeCli, namespace, name)
+		if s != "" {
+			status = s
+		}
+		if apierrors.IsNotFound(errkit.Unwrap(err)) {
+			return false, nil
+		}
+		return ok, err
+	})
+	if err != nil && status != "" {
+		return errkit.Wrap(err, status)
+	}
+	return err
+}
+
+// ReplicaSetPods returns list of running and notrunning pods created by the replicaset.
+func ReplicaSetPods(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) ([]corev1.Pod, []corev1.Pod, error) {
+	rs, err := kubeCli.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, errkit.Wrap(err, "could not get ReplicaSet", "namespace", namespace, "name", name)
+	}
+	return FetchPods(kubeCli, namespace, rs.GetUID())
+}
+
+// ScaleReplicaSet sets the number of replicas of a replicaset and, if
+// waitForReady is set, waits until they are ready.
+func ScaleReplicaSet(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string, replicas int32, waitForReady bool) error {
+	rs, err := kubeCli.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not get ReplicaSet", "namespace", namespace, "name", name)
+	}
+	rs.Spec.Replicas = &replicas
+	_, err = kubeCli.AppsV1().ReplicaSets(namespace).Update(ctx, rs, metav1.UpdateOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not update ReplicaSet", "namespace", namespace, "name", name)
+	}
+	if !waitForReady {
+		return nil
+	}
+	return WaitOnReplicaSetReady(ctx, kubeCli, namespace, name)
+}
+
+// ReplicaSetReplicas returns the number of replicas the replicaset specifies.
+func ReplicaSetReplicas(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) (int32, error) {
+	rs, err := kubeCli.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, errkit.Wrap(err, "Could not get ReplicaSet, to figure out replicas", "Namespace", namespace, "ReplicaSet", name)
+	}
+	return replicaSetSpecReplicas(rs), nil
+}
+
+// replicaSetSpecReplicas returns the replicas of the replicaset, which default
+// to 1 if they are not specified.
+func replicaSetSpecReplicas(rs *appsv1.ReplicaSet) int32 {
+	if rs.Spec.Replicas == nil {
+		return 1
+	}
+	return *rs.Spec.Replicas
+}
+
+// ReplicaSetVolumes returns the PVCs referenced by this replicaset as a [pods spec volume name]->[PVC name] map
+func ReplicaSetVolumes(cli kubernetes.Interface, rs *appsv1.ReplicaSet) (volNameToPvc map[string]string) {
+	return podSpecVolumes(rs.Spec.Template.Spec)
+}
+
+// JobReady checks if a job is running the expected number of active pods,
+// taking its parallelism and remaining completions into account. Finished
+// jobs are always considered ready.
+func JobReady(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) (bool, string, error) {
+	job, err := kubeCli.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, "", errkit.Wrap(err, "could not get Job", "namespace", namespace, "name", name)
+	}
+	if jobFinished(job) {
+		return true, "", nil
+	}
+	want := jobParallelism(job)
+	if job.Spec.Completions != nil {
+		if remaining := *job.Spec.Completions - job.Status.Succeeded; remaining < want {
+			want = remaining
+		}
+	}
+	if job.Status.Active != want {
+		status := fmt.Sprintf(
+			"Expected %d active pods and have %d", want, job.Status.Active,
+		)
+		return false, status, nil
+	}
+	return true, "", nil
+}
+
+// WaitOnJobReady waits for the job to be ready
+func WaitOnJobReady(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) error {
+	var status string
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		ok, s, err := JobReady(ctx, kubeCli, namespace, name)
+		if s != "" {
+			status = s
+		}
+		if apierrors.IsNotFound(errkit.Unwrap(err)) {
+			return false, nil
+		}
+		return ok, err
+	})
+	if err != nil && status != "" {
+		return errkit.Wrap(err, status)
+	}
+	return err
+}
+
+// JobPods returns list of running and notrunning pods created by the job.
+func JobPods(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) ([]corev1.Pod, []corev1.Pod, error) {
+	job, err := kubeCli.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, errkit.Wrap(err, "could not get Job", "namespace", namespace, "name", name)
+	}
+	return FetchPods(kubeCli, namespace, job.GetUID())
+}
+
+// ScaleJob sets the parallelism of a job. Scaling a job to zero stops all of
+// its active pods without failing the job.
+func ScaleJob(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string, replicas int32, waitForReady bool) error {
+	job, err := kubeCli.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not get Job", "namespace", namespace, "name", name)
+	}
+	job.Spec.Parallelism = &replicas
+	_, err = kubeCli.BatchV1().Jobs(namespace).Update(ctx, job, metav1.UpdateOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not update Job", "namespace", namespace, "name", name)
+	}
+	if !waitForReady {
+		return nil
+	}
+	return WaitOnJobReady(ctx, kubeCli, namespace, name)
+}
+
+// JobReplicas returns the parallelism of the job.
+func JobReplicas(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) (int32, error) {
+	job, err := kubeCli.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, errkit.Wrap(err, "Could not get Job, to figure out replicas", "Namespace", namespace, "Job", name)
+	}
+	return jobParallelism(job), nil
+}
+
+// JobVolumes returns the PVCs referenced by this job as a [pods spec volume name]->[PVC name] map
+func JobVolumes(cli kubernetes.Interface, job *batchv1.Job) (volNameToPvc map[string]string) {
+	return podSpecVolumes(job.Spec.Template.Spec)
+}
+
+// FetchJobs fetches the jobs matching the specified owner UID
+func FetchJobs(cli kubernetes.Interface, namespace string, uid types.UID) ([]batchv1.Job, error) {
+	jobs, err := cli.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errkit.Wrap(err, "Could not list Jobs")
+	}
+	var owned []batchv1.Job
+	for _, job := range jobs.Items {
+		if !uidInOwnerRefs(job.OwnerReferences, uid) {
+			continue
+		}
+		owned = append(owned, job)
+	}
+	return owned, nil
+}
+
+// CronJobPods returns list of running and notrunning pods created by the jobs
+// of the cronjob.
+func CronJobPods(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) ([]corev1.Pod, []corev1.Pod, error) {
+	cj, err := kubeCli.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, errkit.Wrap(err, "could not get CronJob", "namespace", namespace, "name", name)
+	}
+	jobs, err := FetchJobs(kubeCli, namespace, cj.GetUID())
+	if err != nil {
+		return nil, nil, err
+	}
+	var runningPods, notRunningPods []corev1.Pod
+	for _, job := range jobs {
+		r, nr, err := FetchPods(kubeCli, namespace, job.GetUID())
+		if err != nil {
+			return nil, nil, err
+		}
+		runningPods = append(runningPods, r...)
+		notRunningPods = append(notRunningPods, nr...)
+	}
+	return runningPods, notRunningPods, nil
+}
+
+// ScaleCronJob suspends the cronjob when scaled to zero and resumes it
+// otherwise. Jobs that were already started are not affected.
+func ScaleCronJob(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string, replicas int32) error {
+	cj, err := kubeCli.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not get CronJob", "namespace", namespace, "name", name)
+	}
+	suspend := replicas == 0
+	cj.Spec.Suspend = &suspend
+	_, err = kubeCli.BatchV1().CronJobs(namespace).Update(ctx, cj, metav1.UpdateOptions{})
+	if err != nil {
+		return errkit.Wrap(err, "Could not update CronJob", "namespace", namespace, "name", name)
+	}
+	return nil
+}
+
+// CronJobReplicas returns zero if the cronjob is suspended and one otherwise.
+func CronJobReplicas(ctx context.Context, kubeCli kubernetes.Interface, namespace, name string) (int32, error) {
+	cj, err := kubeCli.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, errkit.Wrap(err, "Could not get CronJob, to figure out replicas", "Namespace", namespace, "CronJob", name)
+	}
+	if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// CronJobVolumes returns the PVCs referenced by the job template of this cronjob as a [pods spec volume name]->[PVC name] map
+func CronJobVolumes(cli kubernetes.Interface, cj *batchv1.CronJob) (volNameToPvc map[string]string) {
+	return podSpecVolumes(cj.Spec.JobTemplate.Spec.Template.Spec)
+}
+
+func jobParallelism(job *batchv1.Job) int32 {
+	if job.Spec.Parallelism == nil {
+		// Defaulted to 1 by the API server
+		return 1
+	}
+	return *job.Spec.Parallelism
+}
+
+func jobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func podSpecVolumes(spec corev1.PodSpec) map[string]string {
+	volNameToPvc := make(map[string]string)
+	for _, v := range spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		volNameToPvc[v.Name] = v.PersistentVolumeClaim.ClaimName
+	}
+	return volNameToPvc
+}
@@ -6,6 +6,7 @@ import (
 
 	"gopkg.in/check.v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -186,3 +187,82 @@ func getCli(cp cliParams) kubernetes.Interface {
 
 	return fake.NewSimpleClientset(kubeObjects...)
 }
+
+func (s *WorkloadReadySuite) TestReplicaSetReady(c *check.C) {
+	replicas := int32(2)
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "default", UID: "rs-uid", Generation: 1},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+		Status:     appsv1.ReplicaSetStatus{ReadyReplicas: 2, ObservedGeneration: 1},
+	}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: []metav1.OwnerReference{{UID: "rs-uid"}}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	ctx := context.Background()
+
+	ready, status, err := ReplicaSetReady(ctx, fake.NewSimpleClientset(rs, pod("p1", corev1.PodRunning), pod("p2", corev1.PodRunning)), "default", "rs")
+	c.Assert(err, check.IsNil)
+	c.Assert(status, check.Equals, "")
+	c.Assert(ready, check.Equals, true)
+
+	ready, status, err = ReplicaSetReady(ctx, fake.NewSimpleClientset(rs, pod("p1", corev1.PodRunning), pod("p2", corev1.PodPending)), "default", "rs")
+	c.Assert(err, check.IsNil)
+	c.Assert(status, check.Equals, "Specified 2 replicas and only 1 are running")
+	c.Assert(ready, check.Equals, false)
+
+	// Replicas defaults to 1
+	rs.Spec.Replicas = nil
+	n, err := ReplicaSetReplicas(ctx, fake.NewSimpleClientset(rs), "default", "rs")
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, int32(1))
+	ready, status, err = ReplicaSetReady(ctx, fake.NewSimpleClientset(rs, pod("p1", corev1.PodRunning)), "default", "rs")
+	c.Assert(err, check.IsNil)
+	c.Assert(status, check.Equals, "Specified 1 replicas and only 2 are ready")
+	c.Assert(ready, check.Equals, false)
+	rs.Status.ReadyReplicas = 1
+	ready, status, err = ReplicaSetReady(ctx, fake.NewSimpleClientset(rs, pod("p1", corev1.PodRunning)), "default", "rs")
+	c.Assert(err, check.IsNil)
+	c.Assert(status, check.Equals, "")
+	c.Assert(ready, check.Equals, true)
+}
+
+func (s *WorkloadReadySuite) TestJobReady(c *check.C) {
+	parallelism := int32(3)
+	completions := int32(5)
+	for _, tc := range []struct {
+		status batchv1.JobStatus
+		ready  bool
+		want   string
+	}{
+		{
+			status: batchv1.JobStatus{Active: 3},
+			ready:  true,
+		},
+		{
+			status: batchv1.JobStatus{Active: 1},
+			want:   "Expected 3 active pods and have 1",
+		},
+		{
+			// Only 2 completions are remaining
+			status: batchv1.JobStatus{Active: 2, Succeeded: 3},
+			ready:  true,
+		},
+		{
+			status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}},
+			ready:  true,
+		},
+	} {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default"},
+			Spec:       batchv1.JobSpec{Parallelism: &parallelism, Completions: &completions},
+			Status:     tc.status,
+		}
+		ready, status, err := JobReady(context.Background(), fake.NewSimpleClientset(job), "default", "job")
+		c.Assert(err, check.IsNil)
+		c.Assert(ready, check.Equals, tc.ready)
+		c.Assert(status, check.Equals, tc.want)
+	}
+}
@@ -6,9 +6,12 @@ import (
 	osapps "github.com/openshift/api/apps/v1"
 	osversioned "github.com/openshift/client-go/apps/clientset/versioned"
 	"gopkg.in/check.v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 type WorkloadSuite struct{}
@@ -85,3 +88,56 @@ func newDeploymentConfig() *osapps.DeploymentConfig {
 		},
 	}
 }
+
+func (s *WorkloadSuite) TestScaleDaemonSet(c *check.C) {
+	ctx := context.Background()
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "default"},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{NodeSelector: map[string]string{"disk": "ssd"}},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3},
+	}
+	cli := fake.NewSimpleClientset(ds)
+
+	count, err := DaemonSetReplicas(ctx, cli, "default", "ds")
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int32(3))
+
+	err = ScaleDaemonSet(ctx, cli, "default", "ds", 0, false)
+	c.Assert(err, check.IsNil)
+	count, err = DaemonSetReplicas(ctx, cli, "default", "ds")
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int32(0))
+
+	err = ScaleDaemonSet(ctx, cli, "default", "ds", count+3, false)
+	c.Assert(err, check.IsNil)
+	ds, err = cli.AppsV1().DaemonSets("default").Get(ctx, "ds", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ds.Spec.Template.Spec.NodeSelector, check.DeepEquals, map[string]string{"disk": "ssd"})
+}
+
+func (s *WorkloadSuite) TestScaleCronJob(c *check.C) {
+	ctx := context.Background()
+	cli := fake.NewSimpleClientset(&batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "cj", Namespace: "default"},
+	})
+
+	count, err := CronJobReplicas(ctx, cli, "default", "cj")
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int32(1))
+
+	err = ScaleCronJob(ctx, cli, "default", "cj", 0)
+	c.Assert(err, check.IsNil)
+	count, err = CronJobReplicas(ctx, cli, "default", "cj")
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int32(0))
+
+	err = ScaleCronJob(ctx, cli, "default", "cj", 1)
+	c.Assert(err, check.IsNil)
+	count, err = CronJobReplicas(ctx, cli, "default", "cj")
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, int32(1))
+}
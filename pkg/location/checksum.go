@@ -0,0 +1,252 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is only compared with the checksums of object stores
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	"github.com/kanisterio/errkit"
+
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/ratelimit"
+)
+
+// ChecksumTag is the tag of an object that holds the hex encoded SHA-256 of
+// its data as it is stored, i.e. compressed and encrypted. Objects without it
+// were written before checksums were stored and are not verified.
+const ChecksumTag = "kanistersha256"
+
+// checksumObjectTag is the tag of objects whose SHA-256 was not known when
+// they were put, e.g. streams. Their SHA-256 is stored in a separate object
+// once it is, since tags can only be changed by copying the object onto
+// itself.
+const checksumObjectTag = "kanistersha256object"
+
+const (
+	checksumSHA256 = "SHA-256"
+	checksumMD5    = "MD5"
+	checksumCRC32C = "CRC32C"
+)
+
+// ChecksumMismatchError is returned if the data of an artifact does not have
+// the checksum it was written with.
+type ChecksumMismatchError struct {
+	Path      string
+	Bucket    string
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("artifact '%s' in bucket '%s' has %s %s instead of %s", e.Path, e.Bucket, e.Algorithm, e.Actual, e.Expected)
+}
+
+// checksums computes the checksums of the data written to it.
+type checksums struct {
+	sha256 hash.Hash
+	md5    hash.Hash
+	crc32c hash.Hash
+	w      io.Writer
+}
+
+func newChecksums() *checksums {
+	c := &checksums{
+		sha256: sha256.New(),
+		md5:    md5.New(), //nolint:gosec
+		crc32c: crc32.New(crc32.MakeTable(crc32.Castagnoli)),
+	}
+	c.w = io.MultiWriter(c.sha256, c.md5, c.crc32c)
+	return c
+}
+
+func (c *checksums) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+// verify compares the checksums with those in `info`, which either the object
+// store or the writer of the object computed. Checksums that are not known
+// are skipped. It returns the SHA-256 of the data.
+func (c *checksums) verify(info objectstore.ObjectInfo, path, bucket string) (string, error) {
+	sum := hex.EncodeToString(c.sha256.Sum(nil))
+	expected, _ := tagValue(info.Tags, ChecksumTag)
+	for _, cs := range []struct {
+		algorithm string
+		expected  string
+		actual    string
+	}{
+		{checksumSHA256, expected, sum},
+		{checksumMD5, info.MD5, hex.EncodeToString(c.md5.Sum(nil))},
+		{checksumCRC32C, info.CRC32C, hex.EncodeToString(c.crc32c.Sum(nil))},
+	} {
+		if cs.expected != "" && cs.expected != cs.actual {
+			return "", &ChecksumMismatchError{
+				Path:      path,
+				Bucket:    bucket,
+				Algorithm: cs.algorithm,
+				Expected:  cs.expected,
+				Actual:    cs.actual,
+			}
+		}
+	}
+	return sum, nil
+}
+
+// verifyingReader reads the data of an object and fails with a
+// ChecksumMismatchError instead of io.EOF if the data does not have the
+// expected SHA-256.
+type verifyingReader struct {
+	r        io.Reader
+	h        hash.Hash
+	expected string
+	path     string
+	bucket   string
+}
+
+func newVerifyingReader(r io.Reader, expected, path, bucket string) *verifyingReader {
+	return &verifyingReader{r: r, h: sha256.New(), expected: expected, path: path, bucket: bucket}
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n]) //nolint:errcheck
+	if err == io.EOF {
+		if actual := hex.EncodeToString(v.h.Sum(nil)); actual != v.expected {
+			return n, &ChecksumMismatchError{
+				Path:      v.path,
+				Bucket:    v.bucket,
+				Algorithm: checksumSHA256,
+				Expected:  v.expected,
+				Actual:    actual,
+			}
+		}
+	}
+	return n, err
+}
+
+// Verify reads the artifact at the location specified by `profile` and
+// `suffix` without writing it anywhere and verifies that its data has the
+// checksums it was written with and those computed by the object store. It
+// returns the SHA-256 of the data as it is stored. The artifact is verified
+// in the first of the locations of the profile that it is found in.
+func Verify(ctx context.Context, profile param.Profile, suffix string) (string, error) {
+	var errs error
+	for _, p := range locationProfiles(profile) {
+		sum, found, err := verifyLocation(ctx, p, suffix)
+		if err == nil {
+			return sum, nil
+		}
+		errs = appendError(errs, err)
+		if found {
+			break
+		}
+	}
+	return "", errs
+}
+
+// verifyLocation verifies the artifact in the location of `profile` and
+// reports whether it was found there.
+func verifyLocation(ctx context.Context, profile param.Profile, suffix string) (string, bool, error) {
+	osType, err := getProviderType(profile.Location.Type)
+	if err != nil {
+		return "", false, err
+	}
+	bucket, err := getBucket(ctx, osType, profile, objectstore.TransferOptions{})
+	if err != nil {
+		return "", false, err
+	}
+	path := filepath.Join(profile.Location.Prefix, suffix)
+	info, err := bucket.Stat(ctx, path)
+	if err != nil {
+		return "", false, err
+	}
+	if info.Tags, err = withStoredChecksum(ctx, bucket, path, info.Tags); err != nil {
+		return "", true, err
+	}
+	sum, err := verifyData(ctx, bucket, info, path, profile)
+	return sum, true, err
+}
+
+// checksumObject returns the name of the object that holds the SHA-256 of
+// the object `path` if it is tagged with checksumObjectTag.
+func checksumObject(path string) string {
+	return path + "." + ChecksumTag
+}
+
+// putChecksum stores the SHA-256 of the object `path` after it was put.
+func putChecksum(ctx context.Context, bucket objectstore.Bucket, path, sum string) error {
+	err := bucket.Put(ctx, checksumObject(path), strings.NewReader(sum), int64(len(sum)), nil)
+	return errkit.Wrap(err, "Failed to store checksum of artifact", "path", path)
+}
+
+// withStoredChecksum returns the tags of the object `path` with ChecksumTag
+// set to the SHA-256 that is stored in a separate object, if it is.
+func withStoredChecksum(ctx context.Context, bucket objectstore.Bucket, path string, tags map[string]string) (map[string]string, error) {
+	if _, ok := tagValue(tags, ChecksumTag); ok {
+		return tags, nil
+	}
+	if _, ok := tagValue(tags, checksumObjectTag); !ok {
+		return tags, nil
+	}
+	rc, _, err := bucket.Get(ctx, checksumObject(path))
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to get checksum of artifact", "path", path)
+	}
+	defer rc.Close() //nolint:errcheck
+	sum, err := io.ReadAll(io.LimitReader(rc, int64(hex.EncodedLen(sha256.Size))))
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to get checksum of artifact", "path", path)
+	}
+	return mergeTags(maps.Clone(tags), map[string]string{ChecksumTag: string(sum)}), nil
+}
+
+func verifyData(ctx context.Context, bucket objectstore.Bucket, info objectstore.ObjectInfo, path string, profile param.Profile) (string, error) {
+	if _, ok := tagValue(info.Tags, ChecksumTag); !ok && info.MD5 == "" && info.CRC32C == "" {
+		return "", errkit.New("Artifact has no checksums to verify", "path", path)
+	}
+	// Only S3 reports the storage class of objects
+	class := info.StorageClass
+	if class == "" {
+		class = profile.StorageClass
+	}
+	pType, err := getProviderType(profile.Location.Type)
+	if err != nil {
+		return "", err
+	}
+	if err := waitForRestore(ctx, bucket, pType, class, path); err != nil {
+		return "", err
+	}
+	rc, _, err := bucket.Get(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close() //nolint:errcheck
+	sums := newChecksums()
+	if _, err := io.Copy(sums, ratelimit.Reader(ctx, rc, newLimiter(profile.RateLimit))); err != nil {
+		return "", errkit.Wrap(err, "Failed to read artifact", "path", path)
+	}
+	return sums.verify(info, path, profile.Location.Bucket)
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type ChecksumSuite struct{}
+
+var _ = check.Suite(&ChecksumSuite{})
+
+func (s *ChecksumSuite) TearDownTest(c *check.C) {
+	objectstore.DeleteMemoryStore(c.TestName())
+}
+
+func (s *ChecksumSuite) profile(c *check.C) param.Profile {
+	return param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+			Prefix:   "prefix",
+		},
+	}
+}
+
+// corrupt replaces the data of an artifact and keeps its tags.
+func (s *ChecksumSuite) corrupt(c *check.C, profile param.Profile, suffix string) {
+	ctx := context.Background()
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, profile, objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	path := filepath.Join(profile.Location.Prefix, suffix)
+	data, tags, err := bucket.GetBytes(ctx, path)
+	c.Assert(err, check.IsNil)
+	data[len(data)-1]++
+	err = bucket.PutBytes(ctx, path, data, tags)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *ChecksumSuite) TestWriteStoresChecksum(c *check.C) {
+	ctx := context.Background()
+	profile := s.profile(c)
+	data := []byte("test-content-check")
+	expected := sha256.Sum256(data)
+
+	// Streams and files are checksummed the same way
+	err := Write(ctx, bytes.NewBuffer(data), profile, "stream")
+	c.Assert(err, check.IsNil)
+	err = Write(ctx, bytes.NewReader(data), profile, "file")
+	c.Assert(err, check.IsNil)
+	for _, suffix := range []string{"stream", "file"} {
+		info, err := Stat(ctx, profile, suffix)
+		c.Assert(err, check.IsNil)
+		c.Assert(info.Tags[ChecksumTag], check.Equals, hex.EncodeToString(expected[:]))
+		sum, err := Verify(ctx, profile, suffix)
+		c.Assert(err, check.IsNil)
+		c.Assert(sum, check.Equals, hex.EncodeToString(expected[:]))
+	}
+
+	// Compressed artifacts are checksummed as they are stored
+	profile.Compression = crv1alpha1.CompressionTypeZstd
+	err = Write(ctx, bytes.NewBuffer(data), profile, "compressed")
+	c.Assert(err, check.IsNil)
+	stored, err := storedChecksum(ctx, profile, "compressed", objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	info, err := Stat(ctx, profile, "compressed")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.Tags[ChecksumTag], check.Equals, stored)
+}
+
+func (s *ChecksumSuite) TestChecksumObject(c *check.C) {
+	ctx := context.Background()
+	profile := s.profile(c)
+	data := []byte("test-content-check")
+	expected := sha256.Sum256(data)
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, profile, objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+
+	// The checksum of streams is stored next to them instead of being
+	// added to their tags after they are put
+	err = Write(ctx, bytes.NewBuffer(data), profile, "stream")
+	c.Assert(err, check.IsNil)
+	info, err := bucket.Stat(ctx, "prefix/stream")
+	c.Assert(err, check.IsNil)
+	_, ok := tagValue(info.Tags, ChecksumTag)
+	c.Assert(ok, check.Equals, false)
+	_, ok = tagValue(info.Tags, checksumObjectTag)
+	c.Assert(ok, check.Equals, true)
+	sum, _, err := bucket.GetBytes(ctx, checksumObject("prefix/stream"))
+	c.Assert(err, check.IsNil)
+	c.Assert(string(sum), check.Equals, hex.EncodeToString(expected[:]))
+
+	// Copies keep the checksum
+	err = Copy(ctx, profile, "stream", profile, "copy", objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	got, err := Verify(ctx, profile, "copy")
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, hex.EncodeToString(expected[:]))
+
+	// The checksum is deleted with the artifact
+	err = Delete(ctx, profile, "stream")
+	c.Assert(err, check.IsNil)
+	_, _, err = bucket.GetBytes(ctx, checksumObject("prefix/stream"))
+	c.Assert(err, check.NotNil)
+}
+
+func (s *ChecksumSuite) TestReadDetectsCorruption(c *check.C) {
+	ctx := context.Background()
+	for _, compression := range []crv1alpha1.CompressionType{"", crv1alpha1.CompressionTypeGzip} {
+		profile := s.profile(c)
+		profile.Compression = compression
+		err := Write(ctx, bytes.NewBufferString("test-content-check"), profile, "data")
+		c.Assert(err, check.IsNil)
+		s.corrupt(c, profile, "data")
+
+		err = Read(ctx, bytes.NewBuffer(nil), profile, "data")
+		c.Assert(err, check.NotNil)
+		if compression == "" {
+			var cme *ChecksumMismatchError
+			c.Assert(errors.As(err, &cme), check.Equals, true)
+			c.Assert(cme.Path, check.Equals, "prefix/data")
+			c.Assert(cme.Algorithm, check.Equals, "SHA-256")
+		}
+		_, err = Verify(ctx, profile, "data")
+		c.Assert(err, check.ErrorMatches, "artifact 'prefix/data' in bucket 'backups' has SHA-256 .*")
+	}
+}
+
+func (s *ChecksumSuite) TestVerifyWithoutChecksums(c *check.C) {
+	ctx := context.Background()
+	profile := s.profile(c)
+	profile.Location.Type = crv1alpha1.LocationTypeFilesystem
+	profile.Location.Endpoint = c.MkDir()
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeFilesystem, profile, objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+
+	// Artifacts written before checksums were stored are read as they are
+	err = bucket.PutBytes(ctx, "prefix/old", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+	buf := bytes.NewBuffer(nil)
+	err = Read(ctx, buf, profile, "old")
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "data")
+	_, err = Verify(ctx, profile, "old")
+	c.Assert(err, check.ErrorMatches, ".*Artifact has no checksums to verify.*")
+}
+
+func (s *ChecksumSuite) TestChecksumMismatchError(c *check.C) {
+	err := &ChecksumMismatchError{Path: "replicas/dump.sql", Bucket: "dr", Algorithm: "SHA-256", Expected: "aa", Actual: "bb"}
+	c.Assert(err, check.ErrorMatches, "artifact 'replicas/dump.sql' in bucket 'dr' has SHA-256 bb instead of aa")
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kanisterio/errkit"
+	"github.com/klauspost/compress/zstd"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// CompressionTag is the tag of a compressed object that holds the codec it
+// was compressed with. Objects without it are not compressed.
+const CompressionTag = "kanistercompression"
+
+// compressed reports whether data is compressed with `c`.
+func compressed(c crv1alpha1.CompressionType) bool {
+	return c != "" && c != crv1alpha1.CompressionTypeNone
+}
+
+// compress returns a reader of the data of `in` compressed with `c` and the
+// tags to store with it. The reader must be closed to release the encoder
+// if it is not read to the end.
+func compress(in io.Reader, c crv1alpha1.CompressionType) (io.ReadCloser, map[string]string, error) {
+	pr, pw := io.Pipe()
+	var w io.WriteCloser
+	switch c {
+	case crv1alpha1.CompressionTypeGzip:
+		w = gzip.NewWriter(pw)
+	case crv1alpha1.CompressionTypeZstd:
+		zw, err := zstd.NewWriter(pw)
+		if err != nil {
+			return nil, nil, errkit.Wrap(err, "Failed to create zstd encoder")
+		}
+		w = zw
+	default:
+		return nil, nil, errkit.New(fmt.Sprintf("Unsupported compression '%s'", c))
+	}
+	go func() {
+		_, err := io.Copy(w, in)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		pw.CloseWithError(err) //nolint:errcheck
+	}()
+	return pr, map[string]string{CompressionTag: string(c)}, nil
+}
+
+// decompress returns a reader of the data of `in`, which was compressed with
+// `c`.
+func decompress(in io.Reader, c crv1alpha1.CompressionType) (io.ReadCloser, error) {
+	switch c {
+	case crv1alpha1.CompressionTypeGzip:
+		r, err := gzip.NewReader(in)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to create gzip decoder")
+		}
+		return r, nil
+	case crv1alpha1.CompressionTypeZstd:
+		r, err := zstd.NewReader(in)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to create zstd decoder")
+		}
+		return r.IOReadCloser(), nil
+	default:
+		return nil, errkit.New(fmt.Sprintf("Unsupported compression '%s'", c))
+	}
+}
+
+// tagValue returns the value of the tag `key`. Tags are matched regardless of
+// case since some object stores change it.
+func tagValue(tags map[string]string, key string) (string, bool) {
+	for k, v := range tags {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
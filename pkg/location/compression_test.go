@@ -0,0 +1,101 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"context"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type CompressionSuite struct{}
+
+var _ = check.Suite(&CompressionSuite{})
+
+func (s *CompressionSuite) TestWriteRead(c *check.C) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("compressible "), 100000)
+	keys := &param.EncryptionKeys{KeyID: "k", Keys: map[string][]byte{"k": testKey1}}
+	for _, tc := range []struct {
+		compression crv1alpha1.CompressionType
+		encryption  *param.EncryptionKeys
+	}{
+		{compression: ""},
+		{compression: crv1alpha1.CompressionTypeNone},
+		{compression: crv1alpha1.CompressionTypeGzip},
+		{compression: crv1alpha1.CompressionTypeZstd},
+		{compression: crv1alpha1.CompressionTypeZstd, encryption: keys},
+	} {
+		profile := param.Profile{
+			Location: crv1alpha1.Location{
+				Type:     LocationTypeMemory,
+				Bucket:   "backups",
+				Endpoint: c.TestName(),
+			},
+			Compression: tc.compression,
+			Encryption:  tc.encryption,
+		}
+		err := Write(ctx, bytes.NewBuffer(data), profile, "dump")
+		c.Assert(err, check.IsNil)
+
+		bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, profile, objectstore.TransferOptions{})
+		c.Assert(err, check.IsNil)
+		raw, tags, err := bucket.GetBytes(ctx, "dump")
+		c.Assert(err, check.IsNil)
+		codec, ok := tagValue(tags, CompressionTag)
+		c.Assert(ok, check.Equals, compressed(tc.compression))
+		if ok {
+			c.Assert(codec, check.Equals, string(tc.compression))
+			c.Assert(len(raw) < len(data)/10, check.Equals, true)
+		}
+
+		// Objects are decompressed regardless of the compression of the
+		// profile that reads them
+		profile.Compression = ""
+		buf := bytes.NewBuffer(nil)
+		err = Read(ctx, buf, profile, "dump")
+		c.Assert(err, check.IsNil)
+		c.Assert(bytes.Equal(buf.Bytes(), data), check.Equals, true)
+		objectstore.DeleteMemoryStore(c.TestName())
+	}
+}
+
+func (s *CompressionSuite) TestUnsupported(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+		},
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+
+	profile.Compression = "lz4"
+	err := Write(ctx, bytes.NewBufferString("data"), profile, "dump")
+	c.Assert(err, check.ErrorMatches, ".*Unsupported compression 'lz4'.*")
+
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, profile, objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	err = bucket.PutBytes(ctx, "dump", []byte("data"), map[string]string{"KanisterCompression": "lz4"})
+	c.Assert(err, check.IsNil)
+	err = Read(ctx, bytes.NewBuffer(nil), profile, "dump")
+	c.Assert(err, check.ErrorMatches, ".*Unsupported compression 'lz4'.*")
+}
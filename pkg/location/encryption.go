@@ -0,0 +1,294 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/kanisterio/errkit"
+
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// Artifacts are encrypted with a random data key that is unique to each
+// object. The data key is wrapped with a key from the profile's Secret and
+// stored, along with the ID of that key, in the object's tags. Keys that are
+// no longer used for new artifacts can therefore stay in the Secret to read
+// older ones.
+//
+// The data is encrypted with AES-256-GCM in segments so that it can be
+// streamed. The nonce of each segment is made of a random prefix, the index
+// of the segment and a flag that marks the last one, so that reordered,
+// dropped or truncated segments fail to decrypt.
+const (
+	// EncryptionTag is the tag of an encrypted object that holds what is
+	// needed to decrypt it.
+	EncryptionTag = "kanisterencryption"
+
+	encryptionVersion   = 1
+	encryptionAlgorithm = "AES256-GCM-STREAM"
+	encryptionKeySize   = 32
+	segmentSize         = 64 << 10
+	noncePrefixSize     = 7
+)
+
+// encryptionHeader is stored in the EncryptionTag of an encrypted object.
+type encryptionHeader struct {
+	Version     int    `json:"version"`
+	Algorithm   string `json:"alg"`
+	KeyID       string `json:"keyID"`
+	WrappedKey  []byte `json:"wrappedKey"`
+	NoncePrefix []byte `json:"noncePrefix"`
+}
+
+// encryptionKey returns the wrapping key with the given ID. Keys are either
+// 32 raw bytes or their base64 encoding.
+func encryptionKey(keys *param.EncryptionKeys, id string) ([]byte, error) {
+	k, ok := keys.Keys[id]
+	if !ok {
+		return nil, errkit.New(fmt.Sprintf("Encryption key '%s' not found", id))
+	}
+	if len(k) == encryptionKeySize {
+		return k, nil
+	}
+	d, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(k)))
+	if err != nil || len(d) != encryptionKeySize {
+		return nil, errkit.New(fmt.Sprintf("Encryption key '%s' must be %d bytes or their base64 encoding", id, encryptionKeySize))
+	}
+	return d, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create cipher")
+	}
+	return cipher.NewGCM(b)
+}
+
+// encrypt returns a reader of the encrypted data of `in` and the tags to
+// store with it.
+func encrypt(in io.Reader, keys *param.EncryptionKeys) (io.Reader, map[string]string, error) {
+	kek, err := encryptionKey(keys, keys.KeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	dek := make([]byte, encryptionKeySize)
+	prefix := make([]byte, noncePrefixSize)
+	for _, b := range [][]byte{dek, prefix} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, errkit.Wrap(err, "Failed to generate random bytes")
+		}
+	}
+	wrapper, err := newGCM(kek)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, wrapper.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, errkit.Wrap(err, "Failed to generate random bytes")
+	}
+	h := encryptionHeader{
+		Version:     encryptionVersion,
+		Algorithm:   encryptionAlgorithm,
+		KeyID:       keys.KeyID,
+		WrappedKey:  wrapper.Seal(nonce, nonce, dek, []byte(keys.KeyID)),
+		NoncePrefix: prefix,
+	}
+	hb, err := json.Marshal(h)
+	if err != nil {
+		return nil, nil, errkit.WithStack(err)
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &encryptingReader{
+		segments: segments{aead: aead, prefix: prefix},
+		src:      bufio.NewReaderSize(in, segmentSize),
+		plain:    make([]byte, segmentSize),
+	}
+	tags := map[string]string{EncryptionTag: base64.RawURLEncoding.EncodeToString(hb)}
+	return r, tags, nil
+}
+
+// encryptionHeaderFromTags returns the header of an encrypted object, if
+// any.
+func encryptionHeaderFromTags(tags map[string]string) (*encryptionHeader, bool, error) {
+	v, ok := tagValue(tags, EncryptionTag)
+	if !ok {
+		return nil, false, nil
+	}
+	hb, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, true, errkit.Wrap(err, "Failed to decode encryption tag")
+	}
+	h := &encryptionHeader{}
+	if err := json.Unmarshal(hb, h); err != nil {
+		return nil, true, errkit.Wrap(err, "Failed to decode encryption tag")
+	}
+	if h.Version != encryptionVersion || h.Algorithm != encryptionAlgorithm {
+		return nil, true, errkit.New(fmt.Sprintf("Unsupported encryption version %d and algorithm '%s'", h.Version, h.Algorithm))
+	}
+	if len(h.NoncePrefix) != noncePrefixSize {
+		return nil, true, errkit.New("Invalid encryption nonce prefix")
+	}
+	return h, true, nil
+}
+
+// decrypt returns a reader of the data of `in`, which was encrypted as
+// described by `h`.
+func decrypt(in io.Reader, h *encryptionHeader, keys *param.EncryptionKeys) (io.Reader, error) {
+	if keys == nil {
+		return nil, errkit.New("Artifact is encrypted but the profile has no encryption keys", "keyID", h.KeyID)
+	}
+	kek, err := encryptionKey(keys, h.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	ns := wrapper.NonceSize()
+	if len(h.WrappedKey) < ns {
+		return nil, errkit.New("Invalid wrapped data key")
+	}
+	dek, err := wrapper.Open(nil, h.WrappedKey[:ns], h.WrappedKey[ns:], []byte(h.KeyID))
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to unwrap data key", "keyID", h.KeyID)
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReader{
+		segments: segments{aead: aead, prefix: h.NoncePrefix},
+		src:      bufio.NewReaderSize(in, segmentSize+aead.Overhead()),
+		sealed:   make([]byte, segmentSize+aead.Overhead()),
+	}, nil
+}
+
+// segments tracks the nonces of the segments of a stream.
+type segments struct {
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	done    bool
+}
+
+// nonce returns the nonce of the next segment.
+func (s *segments) nonce(last bool) ([]byte, error) {
+	if s.counter == math.MaxUint32 {
+		return nil, errkit.New("Too many segments to encrypt")
+	}
+	n := make([]byte, s.aead.NonceSize())
+	copy(n, s.prefix)
+	binary.BigEndian.PutUint32(n[noncePrefixSize:], s.counter)
+	if last {
+		n[len(n)-1] = 1
+	}
+	s.counter++
+	return n, nil
+}
+
+// fill reads a segment from `src` into `buf` and reports whether it is the
+// last one.
+func fill(src *bufio.Reader, buf []byte) (int, bool, error) {
+	n, err := io.ReadFull(src, buf)
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		return n, true, nil
+	case err != nil:
+		return 0, false, err
+	}
+	if _, err := src.Peek(1); err == io.EOF {
+		return n, true, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	return n, false, nil
+}
+
+type encryptingReader struct {
+	segments
+	src   *bufio.Reader
+	plain []byte
+	out   []byte
+	buf   []byte
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		n, last, err := fill(r.src, r.plain)
+		if err != nil {
+			return 0, err
+		}
+		nonce, err := r.nonce(last)
+		if err != nil {
+			return 0, err
+		}
+		r.out = r.aead.Seal(r.out[:0], nonce, r.plain[:n], nil)
+		r.buf, r.done = r.out, last
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type decryptingReader struct {
+	segments
+	src    *bufio.Reader
+	sealed []byte
+	out    []byte
+	buf    []byte
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		n, last, err := fill(r.src, r.sealed)
+		if err != nil {
+			return 0, err
+		}
+		nonce, err := r.nonce(last)
+		if err != nil {
+			return 0, err
+		}
+		r.out, err = r.aead.Open(r.out[:0], nonce, r.sealed[:n], nil)
+		if err != nil {
+			return 0, errkit.Wrap(err, "Failed to decrypt artifact, it may be truncated or modified")
+		}
+		r.buf, r.done = r.out, last
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
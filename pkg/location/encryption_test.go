@@ -0,0 +1,184 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type EncryptionSuite struct{}
+
+var _ = check.Suite(&EncryptionSuite{})
+
+var (
+	testKey1 = bytes.Repeat([]byte{1}, encryptionKeySize)
+	testKey2 = []byte(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, encryptionKeySize)))
+)
+
+func encryptBytes(c *check.C, data []byte, keys *param.EncryptionKeys) ([]byte, *encryptionHeader) {
+	r, tags, err := encrypt(bytes.NewReader(data), keys)
+	c.Assert(err, check.IsNil)
+	enc, err := io.ReadAll(r)
+	c.Assert(err, check.IsNil)
+	h, ok, err := encryptionHeaderFromTags(tags)
+	c.Assert(err, check.IsNil)
+	c.Assert(ok, check.Equals, true)
+	return enc, h
+}
+
+func decryptBytes(enc []byte, h *encryptionHeader, keys *param.EncryptionKeys) ([]byte, error) {
+	r, err := decrypt(bytes.NewReader(enc), h, keys)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func (s *EncryptionSuite) TestRoundTrip(c *check.C) {
+	keys := &param.EncryptionKeys{KeyID: "k1", Keys: map[string][]byte{"k1": testKey1}}
+	for _, size := range []int{0, 1, segmentSize - 1, segmentSize, segmentSize + 1, 3 * segmentSize} {
+		data := bytes.Repeat([]byte("0123456789"), size/10+1)[:size]
+		enc, h := encryptBytes(c, data, keys)
+		c.Assert(h.KeyID, check.Equals, "k1")
+		if size >= 10 {
+			// Shorter data can be part of the ciphertext by chance
+			c.Assert(bytes.Contains(enc, data), check.Equals, false)
+		}
+		got, err := decryptBytes(enc, h, keys)
+		c.Assert(err, check.IsNil)
+		c.Assert(bytes.Equal(got, data), check.Equals, true, check.Commentf("size %d", size))
+	}
+}
+
+func (s *EncryptionSuite) TestTamper(c *check.C) {
+	keys := &param.EncryptionKeys{KeyID: "k1", Keys: map[string][]byte{"k1": testKey1}}
+	data := bytes.Repeat([]byte("x"), 2*segmentSize+10)
+	enc, h := encryptBytes(c, data, keys)
+	sealedSegment := segmentSize + 16
+
+	modified := append([]byte(nil), enc...)
+	modified[10] ^= 1
+	for _, tc := range []struct {
+		name string
+		enc  []byte
+	}{
+		{name: "modified", enc: modified},
+		{name: "truncated at a segment", enc: enc[:2*sealedSegment]},
+		{name: "truncated within a segment", enc: enc[:sealedSegment+100]},
+		{name: "empty", enc: nil},
+		{name: "reordered", enc: append(append(append([]byte(nil), enc[sealedSegment:2*sealedSegment]...), enc[:sealedSegment]...), enc[2*sealedSegment:]...)},
+	} {
+		_, err := decryptBytes(tc.enc, h, keys)
+		c.Check(err, check.ErrorMatches, ".*Failed to decrypt.*", check.Commentf(tc.name))
+	}
+
+	// The wrapped key is bound to its key ID
+	keys.Keys["k2"] = testKey1
+	h.KeyID = "k2"
+	_, err := decryptBytes(enc, h, keys)
+	c.Assert(err, check.ErrorMatches, ".*Failed to unwrap data key.*")
+}
+
+func (s *EncryptionSuite) TestKeys(c *check.C) {
+	for _, tc := range []struct {
+		key     []byte
+		checker check.Checker
+	}{
+		{key: testKey1, checker: check.IsNil},
+		{key: testKey2, checker: check.IsNil},
+		{key: append(testKey2, '\n'), checker: check.IsNil},
+		{key: []byte("short"), checker: check.NotNil},
+		{key: []byte(base64.StdEncoding.EncodeToString([]byte("short"))), checker: check.NotNil},
+	} {
+		_, err := encryptionKey(&param.EncryptionKeys{Keys: map[string][]byte{"k": tc.key}}, "k")
+		c.Check(err, tc.checker)
+	}
+	_, err := encryptionKey(&param.EncryptionKeys{}, "missing")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *EncryptionSuite) TestLocationKeyRotation(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+		},
+		Encryption: &param.EncryptionKeys{
+			KeyID: "k1",
+			Keys:  map[string][]byte{"k1": testKey1},
+		},
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+	read := func(p param.Profile, name string) (string, error) {
+		buf := bytes.NewBuffer(nil)
+		err := Read(ctx, buf, p, name)
+		return buf.String(), err
+	}
+
+	err := Write(ctx, bytes.NewBufferString("old"), profile, "old")
+	c.Assert(err, check.IsNil)
+
+	// Data is encrypted at rest
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, profile, objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	raw, tags, err := bucket.GetBytes(ctx, "old")
+	c.Assert(err, check.IsNil)
+	c.Assert(bytes.Contains(raw, []byte("old")), check.Equals, false)
+	c.Assert(tags[EncryptionTag], check.Not(check.Equals), "")
+
+	// New artifacts use the new key and old ones can still be read
+	profile.Encryption = &param.EncryptionKeys{
+		KeyID: "k2",
+		Keys:  map[string][]byte{"k1": testKey1, "k2": testKey2},
+	}
+	err = Write(ctx, bytes.NewBufferString("new"), profile, "new")
+	c.Assert(err, check.IsNil)
+	got, err := read(profile, "old")
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, "old")
+	got, err = read(profile, "new")
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, "new")
+
+	// Retiring a key makes its artifacts unreadable
+	delete(profile.Encryption.Keys, "k1")
+	_, err = read(profile, "old")
+	c.Assert(err, check.ErrorMatches, ".*Encryption key 'k1' not found.*")
+
+	profile.Encryption = nil
+	_, err = read(profile, "new")
+	c.Assert(err, check.ErrorMatches, ".*profile has no encryption keys.*")
+
+	// Artifacts without encryption tags are not read as plain text
+	err = Write(ctx, bytes.NewBufferString("plain"), profile, "plain")
+	c.Assert(err, check.IsNil)
+	profile.Encryption = &param.EncryptionKeys{
+		KeyID: "k2",
+		Keys:  map[string][]byte{"k2": testKey2},
+	}
+	_, err = read(profile, "plain")
+	c.Assert(err, check.ErrorMatches, ".*Artifact is not encrypted but the profile requires encryption.*")
+}
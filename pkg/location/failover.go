@@ -0,0 +1,327 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kanisterio/errkit"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+var (
+	errAttemptEnded    = errkit.New("Write to location was abandoned")
+	errLocationStalled = errkit.New("Location stopped reading data")
+)
+
+// fanOutWriteTimeout is how long the writeAll policy waits for a location to
+// take data from a stream before it stops writing to it, so that a location
+// that hangs does not stall the others.
+var fanOutWriteTimeout = 10 * time.Minute
+
+// WriteToLocations pipes data from `in` into the locations of `profile` as
+// configured by its location policy and returns the locations that the data
+// was written to under `suffix`.
+//
+// With the failover policy, the data is written to the first location it
+// can be written to. The start of streams is kept in memory, up to the part
+// size of `opts`, so that writes are retried on the next location unless
+// the attempt read past it.
+//
+// With the writeAll policy, the data is written to all the locations in
+// parallel. It fails only if the data cannot be written to any of them.
+func WriteToLocations(ctx context.Context, in io.Reader, profile param.Profile, suffix string, opts objectstore.TransferOptions) ([]crv1alpha1.Location, error) {
+	ps := locationProfiles(profile)
+	if len(ps) > 1 && profile.LocationPolicy == crv1alpha1.LocationPolicyWriteAll {
+		return writeAll(ctx, in, ps, suffix, opts)
+	}
+	return writeFailover(ctx, in, ps, suffix, opts)
+}
+
+// locations returns the locations of `profile` in the order they are used.
+func locations(profile param.Profile) []crv1alpha1.Location {
+	return append([]crv1alpha1.Location{profile.Location}, profile.SecondaryLocations...)
+}
+
+// locationProfiles returns a copy of `profile` for each of its locations.
+func locationProfiles(profile param.Profile) []param.Profile {
+	ls := locations(profile)
+	profile.SecondaryLocations = nil
+	ps := make([]param.Profile, 0, len(ls))
+	for _, l := range ls {
+		profile.Location = l
+		ps = append(ps, profile)
+	}
+	return ps
+}
+
+func writeFailover(ctx context.Context, in io.Reader, ps []param.Profile, suffix string, opts objectstore.TransferOptions) ([]crv1alpha1.Location, error) {
+	r, size, seekable := resumableSource(in)
+	var sp *spool
+	if !seekable {
+		sp = &spool{r: in, limit: spoolSize(opts)}
+	}
+	var errs error
+	for i, p := range ps {
+		if i > 0 {
+			log.Info().WithContext(ctx).Print("Writing to the next location", field.M{"bucket": p.Location.Bucket, "endpoint": p.Location.Endpoint})
+		}
+		if seekable {
+			err := writeLocation(ctx, io.NewSectionReader(r, 0, size), p, suffix, opts)
+			if err == nil {
+				return []crv1alpha1.Location{p.Location}, nil
+			}
+			errs = appendError(errs, err)
+			continue
+		}
+		a := &streamAttempt{s: sp}
+		err := writeLocation(ctx, a, p, suffix, opts)
+		if err == nil {
+			return []crv1alpha1.Location{p.Location}, nil
+		}
+		errs = appendError(errs, err)
+		if !a.end() {
+			// The data that was read cannot be read again
+			break
+		}
+	}
+	return nil, errs
+}
+
+func writeAll(ctx context.Context, in io.Reader, ps []param.Profile, suffix string, opts objectstore.TransferOptions) ([]crv1alpha1.Location, error) {
+	errs := make([]error, len(ps))
+	var wg sync.WaitGroup
+	if r, size, ok := resumableSource(in); ok {
+		for i, p := range ps {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = writeLocation(ctx, io.NewSectionReader(r, 0, size), p, suffix, opts)
+			}()
+		}
+	} else {
+		// Streams are copied to a pipe for each location
+		fo := &fanOut{}
+		for i, p := range ps {
+			pr, pw := io.Pipe()
+			fo.ws = append(fo.ws, pw)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = writeLocation(ctx, pr, p, suffix, opts)
+				// Writes to the pipe fail if the data was not read to the end
+				pr.CloseWithError(errAttemptEnded) //nolint:errcheck
+			}()
+		}
+		_, err := io.Copy(fo, in)
+		fo.close(err)
+	}
+	wg.Wait()
+
+	var written []crv1alpha1.Location
+	var allErrs error
+	for i, err := range errs {
+		if err != nil {
+			log.Error().WithContext(ctx).WithError(err).Print("Failed to write to location", field.M{"bucket": ps[i].Location.Bucket, "endpoint": ps[i].Location.Endpoint})
+			allErrs = appendError(allErrs, err)
+			continue
+		}
+		written = append(written, ps[i].Location)
+	}
+	if len(written) == 0 {
+		return nil, allErrs
+	}
+	return written, nil
+}
+
+// fanOut writes data to all of its pipes in parallel. Pipes whose reader
+// stopped reading, or did not take the data within fanOutWriteTimeout, are
+// closed and not written to again.
+type fanOut struct {
+	ws     []*io.PipeWriter
+	failed []bool
+}
+
+func (f *fanOut) Write(p []byte) (int, error) {
+	if f.failed == nil {
+		f.failed = make([]bool, len(f.ws))
+	}
+	errs := make([]error, len(f.ws))
+	pending := make([]bool, len(f.ws))
+	done := make(chan int, len(f.ws))
+	for i, w := range f.ws {
+		if f.failed[i] {
+			continue
+		}
+		pending[i] = true
+		go func() {
+			_, errs[i] = w.Write(p)
+			done <- i
+		}()
+	}
+	timer := time.NewTimer(fanOutWriteTimeout)
+	defer timer.Stop()
+	var err error
+	ok := false
+	// Wait for all the writes, since p must not be used once Write returns
+	for n := count(pending); n > 0; {
+		select {
+		case i := <-done:
+			n--
+			pending[i] = false
+			if f.failed[i] {
+				// The pipe was closed when the write timed out
+				err = appendError(err, errLocationStalled)
+				continue
+			}
+			if errs[i] != nil {
+				f.failed[i] = true
+				err = appendError(err, errs[i])
+				continue
+			}
+			ok = true
+		case <-timer.C:
+			// Closing the pipes ends their pending writes
+			for i, w := range f.ws {
+				if pending[i] {
+					f.failed[i] = true
+					w.CloseWithError(errLocationStalled) //nolint:errcheck
+				}
+			}
+		}
+	}
+	if !ok {
+		return 0, errkit.Wrap(err, "Failed to write to any location")
+	}
+	return len(p), nil
+}
+
+// count returns the number of true values in bs.
+func count(bs []bool) int {
+	n := 0
+	for _, b := range bs {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+// close closes the pipes with `err`, or io.EOF if it is nil.
+func (f *fanOut) close(err error) {
+	for _, w := range f.ws {
+		w.CloseWithError(err) //nolint:errcheck
+	}
+}
+
+// spoolSize returns the number of bytes of a stream that are kept to retry
+// writing it, which is the size of the first part that is uploaded.
+func spoolSize(opts objectstore.TransferOptions) int64 {
+	if opts.PartSize > 0 {
+		return max(opts.PartSize, objectstore.MinPartSize)
+	}
+	return objectstore.DefaultPartSize
+}
+
+// spool reads a stream and keeps the first `limit` bytes of it, so that
+// they can be read again.
+type spool struct {
+	r     io.Reader
+	limit int64
+	buf   []byte
+	// full is set once more than `limit` bytes were read, after which the
+	// stream cannot be read again.
+	full bool
+}
+
+// readAt reads the stream at `off`, which is at most the number of bytes
+// read so far.
+func (s *spool) readAt(p []byte, off int64) (int, error) {
+	if off < int64(len(s.buf)) {
+		return copy(p, s.buf[off:]), nil
+	}
+	n, err := s.r.Read(p)
+	if !s.full {
+		if int64(len(s.buf)+n) > s.limit {
+			s.full = true
+			s.buf = nil
+		} else {
+			s.buf = append(s.buf, p[:n]...)
+		}
+	}
+	return n, err
+}
+
+// streamAttempt reads a stream for one attempt to write it. Reads fail once
+// the attempt ended, so that readers of the attempt that are still running,
+// like encoders, do not take data from the next attempt.
+type streamAttempt struct {
+	mu    sync.Mutex
+	s     *spool
+	off   int64
+	ended bool
+}
+
+func (a *streamAttempt) Read(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ended {
+		return 0, errAttemptEnded
+	}
+	n, err := a.s.readAt(p, a.off)
+	a.off += int64(n)
+	return n, err
+}
+
+// end ends the attempt and reports whether the stream can be written by
+// another one, which is if the data it read was kept by the spool.
+func (a *streamAttempt) end() bool {
+	if !a.mu.TryLock() {
+		// A read is in progress
+		return false
+	}
+	defer a.mu.Unlock()
+	a.ended = true
+	return !a.s.full
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// appendError returns err if errs is nil and both errors otherwise, so that
+// the errors of profiles with a single location are returned as they are.
+func appendError(errs, err error) error {
+	if errs == nil {
+		return err
+	}
+	return errkit.Append(errs, err)
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type FailoverSuite struct{}
+
+var _ = check.Suite(&FailoverSuite{})
+
+// unavailable is a location that cannot be written to or read from.
+var unavailable = crv1alpha1.Location{Type: "unavailable", Bucket: "backups"}
+
+func memoryLocation(c *check.C, name string) crv1alpha1.Location {
+	l := crv1alpha1.Location{
+		Type:     LocationTypeMemory,
+		Bucket:   "backups",
+		Endpoint: c.TestName() + "/" + name,
+		Prefix:   "prefix",
+	}
+	return l
+}
+
+func (s *FailoverSuite) TestFailover(c *check.C) {
+	ctx := context.Background()
+	secondary := memoryLocation(c, "secondary")
+	defer objectstore.DeleteMemoryStore(secondary.Endpoint)
+	profile := param.Profile{
+		Location:           unavailable,
+		SecondaryLocations: []crv1alpha1.Location{secondary},
+		Compression:        crv1alpha1.CompressionTypeGzip,
+	}
+	for name, in := range map[string]io.Reader{
+		"file":   bytes.NewReader([]byte("file data")),
+		"stream": bytes.NewBufferString("stream data"),
+	} {
+		locs, err := WriteToLocations(ctx, in, profile, name, objectstore.TransferOptions{})
+		c.Assert(err, check.IsNil)
+		c.Assert(locs, check.DeepEquals, []crv1alpha1.Location{secondary})
+
+		buf := &bytes.Buffer{}
+		c.Assert(Read(ctx, buf, profile, name), check.IsNil)
+		c.Assert(buf.String(), check.Equals, name+" data")
+		_, err = Stat(ctx, profile, name)
+		c.Assert(err, check.IsNil)
+		_, err = Verify(ctx, profile, name)
+		c.Assert(err, check.IsNil)
+	}
+
+	// Reads fail if the artifact is in none of the locations
+	err := Read(ctx, &bytes.Buffer{}, profile, "missing")
+	c.Assert(err, check.NotNil)
+
+	// Writes fail if none of the locations can be written to
+	profile.SecondaryLocations = []crv1alpha1.Location{unavailable}
+	_, err = WriteToLocations(ctx, bytes.NewBufferString("data"), profile, "data", objectstore.TransferOptions{})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *FailoverSuite) TestReadInOrder(c *check.C) {
+	ctx := context.Background()
+	primary := memoryLocation(c, "primary")
+	secondary := memoryLocation(c, "secondary")
+	defer objectstore.DeleteMemoryStore(primary.Endpoint)
+	defer objectstore.DeleteMemoryStore(secondary.Endpoint)
+	err := Write(ctx, strings.NewReader("secondary"), param.Profile{Location: secondary}, "data")
+	c.Assert(err, check.IsNil)
+
+	profile := param.Profile{
+		Location:           primary,
+		SecondaryLocations: []crv1alpha1.Location{secondary},
+	}
+	buf := &bytes.Buffer{}
+	c.Assert(Read(ctx, buf, profile, "data"), check.IsNil)
+	c.Assert(buf.String(), check.Equals, "secondary")
+
+	err = Write(ctx, strings.NewReader("primary"), param.Profile{Location: primary}, "data")
+	c.Assert(err, check.IsNil)
+	buf.Reset()
+	c.Assert(Read(ctx, buf, profile, "data"), check.IsNil)
+	c.Assert(buf.String(), check.Equals, "primary")
+
+	// Artifacts are deleted from all the locations
+	c.Assert(Delete(ctx, profile, "data"), check.IsNil)
+	for _, l := range []crv1alpha1.Location{primary, secondary} {
+		_, err := Stat(ctx, param.Profile{Location: l}, "data")
+		c.Assert(err, check.NotNil)
+	}
+}
+
+func (s *FailoverSuite) TestWriteAll(c *check.C) {
+	ctx := context.Background()
+	primary := memoryLocation(c, "primary")
+	secondary := memoryLocation(c, "secondary")
+	defer objectstore.DeleteMemoryStore(primary.Endpoint)
+	defer objectstore.DeleteMemoryStore(secondary.Endpoint)
+	profile := param.Profile{
+		Location:           primary,
+		SecondaryLocations: []crv1alpha1.Location{unavailable, secondary},
+		LocationPolicy:     crv1alpha1.LocationPolicyWriteAll,
+		Compression:        crv1alpha1.CompressionTypeZstd,
+	}
+	data := bytes.Repeat([]byte("data"), 1<<16)
+	for name, in := range map[string]io.Reader{
+		"file":   bytes.NewReader(data),
+		"stream": bytes.NewBuffer(data),
+	} {
+		locs, err := WriteToLocations(ctx, in, profile, name, objectstore.TransferOptions{})
+		c.Assert(err, check.IsNil)
+		c.Assert(locs, check.DeepEquals, []crv1alpha1.Location{primary, secondary})
+		for _, l := range locs {
+			buf := &bytes.Buffer{}
+			p := profile
+			p.Location, p.SecondaryLocations = l, nil
+			c.Assert(Read(ctx, buf, p, name), check.IsNil)
+			c.Assert(buf.Bytes(), check.DeepEquals, data)
+		}
+	}
+
+	profile.Location = unavailable
+	profile.SecondaryLocations = []crv1alpha1.Location{unavailable}
+	_, err := WriteToLocations(ctx, bytes.NewBuffer(data), profile, "data", objectstore.TransferOptions{})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *FailoverSuite) TestStreamAttempt(c *check.C) {
+	sp := &spool{r: strings.NewReader("data"), limit: 2}
+	a := &streamAttempt{s: sp}
+	c.Assert(a.end(), check.Equals, true)
+	_, err := a.Read(make([]byte, 4))
+	c.Assert(err, check.NotNil)
+
+	// Data that was kept is read again by the next attempt
+	a = &streamAttempt{s: sp}
+	n, err := a.Read(make([]byte, 2))
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 2)
+	c.Assert(a.end(), check.Equals, true)
+	a = &streamAttempt{s: sp}
+	data, err := io.ReadAll(a)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "data")
+
+	// The stream cannot be written again once more than the limit was read
+	c.Assert(a.end(), check.Equals, false)
+}
+
+func (s *FailoverSuite) TestFanOutStalled(c *check.C) {
+	defer func(t time.Duration) { fanOutWriteTimeout = t }(fanOutWriteTimeout)
+	fanOutWriteTimeout = 10 * time.Millisecond
+
+	fo := &fanOut{}
+	var prs []*io.PipeReader
+	for range 2 {
+		pr, pw := io.Pipe()
+		prs = append(prs, pr)
+		fo.ws = append(fo.ws, pw)
+	}
+	// Only the first location reads the data
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(prs[0])
+		done <- data
+	}()
+	for range 2 {
+		n, err := fo.Write([]byte("data"))
+		c.Assert(err, check.IsNil)
+		c.Assert(n, check.Equals, 4)
+	}
+	fo.close(nil)
+	c.Assert(string(<-done), check.Equals, "datadata")
+	_, err := prs[1].Read(make([]byte, 4))
+	c.Assert(err, check.Equals, errLocationStalled)
+
+	// Writes fail once no location reads the data
+	fo = &fanOut{}
+	_, pw := io.Pipe()
+	fo.ws = append(fo.ws, pw)
+	_, err = fo.Write([]byte("data"))
+	c.Assert(err, check.NotNil)
+}
@@ -13,22 +13,29 @@
 // limitations under the License.
 
 // Package location provides utilities for interacting with object storage
-// locations such as AWS S3, Google Cloud Storage, and Azure Blob Storage.
+// locations such as AWS S3, Google Cloud Storage, Azure Blob Storage and
+// filesystems provided by PersistentVolumeClaims.
 package location
 
 import (
-	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"maps"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/kanisterio/errkit"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/aws"
+	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/ratelimit"
 	"github.com/kanisterio/kanister/pkg/secrets"
 )
 
@@ -41,14 +48,37 @@ const (
 	AzureStorageAccount = "AZURE_ACCOUNT_NAME"
 	AzureStorageKey     = "AZURE_ACCOUNT_KEY"
 
-	// buffSize is the maximum size of an object that can be Put to Azure container in a single request
-	// https://github.com/kastenhq/stow/blob/v0.2.6-kasten/azure/container.go#L14
-	buffSize      = 256 * 1024 * 1024
-	buffSizeLimit = 1 * 1024 * 1024 * 1024
+	AWSRoleARN              = "AWS_ROLE_ARN"
+	AWSWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	AzureClientID           = "AZURE_CLIENT_ID"
+	AzureTenantID           = "AZURE_TENANT_ID"
+	AzureFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE"
+
+	// LocationTypeMemory stores artifacts in the memory of the current
+	// process, for tests that must not depend on an object store. Locations
+	// with the same endpoint share their buckets. Profiles cannot use it.
+	LocationTypeMemory crv1alpha1.LocationType = "memory"
 )
 
+// newLimiter returns the limiter that throttles the data moved to and from a
+// location. Tests replace it so that they do not wait.
+var newLimiter = ratelimit.NewLimiter
+
 // Write pipes data from `in` into the location specified by `profile` and `suffix`.
 func Write(ctx context.Context, in io.Reader, profile param.Profile, suffix string) error {
+	return WriteWithOptions(ctx, in, profile, suffix, objectstore.TransferOptions{})
+}
+
+// WriteWithOptions pipes data from `in` into the location specified by
+// `profile` and `suffix`. The data is uploaded in parts as configured by
+// `opts`, so that memory usage is bounded regardless of its size. Profiles
+// with secondary locations write it as configured by their location policy.
+func WriteWithOptions(ctx context.Context, in io.Reader, profile param.Profile, suffix string, opts objectstore.TransferOptions) error {
+	_, err := WriteToLocations(ctx, in, profile, suffix, opts)
+	return err
+}
+
+func writeLocation(ctx context.Context, in io.Reader, profile param.Profile, suffix string, opts objectstore.TransferOptions) error {
 	osType, err := getProviderType(profile.Location.Type)
 	if err != nil {
 		return err
@@ -57,11 +87,37 @@ func Write(ctx context.Context, in io.Reader, profile param.Profile, suffix stri
 		profile.Location.Prefix,
 		suffix,
 	)
-	return writeData(ctx, osType, profile, in, path)
+	return writeData(ctx, osType, profile, opts, in, path)
 }
 
 // Read pipes data from `in` into the location specified by `profile` and `suffix`.
 func Read(ctx context.Context, out io.Writer, profile param.Profile, suffix string) error {
+	return ReadWithOptions(ctx, out, profile, suffix, objectstore.TransferOptions{})
+}
+
+// ReadWithOptions pipes data from the location specified by `profile` and
+// `suffix` into `out`. Large objects are downloaded in parts that are
+// fetched in parallel as configured by `opts`. The locations of profiles
+// with secondary locations are tried in order until the data is read from
+// one of them.
+func ReadWithOptions(ctx context.Context, out io.Writer, profile param.Profile, suffix string, opts objectstore.TransferOptions) error {
+	var errs error
+	for _, p := range locationProfiles(profile) {
+		w := &countingWriter{w: out}
+		err := readLocation(ctx, w, p, suffix, opts)
+		if err == nil {
+			return nil
+		}
+		errs = appendError(errs, err)
+		if w.n > 0 {
+			// Data that was written cannot be taken back
+			break
+		}
+	}
+	return errs
+}
+
+func readLocation(ctx context.Context, out io.Writer, profile param.Profile, suffix string, opts objectstore.TransferOptions) error {
 	osType, err := getProviderType(profile.Location.Type)
 	if err != nil {
 		return err
@@ -70,11 +126,22 @@ func Read(ctx context.Context, out io.Writer, profile param.Profile, suffix stri
 		profile.Location.Prefix,
 		suffix,
 	)
-	return readData(ctx, osType, profile, out, path)
+	return readData(ctx, osType, profile, opts, out, path)
 }
 
-// Delete data from location specified by `profile` and `suffix`.
+// Delete data from location specified by `profile` and `suffix`. Data is
+// deleted from all the locations of profiles with secondary locations.
 func Delete(ctx context.Context, profile param.Profile, suffix string) error {
+	var errs error
+	for _, p := range locationProfiles(profile) {
+		if err := deleteLocation(ctx, p, suffix); err != nil {
+			errs = appendError(errs, err)
+		}
+	}
+	return errs
+}
+
+func deleteLocation(ctx context.Context, profile param.Profile, suffix string) error {
 	osType, err := getProviderType(profile.Location.Type)
 	if err != nil {
 		return err
@@ -86,84 +153,262 @@ func Delete(ctx context.Context, profile param.Profile, suffix string) error {
 	return deleteData(ctx, osType, profile, path)
 }
 
-func readData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, out io.Writer, path string) error {
-	bucket, err := getBucket(ctx, pType, profile)
+// CheckRetention returns an objectstore.RetentionError for the first object
+// under the location specified by `profile` and `suffix` that is retained by
+// an object lock. It returns nil if none of the objects are retained.
+func CheckRetention(ctx context.Context, profile param.Profile, suffix string) error {
+	osType, err := getProviderType(profile.Location.Type)
 	if err != nil {
 		return err
 	}
+	bucket, err := getBucket(ctx, osType, profile, objectstore.TransferOptions{})
+	if err != nil {
+		return err
+	}
+	return objectstore.CheckRetention(ctx, bucket, filepath.Join(profile.Location.Prefix, suffix))
+}
 
-	r, _, err := bucket.Get(ctx, path)
+// Stat returns the size, modification time and tags of the artifact at the
+// location specified by `profile` and `suffix` without reading it. The
+// locations of profiles with secondary locations are tried in order.
+func Stat(ctx context.Context, profile param.Profile, suffix string) (objectstore.ObjectInfo, error) {
+	var errs error
+	for _, p := range locationProfiles(profile) {
+		info, err := statLocation(ctx, p, suffix)
+		if err == nil {
+			return info, nil
+		}
+		errs = appendError(errs, err)
+	}
+	return objectstore.ObjectInfo{}, errs
+}
+
+func statLocation(ctx context.Context, profile param.Profile, suffix string) (objectstore.ObjectInfo, error) {
+	osType, err := getProviderType(profile.Location.Type)
+	if err != nil {
+		return objectstore.ObjectInfo{}, err
+	}
+	bucket, err := getBucket(ctx, osType, profile, objectstore.TransferOptions{})
+	if err != nil {
+		return objectstore.ObjectInfo{}, err
+	}
+	path := filepath.Join(profile.Location.Prefix, suffix)
+	info, err := bucket.Stat(ctx, path)
+	if err != nil {
+		return objectstore.ObjectInfo{}, err
+	}
+	info.Tags, err = withStoredChecksum(ctx, bucket, path, info.Tags)
+	return info, err
+}
+
+// Copy copies the artifact at the location specified by `src` and
+// `srcSuffix` to the location specified by `dst` and `dstSuffix`. Artifacts
+// are copied by the object store if both locations are in the same one and
+// streamed through the caller otherwise. Copies keep the compression and
+// encryption of the artifact.
+func Copy(ctx context.Context, src param.Profile, srcSuffix string, dst param.Profile, dstSuffix string, opts objectstore.TransferOptions) error {
+	srcType, err := getProviderType(src.Location.Type)
 	if err != nil {
 		return err
 	}
-	if _, err := io.Copy(out, r); err != nil {
+	dstType, err := getProviderType(dst.Location.Type)
+	if err != nil {
 		return err
 	}
-	return nil
+	srcBucket, err := getBucket(ctx, srcType, src, opts)
+	if err != nil {
+		return err
+	}
+	dstBucket, err := getBucket(ctx, dstType, dst, opts)
+	if err != nil {
+		return err
+	}
+	srcPath := filepath.Join(src.Location.Prefix, srcSuffix)
+	dstPath := filepath.Join(dst.Location.Prefix, dstSuffix)
+	if err := srcBucket.Copy(ctx, srcPath, dstBucket, dstPath); err != nil {
+		return err
+	}
+	info, err := srcBucket.Stat(ctx, srcPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := tagValue(info.Tags, checksumObjectTag); !ok {
+		return nil
+	}
+	// The copy is tagged like the artifact, so its checksum is copied too
+	dstSumBucket, err := checksumBucket(ctx, dstType, dst, opts, dstBucket)
+	if err != nil {
+		return err
+	}
+	return srcBucket.Copy(ctx, checksumObject(srcPath), dstSumBucket, checksumObject(dstPath))
 }
 
-func writeData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, in io.Reader, path string) error {
-	var input io.Reader
-	var size int64
-	bucket, err := getBucket(ctx, pType, profile)
+func readData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, opts objectstore.TransferOptions, out io.Writer, path string) error {
+	bucket, err := getBucket(ctx, pType, profile, opts)
 	if err != nil {
 		return err
 	}
 
-	var r io.Reader
-	var n int64
-	if pType == objectstore.ProviderTypeAzure {
-		// Switch to multipart upload based on data size
-		r, n, err = readerSize(in, buffSize)
+	if err := waitForRestore(ctx, bucket, pType, profile.StorageClass, path); err != nil {
+		return err
+	}
+	rc, tags, err := bucket.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer rc.Close() //nolint:errcheck
+	if tags, err = withStoredChecksum(ctx, bucket, path, tags); err != nil {
+		return err
+	}
+	r := ratelimit.Reader(ctx, rc, newLimiter(profile.RateLimit))
+	var vr *verifyingReader
+	if sum, ok := tagValue(tags, ChecksumTag); ok {
+		vr = newVerifyingReader(r, sum, path, profile.Location.Bucket)
+		r = vr
+	}
+	h, ok, err := encryptionHeaderFromTags(tags)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ok:
+		if r, err = decrypt(r, h, profile.Encryption); err != nil {
+			return err
+		}
+	case profile.Encryption != nil:
+		// Unencrypted data cannot be told apart from encrypted data whose
+		// tags were removed
+		return errkit.New("Artifact is not encrypted but the profile requires encryption", "path", path)
+	}
+	if c, ok := tagValue(tags, CompressionTag); ok {
+		dr, err := decompress(r, crv1alpha1.CompressionType(c))
 		if err != nil {
 			return err
 		}
+		defer dr.Close() //nolint:errcheck
+		r = dr
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		return err
+	}
+	if vr != nil {
+		// Decoders may stop reading before the end of the stored data
+		if _, err := io.Copy(io.Discard, vr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		input = r
-		size = int64(n)
+func writeData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, opts objectstore.TransferOptions, in io.Reader, path string) error {
+	// The buckets are got before the data is read, so that writes of
+	// streams can be retried on other locations if they cannot be.
+	bucket, err := getBucket(ctx, pType, profile, opts)
+	if err != nil {
+		return err
+	}
+	sumBucket, err := checksumBucket(ctx, pType, profile, opts, bucket)
+	if err != nil {
+		return err
+	}
+	// Data is compressed before it is encrypted
+	var tags map[string]string
+	if compressed(profile.Compression) {
+		rc, t, err := compress(in, profile.Compression)
+		if err != nil {
+			return err
+		}
+		defer rc.Close() //nolint:errcheck
+		in, tags = rc, t
+	}
+	if profile.Encryption != nil {
+		r, t, err := encrypt(in, profile.Encryption)
+		if err != nil {
+			return err
+		}
+		in = r
+		tags = mergeTags(tags, t)
+	}
+	r, size, resumable := resumableSource(in)
+	resumable = resumable && tags == nil
+	lim := newLimiter(profile.RateLimit)
+	sums := newChecksums()
+	if resumable {
+		// Transformed data cannot be read again to resume an upload. Data
+		// that can is read twice so that its checksum is stored with it.
+		if _, err := io.Copy(sums, io.NewSectionReader(r, 0, size)); err != nil {
+			return errkit.Wrap(err, "Failed to compute checksum")
+		}
+		tags = map[string]string{ChecksumTag: hex.EncodeToString(sums.sha256.Sum(nil))}
+		err = objectstore.PutResumable(ctx, bucket, path, ratelimit.ReaderAt(ctx, r, lim), size, tags)
 	} else {
-		input = in
-		size = 0
+		tags = mergeTags(tags, map[string]string{checksumObjectTag: "true"})
+		err = bucket.Put(ctx, path, ratelimit.Reader(ctx, io.TeeReader(in, sums), lim), 0, tags)
 	}
-
-	if err := bucket.Put(ctx, path, input, size, nil); err != nil {
+	if err != nil {
 		return errkit.Wrap(err, fmt.Sprintf("failed to write contents to bucket '%s'", profile.Location.Bucket))
 	}
-
-	return nil
+	return verifyWrite(ctx, bucket, sumBucket, path, profile.Location.Bucket, sums)
 }
 
-// readerSize checks if data size is greater than buffSize i.e the max size of an object that can be Put to Azure container in a single request
-// If size >= buffSize, return buffer size to enable multipart upload, otherwise return 0 buffersize.
-func readerSize(in io.Reader, buffSize int64) (io.Reader, int64, error) {
-	var n int64
-	var err error
-	var r io.Reader
-
-	// Read first buffSize bytes into buffer
-	buf := make([]byte, buffSize)
-	m, err := in.Read(buf)
-	if err != nil && err != io.EOF {
-		return nil, 0, err
+// verifyWrite compares the checksums of the data that was written with those
+// the object store computed, if any, and stores its SHA-256 in a separate
+// object of sumBucket if it was not known before the data was written.
+func verifyWrite(ctx context.Context, bucket, sumBucket objectstore.Bucket, path, bucketName string, sums *checksums) error {
+	info, err := bucket.Stat(ctx, path)
+	if err != nil {
+		return errkit.Wrap(err, "Failed to get written object", "path", path)
 	}
+	sum, err := sums.verify(info, path, bucketName)
+	if err != nil {
+		return err
+	}
+	if _, ok := tagValue(info.Tags, ChecksumTag); ok {
+		return nil
+	}
+	return putChecksum(ctx, sumBucket, path, sum)
+}
 
-	// If first buffSize bytes are read successfully, that means the data size >= buffSize
-	if int64(m) == buffSize {
-		r = io.MultiReader(bytes.NewReader(buf), in)
-		n = buffSizeLimit
-	} else {
-		buf = buf[:m]
-		r = bytes.NewReader(buf)
+func mergeTags(tags, more map[string]string) map[string]string {
+	if tags == nil {
+		tags = make(map[string]string, len(more))
 	}
+	maps.Copy(tags, more)
+	return tags
+}
 
-	return r, n, nil
+// resumableSource returns the remaining data of `in` if it can be read again
+// to resume an interrupted upload, like files but unlike pipes.
+func resumableSource(in io.Reader) (io.ReaderAt, int64, bool) {
+	rs, ok := in.(interface {
+		io.ReadSeeker
+		io.ReaderAt
+	})
+	if !ok {
+		return nil, 0, false
+	}
+	offset, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, 0, false
+	}
+	end, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, 0, false
+	}
+	if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+		return nil, 0, false
+	}
+	return io.NewSectionReader(rs, offset, end-offset), end - offset, true
 }
 
 func deleteData(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, path string) error {
-	bucket, err := getBucket(ctx, pType, profile)
+	bucket, err := getBucket(ctx, pType, profile, objectstore.TransferOptions{})
 	if err != nil {
 		return err
 	}
+	if err := objectstore.AbortUploads(ctx, bucket, path); err != nil {
+		return err
+	}
 	return bucket.DeleteAllWithPrefix(ctx, path)
 }
 
@@ -175,30 +420,143 @@ func getProviderType(lType crv1alpha1.LocationType) (objectstore.ProviderType, e
 		return objectstore.ProviderTypeGCS, nil
 	case crv1alpha1.LocationTypeAzure:
 		return objectstore.ProviderTypeAzure, nil
+	case crv1alpha1.LocationTypeFilesystem:
+		return objectstore.ProviderTypeFilesystem, nil
+	case crv1alpha1.LocationTypeSFTP:
+		return objectstore.ProviderTypeSFTP, nil
+	case LocationTypeMemory:
+		return objectstore.ProviderTypeMemory, nil
 	default:
 		return "", errkit.New(fmt.Sprintf("Unsupported Location type: %s", lType))
 	}
 }
 
-func getBucket(ctx context.Context, pType objectstore.ProviderType, profile param.Profile) (objectstore.Bucket, error) {
+// FilesystemRoot returns the path a filesystem location is mounted at.
+func FilesystemRoot(l crv1alpha1.Location) string {
+	if l.Endpoint != "" {
+		return l.Endpoint
+	}
+	return consts.FilesystemLocationMountPath
+}
+
+func getBucket(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, opts objectstore.TransferOptions) (objectstore.Bucket, error) {
+	if pType == objectstore.ProviderTypeFilesystem || pType == objectstore.ProviderTypeMemory {
+		// Local object stores do not need credentials
+		pc := objectstore.ProviderConfig{
+			Type:                 pType,
+			Endpoint:             profile.Location.Endpoint,
+			Transfer:             opts,
+			ObjectLock:           objectLockOptions(profile.ObjectLock),
+			ServerSideEncryption: serverSideEncryption(profile.ServerSideEncryption),
+			StorageClass:         profile.StorageClass,
+		}
+		if pType == objectstore.ProviderTypeFilesystem {
+			pc.Endpoint = FilesystemRoot(profile.Location)
+		}
+		provider, err := objectstore.NewProvider(ctx, pc, nil)
+		if err != nil {
+			return nil, err
+		}
+		return objectstore.GetOrCreateBucket(ctx, provider, profile.Location.Bucket)
+	}
 	pc := objectstore.ProviderConfig{
-		Type:          pType,
-		Endpoint:      profile.Location.Endpoint,
-		Region:        profile.Location.Region,
-		SkipSSLVerify: profile.SkipSSLVerify,
+		Type:                 pType,
+		Endpoint:             profile.Location.Endpoint,
+		Region:               profile.Location.Region,
+		SkipSSLVerify:        profile.SkipSSLVerify,
+		Transfer:             opts,
+		ObjectLock:           objectLockOptions(profile.ObjectLock),
+		ServerSideEncryption: serverSideEncryption(profile.ServerSideEncryption),
+		StorageClass:         profile.StorageClass,
 	}
-	secret, err := getOSSecret(ctx, pType, profile.Credential)
+	provider, err := objectstore.NewRenewingProvider(ctx, pc, &profileSecret{pType: pType, cred: profile.Credential})
 	if err != nil {
 		return nil, err
 	}
-	provider, err := objectstore.NewProvider(ctx, pc, secret)
+	return provider.GetBucket(ctx, profile.Location.Bucket)
+}
+
+func objectLockOptions(l *crv1alpha1.ObjectLock) *objectstore.ObjectLockOptions {
+	if l == nil {
+		return nil
+	}
+	return &objectstore.ObjectLockOptions{
+		Mode:      objectstore.ObjectLockMode(strings.ToUpper(string(l.Mode))),
+		Retention: l.Retention.Duration,
+	}
+}
+
+var sseTypes = map[crv1alpha1.ServerSideEncryptionType]objectstore.SSEType{
+	crv1alpha1.ServerSideEncryptionTypeS3:       objectstore.SSETypeS3,
+	crv1alpha1.ServerSideEncryptionTypeKMS:      objectstore.SSETypeKMS,
+	crv1alpha1.ServerSideEncryptionTypeCustomer: objectstore.SSETypeCustomer,
+}
+
+func serverSideEncryption(e *param.ServerSideEncryption) *objectstore.ServerSideEncryption {
+	if e == nil {
+		return nil
+	}
+	t, ok := sseTypes[e.Type]
+	if !ok {
+		// Unknown types are rejected by the object store
+		t = objectstore.SSEType(e.Type)
+	}
+	return &objectstore.ServerSideEncryption{
+		Type:            t,
+		KMSKeyID:        e.KMSKeyID,
+		CustomerKey:     e.CustomerKey,
+		EncryptionScope: e.EncryptionScope,
+	}
+}
+
+// profileSecret implements objectstore.SecretProvider for the credential of
+// a profile. Assumed role and workload identity credentials, as well as
+// credentials leased from a credential source, are renewed when the object
+// store asks for them again.
+type profileSecret struct {
+	pType objectstore.ProviderType
+	cred  param.Credential
+}
+
+var _ objectstore.SecretProvider = (*profileSecret)(nil)
+
+// Secret implements objectstore.SecretProvider.
+func (p *profileSecret) Secret(ctx context.Context) (*objectstore.Secret, time.Time, error) {
+	cred := p.cred
+	var expiration time.Time
+	if cred.Renewer != nil {
+		c, exp, err := cred.Renewer.Renew(ctx)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		cred, expiration = *c, exp
+	}
+	if p.pType != objectstore.ProviderTypeS3 {
+		if cred.Type == param.CredentialTypeWorkloadIdentity {
+			return workloadIdentitySecret(ctx, p.pType, cred)
+		}
+		secret, err := getOSSecret(ctx, p.pType, cred)
+		return secret, expiration, err
+	}
+	creds, err := getAWSCredentials(ctx, cred)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
-	return provider.GetBucket(ctx, profile.Location.Bucket)
+	secret, err := awsSecret(ctx, creds)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if exp, err := creds.ExpiresAt(); err == nil && (expiration.IsZero() || exp.Before(expiration)) {
+		expiration = exp
+	}
+	return secret, expiration, nil
 }
 
 func getOSSecret(ctx context.Context, pType objectstore.ProviderType, cred param.Credential) (*objectstore.Secret, error) {
+	if cred.Type == param.CredentialTypeWorkloadIdentity && pType != objectstore.ProviderTypeS3 {
+		secret, _, err := workloadIdentitySecret(ctx, pType, cred)
+		return secret, err
+	}
 	secret := &objectstore.Secret{}
 	switch pType {
 	case objectstore.ProviderTypeS3:
@@ -211,12 +569,23 @@ func getOSSecret(ctx context.Context, pType objectstore.ProviderType, cred param
 		}
 	case objectstore.ProviderTypeAzure:
 		return getAzureSecret(cred)
+	case objectstore.ProviderTypeSFTP:
+		return getSFTPSecret(cred)
 	default:
 		return nil, errkit.New(fmt.Sprintf("unknown or unsupported provider type '%s'", pType))
 	}
 	return secret, nil
 }
 
+// workloadIdentitySecret returns the object store credentials of a workload
+// identity and the time they expire.
+func workloadIdentitySecret(ctx context.Context, pType objectstore.ProviderType, cred param.Credential) (*objectstore.Secret, time.Time, error) {
+	if cred.WorkloadIdentity == nil {
+		return nil, time.Time{}, errkit.New("Workload identity cannot be nil")
+	}
+	return secrets.WorkloadIdentitySecret(ctx, pType, &cred.WorkloadIdentity.WorkloadIdentity, cred.WorkloadIdentity.TokenSource)
+}
+
 func getAzureSecret(cred param.Credential) (*objectstore.Secret, error) {
 	os := &objectstore.Secret{
 		Type: objectstore.SecretTypeAzStorageAccount,
@@ -238,29 +607,61 @@ func getAzureSecret(cred param.Credential) (*objectstore.Secret, error) {
 	return os, nil
 }
 
+func getSFTPSecret(cred param.Credential) (*objectstore.Secret, error) {
+	if cred.Type != param.CredentialTypeSecret {
+		return nil, errkit.New(fmt.Sprintf("Unsupported type '%s' for SFTP credential", cred.Type))
+	}
+	sftpSecret, err := secrets.ExtractSFTPCredentials(cred.Secret)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to extract SFTP credentials")
+	}
+	return &objectstore.Secret{
+		Type: objectstore.SecretTypeSFTP,
+		SFTP: sftpSecret,
+	}, nil
+}
+
 func getAWSSecret(ctx context.Context, cred param.Credential) (*objectstore.Secret, error) {
-	os := &objectstore.Secret{
-		Type: objectstore.SecretTypeAwsAccessKey,
+	creds, err := getAWSCredentials(ctx, cred)
+	if err != nil {
+		return nil, err
 	}
+	return awsSecret(ctx, creds)
+}
+
+// getAWSCredentials returns the AWS credentials of a profile. Assumed role
+// and workload identity credentials are renewed when they expire.
+func getAWSCredentials(ctx context.Context, cred param.Credential) (*credentials.Credentials, error) {
 	switch cred.Type {
 	case param.CredentialTypeKeyPair:
-		os.Aws = &objectstore.SecretAws{
-			AccessKeyID:     cred.KeyPair.ID,
-			SecretAccessKey: cred.KeyPair.Secret,
-		}
-		return os, nil
+		return credentials.NewStaticCredentials(cred.KeyPair.ID, cred.KeyPair.Secret, ""), nil
 	case param.CredentialTypeSecret:
-		creds, err := secrets.ExtractAWSCredentials(ctx, cred.Secret, aws.AssumeRoleDurationDefault)
-		if err != nil {
-			return nil, err
+		return secrets.AWSCredentials(ctx, cred.Secret, aws.AssumeRoleDurationDefault)
+	case param.CredentialTypeWorkloadIdentity:
+		if cred.WorkloadIdentity == nil {
+			return nil, errkit.New("Workload identity cannot be nil")
 		}
-		os.Aws = &objectstore.SecretAws{
-			AccessKeyID:     creds.AccessKeyID,
-			SecretAccessKey: creds.SecretAccessKey,
-			SessionToken:    creds.SessionToken,
+		ts := cred.WorkloadIdentity.TokenSource
+		if ts == nil {
+			ts = secrets.NewFileTokenSource(consts.WorkloadIdentityTokenFilePath)
 		}
-		return os, nil
+		return secrets.WorkloadIdentityAWSCredentials(&cred.WorkloadIdentity.WorkloadIdentity, ts)
 	default:
 		return nil, errkit.New(fmt.Sprintf("Unsupported type '%s' for credential", cred.Type))
 	}
 }
+
+func awsSecret(ctx context.Context, creds *credentials.Credentials) (*objectstore.Secret, error) {
+	val, err := creds.GetWithContext(ctx)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to get AWS credentials")
+	}
+	return &objectstore.Secret{
+		Type: objectstore.SecretTypeAwsAccessKey,
+		Aws: &objectstore.SecretAws{
+			AccessKeyID:     val.AccessKeyID,
+			SecretAccessKey: val.SecretAccessKey,
+			SessionToken:    val.SessionToken,
+		},
+	}, nil
+}
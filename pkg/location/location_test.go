@@ -17,19 +17,25 @@ package location
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"errors"
 	"io"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/kanisterio/errkit"
 	"gopkg.in/check.v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/ratelimit"
 	"github.com/kanisterio/kanister/pkg/secrets"
 	"github.com/kanisterio/kanister/pkg/testutil"
 	"github.com/kanisterio/kanister/pkg/utils/volumesnapshot"
@@ -130,19 +136,15 @@ func (s *LocationSuite) TearDownTest(c *check.C) {
 func (s *LocationSuite) TestWriteAndReadData(c *check.C) {
 	ctx := context.Background()
 	teststring := "test-content-check"
-	err := writeData(ctx, s.osType, s.profile, bytes.NewBufferString(teststring), s.testpath)
+	err := writeData(ctx, s.osType, s.profile, objectstore.TransferOptions{}, bytes.NewBufferString(teststring), s.testpath)
 	c.Check(err, check.IsNil)
 	buf := bytes.NewBuffer(nil)
-	err = readData(ctx, s.osType, s.profile, buf, s.testpath)
+	err = readData(ctx, s.osType, s.profile, objectstore.TransferOptions{}, buf, s.testpath)
 	c.Check(err, check.IsNil)
 	c.Check(buf.String(), check.Equals, teststring)
 }
 
-func (s *LocationSuite) TestAzMultipartUpload(c *check.C) {
-	if s.osType != objectstore.ProviderTypeAzure {
-		c.Skip(fmt.Sprintf("Not applicable for location type %s", s.osType))
-	}
-
+func (s *LocationSuite) TestMultipartUpload(c *check.C) {
 	// Create dir if not exists
 	_, err := os.Stat(s.suiteDirPrefix)
 	if os.IsNotExist(err) {
@@ -157,13 +159,16 @@ func (s *LocationSuite) TestAzMultipartUpload(c *check.C) {
 		c.Assert(err, check.IsNil)
 	}()
 	ctx := context.Background()
+	opts := objectstore.TransferOptions{
+		PartSize:    objectstore.MinPartSize,
+		Concurrency: 2,
+	}
 	for _, fileSize := range []int64{
-		0,                 // empty file
-		100 * 1024 * 1024, // 100M ie < buffSize
-		buffSize - 1,
-		buffSize,
-		buffSize + 1,
-		300 * 1024 * 1024, // 300M ie > buffSize
+		0, // empty file
+		opts.PartSize - 1,
+		opts.PartSize,
+		opts.PartSize + 1,
+		3*opts.PartSize + 7,
 	} {
 		_, err := f.Seek(0, io.SeekStart)
 		c.Assert(err, check.IsNil)
@@ -171,48 +176,15 @@ func (s *LocationSuite) TestAzMultipartUpload(c *check.C) {
 		// Create dump file
 		err = os.Truncate(s.testMultipartPath, fileSize)
 		c.Assert(err, check.IsNil)
-		err = writeData(ctx, s.osType, s.profile, f, s.testMultipartPath)
+		err = writeData(ctx, s.osType, s.profile, opts, f, s.testMultipartPath)
 		c.Check(err, check.IsNil)
 		buf := bytes.NewBuffer(nil)
-		err = readData(ctx, s.osType, s.profile, buf, s.testMultipartPath)
+		err = readData(ctx, s.osType, s.profile, opts, buf, s.testMultipartPath)
 		c.Check(err, check.IsNil)
 		c.Check(int64(buf.Len()), check.Equals, fileSize)
 	}
 }
 
-func (s *LocationSuite) TestReaderSize(c *check.C) {
-	for _, tc := range []struct {
-		input        string
-		buffSize     int64
-		expectedSize int64
-	}{
-		{
-			input:        "dummy-string-1",
-			buffSize:     4,
-			expectedSize: 1073741824, // buffSizeLimit       = 1 * 1024 * 1024 * 1024
-		},
-		{
-			input:        "dummy-string-1",
-			buffSize:     14,
-			expectedSize: 1073741824,
-		},
-		{
-			input:        "dummy-string-1",
-			buffSize:     44,
-			expectedSize: 0,
-		},
-		{
-			input:        "",
-			buffSize:     4,
-			expectedSize: 0,
-		},
-	} {
-		_, size, err := readerSize(bytes.NewBufferString(tc.input), tc.buffSize)
-		c.Assert(err, check.IsNil)
-		c.Assert(size, check.Equals, tc.expectedSize)
-	}
-}
-
 func (s *LocationSuite) TestGetAzureSecret(c *check.C) {
 	for _, tc := range []struct {
 		cred        param.Credential
@@ -320,3 +292,334 @@ func (s *LocationSuite) TestGetAzureSecret(c *check.C) {
 		}
 	}
 }
+
+type ProfileSecretSuite struct{}
+
+var _ = check.Suite(&ProfileSecretSuite{})
+
+type renewer struct {
+	cred       param.Credential
+	expiration time.Time
+	calls      int
+}
+
+func (r *renewer) Renew(ctx context.Context) (*param.Credential, time.Time, error) {
+	r.calls++
+	return &r.cred, r.expiration, nil
+}
+
+func (s *ProfileSecretSuite) TestProfileSecret(c *check.C) {
+	ctx := context.Background()
+	keyPair := param.Credential{
+		Type:    param.CredentialTypeKeyPair,
+		KeyPair: &param.KeyPair{ID: "id", Secret: "secret"},
+	}
+	exp := time.Now().Add(time.Hour)
+	r := &renewer{
+		cred: param.Credential{
+			Type:    param.CredentialTypeKeyPair,
+			KeyPair: &param.KeyPair{ID: "renewed-id", Secret: "renewed-secret"},
+		},
+		expiration: exp,
+	}
+	leased := keyPair
+	leased.Renewer = r
+
+	for _, tc := range []struct {
+		pType      objectstore.ProviderType
+		cred       param.Credential
+		id         string
+		expiration time.Time
+	}{
+		{pType: objectstore.ProviderTypeS3, cred: keyPair, id: "id"},
+		{pType: objectstore.ProviderTypeS3, cred: leased, id: "renewed-id", expiration: exp},
+		{pType: objectstore.ProviderTypeAzure, cred: leased, id: "renewed-id", expiration: exp},
+	} {
+		sec, expiration, err := (&profileSecret{pType: tc.pType, cred: tc.cred}).Secret(ctx)
+		c.Assert(err, check.IsNil)
+		c.Assert(expiration.Equal(tc.expiration), check.Equals, true)
+		switch tc.pType {
+		case objectstore.ProviderTypeS3:
+			c.Assert(sec.Aws.AccessKeyID, check.Equals, tc.id)
+		case objectstore.ProviderTypeAzure:
+			c.Assert(sec.Azure.StorageAccount, check.Equals, tc.id)
+		}
+	}
+	c.Assert(r.calls, check.Equals, 2)
+}
+
+type FilesystemLocationSuite struct{}
+
+var _ = check.Suite(&FilesystemLocationSuite{})
+
+func (s *FilesystemLocationSuite) TestWriteReadDelete(c *check.C) {
+	ctx := context.Background()
+	root := c.MkDir()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Bucket:   "backups",
+			Endpoint: root,
+			Prefix:   "prefix",
+		},
+	}
+	err := Write(ctx, bytes.NewBufferString("test-content-check"), profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	b, err := os.ReadFile(filepath.Join(root, "backups", "prefix", "data.txt"))
+	c.Assert(err, check.IsNil)
+	c.Assert(string(b), check.Equals, "test-content-check")
+
+	buf := bytes.NewBuffer(nil)
+	err = Read(ctx, buf, profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "test-content-check")
+
+	err = Delete(ctx, profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	_, err = os.Stat(filepath.Join(root, "backups", "prefix", "data.txt"))
+	c.Assert(os.IsNotExist(err), check.Equals, true)
+}
+
+func (s *FilesystemLocationSuite) TestFilesystemRoot(c *check.C) {
+	c.Assert(FilesystemRoot(crv1alpha1.Location{}), check.Equals, consts.FilesystemLocationMountPath)
+	c.Assert(FilesystemRoot(crv1alpha1.Location{Endpoint: "/data"}), check.Equals, "/data")
+}
+
+type MemoryLocationSuite struct{}
+
+var _ = check.Suite(&MemoryLocationSuite{})
+
+func (s *MemoryLocationSuite) TestWriteReadDelete(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+			Prefix:   "prefix",
+		},
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+
+	err := Write(ctx, bytes.NewBufferString("test-content-check"), profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	buf := bytes.NewBuffer(nil)
+	err = Read(ctx, buf, profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "test-content-check")
+
+	err = Delete(ctx, profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	err = Read(ctx, bytes.NewBuffer(nil), profile, "data.txt")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *MemoryLocationSuite) TestObjectLock(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+		},
+		ObjectLock: &crv1alpha1.ObjectLock{
+			Mode:      crv1alpha1.ObjectLockModeGovernance,
+			Retention: metav1.Duration{Duration: time.Hour},
+		},
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+
+	err := Write(ctx, bytes.NewBufferString("data"), profile, "backup/data")
+	c.Assert(err, check.IsNil)
+	err = Delete(ctx, profile, "backup")
+	c.Assert(err, check.ErrorMatches, ".*object 'backup/data' is retained in GOVERNANCE mode until .*")
+	var re *objectstore.RetentionError
+	c.Assert(errors.As(err, &re), check.Equals, true)
+	err = Read(ctx, bytes.NewBuffer(nil), profile, "backup/data")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *MemoryLocationSuite) TestServerSideEncryption(c *check.C) {
+	key := bytes.Repeat([]byte{1}, objectstore.SSECustomerKeySize)
+	c.Assert(serverSideEncryption(nil), check.IsNil)
+	c.Assert(serverSideEncryption(&param.ServerSideEncryption{
+		Type:     crv1alpha1.ServerSideEncryptionTypeKMS,
+		KMSKeyID: "alias/backups",
+	}), check.DeepEquals, &objectstore.ServerSideEncryption{
+		Type:     objectstore.SSETypeKMS,
+		KMSKeyID: "alias/backups",
+	})
+	c.Assert(serverSideEncryption(&param.ServerSideEncryption{
+		Type:        crv1alpha1.ServerSideEncryptionTypeCustomer,
+		CustomerKey: key,
+	}), check.DeepEquals, &objectstore.ServerSideEncryption{
+		Type:        objectstore.SSETypeCustomer,
+		CustomerKey: key,
+	})
+	c.Assert(serverSideEncryption(&param.ServerSideEncryption{
+		EncryptionScope: "backups",
+	}), check.DeepEquals, &objectstore.ServerSideEncryption{
+		EncryptionScope: "backups",
+	})
+
+	// Invalid configurations are rejected before anything is written
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+		},
+		ServerSideEncryption: &param.ServerSideEncryption{
+			Type:        crv1alpha1.ServerSideEncryptionTypeCustomer,
+			CustomerKey: key[1:],
+		},
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+	err := Write(context.Background(), bytes.NewBufferString("data"), profile, "data")
+	c.Assert(err, check.ErrorMatches, ".*SSE-C key must be 32 bytes.*")
+}
+
+func (s *MemoryLocationSuite) TestStatAndCopy(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+			Prefix:   "prefix",
+		},
+		Compression: crv1alpha1.CompressionTypeGzip,
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+	archive := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Bucket:   "archive",
+			Endpoint: c.MkDir(),
+		},
+	}
+
+	err := Write(ctx, bytes.NewBufferString("test-content-check"), profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	info, err := Stat(ctx, profile, "data.txt")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.Size > 0, check.Equals, true)
+	c.Assert(info.Tags[CompressionTag], check.Equals, string(crv1alpha1.CompressionTypeGzip))
+
+	// Copies stay compressed and are read like the artifact
+	for _, dst := range []param.Profile{profile, archive} {
+		err = Copy(ctx, profile, "data.txt", dst, "copy/data.txt", objectstore.TransferOptions{})
+		c.Assert(err, check.IsNil)
+		buf := bytes.NewBuffer(nil)
+		err = Read(ctx, buf, dst, "copy/data.txt")
+		c.Assert(err, check.IsNil)
+		c.Assert(buf.String(), check.Equals, "test-content-check")
+	}
+
+	_, err = Stat(ctx, profile, "missing")
+	c.Assert(err, check.NotNil)
+}
+
+// failingReaderAt fails reads after the first part.
+type failingReaderAt struct {
+	*bytes.Reader
+}
+
+func (r failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= objectstore.MinPartSize {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return r.Reader.ReadAt(p, off)
+}
+
+func (s *MemoryLocationSuite) TestRateLimit(c *check.C) {
+	ctx := context.Background()
+	rl := &crv1alpha1.RateLimit{BytesPerSecond: 48 << 10, Burst: 8 << 10}
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+		},
+		RateLimit: rl,
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+	lim := &fakeLimiter{}
+	defer func(f func(*crv1alpha1.RateLimit) ratelimit.Limiter) { newLimiter = f }(newLimiter)
+	newLimiter = func(l *crv1alpha1.RateLimit) ratelimit.Limiter {
+		c.Assert(l, check.DeepEquals, rl)
+		lim.burst = int(l.Burst)
+		return lim
+	}
+	data := bytes.Repeat([]byte("x"), 32<<10)
+
+	// Streams, files and reads are throttled
+	for _, in := range []io.Reader{bytes.NewBuffer(data), bytes.NewReader(data)} {
+		lim.waited = 0
+		c.Assert(Write(ctx, in, profile, "data"), check.IsNil)
+		c.Assert(lim.waited, check.Equals, len(data))
+	}
+	lim.waited = 0
+	buf := bytes.NewBuffer(nil)
+	c.Assert(Read(ctx, buf, profile, "data"), check.IsNil)
+	c.Assert(lim.waited, check.Equals, len(data))
+	c.Assert(buf.Bytes(), check.DeepEquals, data)
+}
+
+// fakeLimiter counts the bytes that are waited for instead of waiting.
+type fakeLimiter struct {
+	mu     sync.Mutex
+	burst  int
+	waited int
+}
+
+func (l *fakeLimiter) WaitN(ctx context.Context, n int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n > l.burst {
+		return errkit.New("Wait exceeds the burst of the limiter")
+	}
+	l.waited += n
+	return ctx.Err()
+}
+
+func (l *fakeLimiter) Burst() int {
+	return l.burst
+}
+
+func (s *MemoryLocationSuite) TestResumableWrite(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+		},
+	}
+	defer objectstore.DeleteMemoryStore(profile.Location.Endpoint)
+	opts := objectstore.TransferOptions{PartSize: objectstore.MinPartSize, Concurrency: 1}
+	data := bytes.Repeat([]byte("x"), 2*objectstore.MinPartSize)
+
+	// Files are resumable, streams are not
+	_, _, ok := resumableSource(bytes.NewBuffer(data))
+	c.Assert(ok, check.Equals, false)
+	_, size, ok := resumableSource(bytes.NewReader(data))
+	c.Assert(ok, check.Equals, true)
+	c.Assert(size, check.Equals, int64(len(data)))
+	err := WriteWithOptions(ctx, bytes.NewReader(data), profile, "complete", opts)
+	c.Assert(err, check.IsNil)
+
+	// Deleting an interrupted upload deletes its state
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, profile, opts)
+	c.Assert(err, check.IsNil)
+	err = objectstore.PutResumable(ctx, bucket, "interrupted", failingReaderAt{bytes.NewReader(data)}, int64(len(data)), nil)
+	c.Assert(err, check.NotNil)
+	objs, err := bucket.ListObjects(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(objs, check.DeepEquals, []string{"complete", "interrupted" + objectstore.UploadStateSuffix})
+	err = Delete(ctx, profile, "interrupted")
+	c.Assert(err, check.IsNil)
+	objs, err = bucket.ListObjects(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(objs, check.DeepEquals, []string{"complete"})
+}
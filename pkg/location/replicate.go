@@ -0,0 +1,190 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path/filepath"
+
+	"github.com/kanisterio/errkit"
+
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// Replicate copies the artifact at the location specified by `src` and
+// `srcSuffix` to the location specified by `dst` and `dstSuffix`, like Copy,
+// and verifies that both have the same data. It returns the SHA-256 of the
+// data as it is stored.
+//
+// The checksums that the object stores computed are compared if both have
+// them, so that the copy does not have to be read. Otherwise the copy is read
+// once and verified with the checksum stored with the artifact. Only
+// artifacts that were written before checksums were stored are read from
+// both locations.
+func Replicate(ctx context.Context, src param.Profile, srcSuffix string, dst param.Profile, dstSuffix string, opts objectstore.TransferOptions) (string, error) {
+	if err := Copy(ctx, src, srcSuffix, dst, dstSuffix, opts); err != nil {
+		return "", err
+	}
+	srcInfo, err := statLocation(ctx, src, srcSuffix)
+	if err != nil {
+		return "", err
+	}
+	dstType, err := getProviderType(dst.Location.Type)
+	if err != nil {
+		return "", err
+	}
+	dstBucket, err := getBucket(ctx, dstType, dst, opts)
+	if err != nil {
+		return "", err
+	}
+	dstPath := filepath.Join(dst.Location.Prefix, dstSuffix)
+	dstInfo, err := dstBucket.Stat(ctx, dstPath)
+	if err != nil {
+		return "", err
+	}
+	if dstInfo.Tags, err = withStoredChecksum(ctx, dstBucket, dstPath, dstInfo.Tags); err != nil {
+		return "", err
+	}
+	matched, err := compareNativeChecksums(srcInfo, dstInfo, dstPath, dst.Location.Bucket)
+	if err != nil {
+		return "", err
+	}
+	sum, stored := tagValue(dstInfo.Tags, ChecksumTag)
+	switch {
+	case matched && stored:
+		return sum, nil
+	case stored || dstInfo.MD5 != "" || dstInfo.CRC32C != "":
+		return verifyData(ctx, dstBucket, dstInfo, dstPath, dst)
+	}
+	return replicaChecksum(ctx, src, srcSuffix, dst, dstSuffix, opts)
+}
+
+// compareNativeChecksums compares the checksums that the object stores
+// computed for an artifact and its copy. It reports whether any of them
+// could be compared.
+func compareNativeChecksums(src, dst objectstore.ObjectInfo, path, bucket string) (bool, error) {
+	matched := false
+	for _, cs := range []struct {
+		algorithm string
+		expected  string
+		actual    string
+	}{
+		{checksumMD5, src.MD5, dst.MD5},
+		{checksumCRC32C, src.CRC32C, dst.CRC32C},
+	} {
+		if cs.expected == "" || cs.actual == "" {
+			continue
+		}
+		if cs.expected != cs.actual {
+			return false, &ChecksumMismatchError{
+				Path:      path,
+				Bucket:    bucket,
+				Algorithm: cs.algorithm,
+				Expected:  cs.expected,
+				Actual:    cs.actual,
+			}
+		}
+		matched = true
+	}
+	return matched, nil
+}
+
+// replicaChecksum reads an artifact without checksums and its copy and
+// returns the SHA-256 of their data if they are the same.
+func replicaChecksum(ctx context.Context, src param.Profile, srcSuffix string, dst param.Profile, dstSuffix string, opts objectstore.TransferOptions) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		sum string
+		err error
+	}
+	srcSum := make(chan result, 1)
+	go func() {
+		sum, err := storedChecksum(ctx, src, srcSuffix, opts)
+		srcSum <- result{sum: sum, err: err}
+	}()
+	dstSum, err := storedChecksum(ctx, dst, dstSuffix, opts)
+	if err != nil {
+		return "", err
+	}
+	sr := <-srcSum
+	if sr.err != nil {
+		return "", sr.err
+	}
+	if sr.sum != dstSum {
+		return "", &ChecksumMismatchError{
+			Path:      filepath.Join(dst.Location.Prefix, dstSuffix),
+			Bucket:    dst.Location.Bucket,
+			Algorithm: checksumSHA256,
+			Expected:  sr.sum,
+			Actual:    dstSum,
+		}
+	}
+	return dstSum, nil
+}
+
+// ReplicateFrom writes the data of `in` to the location specified by `dst`
+// and `dstSuffix`, like WriteWithOptions, and verifies that the data that is
+// read back from the location is the data that was written. It returns the
+// SHA-256 of the data.
+func ReplicateFrom(ctx context.Context, in io.Reader, dst param.Profile, dstSuffix string, opts objectstore.TransferOptions) (string, error) {
+	h := sha256.New()
+	if err := WriteWithOptions(ctx, io.TeeReader(in, h), dst, dstSuffix, opts); err != nil {
+		return "", err
+	}
+	expected := hex.EncodeToString(h.Sum(nil))
+	h.Reset()
+	if err := ReadWithOptions(ctx, h, dst, dstSuffix, opts); err != nil {
+		return "", err
+	}
+	if replicated := hex.EncodeToString(h.Sum(nil)); replicated != expected {
+		return "", &ChecksumMismatchError{
+			Path:      filepath.Join(dst.Location.Prefix, dstSuffix),
+			Bucket:    dst.Location.Bucket,
+			Algorithm: checksumSHA256,
+			Expected:  expected,
+			Actual:    replicated,
+		}
+	}
+	return expected, nil
+}
+
+// storedChecksum returns the SHA-256 of the data of an artifact as it is
+// stored, i.e. compressed and encrypted.
+func storedChecksum(ctx context.Context, profile param.Profile, suffix string, opts objectstore.TransferOptions) (string, error) {
+	osType, err := getProviderType(profile.Location.Type)
+	if err != nil {
+		return "", err
+	}
+	bucket, err := getBucket(ctx, osType, profile, opts)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(profile.Location.Prefix, suffix)
+	rc, _, err := bucket.Get(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close() //nolint:errcheck
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", errkit.Wrap(err, "Failed to read artifact", "path", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
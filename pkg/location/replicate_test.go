@@ -0,0 +1,135 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type ReplicateSuite struct{}
+
+var _ = check.Suite(&ReplicateSuite{})
+
+func (s *ReplicateSuite) TearDownTest(c *check.C) {
+	objectstore.DeleteMemoryStore(c.TestName())
+}
+
+func (s *ReplicateSuite) profiles(c *check.C) (param.Profile, param.Profile) {
+	src := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "primary",
+			Endpoint: c.TestName(),
+			Prefix:   "backups",
+		},
+		Compression: crv1alpha1.CompressionTypeZstd,
+	}
+	dst := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeFilesystem,
+			Bucket:   "dr",
+			Endpoint: c.MkDir(),
+			Prefix:   "replicas",
+		},
+	}
+	return src, dst
+}
+
+func (s *ReplicateSuite) TestReplicate(c *check.C) {
+	ctx := context.Background()
+	src, dst := s.profiles(c)
+	err := Write(ctx, bytes.NewBufferString("test-content-check"), src, "dump.sql")
+	c.Assert(err, check.IsNil)
+
+	for _, p := range []param.Profile{src, dst} {
+		sum, err := Replicate(ctx, src, "dump.sql", p, "copy/dump.sql", objectstore.TransferOptions{})
+		c.Assert(err, check.IsNil)
+		stored, err := storedChecksum(ctx, src, "dump.sql", objectstore.TransferOptions{})
+		c.Assert(err, check.IsNil)
+		c.Assert(sum, check.Equals, stored)
+
+		buf := bytes.NewBuffer(nil)
+		err = Read(ctx, buf, p, "copy/dump.sql")
+		c.Assert(err, check.IsNil)
+		c.Assert(buf.String(), check.Equals, "test-content-check")
+	}
+
+	_, err = Replicate(ctx, src, "missing", dst, "missing", objectstore.TransferOptions{})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *ReplicateSuite) TestReplicateFrom(c *check.C) {
+	ctx := context.Background()
+	_, dst := s.profiles(c)
+	dst.Compression = crv1alpha1.CompressionTypeGzip
+	sum, err := ReplicateFrom(ctx, bytes.NewBufferString("test-content-check"), dst, "dump.sql", objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	expected := sha256.Sum256([]byte("test-content-check"))
+	c.Assert(sum, check.Equals, hex.EncodeToString(expected[:]))
+}
+
+func (s *ReplicateSuite) TestReplicateWithoutChecksums(c *check.C) {
+	ctx := context.Background()
+	src, dst := s.profiles(c)
+	// Artifacts written before checksums were stored have no checksum tag
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, src, objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	err = bucket.Put(ctx, "backups/dump.sql", bytes.NewBufferString("test-content-check"), 0, nil)
+	c.Assert(err, check.IsNil)
+
+	sum, err := Replicate(ctx, src, "dump.sql", dst, "dump.sql", objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	expected := sha256.Sum256([]byte("test-content-check"))
+	c.Assert(sum, check.Equals, hex.EncodeToString(expected[:]))
+}
+
+func (s *ReplicateSuite) TestCompareNativeChecksums(c *check.C) {
+	for _, tc := range []struct {
+		src, dst objectstore.ObjectInfo
+		matched  bool
+		checker  check.Checker
+	}{
+		{
+			src:     objectstore.ObjectInfo{MD5: "a", CRC32C: "b"},
+			dst:     objectstore.ObjectInfo{MD5: "a", CRC32C: "b"},
+			matched: true,
+			checker: check.IsNil,
+		},
+		{
+			// Checksums that only one of the object stores computed are skipped
+			src:     objectstore.ObjectInfo{MD5: "a"},
+			dst:     objectstore.ObjectInfo{CRC32C: "b"},
+			checker: check.IsNil,
+		},
+		{
+			src:     objectstore.ObjectInfo{CRC32C: "b"},
+			dst:     objectstore.ObjectInfo{MD5: "a", CRC32C: "c"},
+			checker: check.NotNil,
+		},
+	} {
+		matched, err := compareNativeChecksums(tc.src, tc.dst, "dump.sql", "dr")
+		c.Check(err, tc.checker)
+		c.Check(matched, check.Equals, tc.matched)
+	}
+}
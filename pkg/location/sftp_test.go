@@ -0,0 +1,107 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore/sftptest"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/secrets"
+)
+
+type SFTPSuite struct {
+	root   string
+	server *sftptest.Server
+}
+
+var _ = check.Suite(&SFTPSuite{})
+
+func (s *SFTPSuite) SetUpTest(c *check.C) {
+	var err error
+	s.root = c.MkDir()
+	c.Assert(os.Mkdir(filepath.Join(s.root, "backups"), 0o755), check.IsNil)
+	s.server, err = sftptest.NewServer(s.root)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *SFTPSuite) TearDownTest(c *check.C) {
+	c.Assert(s.server.Close(), check.IsNil)
+}
+
+func (s *SFTPSuite) profile(hostKey string) param.Profile {
+	return param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeSFTP,
+			Bucket:   "backups",
+			Endpoint: "sftp://" + s.server.Addr + s.root,
+			Prefix:   "prefix",
+		},
+		Credential: param.Credential{
+			Type: param.CredentialTypeSecret,
+			Secret: &corev1.Secret{
+				Type: corev1.SecretType(secrets.SFTPSecretType),
+				Data: map[string][]byte{
+					secrets.SFTPUsername: []byte(sftptest.Username),
+					secrets.SFTPPassword: []byte(sftptest.Password),
+					secrets.SFTPHostKey:  []byte(hostKey),
+				},
+			},
+		},
+	}
+}
+
+func (s *SFTPSuite) TestWriteReadDelete(c *check.C) {
+	ctx := context.Background()
+	profile := s.profile(s.server.HostKey)
+	data := []byte("test-content-check")
+
+	// Streams and files are written the same way
+	err := Write(ctx, bytes.NewBuffer(data), profile, "stream")
+	c.Assert(err, check.IsNil)
+	err = Write(ctx, bytes.NewReader(data), profile, "file")
+	c.Assert(err, check.IsNil)
+	for _, suffix := range []string{"stream", "file"} {
+		stored, err := os.ReadFile(filepath.Join(s.root, "backups", "prefix", suffix))
+		c.Assert(err, check.IsNil)
+		c.Assert(stored, check.DeepEquals, data)
+
+		buf := &bytes.Buffer{}
+		c.Assert(Read(ctx, buf, profile, suffix), check.IsNil)
+		c.Assert(buf.Bytes(), check.DeepEquals, data)
+		_, err = Verify(ctx, profile, suffix)
+		c.Assert(err, check.IsNil)
+
+		c.Assert(Delete(ctx, profile, suffix), check.IsNil)
+		_, err = os.Stat(filepath.Join(s.root, "backups", "prefix", suffix))
+		c.Assert(os.IsNotExist(err), check.Equals, true)
+	}
+}
+
+func (s *SFTPSuite) TestHostKeyMismatch(c *check.C) {
+	other, err := sftptest.NewServer(c.MkDir())
+	c.Assert(err, check.IsNil)
+	defer other.Close() //nolint:errcheck
+
+	err = Write(context.Background(), bytes.NewBufferString("data"), s.profile(other.HostKey), "artifact")
+	c.Assert(err, check.ErrorMatches, ".*unknown host key.*")
+}
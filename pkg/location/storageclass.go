@@ -0,0 +1,69 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"context"
+	"time"
+
+	"github.com/kanisterio/errkit"
+
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// restorePollInterval is how often it is checked whether archived artifacts
+// were restored.
+var restorePollInterval = time.Minute
+
+// waitForRestore restores the artifact at `path` if `class` is an archive
+// storage class and waits until it can be read, which can take hours. The
+// restore continues if the context is canceled, so that waiting for it can be
+// resumed later.
+func waitForRestore(ctx context.Context, bucket objectstore.Bucket, pType objectstore.ProviderType, class, path string) error {
+	if !objectstore.ArchiveStorageClass(pType, class) {
+		return nil
+	}
+	for polls := 0; ; polls++ {
+		ok, err := bucket.Restore(ctx, path, objectstore.RestoreOptions{})
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if polls == 0 {
+			log.Info().WithContext(ctx).Print("Waiting for archived artifact to be restored", field.M{"path": path})
+		}
+		select {
+		case <-ctx.Done():
+			return errkit.Wrap(ctx.Err(), "Archived artifact was not restored in time", "path", path)
+		case <-time.After(restorePollInterval):
+		}
+	}
+}
+
+// checksumBucket returns the bucket that the checksums of artifacts that are
+// written to `bucket` are stored in. Checksums are not archived with the
+// artifacts, so that they can be read without restoring them.
+func checksumBucket(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, opts objectstore.TransferOptions, bucket objectstore.Bucket) (objectstore.Bucket, error) {
+	if !objectstore.ArchiveStorageClass(pType, profile.StorageClass) {
+		return bucket, nil
+	}
+	profile.StorageClass = ""
+	return getBucket(ctx, pType, profile, opts)
+}
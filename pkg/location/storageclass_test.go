@@ -0,0 +1,112 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type StorageClassSuite struct {
+	interval time.Duration
+}
+
+var _ = check.Suite(&StorageClassSuite{})
+
+func (s *StorageClassSuite) SetUpSuite(c *check.C) {
+	s.interval, restorePollInterval = restorePollInterval, time.Millisecond
+}
+
+func (s *StorageClassSuite) TearDownSuite(c *check.C) {
+	restorePollInterval = s.interval
+}
+
+func (s *StorageClassSuite) TearDownTest(c *check.C) {
+	objectstore.DeleteMemoryStore(c.TestName())
+}
+
+func (s *StorageClassSuite) TestArchivedArtifacts(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+			Prefix:   "prefix",
+		},
+		StorageClass: "GLACIER",
+	}
+
+	// Streams and files end up archived with their checksum
+	err := Write(ctx, bytes.NewBufferString("test-content-check"), profile, "stream")
+	c.Assert(err, check.IsNil)
+	err = Write(ctx, bytes.NewReader([]byte("test-content-check")), profile, "file")
+	c.Assert(err, check.IsNil)
+	for _, suffix := range []string{"stream", "file"} {
+		info, err := Stat(ctx, profile, suffix)
+		c.Assert(err, check.IsNil)
+		c.Assert(info.StorageClass, check.Equals, "GLACIER")
+		c.Assert(info.Tags[ChecksumTag], check.Not(check.Equals), "")
+	}
+	// Checksums of streams are not archived, so they are read without restores
+	bucket, err := getBucket(ctx, objectstore.ProviderTypeMemory, profile, objectstore.TransferOptions{})
+	c.Assert(err, check.IsNil)
+	info, err := bucket.Stat(ctx, checksumObject("prefix/stream"))
+	c.Assert(err, check.IsNil)
+	c.Assert(info.StorageClass, check.Equals, "")
+
+	// Reads wait until artifacts are restored
+	buf := bytes.NewBuffer(nil)
+	err = Read(ctx, buf, profile, "stream")
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "test-content-check")
+	_, err = Verify(ctx, profile, "file")
+	c.Assert(err, check.IsNil)
+
+	err = Write(ctx, bytes.NewBufferString("test-content-check"), profile, "canceled")
+	c.Assert(err, check.IsNil)
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = Read(cctx, bytes.NewBuffer(nil), profile, "canceled")
+	c.Assert(err, check.ErrorMatches, ".*Archived artifact was not restored in time.*")
+}
+
+func (s *StorageClassSuite) TestStorageClass(c *check.C) {
+	ctx := context.Background()
+	profile := param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     LocationTypeMemory,
+			Bucket:   "backups",
+			Endpoint: c.TestName(),
+		},
+		StorageClass: "GLACIER_IR",
+	}
+	err := Write(ctx, bytes.NewBufferString("data"), profile, "data")
+	c.Assert(err, check.IsNil)
+	info, err := Stat(ctx, profile, "data")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.StorageClass, check.Equals, "GLACIER_IR")
+
+	profile.StorageClass = "Archive"
+	err = Write(ctx, bytes.NewBufferString("data"), profile, "data")
+	c.Assert(err, check.ErrorMatches, ".*Unsupported storage class 'Archive'.*")
+}
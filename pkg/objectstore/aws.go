@@ -22,6 +22,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/kanisterio/errkit"
 
@@ -75,3 +76,27 @@ func awsConfig(ctx context.Context, pc ProviderConfig, s SecretAws) (*aws.Config
 
 	return cfg, r, nil
 }
+
+// s3ClientConfig returns the configuration of an S3 client that accesses the
+// object store with creds.
+func s3ClientConfig(pc ProviderConfig, creds *credentials.Credentials) *aws.Config {
+	r := defaultS3region
+	if pc.Region != "" {
+		r = s3.NormalizeBucketLocation(pc.Region)
+	}
+	cfg := aws.NewConfig().WithCredentials(creds).WithRegion(r)
+	if pc.Endpoint != "" {
+		cfg = cfg.WithEndpoint(pc.Endpoint).WithS3ForcePathStyle(true)
+	}
+	if pc.SkipSSLVerify {
+		h := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+				},
+			},
+		}
+		cfg = cfg.WithHTTPClient(h)
+	}
+	return cfg
+}
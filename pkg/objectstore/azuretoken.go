@@ -0,0 +1,428 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Stow location for Azure storage accounts that are accessed with Azure AD
+// access tokens, such as the tokens of workload identities, since the Azure
+// location of stow only supports storage account keys. Containers are
+// accessed with user delegation SAS, which the clients of blobs support.
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	az "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/graymeta/stow"
+	stowaz "github.com/graymeta/stow/azure"
+	"github.com/kanisterio/errkit"
+)
+
+const (
+	azureTokenKind            = "kanister-azure-token"
+	azureTokenConfigToken     = "token"
+	azureTokenConfigExpiresOn = "expiresOn"
+	azureTokenConfigEndpoint  = "endpoint"
+)
+
+// azureTokenPermissions are the permissions of the SAS of containers.
+var azureTokenPermissions = (&sas.ContainerPermissions{
+	Read:   true,
+	Add:    true,
+	Create: true,
+	Write:  true,
+	Delete: true,
+	List:   true,
+}).String()
+
+func init() {
+	stow.Register(azureTokenKind, dialAzureToken, func(u *url.URL) bool {
+		return u.Scheme == azureTokenKind
+	}, func(cfg stow.Config) error {
+		_, err := azureTokenLocationConfig(cfg)
+		return err
+	})
+}
+
+func azureTokenConfig(config ProviderConfig, secret *SecretAzure) (stowKind string, stowConfig stow.Config, err error) {
+	if secret.AccessTokenExpiresOn.IsZero() {
+		return "", nil, errkit.New("Expiration of Azure access token not set")
+	}
+	return azureTokenKind, stow.ConfigMap{
+		stowaz.ConfigAccount:      secret.StorageAccount,
+		stowaz.ConfigEnvName:      secret.EnvironmentName,
+		azureTokenConfigToken:     secret.AccessToken,
+		azureTokenConfigExpiresOn: secret.AccessTokenExpiresOn.Format(time.RFC3339),
+		azureTokenConfigEndpoint:  config.Endpoint,
+	}, nil
+}
+
+func azureTokenLocationConfig(cfg stow.Config) (*azureTokenLocation, error) {
+	account, _ := cfg.Config(stowaz.ConfigAccount)
+	if account == "" {
+		return nil, errkit.New("Azure storage account not set")
+	}
+	token, _ := cfg.Config(azureTokenConfigToken)
+	if token == "" {
+		return nil, errkit.New("Azure access token not set")
+	}
+	exp, _ := cfg.Config(azureTokenConfigExpiresOn)
+	expiresOn, err := time.Parse(time.RFC3339, exp)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Invalid expiration of Azure access token")
+	}
+	endpoint, _ := cfg.Config(azureTokenConfigEndpoint)
+	envName, _ := cfg.Config(stowaz.ConfigEnvName)
+	serviceURL, err := azureServiceURL(endpoint, account, envName)
+	if err != nil {
+		return nil, err
+	}
+	return &azureTokenLocation{
+		serviceURL: serviceURL,
+		token:      azcore.AccessToken{Token: token, ExpiresOn: expiresOn},
+	}, nil
+}
+
+func dialAzureToken(cfg stow.Config) (stow.Location, error) {
+	l, err := azureTokenLocationConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	l.svc, err = service.NewClient(l.serviceURL, azureStaticToken{token: l.token}, nil)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create Azure client")
+	}
+	return l, nil
+}
+
+var _ azcore.TokenCredential = azureStaticToken{}
+
+// azureStaticToken implements azcore.TokenCredential with an access token
+// that is renewed by dialing the location again.
+type azureStaticToken struct {
+	token azcore.AccessToken
+}
+
+// GetToken implements azcore.TokenCredential.
+func (t azureStaticToken) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return t.token, nil
+}
+
+var _ stow.Location = (*azureTokenLocation)(nil)
+
+// azureTokenLocation implements stow.Location for a storage account that is
+// accessed with an access token.
+type azureTokenLocation struct {
+	serviceURL string
+	token      azcore.AccessToken
+	svc        *service.Client
+
+	// The user delegation key signs the SAS of all containers. It expires
+	// with the access token.
+	mu  sync.Mutex
+	udc *service.UserDelegationCredential
+}
+
+func (l *azureTokenLocation) Close() error {
+	return nil
+}
+
+func (l *azureTokenLocation) CreateContainer(name string) (stow.Container, error) {
+	_, err := l.svc.CreateContainer(context.Background(), name, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return nil, err
+	}
+	return l.container(name)
+}
+
+func (l *azureTokenLocation) Containers(prefix string, cursor string, count int) ([]stow.Container, string, error) {
+	opts := &service.ListContainersOptions{MaxResults: to.Ptr(int32(count))}
+	if prefix != "" {
+		opts.Prefix = to.Ptr(prefix)
+	}
+	if cursor != stow.CursorStart {
+		opts.Marker = to.Ptr(cursor)
+	}
+	page, err := l.svc.NewListContainersPager(opts).NextPage(context.Background())
+	if err != nil {
+		return nil, "", err
+	}
+	containers := make([]stow.Container, 0, len(page.ContainerItems))
+	for _, ci := range page.ContainerItems {
+		c, err := l.container(*ci.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		containers = append(containers, c)
+	}
+	var next string
+	if page.NextMarker != nil {
+		next = *page.NextMarker
+	}
+	return containers, next, nil
+}
+
+func (l *azureTokenLocation) Container(id string) (stow.Container, error) {
+	_, err := l.svc.NewContainerClient(id).GetProperties(context.Background(), nil)
+	if bloberror.HasCode(err, bloberror.ContainerNotFound) {
+		return nil, stow.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.container(id)
+}
+
+func (l *azureTokenLocation) RemoveContainer(id string) error {
+	_, err := l.svc.DeleteContainer(context.Background(), id, nil)
+	return err
+}
+
+func (l *azureTokenLocation) ItemByURL(u *url.URL) (stow.Item, error) {
+	c, err := l.Container(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(strings.TrimPrefix(u.Path, "/"))
+}
+
+// container returns the container with a SAS that is valid until the access
+// token expires.
+func (l *azureTokenLocation) container(id string) (*azureTokenContainer, error) {
+	udc, err := l.userDelegationCredential()
+	if err != nil {
+		return nil, err
+	}
+	qp, err := sas.BlobSignatureValues{
+		StartTime:     time.Now().Add(-azureClockSkew),
+		ExpiryTime:    l.token.ExpiresOn,
+		Permissions:   azureTokenPermissions,
+		ContainerName: id,
+	}.SignWithUserDelegation(udc)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to sign SAS of container", "container", id)
+	}
+	return &azureTokenContainer{
+		id:         id,
+		serviceURL: l.serviceURL,
+		sas:        qp.Encode(),
+		svc:        l.svc,
+	}, nil
+}
+
+// azureClockSkew is subtracted from the start time of keys and SAS, since
+// they are rejected by storage accounts whose clocks are behind.
+const azureClockSkew = 5 * time.Minute
+
+func (l *azureTokenLocation) userDelegationCredential() (*service.UserDelegationCredential, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.udc != nil {
+		return l.udc, nil
+	}
+	info := service.KeyInfo{
+		Start:  to.Ptr(time.Now().Add(-azureClockSkew).UTC().Format(sas.TimeFormat)),
+		Expiry: to.Ptr(l.token.ExpiresOn.UTC().Format(sas.TimeFormat)),
+	}
+	udc, err := l.svc.GetUserDelegationCredential(context.Background(), info, nil)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to get user delegation key")
+	}
+	l.udc = udc
+	return udc, nil
+}
+
+var _ stow.Container = (*azureTokenContainer)(nil)
+
+// azureTokenContainer implements stow.Container with the blob clients of the
+// object store, which authenticate with the SAS of the container.
+type azureTokenContainer struct {
+	id         string
+	serviceURL string
+	sas        string
+	svc        *service.Client
+}
+
+func (c *azureTokenContainer) ID() string {
+	return c.id
+}
+
+func (c *azureTokenContainer) Name() string {
+	return c.id
+}
+
+// blobContainer returns a reference to the container whose requests add the
+// given headers.
+func (c *azureTokenContainer) blobContainer(headers map[string]string) (*az.Container, error) {
+	client, err := az.NewAccountSASClientFromEndpointToken(c.serviceURL, c.sas)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create Azure client")
+	}
+	client.AddAdditionalHeaders(headers)
+	svc := client.GetBlobService()
+	return svc.GetContainerReference(c.id), nil
+}
+
+func (c *azureTokenContainer) Item(id string) (stow.Item, error) {
+	bc, err := c.blobContainer(nil)
+	if err != nil {
+		return nil, err
+	}
+	blob := bc.GetBlobReference(id)
+	if err := blob.GetProperties(nil); err != nil {
+		var aerr az.AzureStorageServiceError
+		if errors.As(err, &aerr) && aerr.StatusCode == http.StatusNotFound {
+			return nil, stow.ErrNotFound
+		}
+		return nil, err
+	}
+	return newAzureTokenItem(c, blob.Name, blob.Properties, blob.Metadata), nil
+}
+
+func (c *azureTokenContainer) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	bc, err := c.blobContainer(nil)
+	if err != nil {
+		return nil, "", err
+	}
+	res, err := bc.ListBlobs(az.ListBlobsParameters{
+		Prefix:     prefix,
+		Marker:     cursor,
+		MaxResults: uint(count),
+		Include:    &az.IncludeBlobDataset{Metadata: true},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	items := make([]stow.Item, 0, len(res.Blobs))
+	for _, blob := range res.Blobs {
+		items = append(items, newAzureTokenItem(c, blob.Name, blob.Properties, blob.Metadata))
+	}
+	return items, res.NextMarker, nil
+}
+
+func (c *azureTokenContainer) RemoveItem(id string) error {
+	bc, err := c.blobContainer(nil)
+	if err != nil {
+		return err
+	}
+	return bc.GetBlobReference(id).Delete(nil)
+}
+
+func (c *azureTokenContainer) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
+	bc, err := c.blobContainer(nil)
+	if err != nil {
+		return nil, err
+	}
+	// Stow replaces spaces in blob names
+	blob := bc.GetBlobReference(strings.ReplaceAll(name, " ", "+"))
+	blob.Metadata = stringTags(metadata)
+	if err := blob.CreateBlockBlobFromReader(r, nil); err != nil {
+		return nil, err
+	}
+	return c.Item(blob.Name)
+}
+
+var (
+	_ stow.Item       = (*azureTokenItem)(nil)
+	_ stow.ItemRanger = (*azureTokenItem)(nil)
+)
+
+// azureTokenItem implements stow.Item for a blob of an azureTokenContainer.
+type azureTokenItem struct {
+	container  *azureTokenContainer
+	name       string
+	properties az.BlobProperties
+	metadata   map[string]interface{}
+}
+
+func newAzureTokenItem(c *azureTokenContainer, name string, props az.BlobProperties, md az.BlobMetadata) *azureTokenItem {
+	metadata := make(map[string]interface{}, len(md))
+	for k, v := range md {
+		metadata[k] = v
+	}
+	// ETags are returned in quotes, unlike the ETags of stow
+	props.Etag = strings.Trim(props.Etag, `"`)
+	return &azureTokenItem{
+		container:  c,
+		name:       name,
+		properties: props,
+		metadata:   metadata,
+	}
+}
+
+func (i *azureTokenItem) ID() string {
+	return i.name
+}
+
+func (i *azureTokenItem) Name() string {
+	return i.name
+}
+
+func (i *azureTokenItem) URL() *url.URL {
+	return &url.URL{
+		Scheme: azureTokenKind,
+		Host:   i.container.id,
+		Path:   "/" + i.name,
+	}
+}
+
+func (i *azureTokenItem) Size() (int64, error) {
+	return i.properties.ContentLength, nil
+}
+
+func (i *azureTokenItem) Open() (io.ReadCloser, error) {
+	bc, err := i.container.blobContainer(nil)
+	if err != nil {
+		return nil, err
+	}
+	return bc.GetBlobReference(i.name).Get(nil)
+}
+
+func (i *azureTokenItem) OpenRange(start, end uint64) (io.ReadCloser, error) {
+	bc, err := i.container.blobContainer(nil)
+	if err != nil {
+		return nil, err
+	}
+	return bc.GetBlobReference(i.name).GetRange(&az.GetBlobRangeOptions{
+		Range: &az.BlobRange{Start: start, End: end},
+	})
+}
+
+func (i *azureTokenItem) ETag() (string, error) {
+	return i.properties.Etag, nil
+}
+
+func (i *azureTokenItem) LastMod() (time.Time, error) {
+	return time.Time(i.properties.LastModified), nil
+}
+
+func (i *azureTokenItem) Metadata() (map[string]interface{}, error) {
+	md := make(map[string]interface{}, len(i.metadata))
+	for k, v := range i.metadata {
+		md[k] = v
+	}
+	return md, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/graymeta/stow"
+	stowaz "github.com/graymeta/stow/azure"
+	"gopkg.in/check.v1"
+)
+
+type AzureTokenSuite struct{}
+
+var _ = check.Suite(&AzureTokenSuite{})
+
+func (s *AzureTokenSuite) TestAzureConfig(c *check.C) {
+	ctx := context.Background()
+	pc := ProviderConfig{Type: ProviderTypeAzure, Endpoint: "https://account.blob.example.com"}
+	secret := &Secret{
+		Type: SecretTypeAzStorageAccount,
+		Azure: &SecretAzure{
+			StorageAccount: "account",
+			StorageKey:     "key",
+		},
+	}
+	kind, _, err := azureConfig(ctx, pc, secret)
+	c.Assert(err, check.IsNil)
+	c.Assert(kind, check.Equals, stowaz.Kind)
+
+	// Access tokens are used instead of storage account keys
+	expiresOn := time.Now().Add(time.Hour).Truncate(time.Second)
+	secret.Azure = &SecretAzure{
+		StorageAccount:       "account",
+		AccessToken:          "token",
+		AccessTokenExpiresOn: expiresOn,
+	}
+	kind, cfg, err := azureConfig(ctx, pc, secret)
+	c.Assert(err, check.IsNil)
+	c.Assert(kind, check.Equals, azureTokenKind)
+	l, err := stow.Dial(kind, cfg)
+	c.Assert(err, check.IsNil)
+	tl, ok := l.(*azureTokenLocation)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(tl.serviceURL, check.Equals, "https://account.blob.example.com/")
+	c.Assert(tl.token.Token, check.Equals, "token")
+	c.Assert(tl.token.ExpiresOn.Equal(expiresOn), check.Equals, true)
+
+	// SAS of containers expire with the access token
+	secret.Azure.AccessTokenExpiresOn = time.Time{}
+	_, _, err = azureConfig(ctx, pc, secret)
+	c.Assert(err, check.ErrorMatches, ".*Expiration of Azure access token not set.*")
+	_, err = stow.Dial(azureTokenKind, stow.ConfigMap{stowaz.ConfigAccount: "account"})
+	c.Assert(err, check.ErrorMatches, ".*Azure access token not set.*")
+}
+
+func (s *AzureTokenSuite) TestBlobContainer(c *check.C) {
+	tc := &azureTokenContainer{
+		id:         "bucket",
+		serviceURL: "https://account.blob.core.windows.net/",
+		sas:        "sv=2025-01-05&sr=c&sp=racwdl&sig=signature",
+	}
+	bc, err := tc.blobContainer(map[string]string{azureEncryptionScopeHeader: "scope"})
+	c.Assert(err, check.IsNil)
+	c.Assert(bc.Name, check.Equals, "bucket")
+	c.Assert(bc.GetBlobReference("a/b").GetURL(), check.Equals, "https://account.blob.core.windows.net/bucket/a/b")
+}
@@ -19,9 +19,13 @@ package objectstore
 import (
 	"context"
 	"fmt"
+	"io"
 	"path"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -39,8 +43,8 @@ var _ Provider = (*provider)(nil)
 type provider struct {
 	// Object store information
 	config ProviderConfig
-	// Secret
-	secret *Secret
+	// Credentials
+	secrets SecretProvider
 }
 
 var _ Bucket = (*bucket)(nil)
@@ -52,9 +56,20 @@ type bucket struct {
 	location     stow.Location  // Authenticated stow handle
 	hostEndPoint string         // E.g., https://s3-us-west-2.amazonaws.com/bucket1
 	region       string         // E.g., us-west-2
+
+	// Credentials are renewed before expiresAt by dialing the stow location
+	// again.
+	mu           sync.Mutex
+	config       ProviderConfig
+	secrets      SecretProvider
+	secretValue  *Secret
+	expiresAt    time.Time
+	s3Client     *s3.S3 // Signs requests with renewed credentials
+	s3ClientErr  error
+	s3ClientOnce sync.Once
 }
 
-func newBucket(cfg ProviderConfig, c stow.Container, l stow.Location) *bucket {
+func newBucket(cfg ProviderConfig, c stow.Container, l stow.Location, sp SecretProvider, secret *Secret, expiresAt time.Time) *bucket {
 	dir := &directory{
 		path: "/",
 	}
@@ -64,18 +79,115 @@ func newBucket(cfg ProviderConfig, c stow.Container, l stow.Location) *bucket {
 		location:     l,
 		hostEndPoint: bucketEndpoint(cfg, c.ID()),
 		region:       cfg.Region,
+		config:       cfg,
+		secrets:      sp,
+		secretValue:  secret,
+		expiresAt:    expiresAt,
 	}
 	dir.bucket = bucket
 	return bucket
 }
 
+// secret returns the credentials of the bucket. If they are about to expire,
+// they are renewed and the stow container is dialed again.
+func (b *bucket) secret(ctx context.Context) (*Secret, time.Time, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !needsRenewal(b.expiresAt) {
+		return b.secretValue, b.expiresAt, nil
+	}
+	secret, expiresAt, err := b.secrets.Secret(ctx)
+	if err != nil {
+		return nil, time.Time{}, errkit.Wrap(err, "Failed to renew object store credentials")
+	}
+	l, err := getStowLocation(ctx, b.config, secret)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	c, err := l.Container(b.container.ID())
+	if err != nil {
+		return nil, time.Time{}, errkit.Wrap(err, fmt.Sprintf("failed to get bucket %s", b.container.ID()))
+	}
+	log.Debug().WithContext(ctx).Print("Renewed object store credentials", field.M{"bucket": c.ID(), "expiresAt": expiresAt})
+	b.location, b.container, b.secretValue, b.expiresAt = l, c, secret, expiresAt
+	return secret, expiresAt, nil
+}
+
+// stowContainer returns the stow container, using renewed credentials if the
+// previous ones are about to expire.
+func (b *bucket) stowContainer(ctx context.Context) (stow.Container, error) {
+	if _, _, err := b.secret(ctx); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.container, nil
+}
+
+// renewingS3Client returns an S3 client that renews the credentials of the
+// bucket whenever they are about to expire, including between the parts of
+// a multipart upload.
+func (b *bucket) renewingS3Client() (*s3.S3, error) {
+	b.s3ClientOnce.Do(func() {
+		creds := credentials.NewCredentials(&bucketCredentials{bucket: b})
+		sess, err := session.NewSession(s3ClientConfig(b.config, creds))
+		if err != nil {
+			b.s3ClientErr = errkit.Wrap(err, "failed to create session")
+			return
+		}
+		b.s3Client = s3.New(sess)
+	})
+	return b.s3Client, b.s3ClientErr
+}
+
+// s3Put uploads an object with the renewing S3 client. Objects larger than a
+// part are uploaded with a multipart upload.
+func (b *bucket) s3Put(ctx context.Context, key string, r io.Reader, tags map[string]interface{}, opts TransferOptions, lock *ObjectLockOptions) error {
+	client, err := b.renewingS3Client()
+	if err != nil {
+		return err
+	}
+	md := make(map[string]*string, len(tags))
+	for k, v := range tags {
+		if sv, ok := v.(string); ok {
+			md[k] = aws.String(sv)
+		}
+	}
+	uploader := s3manager.NewUploaderWithClient(client, func(u *s3manager.Uploader) {
+		u.PartSize = opts.PartSize
+		u.Concurrency = opts.Concurrency
+	})
+	in := &s3manager.UploadInput{
+		Bucket:   aws.String(b.name()),
+		Key:      aws.String(key),
+		Body:     r,
+		Metadata: md,
+	}
+	if class := b.config.StorageClass; class != "" {
+		in.StorageClass = aws.String(class)
+	}
+	if lock != nil {
+		// Requests that retain objects must have a Content-MD5, which the
+		// SDK adds to objects and parts
+		in.ObjectLockMode = aws.String(string(lock.Mode))
+		in.ObjectLockRetainUntilDate = aws.Time(lock.retainUntil())
+	}
+	b.config.ServerSideEncryption.applyS3Upload(in)
+	_, err = uploader.UploadWithContext(ctx, in)
+	return errkit.Wrap(err, "PutObject, putting object")
+}
+
 // CreateBucket creates the bucket. Bucket naming rules are provider dependent.
 func (p *provider) CreateBucket(ctx context.Context, bucketName string) (Bucket, error) {
-	cfg, err := p.bucketConfig(ctx, bucketName)
+	secret, expiresAt, err := p.secrets.Secret(ctx)
 	if err != nil {
 		return nil, err
 	}
-	l, err := getStowLocation(ctx, cfg, p.secret)
+	cfg, err := p.bucketConfig(ctx, secret, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	l, err := getStowLocation(ctx, cfg, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -83,17 +195,21 @@ func (p *provider) CreateBucket(ctx context.Context, bucketName string) (Bucket,
 	if err != nil {
 		return nil, errkit.Wrap(err, fmt.Sprintf("failed to create bucket %s", bucketName))
 	}
-	return newBucket(cfg, c, l), nil
+	return newBucket(cfg, c, l, p.secrets, secret, expiresAt), nil
 }
 
 // GetBucket gets the handle for the specified bucket. Buckets are searched using prefix search;
 // if multiple buckets matched the name, then returns an error
 func (p *provider) GetBucket(ctx context.Context, bucketName string) (Bucket, error) {
-	cfg, err := p.bucketConfig(ctx, bucketName)
+	secret, expiresAt, err := p.secrets.Secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := p.bucketConfig(ctx, secret, bucketName)
 	if err != nil {
 		return nil, err
 	}
-	l, err := getStowLocation(ctx, cfg, p.secret)
+	l, err := getStowLocation(ctx, cfg, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -101,14 +217,18 @@ func (p *provider) GetBucket(ctx context.Context, bucketName string) (Bucket, er
 	if err != nil {
 		return nil, errkit.Wrap(err, fmt.Sprintf("failed to get bucket %s", bucketName))
 	}
-	return newBucket(cfg, c, l), nil
+	return newBucket(cfg, c, l, p.secrets, secret, expiresAt), nil
 }
 
 // ListBuckets gets the handles of all the buckets.
 func (p *provider) ListBuckets(ctx context.Context) (map[string]Bucket, error) {
 	// Walk all the buckets
 	buckets := make(map[string]Bucket)
-	l, err := getStowLocation(ctx, p.config, p.secret)
+	secret, expiresAt, err := p.secrets.Secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l, err := getStowLocation(ctx, p.config, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +237,7 @@ func (p *provider) ListBuckets(ctx context.Context) (map[string]Bucket, error) {
 			if err != nil {
 				return err
 			}
-			buckets[c.ID()] = newBucket(p.config, c, l)
+			buckets[c.ID()] = newBucket(p.config, c, l, p.secrets, secret, expiresAt)
 			return nil
 		})
 	if err != nil {
@@ -130,7 +250,11 @@ func (p *provider) ListBuckets(ctx context.Context) (map[string]Bucket, error) {
 // For safety, does not delete buckets with contents. Caller should ensure
 // that bucket is empty.
 func (p *provider) DeleteBucket(ctx context.Context, bucketName string) error {
-	location, err := getStowLocation(ctx, p.config, p.secret)
+	secret, _, err := p.secrets.Secret(ctx)
+	if err != nil {
+		return err
+	}
+	location, err := getStowLocation(ctx, p.config, secret)
 	if err != nil {
 		return err
 	}
@@ -146,9 +270,9 @@ func (p *provider) getOrCreateBucket(ctx context.Context, bucketName string) (Bu
 	return p.CreateBucket(ctx, bucketName)
 }
 
-func (p *provider) bucketConfig(ctx context.Context, bucketName string) (ProviderConfig, error) {
+func (p *provider) bucketConfig(ctx context.Context, secret *Secret, bucketName string) (ProviderConfig, error) {
 	if p.config.Type == ProviderTypeS3 {
-		return s3BucketConfig(ctx, p.config, p.secret, bucketName)
+		return s3BucketConfig(ctx, p.config, secret, bucketName)
 	}
 	return p.config, nil
 }
@@ -178,10 +302,14 @@ type s3Provider struct {
 // from the actual region of the bucket. If the bucket does not have a region,
 // then the return value will be "".
 func (p *s3Provider) GetRegionForBucket(ctx context.Context, bucketName string) (string, error) {
-	if p.secret == nil || p.secret.Aws == nil {
+	secret, _, err := p.secrets.Secret(ctx)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil || secret.Aws == nil {
 		return "", errkit.New("AWS Secret required to get region")
 	}
-	return s3BucketRegion(ctx, p.config, *p.secret, bucketName)
+	return s3BucketRegion(ctx, p.config, *secret, bucketName)
 }
 
 func s3BucketRegion(ctx context.Context, cfg ProviderConfig, sec Secret, bucketName string) (string, error) {
@@ -27,6 +27,14 @@ const (
 	ProviderTypeS3 ProviderType = "S3"
 	// ProviderTypeAzure captures enum value "Azure"
 	ProviderTypeAzure ProviderType = "Azure"
+	// ProviderTypeFilesystem captures enum value "Filesystem"
+	ProviderTypeFilesystem ProviderType = "Filesystem"
+	// ProviderTypeSFTP captures enum value "SFTP". Objects are files on an
+	// SFTP server.
+	ProviderTypeSFTP ProviderType = "SFTP"
+	// ProviderTypeMemory captures enum value "Memory". Objects are stored in
+	// the memory of the process, which is only useful for tests.
+	ProviderTypeMemory ProviderType = "Memory"
 )
 
 // SecretType enum for different providers
@@ -39,4 +47,6 @@ const (
 	SecretTypeGcpServiceAccountKey SecretType = "GcpServiceAccountKey"
 	// SecretTypeAzStorageAccount captures enum value "AzStorageAccount"
 	SecretTypeAzStorageAccount SecretType = "AzStorageAccount"
+	// SecretTypeSFTP captures enum value "SFTP"
+	SecretTypeSFTP SecretType = "SFTP"
 )
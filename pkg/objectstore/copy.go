@@ -0,0 +1,290 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Copying objects. Objects in the same object store are copied by the object
+// store, without reading their data. Other objects are streamed from the
+// source to the destination.
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/kanisterio/errkit"
+	storage "google.golang.org/api/storage/v1"
+)
+
+const (
+	// s3MaxCopySize is the size of the largest object that S3 copies with a
+	// single request.
+	s3MaxCopySize = 5 << 30
+	// s3CopyPartSize is the size of the parts of larger objects.
+	s3CopyPartSize = 1 << 30
+	s3MaxParts     = 10000
+)
+
+// Copy copies the named object and its tags to dstName in dst.
+func (d *directory) Copy(ctx context.Context, name string, dst Directory, dstName string) error {
+	if d.path == "" {
+		return errkit.New("invalid entry")
+	}
+	dd, ok := stowDirectory(dst)
+	if !ok {
+		return streamCopy(ctx, d, name, dst, dstName)
+	}
+	same, err := d.bucket.sameStore(ctx, dd.bucket)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return streamCopy(ctx, d, name, dst, dstName)
+	}
+	key, dstKey := cloudName(d.absPathName(name)), cloudName(dd.absPathName(dstName))
+	copied, err := dd.bucket.copyFrom(ctx, d.bucket, key, dstKey)
+	if err != nil {
+		return errkit.Wrap(err, "Failed to copy object", "object", key, "destination", dstKey)
+	}
+	if !copied {
+		return streamCopy(ctx, d, name, dst, dstName)
+	}
+	if err := dd.bucket.retain(ctx, dstKey, dd.bucket.config.ObjectLock); err != nil {
+		return err
+	}
+	return dd.bucket.applyStorageClass(ctx, dstKey)
+}
+
+// streamCopy copies an object by reading it from src and writing it to dst.
+func streamCopy(ctx context.Context, src Directory, name string, dst Directory, dstName string) error {
+	info, err := src.Stat(ctx, name)
+	if err != nil {
+		return err
+	}
+	r, tags, err := src.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer r.Close() //nolint:errcheck
+	return dst.Put(ctx, dstName, r, info.Size, tags)
+}
+
+// sameStore reports whether the buckets are in the same object store and
+// are accessed with the same credentials, so that the object store can copy
+// objects between them.
+func (b *bucket) sameStore(ctx context.Context, o *bucket) (bool, error) {
+	if b.config.Type != o.config.Type || b.config.Endpoint != o.config.Endpoint {
+		return false, nil
+	}
+	secret, _, err := b.secret(ctx)
+	if err != nil {
+		return false, err
+	}
+	oSecret, _, err := o.secret(ctx)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(secret, oSecret), nil
+}
+
+// copyFrom copies an object of src, which is in the same object store, to
+// key. The object is put as configured for the bucket, e.g. encrypted with
+// its server-side encryption. It returns false if the object store cannot
+// copy the object.
+func (b *bucket) copyFrom(ctx context.Context, src *bucket, srcKey, key string) (bool, error) {
+	switch b.config.Type {
+	case ProviderTypeS3:
+		return b.s3CopyFrom(ctx, src, srcKey, key)
+	case ProviderTypeGCS:
+		return true, b.gcsCopyFrom(ctx, src, srcKey, key)
+	case ProviderTypeAzure:
+		return true, b.azureCopyFrom(ctx, src, srcKey, key)
+	case ProviderTypeMemory:
+		return b.memoryCopyFrom(ctx, src, srcKey, key)
+	}
+	return false, nil
+}
+
+func (b *bucket) s3CopyFrom(ctx context.Context, src *bucket, srcKey, key string) (bool, error) {
+	info, err := src.directory.Stat(ctx, "/"+srcKey)
+	if err != nil {
+		return false, err
+	}
+	return true, b.s3Copy(ctx, src, srcKey, key, info.Size, info.Tags, b.config.StorageClass)
+}
+
+// s3Copy copies an object of src with the given size to the storage class
+// and replaces its tags. Objects that are too large to be copied with a
+// single request are copied in parts. The copy is put in the default storage
+// class of the bucket if class is empty.
+func (b *bucket) s3Copy(ctx context.Context, src *bucket, srcKey, key string, size int64, tags map[string]string, class string) error {
+	client, err := b.renewingS3Client()
+	if err != nil {
+		return err
+	}
+	md := make(map[string]*string, len(tags))
+	for k, v := range tags {
+		md[k] = aws.String(v)
+	}
+	copySource := aws.String(url.PathEscape(src.name() + "/" + srcKey))
+	srcAlg, srcCK := src.config.ServerSideEncryption.s3CustomerKey()
+	sse, kmsKeyID := b.config.ServerSideEncryption.s3Encryption()
+	alg, ck := b.config.ServerSideEncryption.s3CustomerKey()
+	var storageClass, lockMode *string
+	if class != "" {
+		storageClass = aws.String(class)
+	}
+	var retainUntil *time.Time
+	if lock := b.config.ObjectLock; lock != nil {
+		lockMode, retainUntil = aws.String(string(lock.Mode)), aws.Time(lock.retainUntil())
+	}
+	if size <= s3MaxCopySize {
+		_, err = client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+			Bucket:                         aws.String(b.name()),
+			Key:                            aws.String(key),
+			CopySource:                     copySource,
+			MetadataDirective:              aws.String(s3.MetadataDirectiveReplace),
+			Metadata:                       md,
+			StorageClass:                   storageClass,
+			ObjectLockMode:                 lockMode,
+			ObjectLockRetainUntilDate:      retainUntil,
+			ServerSideEncryption:           sse,
+			SSEKMSKeyId:                    kmsKeyID,
+			SSECustomerAlgorithm:           alg,
+			SSECustomerKey:                 ck,
+			CopySourceSSECustomerAlgorithm: srcAlg,
+			CopySourceSSECustomerKey:       srcCK,
+		})
+		return err
+	}
+	up, err := client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:                    aws.String(b.name()),
+		Key:                       aws.String(key),
+		Metadata:                  md,
+		StorageClass:              storageClass,
+		ObjectLockMode:            lockMode,
+		ObjectLockRetainUntilDate: retainUntil,
+		ServerSideEncryption:      sse,
+		SSEKMSKeyId:               kmsKeyID,
+		SSECustomerAlgorithm:      alg,
+		SSECustomerKey:            ck,
+	})
+	if err != nil {
+		return err
+	}
+	// Uploads can have at most 10000 parts
+	partSize := max(s3CopyPartSize, (size+s3MaxParts-1)/s3MaxParts)
+	parts := make([]*s3.CompletedPart, 0, (size+partSize-1)/partSize)
+	for offset := int64(0); offset < size; offset += partSize {
+		n := aws.Int64(int64(len(parts) + 1))
+		out, err := client.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+			Bucket:                         aws.String(b.name()),
+			Key:                            aws.String(key),
+			UploadId:                       up.UploadId,
+			PartNumber:                     n,
+			CopySource:                     copySource,
+			CopySourceRange:                aws.String(httpRange(offset, min(partSize, size-offset))),
+			SSECustomerAlgorithm:           alg,
+			SSECustomerKey:                 ck,
+			CopySourceSSECustomerAlgorithm: srcAlg,
+			CopySourceSSECustomerKey:       srcCK,
+		})
+		if err != nil {
+			_, _ = client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(b.name()),
+				Key:      aws.String(key),
+				UploadId: up.UploadId,
+			})
+			return err
+		}
+		parts = append(parts, &s3.CompletedPart{ETag: out.CopyPartResult.ETag, PartNumber: n})
+	}
+	_, err = client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:               aws.String(b.name()),
+		Key:                  aws.String(key),
+		UploadId:             up.UploadId,
+		MultipartUpload:      &s3.CompletedMultipartUpload{Parts: parts},
+		SSECustomerAlgorithm: alg,
+		SSECustomerKey:       ck,
+	})
+	return err
+}
+
+// gcsCopyFrom rewrites the object in the storage class of the bucket.
+func (b *bucket) gcsCopyFrom(ctx context.Context, src *bucket, srcKey, key string) error {
+	return b.gcsRewrite(ctx, src, srcKey, key, &storage.Object{StorageClass: b.config.StorageClass})
+}
+
+// gcsRewrite rewrites an object of src with the properties of object, which
+// takes several requests for large objects that are copied between locations
+// or storage classes.
+func (b *bucket) gcsRewrite(ctx context.Context, src *bucket, srcKey, key string, object *storage.Object) error {
+	svc, err := b.gcsService(ctx)
+	if err != nil {
+		return err
+	}
+	call := svc.Objects.Rewrite(src.name(), srcKey, b.name(), key, object)
+	for {
+		res, err := call.Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		if res.Done {
+			return nil
+		}
+		call.RewriteToken(res.RewriteToken)
+	}
+}
+
+// azureCopyFrom copies the blob and waits until the copy completes. Blobs in
+// the same storage account are read with its shared key, or with the SAS of
+// their container if it is accessed with an access token.
+func (b *bucket) azureCopyFrom(ctx context.Context, src *bucket, srcKey, key string) error {
+	srcBlob, err := src.azureBlob(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	blob, err := b.azureWriteBlob(ctx, key)
+	if err != nil {
+		return err
+	}
+	srcURL := srcBlob.GetURL()
+	if tc, ok := src.azureTokenContainer(); ok {
+		srcURL += "?" + tc.sas
+	}
+	return blob.Copy(srcURL, nil)
+}
+
+func (b *bucket) memoryCopyFrom(ctx context.Context, src *bucket, srcKey, key string) (bool, error) {
+	c, err := b.stowContainer(ctx)
+	if err != nil {
+		return false, err
+	}
+	sc, err := src.stowContainer(ctx)
+	if err != nil {
+		return false, err
+	}
+	mc, ok := c.(*memoryContainer)
+	if !ok {
+		return false, nil
+	}
+	msc, ok := sc.(*memoryContainer)
+	if !ok {
+		return false, nil
+	}
+	return true, mc.copyFrom(msc, srcKey, key)
+}
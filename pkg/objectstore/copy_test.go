@@ -0,0 +1,134 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+type CopySuite struct{}
+
+var _ = check.Suite(&CopySuite{})
+
+func (s *CopySuite) TearDownTest(c *check.C) {
+	DeleteMemoryStore(c.TestName())
+	DeleteMemoryStore(c.TestName() + "-other")
+}
+
+func (s *CopySuite) directory(c *check.C, pc ProviderConfig, bucketName string) Directory {
+	ctx := context.Background()
+	p, err := NewProvider(ctx, pc, nil)
+	c.Assert(err, check.IsNil)
+	b, err := GetOrCreateBucket(ctx, p, bucketName)
+	c.Assert(err, check.IsNil)
+	d, err := b.CreateDirectory(ctx, "dir")
+	c.Assert(err, check.IsNil)
+	return d
+}
+
+func (s *CopySuite) TestStat(c *check.C) {
+	ctx := context.Background()
+	before := time.Now().Add(-time.Minute)
+	for _, pc := range []ProviderConfig{
+		{Type: ProviderTypeMemory, Endpoint: c.TestName()},
+		{Type: ProviderTypeFilesystem, Endpoint: c.MkDir()},
+	} {
+		d := s.directory(c, pc, "bucket")
+		err := d.PutBytes(ctx, "object", []byte("data"), map[string]string{"key": "value"})
+		c.Assert(err, check.IsNil)
+
+		info, err := d.Stat(ctx, "object")
+		c.Assert(err, check.IsNil)
+		c.Check(info.Name, check.Equals, "object")
+		c.Check(info.Size, check.Equals, int64(len("data")))
+		c.Check(info.ModTime.After(before), check.Equals, true)
+		c.Check(info.Tags, check.DeepEquals, map[string]string{"key": "value"})
+
+		_, err = d.Stat(ctx, "missing")
+		c.Check(err, check.NotNil, check.Commentf("%s", pc.Type))
+	}
+}
+
+func (s *CopySuite) TestCopy(c *check.C) {
+	ctx := context.Background()
+	memory := ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName()}
+	for _, tc := range []struct {
+		name string
+		src  ProviderConfig
+		dst  ProviderConfig
+	}{
+		{name: "same memory store", src: memory, dst: memory},
+		{name: "other memory store", src: memory, dst: ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName() + "-other"}},
+		{name: "memory to filesystem", src: memory, dst: ProviderConfig{Type: ProviderTypeFilesystem, Endpoint: c.MkDir()}},
+		{name: "filesystem to memory", src: ProviderConfig{Type: ProviderTypeFilesystem, Endpoint: c.MkDir()}, dst: memory},
+	} {
+		src := s.directory(c, tc.src, "src")
+		dst := s.directory(c, tc.dst, "dst")
+		err := src.PutBytes(ctx, "object", []byte(tc.name), map[string]string{"key": "value"})
+		c.Assert(err, check.IsNil)
+
+		err = src.Copy(ctx, "object", dst, "copies/object")
+		c.Assert(err, check.IsNil, check.Commentf(tc.name))
+		data, tags, err := dst.GetBytes(ctx, "copies/object")
+		c.Assert(err, check.IsNil, check.Commentf(tc.name))
+		c.Check(string(data), check.Equals, tc.name)
+		c.Check(tags, check.DeepEquals, map[string]string{"key": "value"}, check.Commentf(tc.name))
+
+		// The source is kept
+		_, err = src.Stat(ctx, "object")
+		c.Check(err, check.IsNil, check.Commentf(tc.name))
+
+		err = src.Copy(ctx, "missing", dst, "copies/missing")
+		c.Check(err, check.NotNil, check.Commentf(tc.name))
+	}
+}
+
+func (s *CopySuite) TestCopyWithinBucket(c *check.C) {
+	ctx := context.Background()
+	d := s.directory(c, ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName()}, "bucket")
+	err := d.PutBytes(ctx, "object", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+	archive, err := d.CreateDirectory(ctx, "archive")
+	c.Assert(err, check.IsNil)
+
+	err = d.Copy(ctx, "object", archive, "object")
+	c.Assert(err, check.IsNil)
+	data, _, err := d.GetBytes(ctx, "archive/object")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "data")
+}
+
+func (s *CopySuite) TestCopyRetained(c *check.C) {
+	ctx := context.Background()
+	src := s.directory(c, ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName()}, "src")
+	dst := s.directory(c, ProviderConfig{
+		Type:       ProviderTypeMemory,
+		Endpoint:   c.TestName(),
+		ObjectLock: &ObjectLockOptions{Mode: ObjectLockModeCompliance, Retention: time.Hour},
+	}, "dst")
+	err := src.PutBytes(ctx, "object", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+
+	err = src.Copy(ctx, "object", dst, "object")
+	c.Assert(err, check.IsNil)
+	// Copies are retained as configured for the destination
+	c.Assert(src.Delete(ctx, "object"), check.IsNil)
+	var re *RetentionError
+	c.Assert(errors.As(dst.Delete(ctx, "object"), &re), check.Equals, true)
+}
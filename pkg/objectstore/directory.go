@@ -60,7 +60,11 @@ func (d *directory) GetDirectory(ctx context.Context, dir string) (Directory, er
 		return d, nil
 	}
 	dir = d.absDirName(dir)
-	if _, err := d.bucket.container.Item(cloudName(dir)); err == nil {
+	container, err := d.bucket.stowContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := container.Item(cloudName(dir)); err == nil {
 		return &directory{
 			bucket: d.bucket,
 			path:   dir,
@@ -70,7 +74,7 @@ func (d *directory) GetDirectory(ctx context.Context, dir string) (Directory, er
 	// Minio does not support `GET` on "directory" objects. To workaround,
 	// we do a prefix search i.e. if we're trying to open directory `dir1/`,
 	// we check if there is at least 1 object with the prefix `dir1/`.
-	items, _, err := d.bucket.container.Items(cloudName(dir), stow.CursorStart, 1)
+	items, _, err := container.Items(cloudName(dir), stow.CursorStart, 1)
 	switch {
 	case err != nil:
 		return nil, errkit.Wrap(err, fmt.Sprintf("could not get directory marker %s", dir))
@@ -90,40 +94,17 @@ func (d *directory) ListDirectories(ctx context.Context) (map[string]Directory,
 		return nil, errkit.New("invalid entry")
 	}
 
-	directories := make(map[string]Directory)
-
-	err := stow.Walk(d.bucket.container, cloudName(d.path), 10000,
-		func(item stow.Item, err error) error {
-			if err != nil {
-				return err
-			}
-
-			// Check if the object is nested in a directory hierarchy
-			// and if so - return the first directory entry.
-			// e.g.
-			// If we're doing a prefix search for all objects under
-			// `parent/` and we find `parent/dir1/` - then we want
-			// to track `dir1/`
-			dir := strings.TrimPrefix(item.Name(), cloudName(d.path))
-			if dir == "" {
-				// e.g., /<d.path>/
-				return nil
-			}
-
-			if dirEnt, ok := getFirstDirectoryMarker(dir); ok {
-				// Use maps to uniqify
-				// e.g., /dir1/, /dir1/file1, /dir1/dir2/, /dir1/dir2/file2 will leave /dir
-				directories[dirEnt] = &directory{
-					bucket: d.bucket,
-					path:   d.absDirName(dirEnt),
-				}
-			}
-
-			return nil
-		})
+	names, err := d.bucket.listDirectories(ctx, cloudName(d.path))
 	if err != nil {
 		return nil, err
 	}
+	directories := make(map[string]Directory, len(names))
+	for _, name := range names {
+		directories[name] = &directory{
+			bucket: d.bucket,
+			path:   d.absDirName(name),
+		}
+	}
 	return directories, nil
 }
 
@@ -134,18 +115,11 @@ func (d *directory) ListObjects(ctx context.Context) ([]string, error) {
 	}
 
 	objects := make([]string, 0, 1)
-	err := stow.Walk(d.bucket.container, cloudName(d.path), 10000,
-		func(item stow.Item, err error) error {
-			if err != nil {
-				return err
-			}
-			objName := strings.TrimPrefix(item.Name(), cloudName(d.path))
-			if objName != "" && !strings.Contains(objName, "/") {
-				objects = append(objects, objName)
-			}
-			return nil
-		})
-	if err != nil {
+	it := d.IterateObjects(ctx, ListOptions{})
+	for it.Next(ctx) {
+		objects = append(objects, it.Object().Name)
+	}
+	if err := it.Err(); err != nil {
 		return nil, err
 	}
 	return objects, nil
@@ -157,28 +131,34 @@ func (d *directory) DeleteDirectory(ctx context.Context) error {
 	if d.path == "" {
 		return errkit.New("invalid entry")
 	}
-	return deleteWithPrefix(ctx, d.bucket.container, cloudName(d.path))
+	return d.bucket.deleteWithPrefix(ctx, cloudName(d.path))
 }
 
 // DeleteDirectory deletes all objects that have d.path/dir as the prefix
 // <bucket>/<d.path>/dir/<everything> including <bucket>/<d.path>/dir/<some dir>/<objects>
 func (d *directory) DeleteAllWithPrefix(ctx context.Context, prefix string) error {
-	p := cloudName(filepath.Join(d.path, prefix))
-	return deleteWithPrefix(ctx, d.bucket.container, p)
+	return d.bucket.deleteWithPrefix(ctx, cloudName(filepath.Join(d.path, prefix)))
 }
 
-func deleteWithPrefix(ctx context.Context, c stow.Container, prefix string) error {
-	err := stow.Walk(c, prefix, 10000,
-		func(item stow.Item, err error) error {
-			if err != nil {
-				return err
-			}
-			return c.RemoveItem(item.Name())
-		})
+// deleteWithPrefix deletes the objects whose name starts with prefix, a page
+// at a time. It stops at the first object that is retained by an object
+// lock.
+func (b *bucket) deleteWithPrefix(ctx context.Context, prefix string) error {
+	c, err := b.stowContainer(ctx)
 	if err != nil {
-		return errkit.Wrap(err, fmt.Sprintf("Failed to delete item %s", prefix))
+		return err
+	}
+	it := b.iterateObjects("", ListOptions{Prefix: prefix, Recursive: true})
+	for it.Next(ctx) {
+		name := it.Object().Name
+		if err := b.checkRetention(ctx, name); err != nil {
+			return errkit.Wrap(err, fmt.Sprintf("Failed to delete item %s", prefix))
+		}
+		if err := c.RemoveItem(name); err != nil {
+			return errkit.Wrap(err, fmt.Sprintf("Failed to delete item %s", prefix))
+		}
 	}
-	return nil
+	return errkit.Wrap(it.Err(), fmt.Sprintf("Failed to delete item %s", prefix))
 }
 
 func (d *directory) Get(ctx context.Context, name string) (io.ReadCloser, map[string]string, error) {
@@ -187,14 +167,20 @@ func (d *directory) Get(ctx context.Context, name string) (io.ReadCloser, map[st
 	}
 
 	objName := d.absPathName(name)
+	if d.bucket.config.Type == ProviderTypeS3 && d.bucket.config.ServerSideEncryption.customerKey() {
+		return d.bucket.s3Get(ctx, cloudName(objName))
+	}
 
-	item, err := d.bucket.container.Item(cloudName(objName))
+	container, err := d.bucket.stowContainer(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	item, err := container.Item(cloudName(objName))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Open the object and read all data
-	r, err := item.Open()
+	r, err := d.bucket.open(ctx, cloudName(objName), item)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -214,6 +200,36 @@ func (d *directory) Get(ctx context.Context, name string) (io.ReadCloser, map[st
 	return r, tags, nil
 }
 
+// Stat returns the size, modification time and tags of an object without
+// reading its data.
+func (d *directory) Stat(ctx context.Context, name string) (ObjectInfo, error) {
+	if d.path == "" {
+		return ObjectInfo{}, errkit.New("invalid entry")
+	}
+
+	key := cloudName(d.absPathName(name))
+	if d.bucket.config.Type == ProviderTypeS3 {
+		// Stow does not return the storage class of objects
+		info, err := d.bucket.s3Stat(ctx, key)
+		info.Name = name
+		return info, err
+	}
+
+	container, err := d.bucket.stowContainer(ctx)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	item, err := container.Item(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := d.bucket.objectInfo(ctx, item, name, true)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return info, d.bucket.statNative(ctx, key, item, &info)
+}
+
 // Get data and tags associated with an object <bucket>/<d.path>/name.
 func (d *directory) GetBytes(ctx context.Context, name string) ([]byte, map[string]string, error) {
 	r, tags, err := d.Get(ctx, name)
@@ -231,6 +247,11 @@ func (d *directory) GetBytes(ctx context.Context, name string) ([]byte, map[stri
 }
 
 func (d *directory) Put(ctx context.Context, name string, r io.Reader, size int64, tags map[string]string) error {
+	return d.put(ctx, name, r, size, tags, d.bucket.config.ObjectLock)
+}
+
+// put stores an object and retains it as configured by lock, if it is set.
+func (d *directory) put(ctx context.Context, name string, r io.Reader, size int64, tags map[string]string, lock *ObjectLockOptions) error {
 	if d.path == "" {
 		return errkit.New("invalid entry")
 	}
@@ -239,10 +260,34 @@ func (d *directory) Put(ctx context.Context, name string, r io.Reader, size int6
 
 	objName := d.absPathName(name)
 
+	// Stow reads the whole object into memory before uploading it. Stream
+	// the object in parts instead.
+	opts := d.bucket.config.Transfer.withDefaults()
+	switch d.bucket.config.Type {
+	case ProviderTypeS3:
+		return d.bucket.s3Put(ctx, cloudName(objName), r, sTags, opts, lock)
+	case ProviderTypeGCS:
+		return d.bucket.gcsPut(ctx, cloudName(objName), r, sTags, opts.PartSize)
+	case ProviderTypeAzure:
+		if err := d.bucket.azurePut(ctx, cloudName(objName), r, sTags, opts); err != nil {
+			return err
+		}
+		return d.bucket.applyStorageClass(ctx, cloudName(objName))
+	}
+
+	container, err := d.bucket.stowContainer(ctx)
+	if err != nil {
+		return err
+	}
 	// For versioned buckets, Put can return the new version name
 	// TODO: Support versioned buckets
-	_, err := d.bucket.container.Put(cloudName(objName), r, size, sTags)
-	return err
+	if _, err = container.Put(cloudName(objName), r, size, sTags); err != nil {
+		return err
+	}
+	if err := d.bucket.retain(ctx, cloudName(objName), lock); err != nil {
+		return err
+	}
+	return d.bucket.applyStorageClass(ctx, cloudName(objName))
 }
 
 // Put stores a blob in d.path/<name>
@@ -258,7 +303,14 @@ func (d *directory) Delete(ctx context.Context, name string) error {
 
 	objName := d.absPathName(name)
 
-	return d.bucket.container.RemoveItem(cloudName(objName))
+	container, err := d.bucket.stowContainer(ctx)
+	if err != nil {
+		return err
+	}
+	if err := d.bucket.checkRetention(ctx, cloudName(objName)); err != nil {
+		return err
+	}
+	return container.RemoveItem(cloudName(objName))
 }
 
 // If name does not start with '/', prefix with d.path. Add '/' as suffix
@@ -0,0 +1,704 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Object store on a filesystem, such as a mounted NFS share or PVC, or the
+// filesystem of an SFTP server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kanisterio/errkit"
+)
+
+const (
+	// fsReservedPrefix is the prefix of files the filesystem object store
+	// uses internally. They are not listed as objects.
+	fsReservedPrefix = ".kanister-"
+	fsTagsPrefix     = fsReservedPrefix + "tags-"
+	fsUploadPattern  = fsReservedPrefix + "upload-*"
+
+	fsDirMode  = 0o755
+	fsFileMode = 0o644
+)
+
+// fileSystem is the filesystem whose files are the objects of a filesystem
+// object store. Paths are separated by slashes.
+type fileSystem interface {
+	Stat(ctx context.Context, name string) (os.FileInfo, error)
+	// ReadDir returns the entries of a directory sorted by name.
+	ReadDir(ctx context.Context, name string) ([]os.DirEntry, error)
+	Mkdir(ctx context.Context, name string) error
+	MkdirAll(ctx context.Context, name string) error
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// CreateTemp creates a new file in dir like os.CreateTemp.
+	CreateTemp(ctx context.Context, dir, pattern string) (tempFile, error)
+	Chmod(ctx context.Context, name string, mode os.FileMode) error
+	// Rename moves oldpath to newpath, replacing it if it exists.
+	Rename(ctx context.Context, oldpath, newpath string) error
+	Remove(ctx context.Context, name string) error
+	// RemoveAll removes name and everything in it. A missing name is not an
+	// error.
+	RemoveAll(ctx context.Context, name string) error
+	ReadFile(ctx context.Context, name string) ([]byte, error)
+	WriteFile(ctx context.Context, name string, data []byte, mode os.FileMode) error
+}
+
+// tempFile is a file created by fileSystem.CreateTemp.
+type tempFile interface {
+	io.WriteCloser
+	Name() string
+}
+
+var _ fileSystem = localFS{}
+
+// localFS implements fileSystem for the local filesystem.
+type localFS struct{}
+
+func (localFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	return os.Stat(filepath.FromSlash(name))
+}
+
+func (localFS) ReadDir(ctx context.Context, name string) ([]os.DirEntry, error) {
+	return os.ReadDir(filepath.FromSlash(name))
+}
+
+func (localFS) Mkdir(ctx context.Context, name string) error {
+	return os.Mkdir(filepath.FromSlash(name), fsDirMode)
+}
+
+func (localFS) MkdirAll(ctx context.Context, name string) error {
+	return os.MkdirAll(filepath.FromSlash(name), fsDirMode)
+}
+
+func (localFS) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.FromSlash(name))
+}
+
+func (localFS) CreateTemp(ctx context.Context, dir, pattern string) (tempFile, error) {
+	f, err := os.CreateTemp(filepath.FromSlash(dir), pattern)
+	if err != nil {
+		return nil, err
+	}
+	return localTempFile{f}, nil
+}
+
+func (localFS) Chmod(ctx context.Context, name string, mode os.FileMode) error {
+	return os.Chmod(filepath.FromSlash(name), mode)
+}
+
+func (localFS) Rename(ctx context.Context, oldpath, newpath string) error {
+	return os.Rename(filepath.FromSlash(oldpath), filepath.FromSlash(newpath))
+}
+
+func (localFS) Remove(ctx context.Context, name string) error {
+	return os.Remove(filepath.FromSlash(name))
+}
+
+func (localFS) RemoveAll(ctx context.Context, name string) error {
+	return os.RemoveAll(filepath.FromSlash(name))
+}
+
+func (localFS) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.FromSlash(name))
+}
+
+func (localFS) WriteFile(ctx context.Context, name string, data []byte, mode os.FileMode) error {
+	return os.WriteFile(filepath.FromSlash(name), data, mode)
+}
+
+// localTempFile returns slash separated names like the rest of localFS.
+type localTempFile struct {
+	*os.File
+}
+
+func (f localTempFile) Name() string {
+	return filepath.ToSlash(f.File.Name())
+}
+
+var _ Provider = (*fsProvider)(nil)
+
+// fsProvider implements Provider for a directory of a filesystem. Buckets are
+// the subdirectories of the directory.
+type fsProvider struct {
+	pType ProviderType
+	fs    fileSystem
+	root  string
+}
+
+func newFilesystemProvider(config ProviderConfig) (Provider, error) {
+	if config.Endpoint == "" {
+		return nil, errkit.New("Path of filesystem object store not specified")
+	}
+	return &fsProvider{
+		pType: ProviderTypeFilesystem,
+		fs:    localFS{},
+		root:  filepath.ToSlash(filepath.Clean(config.Endpoint)),
+	}, nil
+}
+
+// bucketPath returns the directory of a bucket. An empty name refers to the
+// root directory of the provider.
+func (p *fsProvider) bucketPath(bucketName string) (string, error) {
+	if strings.Contains(bucketName, "/") || bucketName == "." || bucketName == ".." {
+		return "", errkit.New(fmt.Sprintf("invalid bucket name %s", bucketName))
+	}
+	return path.Join(p.root, bucketName), nil
+}
+
+func (p *fsProvider) newBucket(root string) *fsBucket {
+	return newFSBucket(p.pType, p.fs, root)
+}
+
+// CreateBucket creates the directory of the bucket. Fails if the bucket
+// already exists.
+func (p *fsProvider) CreateBucket(ctx context.Context, bucketName string) (Bucket, error) {
+	dir, err := p.bucketPath(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.fs.MkdirAll(ctx, p.root); err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("failed to create bucket %s", bucketName))
+	}
+	if err := p.fs.Mkdir(ctx, dir); err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("failed to create bucket %s", bucketName))
+	}
+	return p.newBucket(dir), nil
+}
+
+// GetBucket gets the handle for an existing bucket.
+func (p *fsProvider) GetBucket(ctx context.Context, bucketName string) (Bucket, error) {
+	dir, err := p.bucketPath(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := p.fs.Stat(ctx, dir)
+	if err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("failed to get bucket %s", bucketName))
+	}
+	if !fi.IsDir() {
+		return nil, errkit.New(fmt.Sprintf("failed to get bucket %s: not a directory", bucketName))
+	}
+	return p.newBucket(dir), nil
+}
+
+// DeleteBucket removes the directory of the bucket. For safety, does not
+// delete buckets with contents.
+func (p *fsProvider) DeleteBucket(ctx context.Context, bucketName string) error {
+	dir, err := p.bucketPath(bucketName)
+	if err != nil {
+		return err
+	}
+	if bucketName == "" {
+		return errkit.New("cannot delete the root directory of the object store")
+	}
+	return errkit.Wrap(p.fs.Remove(ctx, dir), fmt.Sprintf("failed to delete bucket %s", bucketName))
+}
+
+// ListBuckets returns the subdirectories of the root directory.
+func (p *fsProvider) ListBuckets(ctx context.Context) (map[string]Bucket, error) {
+	entries, err := p.fs.ReadDir(ctx, p.root)
+	if err != nil {
+		return nil, errkit.Wrap(err, "failed to list buckets")
+	}
+	buckets := make(map[string]Bucket)
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			buckets[e.Name()] = p.newBucket(path.Join(p.root, e.Name()))
+		}
+	}
+	return buckets, nil
+}
+
+func (p *fsProvider) getOrCreateBucket(ctx context.Context, bucketName string) (Bucket, error) {
+	if b, err := p.GetBucket(ctx, bucketName); err == nil {
+		return b, nil
+	}
+	return p.CreateBucket(ctx, bucketName)
+}
+
+var _ Bucket = (*fsBucket)(nil)
+
+// fsBucket implements Bucket for a directory.
+type fsBucket struct {
+	*fsDirectory              // bucket is the root directory
+	pType        ProviderType // Type of the provider of the bucket
+	fs           fileSystem
+	root         string // Path of the bucket
+}
+
+func newFSBucket(pType ProviderType, fs fileSystem, root string) *fsBucket {
+	dir := &fsDirectory{
+		path: "/",
+	}
+	b := &fsBucket{
+		fsDirectory: dir,
+		pType:       pType,
+		fs:          fs,
+		root:        root,
+	}
+	dir.bucket = b
+	return b
+}
+
+// filePath returns the path of an object or directory in the filesystem.
+// Names cannot refer to files outside of the bucket.
+func (b *fsBucket) filePath(name string) string {
+	return path.Join(b.root, path.Clean("/"+name))
+}
+
+var _ Directory = (*fsDirectory)(nil)
+
+// fsDirectory implements Directory for a subdirectory of a bucket. Objects are
+// files and their tags are stored in separate files next to them.
+type fsDirectory struct {
+	bucket *fsBucket
+	path   string // Starts and ends with a '/'
+}
+
+// String returns the path of the directory.
+func (d *fsDirectory) String() string {
+	return d.bucket.filePath(d.path) + "/"
+}
+
+// CreateDirectory creates a subdirectory.
+func (d *fsDirectory) CreateDirectory(ctx context.Context, dir string) (Directory, error) {
+	dir = d.absDirName(dir)
+	if err := d.bucket.fs.MkdirAll(ctx, d.bucket.filePath(dir)); err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("could not create directory %s", dir))
+	}
+	return &fsDirectory{
+		bucket: d.bucket,
+		path:   dir,
+	}, nil
+}
+
+// GetDirectory gets an existing subdirectory.
+func (d *fsDirectory) GetDirectory(ctx context.Context, dir string) (Directory, error) {
+	if dir == "" {
+		return d, nil
+	}
+	dir = d.absDirName(dir)
+	fi, err := d.bucket.fs.Stat(ctx, d.bucket.filePath(dir))
+	switch {
+	case err != nil:
+		return nil, errkit.Wrap(err, fmt.Sprintf("could not get directory %s", dir))
+	case !fi.IsDir():
+		return nil, errkit.New(fmt.Sprintf("could not get directory %s: not a directory", dir))
+	}
+	return &fsDirectory{
+		bucket: d.bucket,
+		path:   dir,
+	}, nil
+}
+
+// ListDirectories lists the subdirectories indexed by their name.
+func (d *fsDirectory) ListDirectories(ctx context.Context) (map[string]Directory, error) {
+	entries, err := d.readDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	directories := make(map[string]Directory)
+	for _, e := range entries {
+		if e.IsDir() {
+			directories[e.Name()] = &fsDirectory{
+				bucket: d.bucket,
+				path:   d.absDirName(e.Name()),
+			}
+		}
+	}
+	return directories, nil
+}
+
+// ListObjects lists the objects in the directory.
+func (d *fsDirectory) ListObjects(ctx context.Context) ([]string, error) {
+	entries, err := d.readDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			objects = append(objects, e.Name())
+		}
+	}
+	return objects, nil
+}
+
+// IterateObjects returns an iterator over the objects in the directory that
+// are selected by opts. Each page is listed by walking the directory and
+// skipping the subdirectories that only hold objects before the page.
+func (d *fsDirectory) IterateObjects(ctx context.Context, opts ListOptions) *ObjectIterator {
+	opts = opts.withDefaults()
+	return newObjectIterator(opts.StartAfter, func(ctx context.Context, after string) ([]ObjectInfo, string, error) {
+		objs := make([]ObjectInfo, 0, opts.PageSize)
+		if err := d.listPage(ctx, opts, "", after, &objs); err != nil {
+			return nil, "", err
+		}
+		if len(objs) < opts.PageSize {
+			return objs, "", nil
+		}
+		return objs, objs[len(objs)-1].Name, nil
+	})
+}
+
+// listPage appends the objects in the subdirectory rel whose names sort after
+// `after` to objs, in lexical order, until the page is full.
+func (d *fsDirectory) listPage(ctx context.Context, opts ListOptions, rel, after string, objs *[]ObjectInfo) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	dir := &fsDirectory{bucket: d.bucket, path: d.absDirName(rel)}
+	if rel == "" {
+		dir = d
+	}
+	entries, err := dir.readDir(ctx)
+	if err != nil {
+		return err
+	}
+	// Directories sort by the names of the objects in them
+	key := func(e os.DirEntry) string {
+		if e.IsDir() {
+			return rel + e.Name() + "/"
+		}
+		return rel + e.Name()
+	}
+	// Directories cannot be read from a name, so only the entries that can
+	// hold objects of the page are sorted
+	entries = slices.DeleteFunc(entries, func(e os.DirEntry) bool {
+		name := key(e)
+		if e.IsDir() {
+			return !opts.Recursive || (name <= after && !strings.HasPrefix(after, name))
+		}
+		return name <= after
+	})
+	slices.SortFunc(entries, func(a, b os.DirEntry) int {
+		return strings.Compare(key(a), key(b))
+	})
+	for _, e := range entries {
+		name := key(e)
+		switch {
+		case e.IsDir():
+			// Skip directories whose objects are not selected
+			if !opts.Recursive ||
+				(name <= after && !strings.HasPrefix(after, name)) ||
+				(!strings.HasPrefix(name, opts.Prefix) && !strings.HasPrefix(opts.Prefix, name)) {
+				continue
+			}
+			if err := d.listPage(ctx, opts, name, after, objs); err != nil {
+				return err
+			}
+		case e.Type().IsRegular() && name > after && opts.selects(name):
+			fi, err := e.Info()
+			if err != nil {
+				return errkit.Wrap(err, fmt.Sprintf("could not list object %s", name))
+			}
+			obj := ObjectInfo{
+				Name:    name,
+				Size:    fi.Size(),
+				ModTime: fi.ModTime(),
+			}
+			if opts.WithTags {
+				if obj.Tags, err = d.readTags(ctx, d.absPathName(name)); err != nil {
+					return err
+				}
+			}
+			*objs = append(*objs, obj)
+		}
+		if len(*objs) == opts.PageSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// readDir returns the entries of the directory without internal files. A
+// missing directory has no entries.
+func (d *fsDirectory) readDir(ctx context.Context) ([]os.DirEntry, error) {
+	entries, err := d.bucket.fs.ReadDir(ctx, d.bucket.filePath(d.path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("could not list directory %s", d.path))
+	}
+	filtered := entries[:0]
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), fsReservedPrefix) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered, nil
+}
+
+// DeleteDirectory deletes the directory and everything in it.
+func (d *fsDirectory) DeleteDirectory(ctx context.Context) error {
+	if d.path == "/" {
+		// Keep the bucket itself
+		return d.DeleteAllWithPrefix(ctx, "")
+	}
+	return errkit.Wrap(d.bucket.fs.RemoveAll(ctx, d.bucket.filePath(d.path)), fmt.Sprintf("Failed to delete directory %s", d.path))
+}
+
+// DeleteAllWithPrefix deletes all objects and directories whose path, relative
+// to the directory, starts with prefix.
+func (d *fsDirectory) DeleteAllWithPrefix(ctx context.Context, prefix string) error {
+	p := path.Join(d.path, prefix)
+	parent, base := path.Dir(p), path.Base(p)
+	if p == "/" {
+		parent, base = "/", ""
+	}
+	if strings.HasSuffix(prefix, "/") {
+		// Only the contents of the directory prefix refers to
+		parent, base = p, ""
+	}
+	entries, err := d.bucket.fs.ReadDir(ctx, d.bucket.filePath(parent))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("Failed to delete item %s", prefix))
+	}
+	for _, e := range entries {
+		name := strings.TrimPrefix(e.Name(), fsTagsPrefix)
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		if err := d.bucket.fs.RemoveAll(ctx, path.Join(d.bucket.filePath(parent), e.Name())); err != nil {
+			return errkit.Wrap(err, fmt.Sprintf("Failed to delete item %s", prefix))
+		}
+	}
+	return nil
+}
+
+// Get opens an object for reading and returns its tags.
+func (d *fsDirectory) Get(ctx context.Context, name string) (io.ReadCloser, map[string]string, error) {
+	objName := d.absPathName(name)
+	if objName == "" {
+		return nil, nil, errkit.New("invalid entry")
+	}
+	tags, err := d.readTags(ctx, objName)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := d.bucket.fs.Open(ctx, d.bucket.filePath(objName))
+	if err != nil {
+		return nil, nil, errkit.Wrap(err, fmt.Sprintf("could not get object %s", objName))
+	}
+	return f, tags, nil
+}
+
+// Stat returns the size, modification time and tags of an object.
+func (d *fsDirectory) Stat(ctx context.Context, name string) (ObjectInfo, error) {
+	objName := d.absPathName(name)
+	if objName == "" {
+		return ObjectInfo{}, errkit.New("invalid entry")
+	}
+	fi, err := d.bucket.fs.Stat(ctx, d.bucket.filePath(objName))
+	if err != nil {
+		return ObjectInfo{}, errkit.Wrap(err, fmt.Sprintf("could not get object %s", objName))
+	}
+	if fi.IsDir() {
+		return ObjectInfo{}, errkit.New(fmt.Sprintf("could not get object %s: is a directory", objName))
+	}
+	tags, err := d.readTags(ctx, objName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Name: name, Size: fi.Size(), ModTime: fi.ModTime(), Tags: tags}, nil
+}
+
+// AddTags adds tags to an object or replaces their values.
+func (d *fsDirectory) AddTags(ctx context.Context, name string, tags map[string]string) error {
+	objName := d.absPathName(name)
+	if objName == "" {
+		return errkit.New("invalid entry")
+	}
+	if _, err := d.bucket.fs.Stat(ctx, d.bucket.filePath(objName)); err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("could not get object %s", objName))
+	}
+	current, err := d.readTags(ctx, objName)
+	if err != nil {
+		return err
+	}
+	maps.Copy(current, tags)
+	return d.writeTags(ctx, objName, current)
+}
+
+// SetStorageClass returns an error. Files do not have storage classes.
+func (d *fsDirectory) SetStorageClass(ctx context.Context, name, class string) error {
+	return errkit.New("Storage classes are not supported", "type", d.bucket.pType)
+}
+
+// Restore returns true. Files are never archived.
+func (d *fsDirectory) Restore(ctx context.Context, name string, opts RestoreOptions) (bool, error) {
+	return true, nil
+}
+
+// Copy copies an object and its tags. Local files are read by the process
+// regardless of the destination.
+func (d *fsDirectory) Copy(ctx context.Context, name string, dst Directory, dstName string) error {
+	return streamCopy(ctx, d, name, dst, dstName)
+}
+
+// GetBytes returns the data and tags of an object.
+func (d *fsDirectory) GetBytes(ctx context.Context, name string) ([]byte, map[string]string, error) {
+	r, tags, err := d.Get(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer r.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, tags, nil
+}
+
+// Put writes an object. The data is written to a temporary file which then
+// replaces the object, so that readers never see partially written objects.
+func (d *fsDirectory) Put(ctx context.Context, name string, r io.Reader, size int64, tags map[string]string) error {
+	objName := d.absPathName(name)
+	if objName == "" {
+		return errkit.New("invalid entry")
+	}
+	p := d.bucket.filePath(objName)
+	if err := d.bucket.fs.MkdirAll(ctx, path.Dir(p)); err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("could not create directory for object %s", objName))
+	}
+	tmp, err := d.bucket.fs.CreateTemp(ctx, path.Dir(p), fsUploadPattern)
+	if err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("could not create object %s", objName))
+	}
+	defer d.bucket.fs.Remove(ctx, tmp.Name()) //nolint:errcheck
+	_, err = io.Copy(tmp, contextReader{ctx: ctx, r: r})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = d.bucket.fs.Chmod(ctx, tmp.Name(), fsFileMode)
+	}
+	if err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("could not write object %s", objName))
+	}
+	if err := d.writeTags(ctx, objName, tags); err != nil {
+		return err
+	}
+	return errkit.Wrap(d.bucket.fs.Rename(ctx, tmp.Name(), p), fmt.Sprintf("could not write object %s", objName))
+}
+
+// PutBytes writes bytes to an object.
+func (d *fsDirectory) PutBytes(ctx context.Context, name string, data []byte, tags map[string]string) error {
+	return d.Put(ctx, name, bytes.NewReader(data), int64(len(data)), tags)
+}
+
+// Delete removes an object. Deleting a missing object is not an error.
+func (d *fsDirectory) Delete(ctx context.Context, name string) error {
+	objName := d.absPathName(name)
+	if objName == "" {
+		return errkit.New("invalid entry")
+	}
+	for _, p := range []string{d.bucket.filePath(objName), d.tagsPath(objName)} {
+		if err := d.bucket.fs.Remove(ctx, p); err != nil && !os.IsNotExist(err) {
+			return errkit.Wrap(err, fmt.Sprintf("could not delete object %s", objName))
+		}
+	}
+	return nil
+}
+
+// tagsPath returns the file that stores the tags of an object.
+func (d *fsDirectory) tagsPath(objName string) string {
+	p := d.bucket.filePath(objName)
+	return path.Join(path.Dir(p), fsTagsPrefix+path.Base(p))
+}
+
+func (d *fsDirectory) readTags(ctx context.Context, objName string) (map[string]string, error) {
+	b, err := d.bucket.fs.ReadFile(ctx, d.tagsPath(objName))
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("could not read tags of object %s", objName))
+	}
+	tags := map[string]string{}
+	if err := json.Unmarshal(b, &tags); err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("could not parse tags of object %s", objName))
+	}
+	return tags, nil
+}
+
+func (d *fsDirectory) writeTags(ctx context.Context, objName string, tags map[string]string) error {
+	p := d.tagsPath(objName)
+	if len(tags) == 0 {
+		if err := d.bucket.fs.Remove(ctx, p); err != nil && !os.IsNotExist(err) {
+			return errkit.Wrap(err, fmt.Sprintf("could not write tags of object %s", objName))
+		}
+		return nil
+	}
+	// Replace any '/' in tags with '-' like other object stores
+	sTags := make(map[string]string, len(tags))
+	for k, v := range sanitizeTags(tags) {
+		sTags[k] = v.(string)
+	}
+	b, err := json.Marshal(sTags)
+	if err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("could not marshal tags of object %s", objName))
+	}
+	return errkit.Wrap(d.bucket.fs.WriteFile(ctx, p, b, fsFileMode), fmt.Sprintf("could not write tags of object %s", objName))
+}
+
+// If name does not start with '/', prefix with d.path. Add '/' as suffix
+func (d *fsDirectory) absDirName(dir string) string {
+	dir = d.absPathName(dir)
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Clean(dir) + "/"
+	}
+	return dir
+}
+
+// If name does not start with '/', prefix with d.path.
+func (d *fsDirectory) absPathName(name string) string {
+	if name == "" {
+		return ""
+	}
+	if !path.IsAbs(name) {
+		name = d.path + name
+	}
+	return name
+}
+
+// contextReader stops reading once the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/check.v1"
+)
+
+type FilesystemSuite struct {
+	root     string
+	provider Provider
+}
+
+var _ = check.Suite(&FilesystemSuite{})
+
+func (s *FilesystemSuite) SetUpTest(c *check.C) {
+	var err error
+	s.root = c.MkDir()
+	s.provider, err = NewProvider(context.Background(), ProviderConfig{Type: ProviderTypeFilesystem, Endpoint: s.root}, nil)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *FilesystemSuite) TestRootBucket(c *check.C) {
+	ctx := context.Background()
+	b, err := GetOrCreateBucket(ctx, s.provider, "")
+	c.Assert(err, check.IsNil)
+	err = b.PutBytes(ctx, "dir/object", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+
+	data, err := os.ReadFile(filepath.Join(s.root, "dir", "object"))
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "data")
+	c.Assert(s.provider.DeleteBucket(ctx, ""), check.NotNil)
+}
+
+func (s *FilesystemSuite) TestPathsStayInBucket(c *check.C) {
+	ctx := context.Background()
+	b, err := s.provider.CreateBucket(ctx, "bucket")
+	c.Assert(err, check.IsNil)
+	_, err = s.provider.CreateBucket(ctx, "../bucket")
+	c.Assert(err, check.NotNil)
+
+	err = b.PutBytes(ctx, "../../escaped", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+	_, err = os.Stat(filepath.Join(s.root, "bucket", "escaped"))
+	c.Assert(err, check.IsNil)
+	_, err = os.Stat(filepath.Join(filepath.Dir(s.root), "escaped"))
+	c.Assert(os.IsNotExist(err), check.Equals, true)
+}
+
+func (s *FilesystemSuite) TestTags(c *check.C) {
+	ctx := context.Background()
+	b, err := s.provider.CreateBucket(ctx, "bucket")
+	c.Assert(err, check.IsNil)
+
+	err = b.PutBytes(ctx, "object", []byte("data"), map[string]string{"a/b": "c"})
+	c.Assert(err, check.IsNil)
+	_, tags, err := b.GetBytes(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(tags, check.DeepEquals, map[string]string{"a-b": "c"})
+
+	// Tags are not listed as objects and are deleted with the object
+	objs, err := b.ListObjects(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(objs, check.DeepEquals, []string{"object"})
+	c.Assert(b.Delete(ctx, "object"), check.IsNil)
+	entries, err := os.ReadDir(filepath.Join(s.root, "bucket"))
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 0)
+}
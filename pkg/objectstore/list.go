@@ -0,0 +1,421 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Listing objects a page at a time. Only the page that is being iterated is
+// held in memory, regardless of the number of objects.
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"time"
+
+	az "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/graymeta/stow"
+	"github.com/kanisterio/errkit"
+)
+
+// DefaultListPageSize is the number of objects that are listed per request if
+// ListOptions do not specify it.
+const DefaultListPageSize = 1000
+
+// ListOptions select the objects that are listed and how they are listed.
+type ListOptions struct {
+	// Prefix lists only the objects whose names start with it.
+	Prefix string
+	// StartAfter lists only the objects whose names sort after it. The name
+	// of the last object of an interrupted listing resumes it.
+	StartAfter string
+	// PageSize is the number of objects that are listed per request. It
+	// defaults to DefaultListPageSize.
+	PageSize int
+	// Recursive lists the objects in subdirectories as well. Their names
+	// include the subdirectories, e.g. "dir/object".
+	Recursive bool
+	// WithTags returns the tags of objects. Some object stores, such as S3,
+	// need a request per object to get them.
+	WithTags bool
+}
+
+// ObjectInfo describes a listed object.
+type ObjectInfo struct {
+	// Name is the name of the object relative to the directory that lists
+	// it.
+	Name    string
+	Size    int64
+	ModTime time.Time
+	// Tags are only set if they are requested with ListOptions.WithTags.
+	Tags map[string]string
+	// MD5 and CRC32C are the hex encoded checksums of the data that the
+	// object store computed. They are only set by Stat, and only if the
+	// object store provides them.
+	MD5    string
+	CRC32C string
+	// StorageClass is the storage class, or access tier on Azure, of the
+	// object. It is only set by Stat.
+	StorageClass string
+}
+
+// nativeListFunc lists the page at cursor of the objects whose names start
+// with dir and are selected by opts, and of the prefixes that group the names
+// of the objects in subdirectories if opts are not recursive. It returns the
+// cursor of the next page, which is empty after the last page.
+type nativeListFunc func(ctx context.Context, dir string, opts ListOptions, cursor string) ([]ObjectInfo, []string, string, error)
+
+// listPageFunc lists the page of objects at cursor. It returns the cursor of
+// the next page, which is empty after the last page. Pages can be empty
+// before the last page.
+type listPageFunc func(ctx context.Context, cursor string) ([]ObjectInfo, string, error)
+
+// ObjectIterator iterates over listed objects in lexical order of their
+// names, fetching a page at a time.
+//
+//	it := dir.IterateObjects(ctx, objectstore.ListOptions{Recursive: true})
+//	for it.Next(ctx) {
+//		obj := it.Object()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ObjectIterator struct {
+	list   listPageFunc
+	cursor string
+	page   []ObjectInfo
+	obj    ObjectInfo
+	done   bool
+	err    error
+}
+
+func newObjectIterator(cursor string, list listPageFunc) *ObjectIterator {
+	return &ObjectIterator{
+		list:   list,
+		cursor: cursor,
+	}
+}
+
+// Next advances to the next object. It returns false when there are no more
+// objects or listing failed.
+func (it *ObjectIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.err = ctx.Err(); it.err != nil {
+			return false
+		}
+		it.page, it.cursor, it.err = it.list(ctx, it.cursor)
+		it.done = it.cursor == ""
+	}
+	it.obj, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Object returns the object Next advanced to.
+func (it *ObjectIterator) Object() ObjectInfo {
+	return it.obj
+}
+
+// Err returns the error listing failed with, if any.
+func (it *ObjectIterator) Err() error {
+	return it.err
+}
+
+func (o ListOptions) withDefaults() ListOptions {
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultListPageSize
+	}
+	return o
+}
+
+// selects reports whether the object of a directory with the given name is
+// listed.
+func (o ListOptions) selects(name string) bool {
+	return name != "" &&
+		strings.HasPrefix(name, o.Prefix) &&
+		name > o.StartAfter &&
+		(o.Recursive || !strings.Contains(name, "/"))
+}
+
+// IterateObjects returns an iterator over the objects in the directory that
+// are selected by opts.
+func (d *directory) IterateObjects(ctx context.Context, opts ListOptions) *ObjectIterator {
+	return d.bucket.iterateObjects(cloudName(d.path), opts)
+}
+
+// iterateObjects iterates over the objects whose names start with dir. The
+// names of the listed objects are relative to dir.
+func (b *bucket) iterateObjects(dir string, opts ListOptions) *ObjectIterator {
+	opts = opts.withDefaults()
+	if list := b.nativeLister(); list != nil {
+		return newObjectIterator("", func(ctx context.Context, cursor string) ([]ObjectInfo, string, error) {
+			objs, _, next, err := list(ctx, dir, opts, cursor)
+			return objs, next, err
+		})
+	}
+	// Memory lists the items from the cursor, so the items before StartAfter
+	// are skipped
+	cursor := stow.CursorStart
+	if opts.StartAfter != "" {
+		cursor = dir + opts.StartAfter
+	}
+	return newObjectIterator(cursor, func(ctx context.Context, cursor string) ([]ObjectInfo, string, error) {
+		c, err := b.stowContainer(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		items, next, err := c.Items(dir+opts.Prefix, cursor, opts.PageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		if stow.IsCursorEnd(next) {
+			next = ""
+		}
+		objs := make([]ObjectInfo, 0, len(items))
+		for _, item := range items {
+			name := strings.TrimPrefix(item.Name(), dir)
+			if !opts.selects(name) {
+				continue
+			}
+			obj, err := b.objectInfo(ctx, item, name, opts.WithTags)
+			if err != nil {
+				return nil, "", err
+			}
+			objs = append(objs, obj)
+		}
+		return objs, next, nil
+	})
+}
+
+// listDirectories returns the names of the subdirectories of dir, without
+// their trailing '/'. Object stores that group names natively only list
+// the objects and subdirectories of dir itself.
+func (b *bucket) listDirectories(ctx context.Context, dir string) ([]string, error) {
+	list := b.nativeLister()
+	if list == nil {
+		var dirs []string
+		it := b.iterateObjects(dir, ListOptions{Recursive: true})
+		for it.Next(ctx) {
+			if d, ok := getFirstDirectoryMarker(it.Object().Name); ok && (len(dirs) == 0 || dirs[len(dirs)-1] != d) {
+				dirs = append(dirs, d)
+			}
+		}
+		return dirs, it.Err()
+	}
+	opts := ListOptions{}.withDefaults()
+	var dirs []string
+	for cursor := ""; ; {
+		_, prefixes, next, err := list(ctx, dir, opts, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prefixes {
+			if d := strings.TrimSuffix(strings.TrimPrefix(p, dir), "/"); d != "" {
+				dirs = append(dirs, d)
+			}
+		}
+		if next == "" {
+			return dirs, nil
+		}
+		cursor = next
+	}
+}
+
+// nativeLister returns the function that lists the object store natively,
+// or nil if it is listed with stow.
+func (b *bucket) nativeLister() nativeListFunc {
+	switch b.config.Type {
+	case ProviderTypeS3:
+		return b.s3List
+	case ProviderTypeGCS:
+		return b.gcsList
+	case ProviderTypeAzure:
+		return b.azureList
+	}
+	return nil
+}
+
+// delimiter returns the delimiter that object stores group the names of
+// objects in subdirectories by, so that they are not listed.
+func (o ListOptions) delimiter() string {
+	if o.Recursive {
+		return ""
+	}
+	return "/"
+}
+
+// s3List lists pages of S3 objects from StartAfter. Pages are fetched with
+// continuation tokens.
+func (b *bucket) s3List(ctx context.Context, dir string, opts ListOptions, cursor string) ([]ObjectInfo, []string, string, error) {
+	client, err := b.renewingS3Client()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	in := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(b.name()),
+		Prefix:  aws.String(dir + opts.Prefix),
+		MaxKeys: aws.Int64(int64(opts.PageSize)),
+	}
+	if d := opts.delimiter(); d != "" {
+		in.Delimiter = aws.String(d)
+	}
+	if opts.StartAfter != "" {
+		in.StartAfter = aws.String(dir + opts.StartAfter)
+	}
+	if cursor != "" {
+		in.ContinuationToken = aws.String(cursor)
+	}
+	out, err := client.ListObjectsV2WithContext(ctx, in)
+	if err != nil {
+		return nil, nil, "", errkit.Wrap(err, "Failed to list objects", "prefix", dir+opts.Prefix)
+	}
+	objs := make([]ObjectInfo, 0, len(out.Contents))
+	for _, o := range out.Contents {
+		key := aws.StringValue(o.Key)
+		obj := ObjectInfo{
+			Name:    strings.TrimPrefix(key, dir),
+			Size:    aws.Int64Value(o.Size),
+			ModTime: aws.TimeValue(o.LastModified),
+		}
+		if !opts.selects(obj.Name) {
+			continue
+		}
+		if opts.WithTags {
+			// Listings do not include the metadata of objects
+			head, err := b.s3Head(ctx, key)
+			if err != nil {
+				return nil, nil, "", err
+			}
+			obj.Tags = s3Tags(head.Metadata)
+		}
+		objs = append(objs, obj)
+	}
+	prefixes := make([]string, 0, len(out.CommonPrefixes))
+	for _, p := range out.CommonPrefixes {
+		prefixes = append(prefixes, aws.StringValue(p.Prefix))
+	}
+	return objs, prefixes, aws.StringValue(out.NextContinuationToken), nil
+}
+
+// gcsList lists pages of GCS objects from StartAfter. Pages are fetched with
+// page tokens.
+func (b *bucket) gcsList(ctx context.Context, dir string, opts ListOptions, cursor string) ([]ObjectInfo, []string, string, error) {
+	svc, err := b.gcsService(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	call := svc.Objects.List(b.name()).Context(ctx).Prefix(dir + opts.Prefix).MaxResults(int64(opts.PageSize))
+	if d := opts.delimiter(); d != "" {
+		call.Delimiter(d)
+	}
+	if opts.StartAfter != "" {
+		// The start offset is inclusive. The object at it is not selected.
+		call.StartOffset(dir + opts.StartAfter)
+	}
+	if cursor != "" {
+		call.PageToken(cursor)
+	}
+	res, err := call.Do()
+	if err != nil {
+		return nil, nil, "", errkit.Wrap(err, "Failed to list objects", "prefix", dir+opts.Prefix)
+	}
+	objs := make([]ObjectInfo, 0, len(res.Items))
+	for _, o := range res.Items {
+		obj := ObjectInfo{
+			Name: strings.TrimPrefix(o.Name, dir),
+			Size: int64(o.Size),
+		}
+		if !opts.selects(obj.Name) {
+			continue
+		}
+		if obj.ModTime, err = time.Parse(time.RFC3339, o.Updated); err != nil {
+			return nil, nil, "", errkit.Wrap(err, "Failed to parse object modification time", "object", o.Name)
+		}
+		if opts.WithTags {
+			obj.Tags = make(map[string]string, len(o.Metadata))
+			maps.Copy(obj.Tags, o.Metadata)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, res.Prefixes, res.NextPageToken, nil
+}
+
+// azureList lists pages of Azure blobs. Pages are fetched with markers.
+// Azure cannot list from a name, so the blobs before StartAfter are skipped
+// as they are listed.
+func (b *bucket) azureList(ctx context.Context, dir string, opts ListOptions, cursor string) ([]ObjectInfo, []string, string, error) {
+	c, err := b.azureContainer(ctx, nil)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	res, err := c.ListBlobs(az.ListBlobsParameters{
+		Prefix:     dir + opts.Prefix,
+		Delimiter:  opts.delimiter(),
+		Marker:     cursor,
+		MaxResults: uint(opts.PageSize),
+		Include:    &az.IncludeBlobDataset{Metadata: opts.WithTags},
+	})
+	if err != nil {
+		return nil, nil, "", errkit.Wrap(err, "Failed to list objects", "prefix", dir+opts.Prefix)
+	}
+	objs := make([]ObjectInfo, 0, len(res.Blobs))
+	for _, blob := range res.Blobs {
+		obj := ObjectInfo{
+			Name:    strings.TrimPrefix(blob.Name, dir),
+			Size:    blob.Properties.ContentLength,
+			ModTime: time.Time(blob.Properties.LastModified),
+		}
+		if !opts.selects(obj.Name) {
+			continue
+		}
+		if opts.WithTags {
+			obj.Tags = make(map[string]string, len(blob.Metadata))
+			maps.Copy(obj.Tags, blob.Metadata)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, res.BlobPrefixes, res.NextMarker, nil
+}
+
+// objectInfo returns the description of a listed item.
+func (b *bucket) objectInfo(ctx context.Context, item stow.Item, name string, withTags bool) (ObjectInfo, error) {
+	obj := ObjectInfo{Name: name}
+	var err error
+	if obj.Size, err = item.Size(); err != nil {
+		return ObjectInfo{}, err
+	}
+	if obj.ModTime, err = item.LastMod(); err != nil {
+		return ObjectInfo{}, err
+	}
+	if !withTags {
+		return obj, nil
+	}
+	if b.config.Type == ProviderTypeS3 && b.config.ServerSideEncryption.customerKey() {
+		// Stow cannot get the tags of objects encrypted with SSE-C
+		head, err := b.s3Head(ctx, item.Name())
+		if err != nil {
+			return ObjectInfo{}, err
+		}
+		obj.Tags = s3Tags(head.Metadata)
+		return obj, nil
+	}
+	md, err := item.Metadata()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	obj.Tags = stringTags(md)
+	return obj, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+type ListSuite struct{}
+
+var _ = check.Suite(&ListSuite{})
+
+func (s *ListSuite) TearDownTest(c *check.C) {
+	DeleteMemoryStore(c.TestName())
+}
+
+// directories returns a directory of a memory and a filesystem bucket with
+// the same objects.
+func (s *ListSuite) directories(c *check.C, names ...string) map[string]Directory {
+	ctx := context.Background()
+	dirs := map[string]Directory{}
+	for _, pc := range []ProviderConfig{
+		{Type: ProviderTypeMemory, Endpoint: c.TestName()},
+		{Type: ProviderTypeFilesystem, Endpoint: c.MkDir()},
+	} {
+		p, err := NewProvider(ctx, pc, nil)
+		c.Assert(err, check.IsNil)
+		b, err := GetOrCreateBucket(ctx, p, "bucket")
+		c.Assert(err, check.IsNil)
+		d, err := b.CreateDirectory(ctx, "dir")
+		c.Assert(err, check.IsNil)
+		for _, name := range names {
+			err := d.PutBytes(ctx, name, []byte(name), map[string]string{"name": name})
+			c.Assert(err, check.IsNil)
+		}
+		dirs[string(pc.Type)] = d
+	}
+	return dirs
+}
+
+func listNames(c *check.C, d Directory, opts ListOptions) []string {
+	ctx := context.Background()
+	names := []string{}
+	it := d.IterateObjects(ctx, opts)
+	for it.Next(ctx) {
+		names = append(names, it.Object().Name)
+	}
+	c.Assert(it.Err(), check.IsNil)
+	return names
+}
+
+func (s *ListSuite) TestIterateObjects(c *check.C) {
+	names := []string{"a", "b-c", "b/c", "b/d/e", "b0", "c"}
+	for pType, d := range s.directories(c, names...) {
+		for _, tc := range []struct {
+			opts     ListOptions
+			expected []string
+		}{
+			{
+				opts:     ListOptions{},
+				expected: []string{"a", "b-c", "b0", "c"},
+			},
+			{
+				opts:     ListOptions{Recursive: true, PageSize: 2},
+				expected: names,
+			},
+			{
+				opts:     ListOptions{Recursive: true, PageSize: 1, Prefix: "b/"},
+				expected: []string{"b/c", "b/d/e"},
+			},
+			{
+				opts:     ListOptions{Recursive: true, PageSize: 2, StartAfter: "b/c"},
+				expected: []string{"b/d/e", "b0", "c"},
+			},
+			{
+				opts:     ListOptions{Prefix: "b", StartAfter: "b-c"},
+				expected: []string{"b0"},
+			},
+			{
+				opts:     ListOptions{Recursive: true, StartAfter: "c"},
+				expected: []string{},
+			},
+		} {
+			c.Check(listNames(c, d, tc.opts), check.DeepEquals, tc.expected, check.Commentf("%s %+v", pType, tc.opts))
+		}
+	}
+}
+
+func (s *ListSuite) TestObjectInfo(c *check.C) {
+	ctx := context.Background()
+	before := time.Now().Add(-time.Minute)
+	for pType, d := range s.directories(c, "object") {
+		it := d.IterateObjects(ctx, ListOptions{})
+		c.Assert(it.Next(ctx), check.Equals, true)
+		obj := it.Object()
+		c.Check(obj.Name, check.Equals, "object", check.Commentf(pType))
+		c.Check(obj.Size, check.Equals, int64(len("object")), check.Commentf(pType))
+		c.Check(obj.ModTime.After(before), check.Equals, true, check.Commentf(pType))
+		c.Check(obj.Tags, check.IsNil, check.Commentf(pType))
+		c.Assert(it.Next(ctx), check.Equals, false)
+
+		it = d.IterateObjects(ctx, ListOptions{WithTags: true})
+		c.Assert(it.Next(ctx), check.Equals, true)
+		c.Check(it.Object().Tags, check.DeepEquals, map[string]string{"name": "object"}, check.Commentf(pType))
+	}
+}
+
+func (s *ListSuite) TestListDirectories(c *check.C) {
+	ctx := context.Background()
+	for pType, d := range s.directories(c, "a", "b-c", "b/c", "b/d/e", "b0/f", "c") {
+		dirs, err := d.ListDirectories(ctx)
+		c.Assert(err, check.IsNil)
+		names := make([]string, 0, len(dirs))
+		for name := range dirs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		c.Check(names, check.DeepEquals, []string{"b", "b0"}, check.Commentf(pType))
+		objs, err := dirs["b"].ListObjects(ctx)
+		c.Assert(err, check.IsNil)
+		c.Check(objs, check.DeepEquals, []string{"c"}, check.Commentf(pType))
+	}
+}
+
+func (s *ListSuite) TestCanceled(c *check.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for pType, d := range s.directories(c, "object") {
+		it := d.IterateObjects(ctx, ListOptions{})
+		c.Check(it.Next(ctx), check.Equals, false, check.Commentf(pType))
+		c.Check(it.Err(), check.Equals, context.Canceled, check.Commentf(pType))
+	}
+}
+
+func (s *ListSuite) TestDeleteAllWithPrefixInPages(c *check.C) {
+	ctx := context.Background()
+	names := make([]string, 0, 2*DefaultListPageSize+1)
+	for i := range cap(names) {
+		names = append(names, fmt.Sprintf("blobs/%05d", i))
+	}
+	d := s.directories(c, append(names, "other")...)[string(ProviderTypeMemory)]
+	err := d.DeleteAllWithPrefix(ctx, "blobs")
+	c.Assert(err, check.IsNil)
+	c.Assert(listNames(c, d, ListOptions{Recursive: true}), check.DeepEquals, []string{"other"})
+}
@@ -0,0 +1,511 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// In-memory stow location for tests. Buckets and directories are the same
+// stow backed implementation used for cloud providers, so tags, prefixes and
+// directory markers behave like they do in object stores.
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // ETags are not used for security
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/kanisterio/errkit"
+)
+
+const (
+	memoryKind       = "kanister-memory"
+	memoryConfigName = "name"
+)
+
+var (
+	memoryLocationsMu sync.Mutex
+	// memoryLocations are the in-memory object stores indexed by their name.
+	// Providers with the same endpoint share the object store, like clients
+	// of the same server.
+	memoryLocations = map[string]*memoryLocation{}
+)
+
+func init() {
+	stow.Register(memoryKind, dialMemory, func(u *url.URL) bool {
+		return u.Scheme == memoryKind
+	}, func(stow.Config) error {
+		return nil
+	})
+}
+
+func memoryConfig(config ProviderConfig) (stowKind string, stowConfig stow.Config, err error) {
+	return memoryKind, stow.ConfigMap{memoryConfigName: config.Endpoint}, nil
+}
+
+// DeleteMemoryStore deletes all buckets of the in-memory object store of
+// providers with the given endpoint.
+func DeleteMemoryStore(endpoint string) {
+	memoryLocationsMu.Lock()
+	defer memoryLocationsMu.Unlock()
+	delete(memoryLocations, endpoint)
+}
+
+func dialMemory(cfg stow.Config) (stow.Location, error) {
+	name, _ := cfg.Config(memoryConfigName)
+	memoryLocationsMu.Lock()
+	defer memoryLocationsMu.Unlock()
+	l, ok := memoryLocations[name]
+	if !ok {
+		l = &memoryLocation{
+			name:       name,
+			containers: map[string]*memoryContainer{},
+		}
+		memoryLocations[name] = l
+	}
+	return l, nil
+}
+
+var _ stow.Location = (*memoryLocation)(nil)
+
+// memoryLocation implements stow.Location in memory.
+type memoryLocation struct {
+	name       string
+	mu         sync.Mutex
+	containers map[string]*memoryContainer
+}
+
+func (l *memoryLocation) Close() error {
+	return nil
+}
+
+func (l *memoryLocation) CreateContainer(name string) (stow.Container, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.containers[name]; ok {
+		return nil, errkit.New(fmt.Sprintf("bucket %s already exists", name))
+	}
+	c := &memoryContainer{
+		location: l.name,
+		name:     name,
+		items:    map[string]*memoryItem{},
+	}
+	l.containers[name] = c
+	return c, nil
+}
+
+func (l *memoryLocation) Containers(prefix string, cursor string, count int) ([]stow.Container, string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := make([]string, 0, len(l.containers))
+	for name := range l.containers {
+		names = append(names, name)
+	}
+	page, next := memoryPage(names, prefix, cursor, count)
+	containers := make([]stow.Container, 0, len(page))
+	for _, name := range page {
+		containers = append(containers, l.containers[name])
+	}
+	return containers, next, nil
+}
+
+func (l *memoryLocation) Container(id string) (stow.Container, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.containers[id]
+	if !ok {
+		return nil, stow.ErrNotFound
+	}
+	return c, nil
+}
+
+func (l *memoryLocation) RemoveContainer(id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.containers[id]
+	if !ok {
+		return stow.ErrNotFound
+	}
+	if c.len() > 0 {
+		return errkit.New(fmt.Sprintf("bucket %s is not empty", id))
+	}
+	delete(l.containers, id)
+	return nil
+}
+
+func (l *memoryLocation) ItemByURL(u *url.URL) (stow.Item, error) {
+	c, err := l.Container(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	return c.Item(strings.TrimPrefix(u.Path, "/"))
+}
+
+var _ stow.Container = (*memoryContainer)(nil)
+
+// memoryContainer implements stow.Container in memory.
+type memoryContainer struct {
+	location string
+	name     string
+	mu       sync.Mutex
+	items    map[string]*memoryItem
+	// uploads are the parts of incomplete multipart uploads
+	uploads    map[string]map[int][]byte
+	nextUpload int
+}
+
+func (c *memoryContainer) ID() string {
+	return c.name
+}
+
+func (c *memoryContainer) Name() string {
+	return c.name
+}
+
+func (c *memoryContainer) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+func (c *memoryContainer) Item(id string) (stow.Item, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok {
+		return nil, stow.ErrNotFound
+	}
+	return item, nil
+}
+
+func (c *memoryContainer) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, 0, len(c.items))
+	for name := range c.items {
+		names = append(names, name)
+	}
+	page, next := memoryPage(names, prefix, cursor, count)
+	items := make([]stow.Item, 0, len(page))
+	for _, name := range page {
+		items = append(items, c.items[name])
+	}
+	return items, next, nil
+}
+
+// RemoveItem removes an item. Like S3, removing a missing item is not an
+// error.
+func (c *memoryContainer) RemoveItem(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, id)
+	return nil
+}
+
+func (c *memoryContainer) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errkit.Wrap(err, fmt.Sprintf("failed to read object %s", name))
+	}
+	md := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		md[k] = v
+	}
+	sum := md5.Sum(data) //nolint:gosec
+	item := &memoryItem{
+		url: &url.URL{
+			Scheme: memoryKind,
+			Host:   c.name,
+			Path:   "/" + name,
+		},
+		name:     name,
+		data:     data,
+		etag:     hex.EncodeToString(sum[:]),
+		lastMod:  time.Now(),
+		metadata: md,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[name] = item
+	return item, nil
+}
+
+var _ multipartUploader = (*memoryContainer)(nil)
+
+func (c *memoryContainer) createUpload(context.Context, string, map[string]interface{}) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.uploads == nil {
+		c.uploads = map[string]map[int][]byte{}
+	}
+	c.nextUpload++
+	id := strconv.Itoa(c.nextUpload)
+	c.uploads[id] = map[int][]byte{}
+	return id, nil
+}
+
+func (c *memoryContainer) uploadPart(_ context.Context, _, uploadID string, part int, data []byte) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	parts, ok := c.uploads[uploadID]
+	if !ok {
+		return "", errkit.Wrap(errUploadNotFound, "no such upload", "uploadID", uploadID)
+	}
+	parts[part] = bytes.Clone(data)
+	sum := md5.Sum(data) //nolint:gosec
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *memoryContainer) completeUpload(_ context.Context, key, uploadID string, parts []completedPart, tags map[string]interface{}) error {
+	c.mu.Lock()
+	uploaded, ok := c.uploads[uploadID]
+	delete(c.uploads, uploadID)
+	c.mu.Unlock()
+	if !ok {
+		return errkit.Wrap(errUploadNotFound, "no such upload", "uploadID", uploadID)
+	}
+	var data []byte
+	for _, p := range parts {
+		d, ok := uploaded[p.Number]
+		if !ok {
+			return errkit.Wrap(errUploadNotFound, "no such part", "uploadID", uploadID, "part", p.Number)
+		}
+		data = append(data, d...)
+	}
+	_, err := c.Put(key, bytes.NewReader(data), int64(len(data)), tags)
+	return err
+}
+
+func (c *memoryContainer) abortUpload(_ context.Context, _, uploadID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.uploads, uploadID)
+	return nil
+}
+
+// copyFrom copies an item of src, which can be c, to id like a server-side
+// copy. The copy is not retained.
+func (c *memoryContainer) copyFrom(src *memoryContainer, srcID, id string) error {
+	item, err := src.Item(srcID)
+	if err != nil {
+		return err
+	}
+	mi := item.(*memoryItem)
+	if mi.archived() {
+		return errArchived(srcID)
+	}
+	_, err = c.Put(id, bytes.NewReader(mi.data), int64(len(mi.data)), mi.metadata)
+	return err
+}
+
+// addMetadata adds metadata to an item or replaces its values.
+func (c *memoryContainer) addMetadata(id string, md map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok {
+		return stow.ErrNotFound
+	}
+	if item.archived() {
+		return errArchived(id)
+	}
+	updated := *item
+	updated.metadata = maps.Clone(item.metadata)
+	if updated.metadata == nil {
+		updated.metadata = make(map[string]interface{}, len(md))
+	}
+	maps.Copy(updated.metadata, md)
+	c.items[id] = &updated
+	return nil
+}
+
+// setStorageClass moves an item to a storage class. Like S3, items in
+// archive storage classes cannot be moved until they are restored, and are
+// not restored after they are moved.
+func (c *memoryContainer) setStorageClass(id, class string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok {
+		return stow.ErrNotFound
+	}
+	if item.archived() {
+		return errArchived(id)
+	}
+	updated := *item
+	updated.storageClass, updated.restore = class, memoryRestoreNone
+	c.items[id] = &updated
+	return nil
+}
+
+// restore restores an archived item. Restores complete when they are polled
+// the second time, to mimic restores that take a while.
+func (c *memoryContainer) restore(id string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok {
+		return false, stow.ErrNotFound
+	}
+	if !item.archived() {
+		return true, nil
+	}
+	updated := *item
+	updated.restore++
+	c.items[id] = &updated
+	return !updated.archived(), nil
+}
+
+// retain retains an item until the given time, like S3 Object Lock.
+func (c *memoryContainer) retain(id string, mode ObjectLockMode, until time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok {
+		return stow.ErrNotFound
+	}
+	retained := *item
+	retained.lockMode, retained.retainUntil = mode, until
+	c.items[id] = &retained
+	return nil
+}
+
+// retention returns the mode and the time until which an item is retained.
+func (c *memoryContainer) retention(id string) (ObjectLockMode, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok {
+		return "", time.Time{}
+	}
+	return item.lockMode, item.retainUntil
+}
+
+// pendingUploads returns the number of incomplete multipart uploads.
+func (c *memoryContainer) pendingUploads() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.uploads)
+}
+
+// expireUploads deletes the pending uploads, like object stores do with
+// uploads that are not completed in time.
+func (c *memoryContainer) expireUploads() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.uploads)
+}
+
+var _ stow.Item = (*memoryItem)(nil)
+
+// memoryItem implements stow.Item in memory. Items are not modified after
+// they are created, objects are replaced by new items instead.
+type memoryItem struct {
+	url      *url.URL
+	name     string
+	data     []byte
+	etag     string
+	lastMod  time.Time
+	metadata map[string]interface{}
+
+	lockMode    ObjectLockMode
+	retainUntil time.Time
+
+	storageClass string
+	restore      memoryRestore
+}
+
+// memoryRestore is the state of the restore of an archived item.
+type memoryRestore int
+
+const (
+	memoryRestoreNone memoryRestore = iota
+	memoryRestoreOngoing
+	memoryRestoreCompleted
+)
+
+// archived reports whether the item cannot be read until it is restored.
+func (i *memoryItem) archived() bool {
+	return ArchiveStorageClass(ProviderTypeMemory, i.storageClass) && i.restore != memoryRestoreCompleted
+}
+
+func errArchived(id string) error {
+	return errkit.New("object is archived and must be restored", "object", id)
+}
+
+func (i *memoryItem) ID() string {
+	return i.name
+}
+
+func (i *memoryItem) Name() string {
+	return i.name
+}
+
+func (i *memoryItem) URL() *url.URL {
+	return i.url
+}
+
+func (i *memoryItem) Size() (int64, error) {
+	return int64(len(i.data)), nil
+}
+
+func (i *memoryItem) Open() (io.ReadCloser, error) {
+	if i.archived() {
+		return nil, errArchived(i.name)
+	}
+	return io.NopCloser(bytes.NewReader(i.data)), nil
+}
+
+func (i *memoryItem) ETag() (string, error) {
+	return i.etag, nil
+}
+
+func (i *memoryItem) LastMod() (time.Time, error) {
+	return i.lastMod, nil
+}
+
+func (i *memoryItem) Metadata() (map[string]interface{}, error) {
+	md := make(map[string]interface{}, len(i.metadata))
+	for k, v := range i.metadata {
+		md[k] = v
+	}
+	return md, nil
+}
+
+// memoryPage returns a page of at most count names that start with prefix, in
+// lexical order like object store listings, beginning at cursor. The returned
+// cursor is the first name of the next page, or empty if there are no more
+// names.
+func memoryPage(names []string, prefix, cursor string, count int) ([]string, string) {
+	sort.Strings(names)
+	page := make([]string, 0, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || name < cursor {
+			continue
+		}
+		if count > 0 && len(page) == count {
+			return page, name
+		}
+		page = append(page, name)
+	}
+	return page, stow.CursorStart
+}
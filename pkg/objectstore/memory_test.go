@@ -0,0 +1,82 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graymeta/stow"
+	"gopkg.in/check.v1"
+)
+
+type MemorySuite struct{}
+
+var _ = check.Suite(&MemorySuite{})
+
+func (s *MemorySuite) TestSharedStore(c *check.C) {
+	ctx := context.Background()
+	pc := ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName()}
+	defer DeleteMemoryStore(pc.Endpoint)
+
+	p1, err := NewProvider(ctx, pc, nil)
+	c.Assert(err, check.IsNil)
+	b1, err := GetOrCreateBucket(ctx, p1, "bucket")
+	c.Assert(err, check.IsNil)
+	err = b1.PutBytes(ctx, "dir/object", []byte("data"), map[string]string{"a/b": "c"})
+	c.Assert(err, check.IsNil)
+
+	// Providers with the same endpoint see the same objects
+	p2, err := NewProvider(ctx, pc, nil)
+	c.Assert(err, check.IsNil)
+	b2, err := p2.GetBucket(ctx, "bucket")
+	c.Assert(err, check.IsNil)
+	data, tags, err := b2.GetBytes(ctx, "dir/object")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "data")
+	c.Assert(tags, check.DeepEquals, map[string]string{"a-b": "c"})
+
+	c.Assert(p2.DeleteBucket(ctx, "bucket"), check.NotNil)
+	DeleteMemoryStore(pc.Endpoint)
+	_, err = p2.GetBucket(ctx, "bucket")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *MemorySuite) TestPages(c *check.C) {
+	l, err := dialMemory(stow.ConfigMap{memoryConfigName: c.TestName()})
+	c.Assert(err, check.IsNil)
+	defer DeleteMemoryStore(c.TestName())
+	cont, err := l.CreateContainer("bucket")
+	c.Assert(err, check.IsNil)
+	for _, name := range []string{"b/2", "a", "b/1", "b/3", "c"} {
+		_, err := cont.Put(name, strings.NewReader(name), int64(len(name)), nil)
+		c.Assert(err, check.IsNil)
+	}
+
+	var names []string
+	cursor := stow.CursorStart
+	for {
+		var items []stow.Item
+		items, cursor, err = cont.Items("b/", cursor, 2)
+		c.Assert(err, check.IsNil)
+		for _, item := range items {
+			names = append(names, item.Name())
+		}
+		if stow.IsCursorEnd(cursor) {
+			break
+		}
+	}
+	c.Assert(names, check.DeepEquals, []string{"b/1", "b/2", "b/3"})
+}
@@ -14,6 +14,8 @@
 
 package objectstore
 
+import "time"
+
 // ProviderConfig describes the config for the object store (which provider to use)
 type ProviderConfig struct {
 	// object store type
@@ -27,6 +29,16 @@ type ProviderConfig struct {
 	// If true, disable SSL verification. If false (the default), SSL
 	// verification is enabled.
 	SkipSSLVerify bool
+	// Transfer configures the parts objects are uploaded and downloaded in.
+	Transfer TransferOptions
+	// ObjectLock retains the objects that are put, if it is set.
+	ObjectLock *ObjectLockOptions
+	// ServerSideEncryption encrypts the objects that are put, if it is set.
+	ServerSideEncryption *ServerSideEncryption
+	// StorageClass is the storage class, or access tier on Azure, of the
+	// objects that are put. The default of the bucket is used if it is
+	// empty.
+	StorageClass string
 }
 
 // SecretAws AWS keys
@@ -47,6 +59,11 @@ type SecretAzure struct {
 	StorageKey string
 	// environment name
 	EnvironmentName string
+	// Azure AD access token for the storage account, used instead of the
+	// storage key
+	AccessToken string
+	// expiration time of the access token
+	AccessTokenExpiresOn time.Time
 }
 
 // SecretGcp GCP credentials
@@ -57,6 +74,22 @@ type SecretGcp struct {
 	ServiceKey string
 }
 
+// SecretSFTP SFTP credentials
+type SecretSFTP struct {
+	// user name
+	Username string
+	// password, if the user authenticates with a password
+	Password string
+	// PEM encoded private key, if the user authenticates with a key
+	PrivateKey string
+	// passphrase of the private key, if it is encrypted
+	Passphrase string
+	// public keys of the server, one per line in the format of
+	// authorized_keys or known_hosts files. Connections to servers with
+	// other keys are rejected.
+	HostKey string
+}
+
 // Secret contains the credentials for different providers
 type Secret struct {
 	// aws
@@ -65,6 +98,8 @@ type Secret struct {
 	Azure *SecretAzure
 	// gcp
 	Gcp *SecretGcp
+	// sftp
+	SFTP *SecretSFTP
 	// type
 	Type SecretType
 }
@@ -0,0 +1,153 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/kanisterio/errkit"
+)
+
+// ObjectLockMode is the mode objects are retained in.
+type ObjectLockMode string
+
+const (
+	// ObjectLockModeGovernance retains objects unless they are deleted by
+	// users with permission to bypass governance retention.
+	ObjectLockModeGovernance ObjectLockMode = s3.ObjectLockModeGovernance
+	// ObjectLockModeCompliance retains objects regardless of permissions.
+	ObjectLockModeCompliance ObjectLockMode = s3.ObjectLockModeCompliance
+)
+
+// ObjectLockOptions retains the objects that are put for Retention after
+// they are written. Only S3 and memory object stores support it.
+type ObjectLockOptions struct {
+	Mode      ObjectLockMode
+	Retention time.Duration
+}
+
+func (o *ObjectLockOptions) retainUntil() time.Time {
+	return time.Now().Add(o.Retention).UTC()
+}
+
+// RetentionError is returned when an object cannot be deleted because it is
+// retained by an object lock.
+type RetentionError struct {
+	Name        string
+	Mode        ObjectLockMode
+	RetainUntil time.Time
+}
+
+func (e *RetentionError) Error() string {
+	return fmt.Sprintf("object '%s' is retained in %s mode until %s", e.Name, e.Mode, e.RetainUntil.Format(time.RFC3339))
+}
+
+// retention returns the mode and the time until which an object is retained.
+// The time is zero if the object is not retained.
+func (b *bucket) retention(ctx context.Context, key string) (ObjectLockMode, time.Time, error) {
+	switch b.config.Type {
+	case ProviderTypeS3:
+		client, err := b.renewingS3Client()
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		alg, ck := b.config.ServerSideEncryption.s3CustomerKey()
+		out, err := client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket:               aws.String(b.name()),
+			Key:                  aws.String(key),
+			SSECustomerAlgorithm: alg,
+			SSECustomerKey:       ck,
+		})
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			return "", time.Time{}, nil
+		}
+		if err != nil {
+			return "", time.Time{}, errkit.Wrap(err, "Failed to get object retention", "object", key)
+		}
+		return ObjectLockMode(aws.StringValue(out.ObjectLockMode)), aws.TimeValue(out.ObjectLockRetainUntilDate), nil
+	case ProviderTypeMemory:
+		c, err := b.stowContainer(ctx)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if mc, ok := c.(*memoryContainer); ok {
+			mode, until := mc.retention(key)
+			return mode, until, nil
+		}
+	}
+	return "", time.Time{}, nil
+}
+
+// checkRetention returns a RetentionError if the object cannot be deleted
+// yet. Objects are only checked if the bucket retains the objects that are
+// put.
+func (b *bucket) checkRetention(ctx context.Context, key string) error {
+	if b.config.ObjectLock == nil {
+		return nil
+	}
+	return b.retained(ctx, key)
+}
+
+// retained returns a RetentionError if the object is retained.
+func (b *bucket) retained(ctx context.Context, key string) error {
+	mode, until, err := b.retention(ctx, key)
+	if err != nil {
+		return err
+	}
+	if until.After(time.Now()) {
+		return &RetentionError{Name: key, Mode: mode, RetainUntil: until}
+	}
+	return nil
+}
+
+// CheckRetention returns a RetentionError for the first object that has
+// prefix in d and is retained by an object lock. It is meant to explain why
+// deleting the objects failed, so objects are checked regardless of the
+// ObjectLock of the provider.
+func CheckRetention(ctx context.Context, d Directory, prefix string) error {
+	dir, ok := stowDirectory(d)
+	if !ok {
+		return nil
+	}
+	it := dir.bucket.iterateObjects("", ListOptions{Prefix: cloudName(filepath.Join(dir.path, prefix)), Recursive: true})
+	for it.Next(ctx) {
+		if err := dir.bucket.retained(ctx, it.Object().Name); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// retain retains an object that was put by an object store that does not
+// retain objects as they are written.
+func (b *bucket) retain(ctx context.Context, key string, lock *ObjectLockOptions) error {
+	if lock == nil || b.config.Type != ProviderTypeMemory {
+		return nil
+	}
+	c, err := b.stowContainer(ctx)
+	if err != nil {
+		return err
+	}
+	if mc, ok := c.(*memoryContainer); ok {
+		return mc.retain(key, lock.Mode, lock.retainUntil())
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+type ObjectLockSuite struct{}
+
+var _ = check.Suite(&ObjectLockSuite{})
+
+func (s *ObjectLockSuite) bucket(c *check.C, lock *ObjectLockOptions) Bucket {
+	ctx := context.Background()
+	pc := ProviderConfig{
+		Type:       ProviderTypeMemory,
+		Endpoint:   c.TestName(),
+		Transfer:   TransferOptions{PartSize: MinPartSize, Concurrency: 1},
+		ObjectLock: lock,
+	}
+	p, err := NewProvider(ctx, pc, nil)
+	c.Assert(err, check.IsNil)
+	b, err := GetOrCreateBucket(ctx, p, "bucket")
+	c.Assert(err, check.IsNil)
+	return b
+}
+
+func (s *ObjectLockSuite) TearDownTest(c *check.C) {
+	DeleteMemoryStore(c.TestName())
+}
+
+func (s *ObjectLockSuite) TestDeleteRetained(c *check.C) {
+	ctx := context.Background()
+	before := time.Now()
+	b := s.bucket(c, &ObjectLockOptions{Mode: ObjectLockModeCompliance, Retention: time.Hour})
+	err := b.PutBytes(ctx, "dir/object", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+
+	err = b.Delete(ctx, "dir/object")
+	var re *RetentionError
+	c.Assert(errors.As(err, &re), check.Equals, true)
+	c.Assert(re.Name, check.Equals, "dir/object")
+	c.Assert(re.Mode, check.Equals, ObjectLockModeCompliance)
+	c.Assert(re.RetainUntil.After(before.Add(time.Hour)), check.Equals, true)
+	c.Assert(err, check.ErrorMatches, "object 'dir/object' is retained in COMPLIANCE mode until .*")
+
+	err = b.DeleteAllWithPrefix(ctx, "dir")
+	c.Assert(errors.As(err, &re), check.Equals, true)
+	_, _, err = b.GetBytes(ctx, "dir/object")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *ObjectLockSuite) TestDeleteExpired(c *check.C) {
+	ctx := context.Background()
+	b := s.bucket(c, &ObjectLockOptions{Mode: ObjectLockModeGovernance, Retention: -time.Second})
+	err := b.PutBytes(ctx, "object", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+	err = b.Delete(ctx, "object")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *ObjectLockSuite) TestPutResumable(c *check.C) {
+	ctx := context.Background()
+	b := s.bucket(c, &ObjectLockOptions{Mode: ObjectLockModeGovernance, Retention: time.Hour})
+	data := bytes.Repeat([]byte("x"), MinPartSize+1)
+	err := PutResumable(ctx, b, "object", bytes.NewReader(data), int64(len(data)), nil)
+	c.Assert(err, check.IsNil)
+
+	// The object is retained but the state of its upload is deleted
+	objs, err := b.ListObjects(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(objs, check.DeepEquals, []string{"object"})
+	err = b.Delete(ctx, "object")
+	c.Assert(err, check.FitsTypeOf, &RetentionError{})
+}
+
+func (s *ObjectLockSuite) TestCheckRetention(c *check.C) {
+	ctx := context.Background()
+	b := s.bucket(c, &ObjectLockOptions{Mode: ObjectLockModeGovernance, Retention: time.Hour})
+	err := b.PutBytes(ctx, "repo/snapshots/0123abcd", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+
+	// Retention is checked even if the provider does not retain the objects
+	// it puts
+	unlocked := s.bucket(c, nil)
+	var re *RetentionError
+	err = CheckRetention(ctx, unlocked, "repo/snapshots/0123")
+	c.Assert(errors.As(err, &re), check.Equals, true)
+	c.Assert(re.Name, check.Equals, "repo/snapshots/0123abcd")
+	c.Assert(re.Mode, check.Equals, ObjectLockModeGovernance)
+	c.Assert(CheckRetention(ctx, unlocked, "repo/data"), check.IsNil)
+}
@@ -76,6 +76,34 @@ type Directory interface {
 	// ListObjects lists all the objects rooted in the current directory
 	ListObjects(context.Context) ([]string, error)
 
+	// IterateObjects returns an iterator over the objects in the current
+	// directory that are selected by the options. Objects are listed a
+	// page at a time.
+	IterateObjects(context.Context, ListOptions) *ObjectIterator
+
+	// Stat returns the size, modification time and tags of the named
+	// object without reading its data
+	Stat(context.Context, string) (ObjectInfo, error)
+
+	// AddTags adds tags to the named object or replaces their values,
+	// keeping its other tags. Some object stores, such as S3, copy the
+	// object onto itself to do so.
+	AddTags(context.Context, string, map[string]string) error
+
+	// SetStorageClass moves the named object to a storage class, or access
+	// tier on Azure, keeping its data and tags
+	SetStorageClass(ctx context.Context, name, class string) error
+
+	// Restore makes the named object readable if it is in an archive
+	// storage class. It returns false until the object is restored, which
+	// can take hours.
+	Restore(context.Context, string, RestoreOptions) (bool, error)
+
+	// Copy copies the named object and its tags to the named object of
+	// the destination directory. Objects are copied by the object store
+	// if both directories are in the same one, and streamed otherwise.
+	Copy(ctx context.Context, name string, dst Directory, dstName string) error
+
 	// Get returns the io interface to read object data
 	Get(context.Context, string) (io.ReadCloser, map[string]string, error)
 
@@ -97,10 +125,33 @@ type Directory interface {
 
 // NewProvider creates a new Provider
 func NewProvider(ctx context.Context, config ProviderConfig, secret *Secret) (Provider, error) {
+	return NewRenewingProvider(ctx, config, staticSecret{secret: secret})
+}
+
+// NewRenewingProvider creates a new Provider whose credentials are obtained
+// from sp. Credentials are renewed before they expire, including during
+// transfers that outlive them.
+func NewRenewingProvider(ctx context.Context, config ProviderConfig, sp SecretProvider) (Provider, error) {
+	if sp == nil {
+		return nil, errkit.New("Secret provider cannot be nil")
+	}
+	if err := config.ServerSideEncryption.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ValidateStorageClass(config.Type, config.StorageClass); err != nil {
+		return nil, err
+	}
+	if config.Type == ProviderTypeFilesystem {
+		// Local files do not need credentials
+		return newFilesystemProvider(config)
+	}
+	if config.Type == ProviderTypeSFTP {
+		return newSFTPProvider(config, sp)
+	}
 	config.Endpoint = providerEndpoint(config)
 	p := &provider{
-		config: config,
-		secret: secret,
+		config:  config,
+		secrets: sp,
 	}
 	if p.config.Type == ProviderTypeS3 {
 		return &s3Provider{provider: p}, nil
@@ -117,7 +168,7 @@ func providerEndpoint(c ProviderConfig) string {
 
 // Supported returns true if the object store type is supported
 func Supported(t ProviderType) bool {
-	return t == ProviderTypeS3 || t == ProviderTypeGCS || t == ProviderTypeAzure
+	return t == ProviderTypeS3 || t == ProviderTypeGCS || t == ProviderTypeAzure || t == ProviderTypeFilesystem || t == ProviderTypeSFTP
 }
 
 func s3Config(ctx context.Context, config ProviderConfig, secret *Secret) (stowKind string, stowConfig stow.Config, err error) {
@@ -176,12 +227,15 @@ func gcsConfig(ctx context.Context, config ProviderConfig, secret *Secret) (stow
 	return stowgcs.Kind, cm, nil
 }
 
-func azureConfig(ctx context.Context, secret *Secret) (stowKind string, stowConfig stow.Config, err error) {
+func azureConfig(ctx context.Context, config ProviderConfig, secret *Secret) (stowKind string, stowConfig stow.Config, err error) {
 	var azAccount, azStorageKey, azEnvName string
 	if secret != nil {
 		if secret.Type != SecretTypeAzStorageAccount {
 			return "", nil, errkit.New(fmt.Sprintf("invalid secret type %s", secret.Type))
 		}
+		if secret.Azure.AccessToken != "" {
+			return azureTokenConfig(config, secret.Azure)
+		}
 		azAccount = secret.Azure.StorageAccount
 		azStorageKey = secret.Azure.StorageKey
 		azEnvName = secret.Azure.EnvironmentName
@@ -212,7 +266,9 @@ func getConfig(ctx context.Context, config ProviderConfig, secret *Secret) (stow
 	case ProviderTypeGCS:
 		return gcsConfig(ctx, config, secret)
 	case ProviderTypeAzure:
-		return azureConfig(ctx, secret)
+		return azureConfig(ctx, config, secret)
+	case ProviderTypeMemory:
+		return memoryConfig(config)
 	default:
 		return "", nil, errkit.New(fmt.Sprintf("unknown or unimplemented object store type %s", config.Type))
 	}
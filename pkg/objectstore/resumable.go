@@ -0,0 +1,505 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Resumable uploads. The state of a multipart upload is stored next to the
+// object in a sidecar object, so that an upload that was interrupted can be
+// resumed from its last completed part by uploading the same object again.
+// The hash of each completed part is stored with it, so that parts whose data
+// changed since they were uploaded are uploaded again.
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	az "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/kanisterio/errkit"
+
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+)
+
+// UploadStateSuffix is appended to the name of an object to name the object
+// that holds the state of its resumable upload.
+const UploadStateSuffix = ".kanister-upload"
+
+// errUploadNotFound is returned by multipart uploaders if the upload, or
+// parts of it, no longer exist, e.g. since they expired.
+var errUploadNotFound = errkit.NewSentinelErr("upload not found")
+
+// uploadState is the state of a resumable upload.
+type uploadState struct {
+	UploadID string          `json:"uploadID,omitempty"`
+	Size     int64           `json:"size"`
+	PartSize int64           `json:"partSize"`
+	Parts    []completedPart `json:"parts,omitempty"`
+}
+
+// completedPart is a part that was uploaded.
+type completedPart struct {
+	Number int    `json:"number"`
+	ETag   string `json:"etag,omitempty"`
+	// Hash is the SHA-256 of the data of the part.
+	Hash string `json:"hash,omitempty"`
+}
+
+// multipartUploader uploads objects in parts that can be uploaded
+// independently of each other and are assembled when the upload is completed.
+type multipartUploader interface {
+	createUpload(ctx context.Context, key string, tags map[string]interface{}) (string, error)
+	uploadPart(ctx context.Context, key, uploadID string, part int, data []byte) (string, error)
+	completeUpload(ctx context.Context, key, uploadID string, parts []completedPart, tags map[string]interface{}) error
+	abortUpload(ctx context.Context, key, uploadID string) error
+}
+
+// PutResumable uploads size bytes read from r to the named object of the
+// directory. If the object store supports multipart uploads, the upload
+// state is stored in a sidecar object until the upload completes, and an
+// interrupted upload of the same data is resumed from its last completed
+// part. Completed parts whose data changed are uploaded again, and uploads
+// are restarted if the size of the data changed or the parts that were
+// uploaded expired. Otherwise the object is uploaded with Put.
+func PutResumable(ctx context.Context, d Directory, name string, r io.ReaderAt, size int64, tags map[string]string) error {
+	dir, ok := stowDirectory(d)
+	if !ok || size == 0 {
+		return d.Put(ctx, name, io.NewSectionReader(r, 0, size), size, tags)
+	}
+	mp, err := dir.bucket.multipart(ctx)
+	if err != nil {
+		return err
+	}
+	if mp == nil {
+		return d.Put(ctx, name, io.NewSectionReader(r, 0, size), size, tags)
+	}
+	if dir.path == "" {
+		return errkit.New("invalid entry")
+	}
+	opts := dir.bucket.config.Transfer.withDefaults()
+	key := cloudName(dir.absPathName(name))
+	err = dir.putMultipart(ctx, mp, name, key, r, size, sanitizeTags(tags), opts)
+	if errkit.Is(err, errUploadNotFound) {
+		log.Info().WithContext(ctx).Print("Restarting upload that no longer exists", field.M{"object": key})
+		if err := d.Delete(ctx, name+UploadStateSuffix); err != nil {
+			return errkit.Wrap(err, "Failed to delete upload state", "object", key)
+		}
+		err = dir.putMultipart(ctx, mp, name, key, r, size, sanitizeTags(tags), opts)
+	}
+	if err != nil {
+		return err
+	}
+	if err := dir.bucket.retain(ctx, key, dir.bucket.config.ObjectLock); err != nil {
+		return err
+	}
+	if err := dir.bucket.applyStorageClass(ctx, key); err != nil {
+		return err
+	}
+	return d.Delete(ctx, name+UploadStateSuffix)
+}
+
+// putMultipart uploads the object in parts, resuming the upload of its state
+// if it is an upload of data of the same size.
+func (d *directory) putMultipart(ctx context.Context, mp multipartUploader, name, key string, r io.ReaderAt, size int64, tags map[string]interface{}, opts TransferOptions) error {
+	state := d.uploadState(ctx, name)
+	if state != nil && (state.Size != size || state.PartSize != opts.PartSize) {
+		log.Info().WithContext(ctx).Print("Restarting upload of object whose size changed", field.M{"object": key})
+		if err := mp.abortUpload(ctx, key, state.UploadID); err != nil {
+			log.Info().WithContext(ctx).WithError(err).Print("Failed to abort upload", field.M{"object": key})
+		}
+		state = nil
+	}
+	if state == nil {
+		id, err := mp.createUpload(ctx, key, tags)
+		if err != nil {
+			return errkit.Wrap(err, "Failed to create upload", "object", key)
+		}
+		state = &uploadState{
+			UploadID: id,
+			Size:     size,
+			PartSize: opts.PartSize,
+		}
+		if err := d.putUploadState(ctx, name, state); err != nil {
+			return err
+		}
+	} else {
+		parts, err := state.unchangedParts(r)
+		if err != nil {
+			return err
+		}
+		if changed := len(state.Parts) - len(parts); changed > 0 {
+			log.Info().WithContext(ctx).Print("Uploading changed parts again", field.M{"object": key, "changedParts": changed})
+		}
+		state.Parts = parts
+		log.Info().WithContext(ctx).Print("Resuming upload", field.M{"object": key, "completedParts": len(state.Parts)})
+	}
+
+	if err := d.uploadRemainingParts(ctx, mp, name, key, r, state, opts.Concurrency); err != nil {
+		return err
+	}
+	sort.Slice(state.Parts, func(i, j int) bool {
+		return state.Parts[i].Number < state.Parts[j].Number
+	})
+	if err := mp.completeUpload(ctx, key, state.UploadID, state.Parts, tags); err != nil {
+		return errkit.Wrap(err, "Failed to complete upload", "object", key)
+	}
+	return nil
+}
+
+// unchangedParts returns the completed parts of the upload whose data in r
+// is the data that was uploaded. The data of all completed parts is read.
+// Parts that were stored without a hash are treated as changed.
+func (s *uploadState) unchangedParts(r io.ReaderAt) ([]completedPart, error) {
+	parts := slices.Clone(s.Parts)
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Number < parts[j].Number
+	})
+	buf := make([]byte, s.PartSize)
+	unchanged := parts[:0]
+	for _, p := range parts {
+		offset := int64(p.Number) * s.PartSize
+		if p.Hash == "" || offset >= s.Size {
+			continue
+		}
+		data := buf[:min(s.PartSize, s.Size-offset)]
+		if _, err := r.ReadAt(data, offset); err != nil && err != io.EOF {
+			return nil, errkit.Wrap(err, "Failed to read data to upload", "offset", offset)
+		}
+		if partHash(data) == p.Hash {
+			unchanged = append(unchanged, p)
+		}
+	}
+	return unchanged, nil
+}
+
+// partHash returns the hash of the data of a part.
+func partHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// uploadRemainingParts uploads the parts that are not completed yet, with up
+// to concurrency parts in parallel. The state is saved after each part.
+func (d *directory) uploadRemainingParts(ctx context.Context, mp multipartUploader, name, key string, r io.ReaderAt, state *uploadState, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	completed := make(map[int]bool, len(state.Parts))
+	for _, p := range state.Parts {
+		completed[p.Number] = true
+	}
+	parts := make(chan int)
+	go func() {
+		defer close(parts)
+		for n := 0; int64(n)*state.PartSize < state.Size; n++ {
+			if completed[n] {
+				continue
+			}
+			select {
+			case parts <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var uploadErr error
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, state.PartSize)
+			for n := range parts {
+				err := d.uploadPart(ctx, mp, name, key, r, state, n, buf, &mu)
+				if err != nil {
+					mu.Lock()
+					if uploadErr == nil {
+						uploadErr = err
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if uploadErr != nil {
+		return uploadErr
+	}
+	return ctx.Err()
+}
+
+func (d *directory) uploadPart(ctx context.Context, mp multipartUploader, name, key string, r io.ReaderAt, state *uploadState, n int, buf []byte, mu *sync.Mutex) error {
+	offset := int64(n) * state.PartSize
+	data := buf[:min(state.PartSize, state.Size-offset)]
+	if _, err := r.ReadAt(data, offset); err != nil && err != io.EOF {
+		return errkit.Wrap(err, "Failed to read data to upload", "offset", offset)
+	}
+	etag, err := mp.uploadPart(ctx, key, state.UploadID, n, data)
+	if err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("Failed to upload part %d", n), "object", key)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	state.Parts = append(state.Parts, completedPart{Number: n, ETag: etag, Hash: partHash(data)})
+	return d.putUploadState(ctx, name, state)
+}
+
+// uploadState returns the state of the upload of the named object, or nil
+// if there is none.
+func (d *directory) uploadState(ctx context.Context, name string) *uploadState {
+	data, _, err := d.GetBytes(ctx, name+UploadStateSuffix)
+	if err != nil {
+		return nil
+	}
+	state := &uploadState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		log.Info().WithContext(ctx).WithError(err).Print("Ignoring invalid upload state", field.M{"object": name})
+		return nil
+	}
+	return state
+}
+
+func (d *directory) putUploadState(ctx context.Context, name string, state *uploadState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errkit.Wrap(err, "Failed to marshal upload state")
+	}
+	// The state is not retained so that it can be deleted once the upload
+	// completes
+	err = d.put(ctx, name+UploadStateSuffix, bytes.NewReader(data), int64(len(data)), nil, nil)
+	return errkit.Wrap(err, "Failed to save upload state", "object", name)
+}
+
+// AbortUploads aborts the incomplete resumable uploads of objects whose name
+// starts with prefix and deletes their state.
+func AbortUploads(ctx context.Context, d Directory, prefix string) error {
+	dir, ok := stowDirectory(d)
+	if !ok {
+		return nil
+	}
+	mp, err := dir.bucket.multipart(ctx)
+	if err != nil || mp == nil {
+		return err
+	}
+	var states []string
+	it := dir.IterateObjects(ctx, ListOptions{Prefix: prefix, Recursive: true})
+	for it.Next(ctx) {
+		if name := it.Object().Name; strings.HasSuffix(name, UploadStateSuffix) {
+			states = append(states, name)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return errkit.Wrap(err, "Failed to list uploads", "prefix", prefix)
+	}
+	for _, s := range states {
+		name := strings.TrimSuffix(s, UploadStateSuffix)
+		key := cloudName(dir.absPathName(name))
+		if state := dir.uploadState(ctx, name); state != nil {
+			if err := mp.abortUpload(ctx, key, state.UploadID); err != nil {
+				return errkit.Wrap(err, "Failed to abort upload", "object", key)
+			}
+		}
+		if err := dir.Delete(ctx, s); err != nil {
+			return errkit.Wrap(err, "Failed to delete upload state", "object", key)
+		}
+	}
+	return nil
+}
+
+// stowDirectory returns the stow backed directory of d.
+func stowDirectory(d Directory) (*directory, bool) {
+	switch d := d.(type) {
+	case *directory:
+		return d, true
+	case *bucket:
+		return d.directory, true
+	default:
+		return nil, false
+	}
+}
+
+// multipart returns the multipart uploader of the bucket, or nil if the
+// object store does not support resumable multipart uploads.
+func (b *bucket) multipart(ctx context.Context) (multipartUploader, error) {
+	switch b.config.Type {
+	case ProviderTypeS3:
+		client, err := b.renewingS3Client()
+		if err != nil {
+			return nil, err
+		}
+		return &s3Multipart{
+			client:       client,
+			bucket:       b.name(),
+			storageClass: b.config.StorageClass,
+			lock:         b.config.ObjectLock,
+			sse:          b.config.ServerSideEncryption,
+		}, nil
+	case ProviderTypeAzure:
+		return &azureMultipart{bucket: b}, nil
+	case ProviderTypeMemory:
+		c, err := b.stowContainer(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if mc, ok := c.(*memoryContainer); ok {
+			return mc, nil
+		}
+	}
+	return nil, nil
+}
+
+var _ multipartUploader = (*s3Multipart)(nil)
+
+// s3Multipart uses S3 multipart uploads.
+type s3Multipart struct {
+	client       *s3.S3
+	bucket       string
+	storageClass string
+	lock         *ObjectLockOptions
+	sse          *ServerSideEncryption
+}
+
+func (m *s3Multipart) createUpload(ctx context.Context, key string, tags map[string]interface{}) (string, error) {
+	in := &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(m.bucket),
+		Key:      aws.String(key),
+		Metadata: aws.StringMap(stringTags(tags)),
+	}
+	if m.storageClass != "" {
+		in.StorageClass = aws.String(m.storageClass)
+	}
+	if m.lock != nil {
+		in.ObjectLockMode = aws.String(string(m.lock.Mode))
+		in.ObjectLockRetainUntilDate = aws.Time(m.lock.retainUntil())
+	}
+	m.sse.applyS3CreateUpload(in)
+	out, err := m.client.CreateMultipartUploadWithContext(ctx, in)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.UploadId), nil
+}
+
+func (m *s3Multipart) uploadPart(ctx context.Context, key, uploadID string, part int, data []byte) (string, error) {
+	alg, ck := m.sse.s3CustomerKey()
+	out, err := m.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:               aws.String(m.bucket),
+		Key:                  aws.String(key),
+		UploadId:             aws.String(uploadID),
+		PartNumber:           aws.Int64(int64(part + 1)),
+		Body:                 bytes.NewReader(data),
+		SSECustomerAlgorithm: alg,
+		SSECustomerKey:       ck,
+	})
+	if err != nil {
+		return "", s3UploadError(err)
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+func (m *s3Multipart) completeUpload(ctx context.Context, key, uploadID string, parts []completedPart, _ map[string]interface{}) error {
+	cp := make([]*s3.CompletedPart, 0, len(parts))
+	for _, p := range parts {
+		cp = append(cp, &s3.CompletedPart{
+			ETag:       aws.String(p.ETag),
+			PartNumber: aws.Int64(int64(p.Number + 1)),
+		})
+	}
+	_, err := m.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(m.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: cp},
+	})
+	return s3UploadError(err)
+}
+
+// s3UploadError returns errUploadNotFound if the upload was aborted, e.g. by
+// a lifecycle rule, and err otherwise.
+func s3UploadError(err error) error {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchUpload {
+		return errkit.Wrap(errUploadNotFound, aerr.Message())
+	}
+	return err
+}
+
+func (m *s3Multipart) abortUpload(ctx context.Context, key, uploadID string) error {
+	_, err := m.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(m.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+var _ multipartUploader = (*azureMultipart)(nil)
+
+// azureMultipart uploads the blocks of block blobs. Blocks that are not
+// committed are kept until the blob is committed, or for a week.
+type azureMultipart struct {
+	bucket *bucket
+}
+
+func (m *azureMultipart) createUpload(context.Context, string, map[string]interface{}) (string, error) {
+	return "", nil
+}
+
+func (m *azureMultipart) uploadPart(ctx context.Context, key, _ string, part int, data []byte) (string, error) {
+	blob, err := m.bucket.azureWriteBlob(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return "", blob.PutBlock(azureBlockID(part), data, nil)
+}
+
+func (m *azureMultipart) completeUpload(ctx context.Context, key, _ string, parts []completedPart, tags map[string]interface{}) error {
+	blob, err := m.bucket.azureWriteBlob(ctx, key)
+	if err != nil {
+		return err
+	}
+	blocks := make([]az.Block, 0, len(parts))
+	for _, p := range parts {
+		blocks = append(blocks, az.Block{ID: azureBlockID(p.Number), Status: az.BlockStatusUncommitted})
+	}
+	blob.Metadata = stringTags(tags)
+	err = blob.PutBlockList(blocks, nil)
+	// Uncommitted blocks are deleted after a week
+	var aerr az.AzureStorageServiceError
+	if errors.As(err, &aerr) && aerr.Code == "InvalidBlockList" {
+		return errkit.Wrap(errUploadNotFound, aerr.Message)
+	}
+	return err
+}
+
+// abortUpload is a no-op. Azure does not delete uncommitted blocks on
+// request.
+func (m *azureMultipart) abortUpload(context.Context, string, string) error {
+	return nil
+}
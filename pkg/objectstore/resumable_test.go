@@ -0,0 +1,191 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/kanisterio/errkit"
+	"gopkg.in/check.v1"
+)
+
+type ResumableSuite struct {
+	bucket Bucket
+	data   []byte
+}
+
+var _ = check.Suite(&ResumableSuite{})
+
+func (s *ResumableSuite) SetUpTest(c *check.C) {
+	ctx := context.Background()
+	pc := ProviderConfig{
+		Type:     ProviderTypeMemory,
+		Endpoint: c.TestName(),
+		Transfer: TransferOptions{PartSize: MinPartSize, Concurrency: 1},
+	}
+	p, err := NewProvider(ctx, pc, nil)
+	c.Assert(err, check.IsNil)
+	s.bucket, err = GetOrCreateBucket(ctx, p, "bucket")
+	c.Assert(err, check.IsNil)
+	s.data = bytes.Repeat([]byte("0123456789"), (2*MinPartSize+10)/10)
+}
+
+func (s *ResumableSuite) TearDownTest(c *check.C) {
+	DeleteMemoryStore(c.TestName())
+}
+
+func (s *ResumableSuite) pendingUploads(c *check.C) int {
+	cont, err := s.bucket.(*bucket).stowContainer(context.Background())
+	c.Assert(err, check.IsNil)
+	return cont.(*memoryContainer).pendingUploads()
+}
+
+// readerAt records the offsets that are read and fails reads at or after
+// failAt if it is positive.
+type readerAt struct {
+	data    []byte
+	failAt  int64
+	mu      sync.Mutex
+	offsets []int64
+}
+
+func (r *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if r.failAt > 0 && off >= r.failAt {
+		return 0, errkit.New("interrupted")
+	}
+	r.mu.Lock()
+	r.offsets = append(r.offsets, off)
+	r.mu.Unlock()
+	return bytes.NewReader(r.data).ReadAt(p, off)
+}
+
+func (s *ResumableSuite) TestResume(c *check.C) {
+	ctx := context.Background()
+	size := int64(len(s.data))
+	r := &readerAt{data: s.data, failAt: MinPartSize}
+	err := PutResumable(ctx, s.bucket, "dir/object", r, size, map[string]string{"a": "b"})
+	c.Assert(err, check.ErrorMatches, ".*interrupted.*")
+	_, _, err = s.bucket.GetBytes(ctx, "dir/object"+UploadStateSuffix)
+	c.Assert(err, check.IsNil)
+
+	// The first part is only read to compare its hash, and not uploaded
+	// again
+	r = &readerAt{data: s.data}
+	err = PutResumable(ctx, s.bucket, "dir/object", r, size, map[string]string{"a": "b"})
+	c.Assert(err, check.IsNil)
+	c.Assert(r.offsets, check.DeepEquals, []int64{0, MinPartSize, 2 * MinPartSize})
+
+	data, tags, err := s.bucket.GetBytes(ctx, "dir/object")
+	c.Assert(err, check.IsNil)
+	c.Assert(bytes.Equal(data, s.data), check.Equals, true)
+	c.Assert(tags, check.DeepEquals, map[string]string{"a": "b"})
+	_, _, err = s.bucket.GetBytes(ctx, "dir/object"+UploadStateSuffix)
+	c.Assert(err, check.NotNil)
+	c.Assert(s.pendingUploads(c), check.Equals, 0)
+}
+
+func (s *ResumableSuite) TestRestartChangedObject(c *check.C) {
+	ctx := context.Background()
+	r := &readerAt{data: s.data, failAt: MinPartSize}
+	err := PutResumable(ctx, s.bucket, "object", r, int64(len(s.data)), nil)
+	c.Assert(err, check.NotNil)
+
+	// An object of a different size is uploaded from the start
+	data := s.data[:MinPartSize+1]
+	r = &readerAt{data: data}
+	err = PutResumable(ctx, s.bucket, "object", r, int64(len(data)), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.offsets, check.DeepEquals, []int64{0, MinPartSize})
+	got, _, err := s.bucket.GetBytes(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(bytes.Equal(got, data), check.Equals, true)
+	c.Assert(s.pendingUploads(c), check.Equals, 0)
+
+	// Completed parts of an object of the same size with different data are
+	// uploaded again
+	r = &readerAt{data: s.data, failAt: MinPartSize}
+	err = PutResumable(ctx, s.bucket, "object", r, int64(len(s.data)), nil)
+	c.Assert(err, check.NotNil)
+	data = bytes.ToUpper(bytes.Repeat([]byte("abcdefghij"), len(s.data)/10))
+	r = &readerAt{data: data}
+	err = PutResumable(ctx, s.bucket, "object", r, int64(len(data)), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.offsets, check.DeepEquals, []int64{0, 0, MinPartSize, 2 * MinPartSize})
+	got, _, err = s.bucket.GetBytes(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(bytes.Equal(got, data), check.Equals, true)
+	c.Assert(s.pendingUploads(c), check.Equals, 0)
+}
+
+func (s *ResumableSuite) TestUploadChangedParts(c *check.C) {
+	ctx := context.Background()
+	r := &readerAt{data: s.data, failAt: 2 * MinPartSize}
+	err := PutResumable(ctx, s.bucket, "object", r, int64(len(s.data)), nil)
+	c.Assert(err, check.NotNil)
+
+	// Only the completed part whose data changed is uploaded again
+	data := bytes.Clone(s.data)
+	copy(data[MinPartSize+1:], "changed")
+	r = &readerAt{data: data}
+	err = PutResumable(ctx, s.bucket, "object", r, int64(len(data)), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.offsets, check.DeepEquals, []int64{0, MinPartSize, MinPartSize, 2 * MinPartSize})
+	got, _, err := s.bucket.GetBytes(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(bytes.Equal(got, data), check.Equals, true)
+	c.Assert(s.pendingUploads(c), check.Equals, 0)
+}
+
+func (s *ResumableSuite) TestRestartExpiredUpload(c *check.C) {
+	ctx := context.Background()
+	r := &readerAt{data: s.data, failAt: MinPartSize}
+	err := PutResumable(ctx, s.bucket, "object", r, int64(len(s.data)), nil)
+	c.Assert(err, check.NotNil)
+	cont, err := s.bucket.(*bucket).stowContainer(ctx)
+	c.Assert(err, check.IsNil)
+	cont.(*memoryContainer).expireUploads()
+
+	// The upload is restarted once its parts are found to be gone
+	r = &readerAt{data: s.data}
+	err = PutResumable(ctx, s.bucket, "object", r, int64(len(s.data)), nil)
+	c.Assert(err, check.IsNil)
+	got, _, err := s.bucket.GetBytes(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(bytes.Equal(got, s.data), check.Equals, true)
+	_, _, err = s.bucket.GetBytes(ctx, "object"+UploadStateSuffix)
+	c.Assert(err, check.NotNil)
+	c.Assert(s.pendingUploads(c), check.Equals, 0)
+}
+
+func (s *ResumableSuite) TestAbortUploads(c *check.C) {
+	ctx := context.Background()
+	for _, name := range []string{"dir/a", "dir/b", "other"} {
+		r := &readerAt{data: s.data, failAt: MinPartSize}
+		err := PutResumable(ctx, s.bucket, name, r, int64(len(s.data)), nil)
+		c.Assert(err, check.NotNil)
+	}
+	c.Assert(s.pendingUploads(c), check.Equals, 3)
+
+	d, err := s.bucket.GetDirectory(ctx, "dir")
+	c.Assert(err, check.IsNil)
+	err = AbortUploads(ctx, d, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(s.pendingUploads(c), check.Equals, 1)
+	objs, err := s.bucket.ListObjects(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(objs, check.DeepEquals, []string{"other" + UploadStateSuffix})
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/kanisterio/errkit"
+)
+
+// SecretRenewalWindow is how long before their expiration credentials are
+// renewed.
+const SecretRenewalWindow = 5 * time.Minute
+
+// SecretProvider provides the credentials of an object store, such as
+// assumed role or workload identity credentials, that expire and have to be
+// renewed.
+type SecretProvider interface {
+	// Secret returns valid credentials and the time they expire. A zero time
+	// means that the credentials do not expire.
+	Secret(ctx context.Context) (*Secret, time.Time, error)
+}
+
+// staticSecret is a SecretProvider for credentials that do not expire.
+type staticSecret struct {
+	secret *Secret
+}
+
+var _ SecretProvider = staticSecret{}
+
+// Secret implements SecretProvider.
+func (s staticSecret) Secret(ctx context.Context) (*Secret, time.Time, error) {
+	return s.secret, time.Time{}, nil
+}
+
+// needsRenewal returns true if credentials expiring at expiresAt have to be
+// renewed.
+func needsRenewal(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && time.Now().Add(SecretRenewalWindow).After(expiresAt)
+}
+
+var _ credentials.ProviderWithContext = (*bucketCredentials)(nil)
+
+// bucketCredentials implements credentials.Provider using the renewed
+// credentials of a bucket, so that S3 clients sign every request with valid
+// credentials.
+type bucketCredentials struct {
+	credentials.Expiry
+	bucket *bucket
+}
+
+// Retrieve implements credentials.Provider.
+func (b *bucketCredentials) Retrieve() (credentials.Value, error) {
+	return b.RetrieveWithContext(context.Background())
+}
+
+// RetrieveWithContext implements credentials.ProviderWithContext.
+func (b *bucketCredentials) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	secret, expiresAt, err := b.bucket.secret(ctx)
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	if secret == nil || secret.Aws == nil {
+		return credentials.Value{}, errkit.New("AWS Secret required to sign S3 requests")
+	}
+	if !expiresAt.IsZero() {
+		b.SetExpiration(expiresAt, SecretRenewalWindow)
+	}
+	return credentials.Value{
+		AccessKeyID:     secret.Aws.AccessKeyID,
+		SecretAccessKey: secret.Aws.SecretAccessKey,
+		SessionToken:    secret.Aws.SessionToken,
+		ProviderName:    "KanisterObjectStore",
+	}, nil
+}
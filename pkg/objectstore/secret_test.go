@@ -0,0 +1,141 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+type RenewingSecretSuite struct{}
+
+var _ = check.Suite(&RenewingSecretSuite{})
+
+// fakeS3 serves the S3 requests used by the object store and records the
+// access key that signed each request.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	signers map[string]string
+}
+
+var credentialRe = regexp.MustCompile(`Credential=([^/]+)/`)
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var key string
+	if m := credentialRe.FindStringSubmatch(r.Header.Get("Authorization")); m != nil {
+		key = m[1]
+	}
+	f.signers[r.Method+" "+r.URL.Path] = key
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Has("location"):
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`)
+	case r.Method == http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = b
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		b, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", fmt.Sprint(len(b)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// expiringSecrets returns new access keys every time it is called. The first
+// keys expire within SecretRenewalWindow.
+type expiringSecrets struct {
+	calls int
+}
+
+func (e *expiringSecrets) Secret(ctx context.Context) (*Secret, time.Time, error) {
+	e.calls++
+	expiresAt := time.Now().Add(time.Hour)
+	if e.calls == 1 {
+		expiresAt = time.Now().Add(SecretRenewalWindow / 2)
+	}
+	return &Secret{
+		Type: SecretTypeAwsAccessKey,
+		Aws: &SecretAws{
+			AccessKeyID:     fmt.Sprintf("key-%d", e.calls),
+			SecretAccessKey: "secret",
+			SessionToken:    "token",
+		},
+	}, expiresAt, nil
+}
+
+func (s *RenewingSecretSuite) TestRenewDuringTransfers(c *check.C) {
+	ctx := context.Background()
+	fs := &fakeS3{objects: map[string][]byte{}, signers: map[string]string{}}
+	srv := httptest.NewServer(fs)
+	defer srv.Close()
+
+	sp := &expiringSecrets{}
+	p, err := NewRenewingProvider(ctx, ProviderConfig{Type: ProviderTypeS3, Endpoint: srv.URL, Region: "us-east-1"}, sp)
+	c.Assert(err, check.IsNil)
+	b, err := p.GetBucket(ctx, "bucket")
+	c.Assert(err, check.IsNil)
+	c.Assert(sp.calls, check.Equals, 1)
+
+	// The first keys are about to expire and are renewed before uploading
+	data := strings.Repeat("kanister", 1024)
+	err = b.Put(ctx, "object", strings.NewReader(data), int64(len(data)), map[string]string{"tag": "value"})
+	c.Assert(err, check.IsNil)
+	c.Assert(sp.calls, check.Equals, 2)
+	c.Assert(fs.signers["PUT /bucket/object"], check.Equals, "key-2")
+
+	// The renewed keys are valid and reused
+	r, _, err := b.Get(ctx, "object")
+	c.Assert(err, check.IsNil)
+	defer r.Close() //nolint:errcheck
+	buf := &bytes.Buffer{}
+	_, err = io.Copy(buf, r)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, data)
+	c.Assert(sp.calls, check.Equals, 2)
+	c.Assert(fs.signers["GET /bucket/object"], check.Equals, "key-2")
+}
+
+func (s *RenewingSecretSuite) TestStaticSecret(c *check.C) {
+	secret := &Secret{Type: SecretTypeAwsAccessKey, Aws: &SecretAws{AccessKeyID: "id"}}
+	got, expiresAt, err := staticSecret{secret: secret}.Secret(context.Background())
+	c.Assert(err, check.IsNil)
+	c.Assert(got, check.Equals, secret)
+	c.Assert(expiresAt.IsZero(), check.Equals, true)
+	c.Assert(needsRenewal(expiresAt), check.Equals, false)
+	c.Assert(needsRenewal(time.Now().Add(time.Minute)), check.Equals, true)
+	c.Assert(needsRenewal(time.Now().Add(time.Hour)), check.Equals, false)
+}
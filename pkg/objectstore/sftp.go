@@ -0,0 +1,442 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Object store on the filesystem of an SFTP server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kanisterio/errkit"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sftpDefaultPort = "22"
+	// sftpDialTimeout is how long connecting to the server and the SSH
+	// handshake may take.
+	sftpDialTimeout = 30 * time.Second
+	// sftpPosixRename is the extension of OpenSSH servers that replaces the
+	// target of renames.
+	sftpPosixRename = "posix-rename@openssh.com"
+)
+
+// sftpIdleTimeout is how long connections are kept open without being used.
+// Providers are not closed, so the connection of a provider is closed once
+// it is idle.
+var sftpIdleTimeout = time.Minute
+
+// newSFTPProvider returns a filesystem provider for the filesystem of an SFTP
+// server. The endpoint is `[sftp://]host[:port][/path]`, where the path is
+// the directory of the buckets. It is the login directory of the user if the
+// path is empty.
+func newSFTPProvider(config ProviderConfig, sp SecretProvider) (Provider, error) {
+	addr, root, err := parseSFTPEndpoint(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &fsProvider{
+		pType: ProviderTypeSFTP,
+		fs:    &sftpFS{addr: addr, secrets: sp},
+		root:  root,
+	}, nil
+}
+
+func parseSFTPEndpoint(endpoint string) (addr, root string, err error) {
+	if endpoint == "" {
+		return "", "", errkit.New("Endpoint of SFTP object store not specified")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "sftp://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", errkit.Wrap(err, "Failed to parse endpoint of SFTP object store", "endpoint", endpoint)
+	}
+	if u.Scheme != "sftp" || u.Hostname() == "" {
+		return "", "", errkit.New("Invalid endpoint of SFTP object store", "endpoint", endpoint)
+	}
+	port := u.Port()
+	if port == "" {
+		port = sftpDefaultPort
+	}
+	root = "."
+	if u.Path != "" && u.Path != "/" {
+		root = path.Clean(u.Path)
+	}
+	return net.JoinHostPort(u.Hostname(), port), root, nil
+}
+
+// sftpClientConfig returns the SSH configuration that authenticates as the
+// user of secret and only accepts the host keys of secret.
+func sftpClientConfig(secret *Secret) (*ssh.ClientConfig, error) {
+	if secret == nil || secret.Type != SecretTypeSFTP || secret.SFTP == nil {
+		return nil, errkit.New("SFTP Secret required to connect to SFTP server")
+	}
+	s := secret.SFTP
+	if s.Username == "" {
+		return nil, errkit.New("SFTP Secret is missing the user name")
+	}
+	var auth []ssh.AuthMethod
+	if s.PrivateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if s.Passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(s.PrivateKey), []byte(s.Passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(s.PrivateKey))
+		}
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to parse SFTP private key")
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if s.Password != "" {
+		auth = append(auth, ssh.Password(s.Password))
+	}
+	if len(auth) == 0 {
+		return nil, errkit.New("SFTP Secret is missing a password or private key")
+	}
+	hostKeys, err := parseHostKeys(s.HostKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{
+		User:            s.Username,
+		Auth:            auth,
+		HostKeyCallback: pinnedHostKeys(hostKeys),
+		Timeout:         sftpDialTimeout,
+	}, nil
+}
+
+// parseHostKeys parses public keys in the format of authorized_keys files,
+// or of known_hosts files such as the output of ssh-keyscan.
+func parseHostKeys(s string) ([]ssh.PublicKey, error) {
+	var keys []ssh.PublicKey
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// The host names of known_hosts lines are parsed as options
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to parse SFTP host key")
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errkit.New("SFTP Secret is missing the host key of the server")
+	}
+	return keys, nil
+}
+
+// pinnedHostKeys accepts servers that have one of keys.
+func pinnedHostKeys(keys []ssh.PublicKey) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		for _, k := range keys {
+			if bytes.Equal(k.Marshal(), key.Marshal()) {
+				return nil
+			}
+		}
+		return errkit.New("SFTP server has an unknown host key", "host", hostname, "fingerprint", ssh.FingerprintSHA256(key))
+	}
+}
+
+var _ fileSystem = (*sftpFS)(nil)
+
+// sftpFS implements fileSystem for the filesystem of an SFTP server. The
+// operations share a connection, which is opened when it is first needed
+// and closed once it is idle.
+type sftpFS struct {
+	addr    string
+	secrets SecretProvider
+
+	mu   sync.Mutex
+	conn *sftpConn
+}
+
+// sftpConn is a connection to an SFTP server.
+type sftpConn struct {
+	*sftp.Client
+	ssh   *ssh.Client
+	users int // Operations and files that use the connection
+	idle  *time.Timer
+}
+
+func (c *sftpConn) close() {
+	_ = c.Client.Close()
+	_ = c.ssh.Close()
+}
+
+// acquire returns the connection to the server, connecting if there is none.
+// It must be released once it is no longer used.
+func (f *sftpFS) acquire(ctx context.Context) (*sftpConn, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn == nil {
+		c, err := f.dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+		f.conn = c
+		go func() {
+			_ = c.ssh.Wait()
+			f.drop(c)
+		}()
+	}
+	c := f.conn
+	if c.idle != nil {
+		c.idle.Stop()
+		c.idle = nil
+	}
+	c.users++
+	return c, nil
+}
+
+// release closes the connection once it is idle for sftpIdleTimeout, or
+// immediately if it was dropped.
+func (f *sftpFS) release(c *sftpConn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c.users--
+	if c.users > 0 {
+		return
+	}
+	if f.conn != c {
+		c.close()
+		return
+	}
+	c.idle = time.AfterFunc(sftpIdleTimeout, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.conn == c && c.users == 0 {
+			f.conn = nil
+			c.close()
+		}
+	})
+}
+
+// drop stops using a connection that was lost, so that the next operation
+// connects again.
+func (f *sftpFS) drop(c *sftpConn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn == c {
+		f.conn = nil
+	}
+}
+
+func (f *sftpFS) dial(ctx context.Context) (*sftpConn, error) {
+	secret, _, err := f.secrets.Secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config, err := sftpClientConfig(secret)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, sftpDialTimeout)
+	defer cancel()
+	nc, err := (&net.Dialer{}).DialContext(ctx, "tcp", f.addr)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to connect to SFTP server", "address", f.addr)
+	}
+	conn, chans, reqs, err := ssh.NewClientConn(nc, f.addr, config)
+	if err != nil {
+		_ = nc.Close()
+		return nil, errkit.Wrap(err, "Failed to connect to SFTP server", "address", f.addr)
+	}
+	sshClient := ssh.NewClient(conn, chans, reqs)
+	// Objects are written to temporary files that are only renamed once
+	// they are complete, so writes may be out of order
+	client, err := sftp.NewClient(sshClient, sftp.UseConcurrentWrites(true))
+	if err != nil {
+		_ = sshClient.Close()
+		return nil, errkit.Wrap(err, "Failed to start SFTP session", "address", f.addr)
+	}
+	return &sftpConn{Client: client, ssh: sshClient}, nil
+}
+
+// do runs op with the connection to the server.
+func (f *sftpFS) do(ctx context.Context, op func(c *sftpConn) error) error {
+	c, err := f.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer f.release(c)
+	return op(c)
+}
+
+func (f *sftpFS) Stat(ctx context.Context, name string) (fi os.FileInfo, err error) {
+	err = f.do(ctx, func(c *sftpConn) error {
+		fi, err = c.Stat(name)
+		return err
+	})
+	return fi, err
+}
+
+func (f *sftpFS) ReadDir(ctx context.Context, name string) ([]os.DirEntry, error) {
+	var entries []os.DirEntry
+	err := f.do(ctx, func(c *sftpConn) error {
+		fis, err := c.ReadDirContext(ctx, name)
+		if err != nil {
+			return err
+		}
+		for _, fi := range fis {
+			entries = append(entries, fs.FileInfoToDirEntry(fi))
+		}
+		return nil
+	})
+	slices.SortFunc(entries, func(a, b os.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, err
+}
+
+func (f *sftpFS) Mkdir(ctx context.Context, name string) error {
+	return f.do(ctx, func(c *sftpConn) error {
+		return c.Mkdir(name)
+	})
+}
+
+func (f *sftpFS) MkdirAll(ctx context.Context, name string) error {
+	return f.do(ctx, func(c *sftpConn) error {
+		return c.MkdirAll(name)
+	})
+}
+
+func (f *sftpFS) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	c, err := f.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	file, err := c.Open(name)
+	if err != nil {
+		f.release(c)
+		return nil, err
+	}
+	return &sftpFile{File: file, release: func() { f.release(c) }}, nil
+}
+
+func (f *sftpFS) CreateTemp(ctx context.Context, dir, pattern string) (tempFile, error) {
+	c, err := f.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for range 10 {
+		name := path.Join(dir, strings.Replace(pattern, "*", fmt.Sprint(rand.Uint32()), 1))
+		file, err := c.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			f.release(c)
+			return nil, err
+		}
+		return &sftpFile{File: file, release: func() { f.release(c) }}, nil
+	}
+	f.release(c)
+	return nil, errkit.New("Failed to create temporary file", "directory", dir)
+}
+
+func (f *sftpFS) Chmod(ctx context.Context, name string, mode os.FileMode) error {
+	return f.do(ctx, func(c *sftpConn) error {
+		return c.Chmod(name, mode)
+	})
+}
+
+func (f *sftpFS) Rename(ctx context.Context, oldpath, newpath string) error {
+	return f.do(ctx, func(c *sftpConn) error {
+		if _, ok := c.HasExtension(sftpPosixRename); ok {
+			return c.PosixRename(oldpath, newpath)
+		}
+		// Plain SFTP renames fail if the target exists
+		if err := c.Remove(newpath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return c.Rename(oldpath, newpath)
+	})
+}
+
+func (f *sftpFS) Remove(ctx context.Context, name string) error {
+	return f.do(ctx, func(c *sftpConn) error {
+		return c.Remove(name)
+	})
+}
+
+func (f *sftpFS) RemoveAll(ctx context.Context, name string) error {
+	return f.do(ctx, func(c *sftpConn) error {
+		if err := c.RemoveAll(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+func (f *sftpFS) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	r, err := f.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() //nolint:errcheck
+	return io.ReadAll(r)
+}
+
+func (f *sftpFS) WriteFile(ctx context.Context, name string, data []byte, mode os.FileMode) error {
+	return f.do(ctx, func(c *sftpConn) error {
+		file, err := c.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(data)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		return c.Chmod(name, mode)
+	})
+}
+
+// sftpFile releases the connection of a file once it is closed.
+type sftpFile struct {
+	*sftp.File
+	release func()
+	once    sync.Once
+}
+
+func (f *sftpFile) Close() error {
+	err := f.File.Close()
+	f.once.Do(f.release)
+	return err
+}
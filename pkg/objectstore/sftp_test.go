@@ -0,0 +1,199 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/kanisterio/kanister/pkg/objectstore/sftptest"
+)
+
+type SFTPSuite struct {
+	root   string
+	server *sftptest.Server
+}
+
+var _ = check.Suite(&SFTPSuite{})
+
+func (s *SFTPSuite) SetUpTest(c *check.C) {
+	var err error
+	s.root = c.MkDir()
+	s.server, err = sftptest.NewServer(s.root)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *SFTPSuite) TearDownTest(c *check.C) {
+	c.Assert(s.server.Close(), check.IsNil)
+}
+
+func (s *SFTPSuite) provider(c *check.C, secret *SecretSFTP) Provider {
+	p, err := NewProvider(context.Background(), ProviderConfig{
+		Type:     ProviderTypeSFTP,
+		Endpoint: "sftp://" + s.server.Addr + s.root,
+	}, &Secret{Type: SecretTypeSFTP, SFTP: secret})
+	c.Assert(err, check.IsNil)
+	return p
+}
+
+func (s *SFTPSuite) passwordSecret() *SecretSFTP {
+	return &SecretSFTP{
+		Username: sftptest.Username,
+		Password: sftptest.Password,
+		HostKey:  s.server.HostKey,
+	}
+}
+
+func (s *SFTPSuite) TestObjects(c *check.C) {
+	ctx := context.Background()
+	p := s.provider(c, s.passwordSecret())
+	b, err := p.CreateBucket(ctx, "bucket")
+	c.Assert(err, check.IsNil)
+
+	err = b.PutBytes(ctx, "dir/object", []byte("data"), map[string]string{"a/b": "c"})
+	c.Assert(err, check.IsNil)
+	data, err := os.ReadFile(filepath.Join(s.root, "bucket", "dir", "object"))
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "data")
+
+	// Objects are replaced
+	err = b.PutBytes(ctx, "dir/object", []byte("new data"), nil)
+	c.Assert(err, check.IsNil)
+	data, tags, err := b.GetBytes(ctx, "dir/object")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "new data")
+	c.Assert(tags, check.DeepEquals, map[string]string{})
+
+	c.Assert(b.AddTags(ctx, "dir/object", map[string]string{"a/b": "c"}), check.IsNil)
+	info, err := b.Stat(ctx, "dir/object")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.Size, check.Equals, int64(8))
+	c.Assert(info.Tags, check.DeepEquals, map[string]string{"a-b": "c"})
+
+	it := b.IterateObjects(ctx, ListOptions{Recursive: true})
+	c.Assert(it.Next(ctx), check.Equals, true)
+	c.Assert(it.Object().Name, check.Equals, "dir/object")
+	c.Assert(it.Next(ctx), check.Equals, false)
+	c.Assert(it.Err(), check.IsNil)
+
+	buckets, err := p.ListBuckets(ctx)
+	c.Assert(err, check.IsNil)
+	c.Assert(buckets, check.HasLen, 1)
+
+	c.Assert(b.DeleteAllWithPrefix(ctx, "dir"), check.IsNil)
+	entries, err := os.ReadDir(filepath.Join(s.root, "bucket"))
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 0)
+	c.Assert(p.DeleteBucket(ctx, "bucket"), check.IsNil)
+}
+
+func (s *SFTPSuite) TestPrivateKey(c *check.C) {
+	ctx := context.Background()
+	p := s.provider(c, &SecretSFTP{
+		Username:   sftptest.Username,
+		PrivateKey: s.server.PrivateKey,
+		HostKey:    s.server.HostKey,
+	})
+	b, err := GetOrCreateBucket(ctx, p, "bucket")
+	c.Assert(err, check.IsNil)
+	c.Assert(b.PutBytes(ctx, "object", []byte("data"), nil), check.IsNil)
+
+	// Wrong credentials are rejected
+	p = s.provider(c, &SecretSFTP{
+		Username: sftptest.Username,
+		Password: "wrong",
+		HostKey:  s.server.HostKey,
+	})
+	_, err = p.GetBucket(ctx, "bucket")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *SFTPSuite) TestHostKeyPinning(c *check.C) {
+	ctx := context.Background()
+	// Keys in the format of ssh-keyscan are accepted
+	secret := s.passwordSecret()
+	secret.HostKey = "# comment\n[127.0.0.1]:22 " + s.server.HostKey + "\n"
+	_, err := s.provider(c, secret).CreateBucket(ctx, "bucket")
+	c.Assert(err, check.IsNil)
+
+	other, err := sftptest.NewServer(c.MkDir())
+	c.Assert(err, check.IsNil)
+	defer other.Close() //nolint:errcheck
+	secret.HostKey = other.HostKey
+	_, err = s.provider(c, secret).GetBucket(ctx, "bucket")
+	c.Assert(err, check.ErrorMatches, ".*unknown host key.*")
+
+	secret.HostKey = ""
+	_, err = s.provider(c, secret).GetBucket(ctx, "bucket")
+	c.Assert(err, check.ErrorMatches, ".*missing the host key.*")
+}
+
+func (s *SFTPSuite) TestConnectionReuse(c *check.C) {
+	defer func(d time.Duration) { sftpIdleTimeout = d }(sftpIdleTimeout)
+	sftpIdleTimeout = 100 * time.Millisecond
+
+	ctx := context.Background()
+	b, err := s.provider(c, s.passwordSecret()).CreateBucket(ctx, "bucket")
+	c.Assert(err, check.IsNil)
+	r, _, err := b.Get(ctx, "missing")
+	c.Assert(err, check.NotNil)
+	c.Assert(r, check.IsNil)
+	c.Assert(b.PutBytes(ctx, "object", []byte("data"), nil), check.IsNil)
+	r, _, err = b.Get(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(s.server.Accepted(), check.Equals, 1)
+
+	// Open objects keep the connection open
+	time.Sleep(3 * sftpIdleTimeout)
+	_, _, err = b.GetBytes(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(s.server.Accepted(), check.Equals, 1)
+	c.Assert(r.Close(), check.IsNil)
+
+	// Idle connections are closed and opened again when needed
+	time.Sleep(3 * sftpIdleTimeout)
+	_, _, err = b.GetBytes(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(s.server.Accepted(), check.Equals, 2)
+}
+
+func (s *SFTPSuite) TestParseEndpoint(c *check.C) {
+	for _, tc := range []struct {
+		endpoint string
+		addr     string
+		root     string
+		err      bool
+	}{
+		{endpoint: "sftp://example.com:2222/srv/backups", addr: "example.com:2222", root: "/srv/backups"},
+		{endpoint: "example.com/srv/backups/", addr: "example.com:22", root: "/srv/backups"},
+		{endpoint: "example.com", addr: "example.com:22", root: "."},
+		{endpoint: "[::1]:2222", addr: "[::1]:2222", root: "."},
+		{endpoint: "", err: true},
+		{endpoint: "s3://example.com", err: true},
+	} {
+		addr, root, err := parseSFTPEndpoint(tc.endpoint)
+		if tc.err {
+			c.Assert(err, check.NotNil, check.Commentf(tc.endpoint))
+			continue
+		}
+		c.Assert(err, check.IsNil, check.Commentf(tc.endpoint))
+		c.Assert(addr, check.Equals, tc.addr)
+		c.Assert(root, check.Equals, tc.root)
+	}
+}
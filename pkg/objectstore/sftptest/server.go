@@ -0,0 +1,206 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sftptest provides an in-process SFTP server for tests of SFTP
+// locations.
+package sftptest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/kanisterio/errkit"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// Username is the user that is accepted by servers.
+	Username = "kanister"
+	// Password is the password of Username.
+	Password = "password"
+)
+
+// Server is an SFTP server listening on the loopback interface. Relative
+// paths are relative to the directory it serves, but clients can access any
+// file of the process.
+type Server struct {
+	// Addr is the address of the server as host:port.
+	Addr string
+	// HostKey is the public key of the server in the format of
+	// authorized_keys files.
+	HostKey string
+	// PrivateKey is a PEM encoded private key that Username can
+	// authenticate with.
+	PrivateKey string
+
+	listener net.Listener
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	accepted int
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server for the directory dir.
+func NewServer(dir string) (*Server, error) {
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		return nil, err
+	}
+	userPub, userPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	userKey, err := ssh.NewPublicKey(userPub)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(userPriv, "")
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if c.User() == Username && string(password) == Password {
+				return nil, nil
+			}
+			return nil, errkit.New("Invalid password")
+		},
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if c.User() == Username && bytes.Equal(key.Marshal(), userKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errkit.New("Invalid key")
+		},
+	}
+	config.AddHostKey(hostSigner)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		Addr:       l.Addr().String(),
+		HostKey:    strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey()))),
+		PrivateKey: string(pem.EncodeToMemory(block)),
+		listener:   l,
+		conns:      map[net.Conn]struct{}{},
+	}
+	s.wg.Add(1)
+	go s.serve(config, dir)
+	return s, nil
+}
+
+// Accepted returns the number of connections the server accepted.
+func (s *Server) Accepted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve(config *ssh.ServerConfig, dir string) {
+	defer s.wg.Done()
+	for {
+		nc, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[nc] = struct{}{}
+		s.accepted++
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(nc, config, dir)
+			s.mu.Lock()
+			delete(s.conns, nc)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// handle serves the sftp subsystem on the sessions of a connection.
+func (s *Server) handle(nc net.Conn, config *ssh.ServerConfig, dir string) {
+	defer nc.Close() //nolint:errcheck
+	conn, chans, reqs, err := ssh.NewServerConn(nc, config)
+	if err != nil {
+		return
+	}
+	defer conn.Close() //nolint:errcheck
+	go ssh.DiscardRequests(reqs)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for nch := range chans {
+		if nch.ChannelType() != "session" {
+			_ = nch.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, reqs, err := nch.Accept()
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer ch.Close() //nolint:errcheck
+			for req := range reqs {
+				ok := req.Type == "subsystem" && subsystem(req.Payload) == "sftp"
+				_ = req.Reply(ok, nil)
+				if !ok {
+					continue
+				}
+				srv, err := sftp.NewServer(ch, sftp.WithServerWorkingDirectory(dir))
+				if err != nil {
+					return
+				}
+				_ = srv.Serve()
+				return
+			}
+		}()
+	}
+}
+
+// subsystem returns the name of the subsystem of a subsystem request.
+func subsystem(payload []byte) string {
+	if len(payload) < 4 {
+		return ""
+	}
+	n := binary.BigEndian.Uint32(payload)
+	if int(n) > len(payload)-4 {
+		return ""
+	}
+	return string(payload[4 : 4+n])
+}
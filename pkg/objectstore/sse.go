@@ -0,0 +1,193 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Server-side encryption. Stow does not set encryption headers, so objects
+// are put and, for SSE-C, read with the clients of the object stores.
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/kanisterio/errkit"
+)
+
+// SSEType is a kind of S3 server-side encryption.
+type SSEType string
+
+const (
+	// SSETypeS3 encrypts objects with keys managed by S3.
+	SSETypeS3 SSEType = "SSE-S3"
+	// SSETypeKMS encrypts objects with a KMS key.
+	SSETypeKMS SSEType = "SSE-KMS"
+	// SSETypeCustomer encrypts objects with a key that is sent with every
+	// request that writes or reads them.
+	SSETypeCustomer SSEType = "SSE-C"
+
+	// SSECustomerKeySize is the size of SSE-C keys.
+	SSECustomerKeySize = 32
+
+	azureEncryptionScopeHeader = "x-ms-encryption-scope"
+)
+
+// ServerSideEncryption configures how the object store encrypts the objects
+// that are put. Type and the keys apply to S3 and EncryptionScope to Azure.
+type ServerSideEncryption struct {
+	Type SSEType
+	// KMSKeyID is the KMS key of SSE-KMS. The AWS managed key is used if it
+	// is empty.
+	KMSKeyID string
+	// CustomerKey is the key of SSE-C.
+	CustomerKey []byte
+	// EncryptionScope is the Azure encryption scope of the blobs that are
+	// put.
+	EncryptionScope string
+}
+
+// Validate returns an error if the configuration is not supported.
+func (e *ServerSideEncryption) Validate() error {
+	if e == nil {
+		return nil
+	}
+	switch e.Type {
+	case "", SSETypeS3, SSETypeKMS:
+	case SSETypeCustomer:
+		if len(e.CustomerKey) != SSECustomerKeySize {
+			return errkit.New("SSE-C key must be 32 bytes")
+		}
+	default:
+		return errkit.New("Unsupported server-side encryption", "type", e.Type)
+	}
+	if e.KMSKeyID != "" && e.Type != SSETypeKMS {
+		return errkit.New("KMS key is only supported with SSE-KMS")
+	}
+	return nil
+}
+
+// customerKey reports whether objects must be read with their key.
+func (e *ServerSideEncryption) customerKey() bool {
+	return e != nil && e.Type == SSETypeCustomer
+}
+
+// s3Encryption returns the values of the x-amz-server-side-encryption and
+// x-amz-server-side-encryption-aws-kms-key-id headers of puts.
+func (e *ServerSideEncryption) s3Encryption() (sse, kmsKeyID *string) {
+	if e == nil {
+		return nil, nil
+	}
+	switch e.Type {
+	case SSETypeS3:
+		return aws.String(s3.ServerSideEncryptionAes256), nil
+	case SSETypeKMS:
+		if e.KMSKeyID != "" {
+			kmsKeyID = aws.String(e.KMSKeyID)
+		}
+		return aws.String(s3.ServerSideEncryptionAwsKms), kmsKeyID
+	}
+	return nil, nil
+}
+
+// s3CustomerKey returns the algorithm and key of requests that write or
+// read objects with SSE-C. The SDK encodes the key and adds its MD5.
+func (e *ServerSideEncryption) s3CustomerKey() (algorithm, key *string) {
+	if !e.customerKey() {
+		return nil, nil
+	}
+	return aws.String(s3.ServerSideEncryptionAes256), aws.String(string(e.CustomerKey))
+}
+
+func (e *ServerSideEncryption) applyS3Upload(in *s3manager.UploadInput) {
+	in.ServerSideEncryption, in.SSEKMSKeyId = e.s3Encryption()
+	in.SSECustomerAlgorithm, in.SSECustomerKey = e.s3CustomerKey()
+}
+
+func (e *ServerSideEncryption) applyS3CreateUpload(in *s3.CreateMultipartUploadInput) {
+	in.ServerSideEncryption, in.SSEKMSKeyId = e.s3Encryption()
+	in.SSECustomerAlgorithm, in.SSECustomerKey = e.s3CustomerKey()
+}
+
+// azureHeaders returns the headers of requests that write blobs.
+func (e *ServerSideEncryption) azureHeaders() map[string]string {
+	if e == nil || e.EncryptionScope == "" {
+		return nil
+	}
+	return map[string]string{azureEncryptionScopeHeader: e.EncryptionScope}
+}
+
+// s3Head returns the properties of an object that is encrypted with SSE-C.
+// Stow cannot get them since the key must be sent with HEAD requests as
+// well.
+func (b *bucket) s3Head(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+	client, err := b.renewingS3Client()
+	if err != nil {
+		return nil, err
+	}
+	alg, ck := b.config.ServerSideEncryption.s3CustomerKey()
+	head, err := client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(b.name()),
+		Key:                  aws.String(key),
+		SSECustomerAlgorithm: alg,
+		SSECustomerKey:       ck,
+	})
+	return head, errkit.Wrap(err, "Failed to get object", "object", key)
+}
+
+// s3Tags returns the tags of an S3 object. Stow lowercases them.
+func s3Tags(md map[string]*string) map[string]string {
+	tags := make(map[string]string, len(md))
+	for k, v := range md {
+		tags[strings.ToLower(k)] = aws.StringValue(v)
+	}
+	return tags
+}
+
+// s3Get opens an object that is encrypted with SSE-C.
+func (b *bucket) s3Get(ctx context.Context, key string) (io.ReadCloser, map[string]string, error) {
+	head, err := b.s3Head(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags := s3Tags(head.Metadata)
+	opts := b.config.Transfer.withDefaults()
+	if size := aws.Int64Value(head.ContentLength); size > opts.PartSize && opts.Concurrency > 1 {
+		get, err := b.rangeGetter(ctx, key, objectVersion{
+			etag:      aws.StringValue(head.ETag),
+			versionID: aws.StringValue(head.VersionId),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return newPartReader(ctx, size, opts.PartSize, opts.Concurrency, get), tags, nil
+	}
+	client, err := b.renewingS3Client()
+	if err != nil {
+		return nil, nil, err
+	}
+	alg, ck := b.config.ServerSideEncryption.s3CustomerKey()
+	out, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket:               aws.String(b.name()),
+		Key:                  aws.String(key),
+		SSECustomerAlgorithm: alg,
+		SSECustomerKey:       ck,
+	})
+	if err != nil {
+		return nil, nil, errkit.Wrap(err, "Failed to get object", "object", key)
+	}
+	return out.Body, tags, nil
+}
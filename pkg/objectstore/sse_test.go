@@ -0,0 +1,112 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"gopkg.in/check.v1"
+)
+
+type SSESuite struct{}
+
+var _ = check.Suite(&SSESuite{})
+
+func (s *SSESuite) TestValidate(c *check.C) {
+	key := bytes.Repeat([]byte{1}, SSECustomerKeySize)
+	for _, tc := range []struct {
+		sse     *ServerSideEncryption
+		checker check.Checker
+	}{
+		{sse: nil, checker: check.IsNil},
+		{sse: &ServerSideEncryption{Type: SSETypeS3}, checker: check.IsNil},
+		{sse: &ServerSideEncryption{Type: SSETypeKMS, KMSKeyID: "alias/backups"}, checker: check.IsNil},
+		{sse: &ServerSideEncryption{Type: SSETypeCustomer, CustomerKey: key}, checker: check.IsNil},
+		{sse: &ServerSideEncryption{EncryptionScope: "backups"}, checker: check.IsNil},
+		{sse: &ServerSideEncryption{Type: SSETypeCustomer, CustomerKey: key[1:]}, checker: check.NotNil},
+		{sse: &ServerSideEncryption{Type: SSETypeS3, KMSKeyID: "alias/backups"}, checker: check.NotNil},
+		{sse: &ServerSideEncryption{Type: "SSE-X"}, checker: check.NotNil},
+	} {
+		c.Check(tc.sse.Validate(), tc.checker, check.Commentf("%+v", tc.sse))
+	}
+
+	_, err := NewProvider(context.Background(), ProviderConfig{
+		Type:                 ProviderTypeMemory,
+		ServerSideEncryption: &ServerSideEncryption{Type: SSETypeCustomer},
+	}, nil)
+	c.Assert(err, check.ErrorMatches, ".*SSE-C key must be 32 bytes.*")
+}
+
+func (s *SSESuite) TestS3Inputs(c *check.C) {
+	key := bytes.Repeat([]byte{1}, SSECustomerKeySize)
+	for _, tc := range []struct {
+		sse         *ServerSideEncryption
+		encryption  *string
+		kmsKeyID    *string
+		algorithm   *string
+		customerKey *string
+	}{
+		{sse: nil},
+		{sse: &ServerSideEncryption{EncryptionScope: "backups"}},
+		{
+			sse:        &ServerSideEncryption{Type: SSETypeS3},
+			encryption: aws.String("AES256"),
+		},
+		{
+			sse:        &ServerSideEncryption{Type: SSETypeKMS},
+			encryption: aws.String("aws:kms"),
+		},
+		{
+			sse:        &ServerSideEncryption{Type: SSETypeKMS, KMSKeyID: "alias/backups"},
+			encryption: aws.String("aws:kms"),
+			kmsKeyID:   aws.String("alias/backups"),
+		},
+		{
+			sse:         &ServerSideEncryption{Type: SSETypeCustomer, CustomerKey: key},
+			algorithm:   aws.String("AES256"),
+			customerKey: aws.String(string(key)),
+		},
+	} {
+		up := &s3manager.UploadInput{}
+		tc.sse.applyS3Upload(up)
+		c.Check(up.ServerSideEncryption, check.DeepEquals, tc.encryption)
+		c.Check(up.SSEKMSKeyId, check.DeepEquals, tc.kmsKeyID)
+		c.Check(up.SSECustomerAlgorithm, check.DeepEquals, tc.algorithm)
+		c.Check(up.SSECustomerKey, check.DeepEquals, tc.customerKey)
+
+		mp := &s3.CreateMultipartUploadInput{}
+		tc.sse.applyS3CreateUpload(mp)
+		c.Check(mp.ServerSideEncryption, check.DeepEquals, tc.encryption)
+		c.Check(mp.SSEKMSKeyId, check.DeepEquals, tc.kmsKeyID)
+		c.Check(mp.SSECustomerAlgorithm, check.DeepEquals, tc.algorithm)
+		c.Check(mp.SSECustomerKey, check.DeepEquals, tc.customerKey)
+
+		// Only SSE-C objects are read with their key
+		c.Check(tc.sse.customerKey(), check.Equals, tc.customerKey != nil)
+	}
+}
+
+func (s *SSESuite) TestAzureHeaders(c *check.C) {
+	var sse *ServerSideEncryption
+	c.Assert(sse.azureHeaders(), check.IsNil)
+	sse = &ServerSideEncryption{Type: SSETypeS3}
+	c.Assert(sse.azureHeaders(), check.IsNil)
+	sse = &ServerSideEncryption{EncryptionScope: "backups"}
+	c.Assert(sse.azureHeaders(), check.DeepEquals, map[string]string{"x-ms-encryption-scope": "backups"})
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Storage classes, and restores of objects in archive storage classes which
+// cannot be read until they are restored.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	stowaz "github.com/graymeta/stow/azure"
+	"github.com/kanisterio/errkit"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// RestoreTier is how fast archived objects are restored, and how much it
+// costs.
+type RestoreTier string
+
+const (
+	// RestoreTierStandard restores objects within hours.
+	RestoreTierStandard RestoreTier = "Standard"
+	// RestoreTierExpedited restores objects within minutes on S3, and with
+	// high priority on Azure.
+	RestoreTierExpedited RestoreTier = "Expedited"
+	// RestoreTierBulk restores objects within a day on S3. It is the same
+	// as RestoreTierStandard on Azure.
+	RestoreTierBulk RestoreTier = "Bulk"
+
+	// azureRehydrateTier is the access tier that archived blobs are
+	// rehydrated to.
+	azureRehydrateTier = blob.AccessTierCool
+)
+
+// RestoreOptions configures restores of archived objects.
+type RestoreOptions struct {
+	// Days is how long S3 keeps the restored copy of an object. It is 1 if
+	// it is not set. Azure rehydrates archived blobs permanently.
+	Days int64
+	// Tier is RestoreTierStandard if it is not set.
+	Tier RestoreTier
+}
+
+var storageClasses = map[ProviderType][]string{
+	ProviderTypeS3:     s3.StorageClass_Values(),
+	ProviderTypeGCS:    {"STANDARD", "NEARLINE", "COLDLINE", "ARCHIVE", "MULTI_REGIONAL", "REGIONAL", "DURABLE_REDUCED_AVAILABILITY"},
+	ProviderTypeAzure:  {"Hot", "Cool", "Cold", "Archive"},
+	ProviderTypeMemory: s3.StorageClass_Values(),
+}
+
+// ValidateStorageClass returns an error if objects cannot be put in the
+// storage class, or access tier on Azure, in object stores of type t. The
+// empty storage class is the default of the bucket.
+func ValidateStorageClass(t ProviderType, class string) error {
+	if class == "" || slices.Contains(storageClasses[t], class) {
+		return nil
+	}
+	return errkit.New(fmt.Sprintf("Unsupported storage class '%s'", class), "type", t)
+}
+
+// ArchiveStorageClass reports whether objects in the storage class of object
+// stores of type t must be restored before they can be read, copied or have
+// their tags changed.
+func ArchiveStorageClass(t ProviderType, class string) bool {
+	switch t {
+	case ProviderTypeS3, ProviderTypeMemory:
+		return class == s3.StorageClassGlacier || class == s3.StorageClassDeepArchive
+	case ProviderTypeAzure:
+		return class == string(blob.AccessTierArchive)
+	}
+	return false
+}
+
+// SetStorageClass moves the named object to the storage class, keeping its
+// data and tags. S3 copies the object onto itself to do so.
+func (d *directory) SetStorageClass(ctx context.Context, name, class string) error {
+	if d.path == "" {
+		return errkit.New("invalid entry")
+	}
+	if err := ValidateStorageClass(d.bucket.config.Type, class); err != nil {
+		return err
+	}
+	return d.bucket.setStorageClass(ctx, cloudName(d.absPathName(name)), class)
+}
+
+func (b *bucket) setStorageClass(ctx context.Context, key, class string) error {
+	switch b.config.Type {
+	case ProviderTypeS3:
+		info, err := b.s3Stat(ctx, key)
+		if err != nil {
+			return err
+		}
+		err = b.s3Copy(ctx, b, key, key, info.Size, info.Tags, class)
+		return errkit.Wrap(err, "Failed to set storage class", "object", key)
+	case ProviderTypeGCS:
+		err := b.gcsRewrite(ctx, b, key, key, &storage.Object{StorageClass: class})
+		return errkit.Wrap(err, "Failed to set storage class", "object", key)
+	case ProviderTypeAzure:
+		bc, err := b.azureBlobClient(ctx, key)
+		if err != nil {
+			return err
+		}
+		_, err = bc.SetTier(ctx, blob.AccessTier(class), nil)
+		return errkit.Wrap(err, "Failed to set access tier", "object", key)
+	case ProviderTypeMemory:
+		c, err := b.stowContainer(ctx)
+		if err != nil {
+			return err
+		}
+		if mc, ok := c.(*memoryContainer); ok {
+			return mc.setStorageClass(key, class)
+		}
+	}
+	return errkit.New("Storage classes are not supported", "type", b.config.Type)
+}
+
+// applyStorageClass moves an object that was put to the storage class of the
+// bucket if the object store cannot put objects in it directly.
+func (b *bucket) applyStorageClass(ctx context.Context, key string) error {
+	class := b.config.StorageClass
+	if class == "" || (b.config.Type != ProviderTypeAzure && b.config.Type != ProviderTypeMemory) {
+		return nil
+	}
+	return b.setStorageClass(ctx, key, class)
+}
+
+// Restore makes the named object readable if it is archived. It returns true
+// if the object can be read, and false while it is restored, which takes
+// minutes to hours. The restore is started by the first call.
+func (d *directory) Restore(ctx context.Context, name string, opts RestoreOptions) (bool, error) {
+	if d.path == "" {
+		return false, errkit.New("invalid entry")
+	}
+	if opts.Days == 0 {
+		opts.Days = 1
+	}
+	if opts.Tier == "" {
+		opts.Tier = RestoreTierStandard
+	}
+	key := cloudName(d.absPathName(name))
+	switch d.bucket.config.Type {
+	case ProviderTypeS3:
+		return d.bucket.s3Restore(ctx, key, opts)
+	case ProviderTypeAzure:
+		return d.bucket.azureRestore(ctx, key, opts)
+	case ProviderTypeMemory:
+		c, err := d.bucket.stowContainer(ctx)
+		if err != nil {
+			return false, err
+		}
+		if mc, ok := c.(*memoryContainer); ok {
+			return mc.restore(key)
+		}
+	}
+	// Objects in the archive storage class of GCS can be read
+	return true, nil
+}
+
+// s3Restore restores objects in the Glacier storage classes, and objects
+// that Intelligent-Tiering moved to its archive tiers. Restored copies of
+// Glacier objects expire after opts.Days.
+func (b *bucket) s3Restore(ctx context.Context, key string, opts RestoreOptions) (bool, error) {
+	head, err := b.s3Head(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	class := aws.StringValue(head.StorageClass)
+	if !ArchiveStorageClass(ProviderTypeS3, class) && head.ArchiveStatus == nil {
+		return true, nil
+	}
+	// The Restore header is only set once a restore was requested
+	if restore := aws.StringValue(head.Restore); restore != "" {
+		return strings.Contains(restore, `ongoing-request="false"`), nil
+	}
+	client, err := b.renewingS3Client()
+	if err != nil {
+		return false, err
+	}
+	req := &s3.RestoreRequest{
+		GlacierJobParameters: &s3.GlacierJobParameters{Tier: aws.String(string(opts.Tier))},
+	}
+	if head.ArchiveStatus == nil {
+		// Objects that are restored from Intelligent-Tiering do not expire
+		req.Days = aws.Int64(opts.Days)
+	}
+	_, err = client.RestoreObjectWithContext(ctx, &s3.RestoreObjectInput{
+		Bucket:         aws.String(b.name()),
+		Key:            aws.String(key),
+		RestoreRequest: req,
+	})
+	var aerr awserr.Error
+	if errors.As(err, &aerr) && aerr.Code() == "RestoreAlreadyInProgress" {
+		return false, nil
+	}
+	return false, errkit.Wrap(err, "Failed to restore object", "object", key)
+}
+
+// azureRestore rehydrates archived blobs to the cool access tier.
+func (b *bucket) azureRestore(ctx context.Context, key string, opts RestoreOptions) (bool, error) {
+	bc, err := b.azureBlobClient(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	props, err := bc.GetProperties(ctx, nil)
+	if err != nil {
+		return false, errkit.Wrap(err, "Failed to get object", "object", key)
+	}
+	if blob.AccessTier(aws.StringValue(props.AccessTier)) != blob.AccessTierArchive {
+		return true, nil
+	}
+	// Blobs that are rehydrated have an archive status until they are moved
+	if props.ArchiveStatus != nil {
+		return false, nil
+	}
+	priority := blob.RehydratePriorityStandard
+	if opts.Tier == RestoreTierExpedited {
+		priority = blob.RehydratePriorityHigh
+	}
+	_, err = bc.SetTier(ctx, azureRehydrateTier, &blob.SetTierOptions{RehydratePriority: to.Ptr(priority)})
+	return false, errkit.Wrap(err, "Failed to rehydrate object", "object", key)
+}
+
+// azureBlobClient returns a client of the named blob that supports access
+// tiers, which the client of stow does not.
+func (b *bucket) azureBlobClient(ctx context.Context, key string) (*blob.Client, error) {
+	secret, _, err := b.secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tc, ok := b.azureTokenContainer(); ok {
+		// Stow replaces spaces in blob names
+		return tc.svc.NewContainerClient(b.name()).NewBlobClient(strings.ReplaceAll(key, " ", "+")), nil
+	}
+	_, cfg, err := azureConfig(ctx, b.config, secret)
+	if err != nil {
+		return nil, err
+	}
+	account, _ := cfg.Config(stowaz.ConfigAccount)
+	accountKey, _ := cfg.Config(stowaz.ConfigKey)
+	envName, _ := cfg.Config(stowaz.ConfigEnvName)
+	serviceURL, err := azureServiceURL(b.config.Endpoint, account, envName)
+	if err != nil {
+		return nil, err
+	}
+	cred, err := service.NewSharedKeyCredential(account, accountKey)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create Azure credential")
+	}
+	svc, err := service.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create Azure client")
+	}
+	// Stow replaces spaces in blob names
+	return svc.NewContainerClient(b.name()).NewBlobClient(strings.ReplaceAll(key, " ", "+")), nil
+}
+
+// azureServiceURL returns the URL of the blob service of the storage account.
+// The endpoint of the profile is used if it is set, since the account may not
+// be reached at the default endpoint of its cloud.
+func azureServiceURL(endpoint, account, envName string) (string, error) {
+	if endpoint != "" {
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "https://" + endpoint
+		}
+		return strings.TrimSuffix(endpoint, "/") + "/", nil
+	}
+	env := azure.PublicCloud
+	if envName != "" {
+		var err error
+		if env, err = azure.EnvironmentFromName(envName); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("https://%s.blob.%s/", account, env.StorageEndpointSuffix), nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+
+	"gopkg.in/check.v1"
+)
+
+type StorageClassSuite struct{}
+
+var _ = check.Suite(&StorageClassSuite{})
+
+func (s *StorageClassSuite) TearDownTest(c *check.C) {
+	DeleteMemoryStore(c.TestName())
+}
+
+func (s *StorageClassSuite) TestValidateStorageClass(c *check.C) {
+	for _, tc := range []struct {
+		t       ProviderType
+		class   string
+		checker check.Checker
+		archive bool
+	}{
+		{ProviderTypeS3, "", check.IsNil, false},
+		{ProviderTypeS3, "GLACIER_IR", check.IsNil, false},
+		{ProviderTypeS3, "DEEP_ARCHIVE", check.IsNil, true},
+		{ProviderTypeS3, "Archive", check.NotNil, false},
+		{ProviderTypeGCS, "ARCHIVE", check.IsNil, false},
+		{ProviderTypeAzure, "Cool", check.IsNil, false},
+		{ProviderTypeAzure, "Archive", check.IsNil, true},
+		{ProviderTypeFilesystem, "", check.IsNil, false},
+		{ProviderTypeFilesystem, "STANDARD", check.NotNil, false},
+	} {
+		c.Check(ValidateStorageClass(tc.t, tc.class), tc.checker, check.Commentf("%s %s", tc.t, tc.class))
+		c.Check(ArchiveStorageClass(tc.t, tc.class), check.Equals, tc.archive, check.Commentf("%s %s", tc.t, tc.class))
+	}
+
+	_, err := NewProvider(context.Background(), ProviderConfig{Type: ProviderTypeGCS, StorageClass: "GLACIER"}, nil)
+	c.Assert(err, check.ErrorMatches, ".*Unsupported storage class 'GLACIER'.*")
+}
+
+func (s *StorageClassSuite) TestAzureServiceURL(c *check.C) {
+	for _, tc := range []struct {
+		endpoint string
+		envName  string
+		url      string
+	}{
+		{"", "", "https://account.blob.core.windows.net/"},
+		{"", "AzureChinaCloud", "https://account.blob.core.chinacloudapi.cn/"},
+		{"http://127.0.0.1:10000/account", "", "http://127.0.0.1:10000/account/"},
+		{"account.blob.example.com/", "AzureChinaCloud", "https://account.blob.example.com/"},
+	} {
+		url, err := azureServiceURL(tc.endpoint, "account", tc.envName)
+		c.Assert(err, check.IsNil)
+		c.Check(url, check.Equals, tc.url, check.Commentf("%s %s", tc.endpoint, tc.envName))
+	}
+	_, err := azureServiceURL("", "account", "MoonCloud")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *StorageClassSuite) TestPutAndRestore(c *check.C) {
+	ctx := context.Background()
+	pc := ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName()}
+	d := (&CopySuite{}).directory(c, pc, "bucket")
+	pc.StorageClass = "GLACIER"
+	archive := (&CopySuite{}).directory(c, pc, "bucket")
+
+	err := d.PutBytes(ctx, "standard", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+	err = archive.PutBytes(ctx, "archived", []byte("data"), map[string]string{"a": "1"})
+	c.Assert(err, check.IsNil)
+	info, err := d.Stat(ctx, "standard")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.StorageClass, check.Equals, "")
+	info, err = d.Stat(ctx, "archived")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.StorageClass, check.Equals, "GLACIER")
+
+	// Archived objects cannot be read, copied or tagged until they are
+	// restored
+	_, _, err = d.GetBytes(ctx, "archived")
+	c.Assert(err, check.ErrorMatches, ".*object is archived.*")
+	err = d.Copy(ctx, "archived", d, "copy")
+	c.Assert(err, check.NotNil)
+	err = d.AddTags(ctx, "archived", map[string]string{"b": "2"})
+	c.Assert(err, check.NotNil)
+
+	ok, err := d.Restore(ctx, "standard", RestoreOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ok, check.Equals, true)
+	ok, err = d.Restore(ctx, "archived", RestoreOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ok, check.Equals, false)
+	ok, err = d.Restore(ctx, "archived", RestoreOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ok, check.Equals, true)
+	data, tags, err := d.GetBytes(ctx, "archived")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "data")
+	c.Assert(tags, check.DeepEquals, map[string]string{"a": "1"})
+
+	_, err = d.Restore(ctx, "missing", RestoreOptions{})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *StorageClassSuite) TestSetStorageClass(c *check.C) {
+	ctx := context.Background()
+	d := (&CopySuite{}).directory(c, ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName()}, "bucket")
+	err := d.PutBytes(ctx, "object", []byte("data"), map[string]string{"a": "1"})
+	c.Assert(err, check.IsNil)
+
+	err = d.SetStorageClass(ctx, "object", "STANDARD_IA")
+	c.Assert(err, check.IsNil)
+	info, err := d.Stat(ctx, "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(info.StorageClass, check.Equals, "STANDARD_IA")
+	c.Assert(info.Tags, check.DeepEquals, map[string]string{"a": "1"})
+
+	err = d.SetStorageClass(ctx, "object", "Hot")
+	c.Assert(err, check.ErrorMatches, ".*Unsupported storage class 'Hot'.*")
+
+	fs := (&CopySuite{}).directory(c, ProviderConfig{Type: ProviderTypeFilesystem, Endpoint: c.MkDir()}, "bucket")
+	err = fs.PutBytes(ctx, "object", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+	err = fs.SetStorageClass(ctx, "object", "STANDARD")
+	c.Assert(err, check.NotNil)
+	ok, err := fs.Restore(ctx, "object", RestoreOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ok, check.Equals, true)
+}
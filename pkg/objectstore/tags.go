@@ -0,0 +1,159 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Tags and checksums of objects that were already put.
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"maps"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/graymeta/stow"
+	"github.com/kanisterio/errkit"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// AddTags adds tags to an object or replaces their values.
+func (d *directory) AddTags(ctx context.Context, name string, tags map[string]string) error {
+	if d.path == "" {
+		return errkit.New("invalid entry")
+	}
+	objName := d.absPathName(name)
+	key := cloudName(objName)
+	sTags := stringTags(sanitizeTags(tags))
+	switch d.bucket.config.Type {
+	case ProviderTypeS3:
+		info, err := d.bucket.s3Stat(ctx, key)
+		if err != nil {
+			return err
+		}
+		maps.Copy(info.Tags, sTags)
+		err = d.bucket.s3Copy(ctx, d.bucket, key, key, info.Size, info.Tags, info.StorageClass)
+		return errkit.Wrap(err, "Failed to set object metadata", "object", key)
+	case ProviderTypeGCS:
+		svc, err := d.bucket.gcsService(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = svc.Objects.Patch(d.bucket.name(), key, &storage.Object{Metadata: sTags}).Context(ctx).Do()
+		return errkit.Wrap(err, "Failed to set object metadata", "object", key)
+	case ProviderTypeAzure:
+		blob, err := d.bucket.azureWriteBlob(ctx, key)
+		if err != nil {
+			return err
+		}
+		if err := blob.GetMetadata(nil); err != nil {
+			return errkit.Wrap(err, "Failed to get object metadata", "object", key)
+		}
+		if blob.Metadata == nil {
+			blob.Metadata = sTags
+		} else {
+			maps.Copy(blob.Metadata, sTags)
+		}
+		return errkit.Wrap(blob.SetMetadata(nil), "Failed to set object metadata", "object", key)
+	case ProviderTypeMemory:
+		c, err := d.bucket.stowContainer(ctx)
+		if err != nil {
+			return err
+		}
+		if mc, ok := c.(*memoryContainer); ok {
+			return mc.addMetadata(key, sanitizeTags(tags))
+		}
+	}
+	return errkit.New("Adding tags is not supported", "type", d.bucket.config.Type)
+}
+
+// s3Stat returns the properties of an S3 object. The MD5 is only set for
+// objects that were put with a single request and are not encrypted with
+// KMS or customer keys, whose ETag is the MD5 of their data.
+func (b *bucket) s3Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	head, err := b.s3Head(ctx, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info := ObjectInfo{
+		Size:         aws.Int64Value(head.ContentLength),
+		ModTime:      aws.TimeValue(head.LastModified),
+		Tags:         s3Tags(head.Metadata),
+		StorageClass: aws.StringValue(head.StorageClass),
+	}
+	if info.StorageClass == "" {
+		// S3 omits the default storage class
+		info.StorageClass = s3.StorageClassStandard
+	}
+	if aws.StringValue(head.ServerSideEncryption) == s3.ServerSideEncryptionAwsKms || head.SSECustomerAlgorithm != nil {
+		return info, nil
+	}
+	etag := strings.Trim(aws.StringValue(head.ETag), `"`)
+	if _, err := hex.DecodeString(etag); err == nil && len(etag) == 32 {
+		info.MD5 = etag
+	}
+	return info, nil
+}
+
+// statNative sets the checksums of the data of an object that the object
+// store computed, if any, and its storage class.
+func (b *bucket) statNative(ctx context.Context, key string, item stow.Item, info *ObjectInfo) error {
+	var err error
+	switch b.config.Type {
+	case ProviderTypeMemory:
+		// ETags of the memory store are always the MD5
+		info.MD5, err = item.ETag()
+		if mi, ok := item.(*memoryItem); ok {
+			info.StorageClass = mi.storageClass
+		}
+		return err
+	case ProviderTypeGCS:
+		svc, err := b.gcsService(ctx)
+		if err != nil {
+			return err
+		}
+		obj, err := svc.Objects.Get(b.name(), key).Context(ctx).Do()
+		if err != nil {
+			return errkit.Wrap(err, "Failed to get object", "object", key)
+		}
+		if info.MD5, err = base64Hex(obj.Md5Hash); err != nil {
+			return err
+		}
+		info.StorageClass = obj.StorageClass
+		info.CRC32C, err = base64Hex(obj.Crc32c)
+		return err
+	case ProviderTypeAzure:
+		bc, err := b.azureBlobClient(ctx, key)
+		if err != nil {
+			return err
+		}
+		props, err := bc.GetProperties(ctx, nil)
+		if err != nil {
+			return errkit.Wrap(err, "Failed to get object", "object", key)
+		}
+		info.StorageClass = aws.StringValue(props.AccessTier)
+	}
+	return nil
+}
+
+// base64Hex converts a base64 encoded checksum to hex.
+func base64Hex(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to decode checksum")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+
+	"gopkg.in/check.v1"
+)
+
+type TagsSuite struct{}
+
+var _ = check.Suite(&TagsSuite{})
+
+func (s *TagsSuite) TearDownTest(c *check.C) {
+	DeleteMemoryStore(c.TestName())
+}
+
+func (s *TagsSuite) TestAddTags(c *check.C) {
+	ctx := context.Background()
+	for _, pc := range []ProviderConfig{
+		{Type: ProviderTypeMemory, Endpoint: c.TestName()},
+		{Type: ProviderTypeFilesystem, Endpoint: c.MkDir()},
+	} {
+		d := (&CopySuite{}).directory(c, pc, "bucket")
+		err := d.PutBytes(ctx, "object", []byte("data"), map[string]string{"a": "1", "b": "2"})
+		c.Assert(err, check.IsNil)
+
+		err = d.AddTags(ctx, "object", map[string]string{"b": "3", "c/d": "4"})
+		c.Assert(err, check.IsNil)
+		data, tags, err := d.GetBytes(ctx, "object")
+		c.Assert(err, check.IsNil)
+		c.Check(string(data), check.Equals, "data")
+		c.Check(tags, check.DeepEquals, map[string]string{"a": "1", "b": "3", "c-d": "4"}, check.Commentf("%s", pc.Type))
+
+		err = d.AddTags(ctx, "missing", map[string]string{"a": "1"})
+		c.Check(err, check.NotNil, check.Commentf("%s", pc.Type))
+	}
+}
+
+func (s *TagsSuite) TestNativeChecksums(c *check.C) {
+	ctx := context.Background()
+	d := (&CopySuite{}).directory(c, ProviderConfig{Type: ProviderTypeMemory, Endpoint: c.TestName()}, "bucket")
+	err := d.PutBytes(ctx, "object", []byte("data"), nil)
+	c.Assert(err, check.IsNil)
+	info, err := d.Stat(ctx, "object")
+	c.Assert(err, check.IsNil)
+	sum := md5.Sum([]byte("data")) //nolint:gosec
+	c.Assert(info.MD5, check.Equals, hex.EncodeToString(sum[:]))
+	c.Assert(info.CRC32C, check.Equals, "")
+}
@@ -0,0 +1,501 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+// Transfer objects in parts. Only Concurrency parts of PartSize bytes are
+// held in memory at any time, regardless of the size of the object.
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	az "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/graymeta/stow"
+	stowaz "github.com/graymeta/stow/azure"
+	stowgcs "github.com/graymeta/stow/google"
+	"github.com/kanisterio/errkit"
+	"google.golang.org/api/googleapi"
+	storage "google.golang.org/api/storage/v1"
+)
+
+const (
+	// DefaultPartSize is the size of the parts objects are transferred in if
+	// TransferOptions do not specify it.
+	DefaultPartSize = 16 * 1024 * 1024
+	// DefaultConcurrency is the number of parts that are transferred in
+	// parallel if TransferOptions do not specify it.
+	DefaultConcurrency = 4
+	// MinPartSize is the smallest part size. S3 does not accept smaller parts
+	// in multipart uploads.
+	MinPartSize = 5 * 1024 * 1024
+)
+
+// TransferOptions configure how objects are uploaded and downloaded in parts.
+// Up to Concurrency parts of PartSize bytes are buffered in memory.
+type TransferOptions struct {
+	// PartSize is the size of the parts in bytes. It defaults to
+	// DefaultPartSize and is at least MinPartSize.
+	PartSize int64
+	// Concurrency is the number of parts transferred in parallel. It
+	// defaults to DefaultConcurrency.
+	Concurrency int
+}
+
+func (o TransferOptions) withDefaults() TransferOptions {
+	switch {
+	case o.PartSize == 0:
+		o.PartSize = DefaultPartSize
+	case o.PartSize < MinPartSize:
+		o.PartSize = MinPartSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+	return o
+}
+
+// putPartFunc uploads the part with the given index.
+type putPartFunc func(ctx context.Context, part int, data []byte) error
+
+// uploadParts reads r in parts of partSize bytes and uploads up to
+// concurrency of them in parallel with put. Buffers are reused once their
+// part is uploaded, so at most concurrency parts are held in memory. It
+// returns the number of parts read from r.
+func uploadParts(ctx context.Context, r io.Reader, partSize int64, concurrency int, put putPartFunc) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	buffers := make(chan []byte, concurrency)
+	for range concurrency {
+		buffers <- nil
+	}
+	var wg sync.WaitGroup
+	var once sync.Once
+	var putErr error
+	fail := func(err error) {
+		once.Do(func() {
+			putErr = err
+			cancel()
+		})
+	}
+
+	parts := 0
+	for done := false; !done; {
+		var buf []byte
+		select {
+		case buf = <-buffers:
+		case <-ctx.Done():
+			done = true
+			continue
+		}
+		if buf == nil {
+			buf = make([]byte, partSize)
+		}
+		n, err := io.ReadFull(r, buf)
+		switch {
+		case err == io.EOF:
+			// r ended with the previous part
+			done = true
+			continue
+		case err == io.ErrUnexpectedEOF:
+			done = true
+		case err != nil:
+			fail(errkit.Wrap(err, "Failed to read data to upload"))
+			done = true
+			continue
+		}
+		part := parts
+		parts++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := put(ctx, part, buf[:n]); err != nil {
+				fail(errkit.Wrap(err, fmt.Sprintf("Failed to upload part %d", part)))
+			}
+			buffers <- buf
+		}()
+	}
+	wg.Wait()
+	if putErr != nil {
+		return 0, putErr
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return parts, nil
+}
+
+// getRangeFunc opens the length bytes of an object starting at offset.
+type getRangeFunc func(ctx context.Context, offset, length int64) (io.ReadCloser, error)
+
+type partResult struct {
+	data []byte
+	err  error
+}
+
+var _ io.ReadCloser = (*partReader)(nil)
+
+// partReader reads an object in parts, fetching up to concurrency of them in
+// parallel. Parts are returned in order.
+type partReader struct {
+	cancel context.CancelFunc
+	// parts holds the results of the parts that are being fetched, in order.
+	parts <-chan chan partResult
+	buf   []byte
+	err   error
+}
+
+// newPartReader returns a reader for an object of the given size that
+// fetches it in parts of partSize bytes with get.
+func newPartReader(ctx context.Context, size, partSize int64, concurrency int, get getRangeFunc) *partReader {
+	ctx, cancel := context.WithCancel(ctx)
+	// The part that is being read is not in the queue, so that only
+	// concurrency parts are buffered.
+	parts := make(chan chan partResult, concurrency-1)
+	go func() {
+		defer close(parts)
+		for offset := int64(0); offset < size; offset += partSize {
+			res := make(chan partResult, 1)
+			select {
+			case parts <- res:
+			case <-ctx.Done():
+				return
+			}
+			length := min(partSize, size-offset)
+			go func() {
+				data, err := getRange(ctx, get, offset, length)
+				res <- partResult{data: data, err: err}
+			}()
+		}
+	}()
+	return &partReader{
+		cancel: cancel,
+		parts:  parts,
+	}
+}
+
+func getRange(ctx context.Context, get getRangeFunc, offset, length int64) ([]byte, error) {
+	r, err := get(ctx, offset, length)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to get object range", "offset", offset, "length", length)
+	}
+	defer r.Close() //nolint:errcheck
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errkit.Wrap(err, "Failed to read object range", "offset", offset, "length", length)
+	}
+	return data, nil
+}
+
+// Read implements io.Reader.
+func (r *partReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		res, ok := <-r.parts
+		if !ok {
+			r.err = io.EOF
+			continue
+		}
+		part := <-res
+		r.buf, r.err = part.data, part.err
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close implements io.Closer. It stops fetching parts.
+func (r *partReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// open opens the named item. Items larger than a part are read in parts that
+// are fetched in parallel.
+func (b *bucket) open(ctx context.Context, name string, item stow.Item) (io.ReadCloser, error) {
+	opts := b.config.Transfer.withDefaults()
+	size, err := item.Size()
+	if err != nil || size <= opts.PartSize || opts.Concurrency == 1 {
+		return item.Open()
+	}
+	v, err := itemVersion(item)
+	if err != nil {
+		return nil, err
+	}
+	get, err := b.rangeGetter(ctx, name, v)
+	if err != nil {
+		return nil, err
+	}
+	if get == nil {
+		return item.Open()
+	}
+	return newPartReader(ctx, size, opts.PartSize, opts.Concurrency, get), nil
+}
+
+// objectVersion identifies the version of an object that its size was read
+// from. Fields that are unknown are empty.
+type objectVersion struct {
+	etag string
+	// versionID is the version ID of versioned S3 objects.
+	versionID string
+	// generation is the generation of GCS objects.
+	generation int64
+}
+
+// itemVersion returns the version of the object of item.
+func itemVersion(item stow.Item) (objectVersion, error) {
+	etag, err := item.ETag()
+	if err != nil {
+		return objectVersion{}, errkit.Wrap(err, "Failed to get object ETag", "object", item.Name())
+	}
+	v := objectVersion{etag: etag}
+	if gi, ok := item.(*stowgcs.Item); ok && gi.StorageObject() != nil {
+		v.generation = gi.StorageObject().Generation
+	}
+	return v, nil
+}
+
+// rangeGetter returns a function that reads ranges of the named object, or
+// nil if the object store does not support ranged reads. Ranges are read from
+// version v of the object, so that parts of objects that are overwritten
+// while they are read are not mixed.
+func (b *bucket) rangeGetter(ctx context.Context, name string, v objectVersion) (getRangeFunc, error) {
+	switch b.config.Type {
+	case ProviderTypeS3:
+		client, err := b.renewingS3Client()
+		if err != nil {
+			return nil, err
+		}
+		bucketName := b.name()
+		alg, ck := b.config.ServerSideEncryption.s3CustomerKey()
+		var ifMatch, versionID *string
+		if v.etag != "" {
+			// Stow strips the quotes of ETags
+			ifMatch = aws.String(`"` + strings.Trim(v.etag, `"`) + `"`)
+		}
+		if v.versionID != "" {
+			versionID = aws.String(v.versionID)
+		}
+		return func(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+			out, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+				Bucket:               aws.String(bucketName),
+				Key:                  aws.String(name),
+				Range:                aws.String(httpRange(offset, length)),
+				IfMatch:              ifMatch,
+				VersionId:            versionID,
+				SSECustomerAlgorithm: alg,
+				SSECustomerKey:       ck,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return out.Body, nil
+		}, nil
+	case ProviderTypeGCS:
+		svc, err := b.gcsService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		bucketName := b.name()
+		return func(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+			call := svc.Objects.Get(bucketName, name).Context(ctx)
+			if v.generation != 0 {
+				call.Generation(v.generation)
+			}
+			call.Header().Set("Range", httpRange(offset, length))
+			resp, err := call.Download()
+			if err != nil {
+				return nil, err
+			}
+			return resp.Body, nil
+		}, nil
+	case ProviderTypeAzure:
+		blob, err := b.azureBlob(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+			return blob.GetRange(&az.GetBlobRangeOptions{
+				Range: &az.BlobRange{
+					Start: uint64(offset),
+					End:   uint64(offset + length - 1),
+				},
+				GetBlobOptions: &az.GetBlobOptions{IfMatch: v.etag},
+			})
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func httpRange(offset, length int64) string {
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+// name returns the name of the bucket in the object store.
+func (b *bucket) name() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.container.ID()
+}
+
+// gcsService returns the GCS client of the stow location.
+func (b *bucket) gcsService(ctx context.Context) (*storage.Service, error) {
+	if _, _, err := b.secret(ctx); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	l, ok := b.location.(interface{ Service() *storage.Service })
+	if !ok {
+		return nil, errkit.New("GCS client not available")
+	}
+	return l.Service(), nil
+}
+
+// gcsPut uploads an object to GCS with a resumable upload in chunks of
+// partSize bytes.
+func (b *bucket) gcsPut(ctx context.Context, key string, r io.Reader, tags map[string]interface{}, partSize int64) error {
+	svc, err := b.gcsService(ctx)
+	if err != nil {
+		return err
+	}
+	object := &storage.Object{
+		Name:         key,
+		Metadata:     stringTags(tags),
+		StorageClass: b.config.StorageClass,
+	}
+	_, err = svc.Objects.Insert(b.name(), object).Media(r, googleapi.ChunkSize(int(partSize))).Context(ctx).Do()
+	return errkit.Wrap(err, "Failed to upload object", "object", key)
+}
+
+// azureBlob returns a reference to the named blob in the bucket.
+func (b *bucket) azureBlob(ctx context.Context, key string) (*az.Blob, error) {
+	return b.azureBlobWithHeaders(ctx, key, nil)
+}
+
+// azureWriteBlob returns a reference to the named blob in the bucket whose
+// requests set the encryption scope of the bucket, if it has one.
+func (b *bucket) azureWriteBlob(ctx context.Context, key string) (*az.Blob, error) {
+	return b.azureBlobWithHeaders(ctx, key, b.config.ServerSideEncryption.azureHeaders())
+}
+
+func (b *bucket) azureBlobWithHeaders(ctx context.Context, key string, headers map[string]string) (*az.Blob, error) {
+	c, err := b.azureContainer(ctx, headers)
+	if err != nil {
+		return nil, err
+	}
+	// Stow replaces spaces in blob names
+	return c.GetBlobReference(strings.ReplaceAll(key, " ", "+")), nil
+}
+
+// azureContainer returns a reference to the container of the bucket whose
+// requests add the given headers.
+func (b *bucket) azureContainer(ctx context.Context, headers map[string]string) (*az.Container, error) {
+	secret, _, err := b.secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tc, ok := b.azureTokenContainer(); ok {
+		return tc.blobContainer(headers)
+	}
+	_, cfg, err := azureConfig(ctx, b.config, secret)
+	if err != nil {
+		return nil, err
+	}
+	account, _ := cfg.Config(stowaz.ConfigAccount)
+	accountKey, _ := cfg.Config(stowaz.ConfigKey)
+	envName, _ := cfg.Config(stowaz.ConfigEnvName)
+	var client az.Client
+	if envName != "" {
+		env, err := azure.EnvironmentFromName(envName)
+		if err != nil {
+			return nil, err
+		}
+		client, err = az.NewBasicClientOnSovereignCloud(account, accountKey, env)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to create Azure client")
+		}
+	} else {
+		client, err = az.NewBasicClient(account, accountKey)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to create Azure client")
+		}
+	}
+	// Headers are added before the requests are signed
+	client.AddAdditionalHeaders(headers)
+	svc := client.GetBlobService()
+	return svc.GetContainerReference(b.name()), nil
+}
+
+// azureTokenContainer returns the container of the bucket if it is accessed
+// with an access token rather than a storage account key.
+func (b *bucket) azureTokenContainer() (*azureTokenContainer, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tc, ok := b.container.(*azureTokenContainer)
+	return tc, ok
+}
+
+// azurePut uploads an object to Azure as a block blob whose blocks are
+// uploaded in parallel.
+func (b *bucket) azurePut(ctx context.Context, key string, r io.Reader, tags map[string]interface{}, opts TransferOptions) error {
+	blob, err := b.azureWriteBlob(ctx, key)
+	if err != nil {
+		return err
+	}
+	n, err := uploadParts(ctx, r, opts.PartSize, opts.Concurrency, func(_ context.Context, part int, data []byte) error {
+		return blob.PutBlock(azureBlockID(part), data, nil)
+	})
+	if err != nil {
+		return err
+	}
+	blocks := make([]az.Block, n)
+	for i := range blocks {
+		blocks[i] = az.Block{ID: azureBlockID(i), Status: az.BlockStatusUncommitted}
+	}
+	// Metadata is committed with the blocks, so that the blob is never
+	// visible without it
+	blob.Metadata = stringTags(tags)
+	return errkit.Wrap(blob.PutBlockList(blocks, nil), "Failed to commit blocks", "object", key)
+}
+
+// azureBlockID returns the ID of a block of a block blob. IDs of the blocks
+// of a blob must have the same length.
+func azureBlockID(part int) string {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, uint64(part))
+	return base64.StdEncoding.EncodeToString(id)
+}
+
+func stringTags(tags map[string]interface{}) map[string]string {
+	st := make(map[string]string, len(tags))
+	for k, v := range tags {
+		if sv, ok := v.(string); ok {
+			st[k] = sv
+		}
+	}
+	return st
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kanisterio/errkit"
+	"gopkg.in/check.v1"
+)
+
+type TransferSuite struct{}
+
+var _ = check.Suite(&TransferSuite{})
+
+// inFlight counts the calls that are in progress and records their maximum.
+type inFlight struct {
+	mu       sync.Mutex
+	cur, max int
+}
+
+func (f *inFlight) start() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cur++
+	f.max = max(f.max, f.cur)
+}
+
+func (f *inFlight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cur--
+}
+
+func (s *TransferSuite) TestWithDefaults(c *check.C) {
+	for _, tc := range []struct {
+		opts TransferOptions
+		exp  TransferOptions
+	}{
+		{
+			opts: TransferOptions{},
+			exp:  TransferOptions{PartSize: DefaultPartSize, Concurrency: DefaultConcurrency},
+		},
+		{
+			opts: TransferOptions{PartSize: 1, Concurrency: -1},
+			exp:  TransferOptions{PartSize: MinPartSize, Concurrency: DefaultConcurrency},
+		},
+		{
+			opts: TransferOptions{PartSize: 2 * MinPartSize, Concurrency: 1},
+			exp:  TransferOptions{PartSize: 2 * MinPartSize, Concurrency: 1},
+		},
+	} {
+		c.Check(tc.opts.withDefaults(), check.Equals, tc.exp)
+	}
+}
+
+func (s *TransferSuite) TestUploadParts(c *check.C) {
+	for _, size := range []int{0, 3, 4, 5, 17} {
+		data := bytes.Repeat([]byte("0123456789"), 2)[:size]
+		var mu sync.Mutex
+		parts := map[int][]byte{}
+		var f inFlight
+		n, err := uploadParts(context.Background(), bytes.NewReader(data), 4, 2, func(_ context.Context, part int, b []byte) error {
+			f.start()
+			defer f.done()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			parts[part] = append([]byte(nil), b...)
+			return nil
+		})
+		c.Assert(err, check.IsNil)
+		c.Assert(n, check.Equals, (size+3)/4)
+		c.Assert(f.max <= 2, check.Equals, true)
+		var got []byte
+		for i := range n {
+			got = append(got, parts[i]...)
+		}
+		c.Assert(bytes.Equal(got, data), check.Equals, true)
+	}
+}
+
+func (s *TransferSuite) TestUploadPartsError(c *check.C) {
+	data := bytes.Repeat([]byte("x"), 100)
+	_, err := uploadParts(context.Background(), bytes.NewReader(data), 4, 3, func(_ context.Context, part int, _ []byte) error {
+		if part == 5 {
+			return errkit.New("upload failed")
+		}
+		return nil
+	})
+	c.Assert(err, check.ErrorMatches, ".*upload failed.*")
+}
+
+func (s *TransferSuite) TestPartReader(c *check.C) {
+	data := bytes.Repeat([]byte("0123456789"), 5)[:43]
+	var f inFlight
+	get := func(_ context.Context, offset, length int64) (io.ReadCloser, error) {
+		f.start()
+		defer f.done()
+		time.Sleep(time.Millisecond)
+		return io.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
+	}
+	r := newPartReader(context.Background(), int64(len(data)), 4, 3, get)
+	got, err := io.ReadAll(r)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.Close(), check.IsNil)
+	c.Assert(bytes.Equal(got, data), check.Equals, true)
+	c.Assert(f.max <= 3, check.Equals, true)
+}
+
+func (s *TransferSuite) TestPartReaderError(c *check.C) {
+	get := func(_ context.Context, offset, length int64) (io.ReadCloser, error) {
+		if offset >= 8 {
+			return nil, errkit.New("download failed")
+		}
+		return io.NopCloser(bytes.NewReader(make([]byte, length))), nil
+	}
+	r := newPartReader(context.Background(), 20, 4, 2, get)
+	defer r.Close() //nolint:errcheck
+	got, err := io.ReadAll(r)
+	c.Assert(err, check.ErrorMatches, ".*download failed.*")
+	c.Assert(got, check.HasLen, 8)
+}
@@ -0,0 +1,252 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/kanisterio/errkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/ksprig"
+	"github.com/kanisterio/kanister/pkg/log"
+)
+
+const (
+	k8sGetFuncName         = "k8sGet"
+	podsBySelectorFuncName = "podsBySelector"
+	secretValueFuncName    = "secretValue"
+	configMapValueFuncName = "configMapValue"
+	toYamlFuncName         = "toYaml"
+	fromYamlFuncName       = "fromYaml"
+
+	redactedValue = "<redacted>"
+)
+
+var errLookupUnavailable = errkit.NewSentinelErr("cluster lookups are not available while rendering this template")
+
+// templateLookup implements the read-only cluster lookups that are available
+// as template functions. Lookups are performed with the clients, and hence the
+// RBAC permissions, of the controller, so they can only read objects in the
+// namespace of the ActionSet. Results are cached for the
+// lifetime of the lookup, which is a single action.
+type templateLookup struct {
+	// ctx is the context of the action, which the templates are rendered for
+	ctx       context.Context
+	cli       kubernetes.Interface
+	dynCli    dynamic.Interface
+	namespace string
+
+	mu    sync.Mutex
+	cache map[string]interface{}
+}
+
+func newTemplateLookup(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, namespace string) *templateLookup {
+	return &templateLookup{
+		ctx:       ctx,
+		cli:       cli,
+		dynCli:    dynCli,
+		namespace: namespace,
+		cache:     make(map[string]interface{}),
+	}
+}
+
+// checkNamespace returns an error if objects in `namespace` cannot be read
+// with funcName.
+func (l *templateLookup) checkNamespace(funcName, namespace string) error {
+	if l != nil && namespace != l.namespace {
+		return errkit.New(fmt.Sprintf("%s can only read objects in the namespace of the ActionSet '%s'", funcName, l.namespace))
+	}
+	return nil
+}
+
+// funcMap returns the sprig functions merged with the Kanister specific
+// template functions. Cluster lookups fail if l is nil.
+func funcMap(l *templateLookup) template.FuncMap {
+	fm := ksprig.TxtFuncMap()
+	fm[toYamlFuncName] = toYaml
+	fm[fromYamlFuncName] = fromYaml
+	fm[k8sGetFuncName] = l.k8sGet
+	fm[podsBySelectorFuncName] = l.podsBySelector
+	fm[secretValueFuncName] = l.secretValue
+	fm[configMapValueFuncName] = l.configMapValue
+	return fm
+}
+
+// k8sGet returns the referenced object, which must be in the namespace of
+// the ActionSet. apiVersion is either `version` for the core group, or
+// `group/version`. Secrets can only be read using secretValue.
+func (l *templateLookup) k8sGet(apiVersion, resource, namespace, name string) (map[string]interface{}, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Invalid apiVersion", "apiVersion", apiVersion)
+	}
+	if gv.Group == "" && strings.EqualFold(resource, "secrets") {
+		return nil, errkit.New(fmt.Sprintf("%s cannot be used to read secrets, use %s instead", k8sGetFuncName, secretValueFuncName))
+	}
+	if err := l.checkNamespace(k8sGetFuncName, namespace); err != nil {
+		return nil, err
+	}
+	gvr := gv.WithResource(resource)
+	v, err := l.cached(k8sGetFuncName, []string{apiVersion, resource, namespace, name}, func(ctx context.Context) (interface{}, error) {
+		u, err := l.dynCli.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return u.UnstructuredContent(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Templates can modify maps through sprig functions such as `set`.
+	return runtime.DeepCopyJSON(v.(map[string]interface{})), nil
+}
+
+// podsBySelector returns the pods in the namespace of the ActionSet that
+// match the label selector, sorted by name.
+func (l *templateLookup) podsBySelector(namespace, selector string) ([]interface{}, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Invalid label selector", "selector", selector)
+	}
+	if err := l.checkNamespace(podsBySelectorFuncName, namespace); err != nil {
+		return nil, err
+	}
+	v, err := l.cached(podsBySelectorFuncName, []string{namespace, sel.String()}, func(ctx context.Context) (interface{}, error) {
+		pods, err := l.cli.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+		out := make([]interface{}, 0, len(pods.Items))
+		for i := range pods.Items {
+			u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&pods.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, u)
+		}
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	pods := v.([]interface{})
+	out := make([]interface{}, 0, len(pods))
+	for _, p := range pods {
+		out = append(out, runtime.DeepCopyJSON(p.(map[string]interface{})))
+	}
+	return out, nil
+}
+
+// secretValue returns the decoded value of a key of a secret in the
+// namespace of the ActionSet.
+func (l *templateLookup) secretValue(namespace, name, key string) (string, error) {
+	if err := l.checkNamespace(secretValueFuncName, namespace); err != nil {
+		return "", err
+	}
+	v, err := l.cached(secretValueFuncName, []string{namespace, name, key}, func(ctx context.Context) (interface{}, error) {
+		s, err := l.cli.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		val, ok := s.Data[key]
+		if !ok {
+			return nil, errkit.New(fmt.Sprintf("Key '%s' not found in secret '%s:%s'", key, namespace, name))
+		}
+		return string(val), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// configMapValue returns the value of a key of a config map in the
+// namespace of the ActionSet.
+func (l *templateLookup) configMapValue(namespace, name, key string) (string, error) {
+	if err := l.checkNamespace(configMapValueFuncName, namespace); err != nil {
+		return "", err
+	}
+	v, err := l.cached(configMapValueFuncName, []string{namespace, name, key}, func(ctx context.Context) (interface{}, error) {
+		cm, err := l.cli.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		val, ok := cm.Data[key]
+		if !ok {
+			return nil, errkit.New(fmt.Sprintf("Key '%s' not found in configmap '%s:%s'", key, namespace, name))
+		}
+		return val, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// cached returns the cached result of the lookup, or performs the lookup and
+// caches its result. The lock is not held during lookups, so that a slow
+// lookup does not block the others. Lookup results are never logged.
+func (l *templateLookup) cached(funcName string, args []string, lookup func(context.Context) (interface{}, error)) (interface{}, error) {
+	if l == nil {
+		return nil, errkit.Wrap(errLookupUnavailable, "Failed to call template function", "function", funcName)
+	}
+	key := funcName + "\x00" + strings.Join(args, "\x00")
+	l.mu.Lock()
+	v, ok := l.cache[key]
+	l.mu.Unlock()
+	if ok {
+		return v, nil
+	}
+	v, err := lookup(l.ctx)
+	if err != nil {
+		log.Debug().WithContext(l.ctx).WithError(err).Print("Template lookup failed", field.M{"function": funcName, "args": args})
+		return nil, errkit.Wrap(err, "Failed to call template function", "function", funcName)
+	}
+	log.Debug().WithContext(l.ctx).Print("Template lookup", field.M{"function": funcName, "args": args, "result": redactedValue})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache[key] = v
+	return v, nil
+}
+
+func toYaml(v interface{}) (string, error) {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to marshal value to YAML")
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func fromYaml(s string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(s), &out); err != nil {
+		return nil, errkit.Wrap(err, "Failed to unmarshal YAML")
+	}
+	return out, nil
+}
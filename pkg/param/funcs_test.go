@@ -0,0 +1,167 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	"context"
+
+	"gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakedyncli "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+type FuncsSuite struct{}
+
+var _ = check.Suite(&FuncsSuite{})
+
+func (s *FuncsSuite) TestLookupFuncs(c *check.C) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "pg", Port: 5432}}},
+	}
+	cli := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-1", Namespace: "ns", Labels: map[string]string{"role": "primary"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "ns", Labels: map[string]string{"role": "replica"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "ns"}, Data: map[string][]byte{"password": []byte("s3cr3t")}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "conf", Namespace: "ns"}, Data: map[string]string{"db": "postgres"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "other"}, Data: map[string][]byte{"password": []byte("s3cr3t")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "other"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "conf", Namespace: "other"}, Data: map[string]string{"db": "postgres"}},
+	)
+	tp := TemplateParams{lookup: newTemplateLookup(context.Background(), cli, fakedyncli.NewSimpleDynamicClient(scheme.Scheme, svc), "ns")}
+
+	for _, tc := range []struct {
+		arg     string
+		out     string
+		checker check.Checker
+	}{
+		{
+			arg:     `{{ (index (podsBySelector "ns" "role=primary") 0).metadata.name }}`,
+			out:     "db-1",
+			checker: check.IsNil,
+		},
+		{
+			arg:     `{{ range podsBySelector "ns" "" }}{{ .metadata.name }} {{ end }}`,
+			out:     "db-0 db-1 ",
+			checker: check.IsNil,
+		},
+		{
+			arg:     `{{ (index (k8sGet "v1" "services" "ns" "db").spec.ports 0).port }}`,
+			out:     "5432",
+			checker: check.IsNil,
+		},
+		{
+			arg:     `{{ secretValue "ns" "creds" "password" }}`,
+			out:     "s3cr3t",
+			checker: check.IsNil,
+		},
+		{
+			arg:     `{{ configMapValue "ns" "conf" "db" }}`,
+			out:     "postgres",
+			checker: check.IsNil,
+		},
+		{
+			arg:     `{{ configMapValue "ns" "conf" "missing" }}`,
+			checker: check.NotNil,
+		},
+		{
+			arg:     `{{ k8sGet "v1" "secrets" "ns" "creds" }}`,
+			checker: check.NotNil,
+		},
+		{
+			// Secrets and objects outside the namespace of the ActionSet
+			// cannot be read
+			arg:     `{{ secretValue "other" "creds" "password" }}`,
+			checker: check.NotNil,
+		},
+		{
+			arg:     `{{ k8sGet "v1" "services" "other" "db" }}`,
+			checker: check.NotNil,
+		},
+		{
+			arg:     `{{ k8sGet "v1" "namespaces" "" "ns" }}`,
+			checker: check.NotNil,
+		},
+		{
+			arg:     `{{ podsBySelector "other" "" }}`,
+			checker: check.NotNil,
+		},
+		{
+			arg:     `{{ configMapValue "other" "conf" "db" }}`,
+			checker: check.NotNil,
+		},
+		{
+			arg:     `{{ (fromYaml "a:\n  b: c").a.b }}`,
+			out:     "c",
+			checker: check.IsNil,
+		},
+		{
+			arg:     `{{ dict "a" (list 1 2) | toYaml }}`,
+			out:     "a:\n- 1\n- 2",
+			checker: check.IsNil,
+		},
+	} {
+		out, err := renderStringArg(tc.arg, tp)
+		c.Assert(err, tc.checker, check.Commentf("%s", tc.arg))
+		if err == nil {
+			c.Assert(out, check.Equals, tc.out)
+		}
+	}
+}
+
+func (s *FuncsSuite) TestLookupCache(c *check.C) {
+	cli := fake.NewSimpleClientset(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "conf", Namespace: "ns"}, Data: map[string]string{"db": "postgres"}},
+	)
+	tp := TemplateParams{lookup: newTemplateLookup(context.Background(), cli, nil, "ns")}
+	for i := 0; i < 3; i++ {
+		out, err := renderStringArg(`{{ configMapValue "ns" "conf" "db" }}`, tp)
+		c.Assert(err, check.IsNil)
+		c.Assert(out, check.Equals, "postgres")
+	}
+	c.Assert(cli.Actions(), check.HasLen, 1)
+}
+
+func (s *FuncsSuite) TestLookupConcurrent(c *check.C) {
+	l := newTemplateLookup(context.Background(), nil, nil, "ns")
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan interface{})
+	go func() {
+		v, _ := l.cached("slow", nil, func(context.Context) (interface{}, error) {
+			close(started)
+			<-release
+			return "slow", nil
+		})
+		done <- v
+	}()
+	<-started
+	// Lookups are not blocked by a lookup that is in progress
+	v, err := l.cached("fast", nil, func(context.Context) (interface{}, error) {
+		return "fast", nil
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(v, check.Equals, "fast")
+	close(release)
+	c.Assert(<-done, check.Equals, "slow")
+}
+
+func (s *FuncsSuite) TestLookupUnavailable(c *check.C) {
+	_, err := renderStringArg(`{{ configMapValue "ns" "conf" "db" }}`, TemplateParams{})
+	c.Assert(err, check.NotNil)
+}
@@ -18,21 +18,25 @@ package param
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kanisterio/errkit"
 	osversioned "github.com/openshift/client-go/apps/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/field"
 	"github.com/kanisterio/kanister/pkg/kopia/command"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/log"
@@ -42,6 +46,7 @@ import (
 const (
 	timeFormat         = time.RFC3339Nano
 	clusterLocalDomain = "svc.cluster.local"
+	sseCustomerKeySize = 32
 )
 
 // TemplateParams are the values that will change between separate runs of Phases.
@@ -49,6 +54,11 @@ type TemplateParams struct {
 	StatefulSet      *StatefulSetParams
 	DeploymentConfig *DeploymentConfigParams
 	Deployment       *DeploymentParams
+	DaemonSet        *DaemonSetParams
+	ReplicaSet       *ReplicaSetParams
+	Job              *JobParams
+	CronJob          *JobParams
+	Workload         *WorkloadParams
 	PVC              *PVCParams
 	Namespace        *NamespaceParams
 	ArtifactsIn      map[string]crv1alpha1.Artifact
@@ -65,6 +75,12 @@ type TemplateParams struct {
 	PodOverride      crv1alpha1.JSONMap
 	PodAnnotations   map[string]string
 	PodLabels        map[string]string
+
+	// lookup provides the cluster lookup template functions of the action
+	lookup *templateLookup
+	// cli and crCli are the clients of the action
+	cli   kubernetes.Interface
+	crCli versioned.Interface
 }
 
 // DeploymentConfigParams are params for deploymentconfig, will be used if working on open shift cluster
@@ -95,6 +111,48 @@ type DeploymentParams struct {
 	PersistentVolumeClaims map[string]map[string]string
 }
 
+// DaemonSetParams are params for daemon sets
+type DaemonSetParams struct {
+	Name                   string
+	Namespace              string
+	Pods                   []string
+	Containers             [][]string
+	PersistentVolumeClaims map[string]map[string]string
+}
+
+// ReplicaSetParams are params for replica sets
+type ReplicaSetParams struct {
+	Name                   string
+	Namespace              string
+	Pods                   []string
+	Containers             [][]string
+	PersistentVolumeClaims map[string]map[string]string
+}
+
+// JobParams are params for jobs and cron jobs. For cron jobs, the pods of
+// all jobs currently owned by the cron job are listed.
+type JobParams struct {
+	Name                   string
+	Namespace              string
+	Pods                   []string
+	Containers             [][]string
+	PersistentVolumeClaims map[string]map[string]string
+}
+
+// WorkloadParams are params for objects of any other kind, typically custom
+// resources, that manage pods through a label selector in `spec.selector` or
+// through a `scale` subresource.
+type WorkloadParams struct {
+	Name                   string
+	Namespace              string
+	Group                  string
+	Version                string
+	Resource               string
+	Pods                   []string
+	Containers             [][]string
+	PersistentVolumeClaims map[string]map[string]string
+}
+
 // PVCParams are params for persistent volume claims
 type PVCParams struct {
 	Name      string
@@ -109,9 +167,35 @@ type NamespaceParams struct {
 
 // Profile contains where to store artifacts and how to access them.
 type Profile struct {
-	Location      crv1alpha1.Location
-	Credential    Credential
-	SkipSSLVerify bool
+	Location             crv1alpha1.Location
+	Credential           Credential
+	SkipSSLVerify        bool
+	Encryption           *EncryptionKeys            `json:",omitempty"`
+	Compression          crv1alpha1.CompressionType `json:",omitempty"`
+	ObjectLock           *crv1alpha1.ObjectLock     `json:",omitempty"`
+	ServerSideEncryption *ServerSideEncryption      `json:",omitempty"`
+	StorageClass         string                     `json:",omitempty"`
+	RateLimit            *crv1alpha1.RateLimit      `json:",omitempty"`
+	SecondaryLocations   []crv1alpha1.Location      `json:",omitempty"`
+	LocationPolicy       crv1alpha1.LocationPolicy  `json:",omitempty"`
+}
+
+// ServerSideEncryption configures how the object store encrypts artifacts.
+type ServerSideEncryption struct {
+	Type     crv1alpha1.ServerSideEncryptionType
+	KMSKeyID string `json:",omitempty"`
+	// CustomerKey is the 32 byte key of sse-c.
+	CustomerKey     []byte `json:",omitempty"`
+	EncryptionScope string `json:",omitempty"`
+}
+
+// EncryptionKeys are the keys that wrap the data keys of artifacts that are
+// encrypted on the client.
+type EncryptionKeys struct {
+	// KeyID is the ID of the key that wraps the data keys of new artifacts.
+	KeyID string
+	// Keys are the wrapping keys indexed by their ID.
+	Keys map[string][]byte
 }
 
 type CredentialType string
@@ -120,6 +204,8 @@ const (
 	CredentialTypeKeyPair CredentialType = "keyPair"
 	CredentialTypeSecret  CredentialType = "secret"
 	CredentialTypeKopia   CredentialType = "kopia"
+
+	CredentialTypeWorkloadIdentity CredentialType = "workloadIdentity"
 )
 
 // Credential resolves the storage
@@ -128,6 +214,27 @@ type Credential struct {
 	KeyPair           *KeyPair
 	Secret            *corev1.Secret
 	KopiaServerSecret *KopiaServerCreds
+	WorkloadIdentity  *WorkloadIdentity `json:",omitempty"`
+	// Renewer reads credentials that are leased from a credential source
+	// again when they expire. It is only set in the controller.
+	Renewer CredentialRenewer `json:"-"`
+}
+
+// CredentialRenewer renews credentials that expire.
+type CredentialRenewer interface {
+	// Renew returns the credential and when it expires. The credential is
+	// read again if it is about to expire.
+	Renew(ctx context.Context) (*Credential, time.Time, error)
+}
+
+// WorkloadIdentity is a credential that is obtained by exchanging tokens of a
+// Kubernetes ServiceAccount with the cloud provider.
+type WorkloadIdentity struct {
+	crv1alpha1.WorkloadIdentity
+	// TokenSource requests the ServiceAccount tokens. It is only set in the
+	// controller. Data mover pods read the tokens from
+	// consts.WorkloadIdentityTokenFilePath instead.
+	TokenSource secrets.TokenSource `json:"-"`
 }
 
 // KeyPair is a credential that contains two strings: an ID and a secret.
@@ -173,14 +280,20 @@ const (
 	DeploymentKind       = "deployment"
 	StatefulSetKind      = "statefulset"
 	DeploymentConfigKind = "deploymentconfig"
+	DaemonSetKind        = "daemonset"
+	ReplicaSetKind       = "replicaset"
+	JobKind              = "job"
+	CronJobKind          = "cronjob"
 	PVCKind              = "pvc"
 	NamespaceKind        = "namespace"
 	SecretKind           = "secret"
 	ConfigMapKind        = "configmap"
 )
 
-// New function fetches and returns the desired params
-func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, crCli versioned.Interface, osCli osversioned.Interface, as crv1alpha1.ActionSpec) (*TemplateParams, error) {
+// New function fetches and returns the desired params. actionSetNamespace is
+// the namespace of the ActionSet, which the template lookup functions are
+// restricted to.
+func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, crCli versioned.Interface, osCli osversioned.Interface, as crv1alpha1.ActionSpec, actionSetNamespace string) (*TemplateParams, error) {
 	secrets, err := fetchSecrets(ctx, cli, as.Secrets)
 	if err != nil {
 		return nil, err
@@ -193,6 +306,15 @@ func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface
 	if err != nil {
 		return nil, err
 	}
+	// Functions and the kando commands they run get the rate limit of the
+	// action from the profile
+	rateLimit, err := RateLimit(prof, as.Options)
+	if err != nil {
+		return nil, err
+	}
+	if prof != nil {
+		prof.RateLimit = rateLimit
+	}
 	repoServer, err := fetchRepositoryServer(ctx, cli, crCli, as.RepositoryServer)
 	if err != nil {
 		return nil, err
@@ -211,6 +333,9 @@ func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface
 		PodLabels:        as.PodLabels,
 		DeferPhase:       &Phase{},
 		Phases:           make(map[string]*Phase),
+		lookup:           newTemplateLookup(ctx, cli, dynCli, actionSetNamespace),
+		cli:              cli,
+		crCli:            crCli,
 	}
 	var gvr schema.GroupVersionResource
 	namespace := as.Object.Namespace
@@ -236,6 +361,34 @@ func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface
 		}
 		tp.Deployment = dp
 		gvr = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	case DaemonSetKind:
+		dsp, err := fetchDaemonSetParams(ctx, cli, as.Object.Namespace, as.Object.Name)
+		if err != nil {
+			return nil, err
+		}
+		tp.DaemonSet = dsp
+		gvr = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	case ReplicaSetKind:
+		rsp, err := fetchReplicaSetParams(ctx, cli, as.Object.Namespace, as.Object.Name)
+		if err != nil {
+			return nil, err
+		}
+		tp.ReplicaSet = rsp
+		gvr = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	case JobKind:
+		jp, err := fetchJobParams(ctx, cli, as.Object.Namespace, as.Object.Name)
+		if err != nil {
+			return nil, err
+		}
+		tp.Job = jp
+		gvr = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	case CronJobKind:
+		cjp, err := fetchCronJobParams(ctx, cli, as.Object.Namespace, as.Object.Name)
+		if err != nil {
+			return nil, err
+		}
+		tp.CronJob = cjp
+		gvr = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
 	case PVCKind:
 		pp, err := fetchPVCParams(ctx, cli, as.Object.Namespace, as.Object.Name)
 		if err != nil {
@@ -260,10 +413,35 @@ func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface
 		return nil, errkit.Wrap(err, fmt.Sprintf("could not fetch object name: %s, namespace: %s, group: %s, version: %s, resource: %s", as.Object.Name, namespace, gvr.Group, gvr.Version, gvr.Resource))
 	}
 	tp.Object = u.UnstructuredContent()
+	if !isKnownKind(as.Object.Kind) && gvr.Group != "" {
+		wp, err := fetchWorkloadParams(ctx, cli, dynCli, gvr, &unstructured.Unstructured{Object: tp.Object})
+		if err != nil {
+			return nil, err
+		}
+		tp.Workload = wp
+	}
 
 	return &tp, nil
 }
 
+func isKnownKind(kind string) bool {
+	switch strings.ToLower(kind) {
+	case StatefulSetKind, DeploymentConfigKind, DeploymentKind, DaemonSetKind, ReplicaSetKind, JobKind, CronJobKind, PVCKind, NamespaceKind:
+		return true
+	}
+	return false
+}
+
+// FetchProfile returns the Profile that ref refers to, with the credentials
+// and keys of its secrets, using the clients the TemplateParams were created
+// with. It returns nil if ref is nil.
+func (tp *TemplateParams) FetchProfile(ctx context.Context, ref *crv1alpha1.ObjectReference) (*Profile, error) {
+	if tp.cli == nil || tp.crCli == nil {
+		return nil, errkit.New("Profiles can only be fetched for TemplateParams created by New")
+	}
+	return fetchProfile(ctx, tp.cli, tp.crCli, ref)
+}
+
 func fetchProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, ref *crv1alpha1.ObjectReference) (*Profile, error) {
 	if ref == nil {
 		log.Debug().Print("Executing the action without a profile")
@@ -273,17 +451,92 @@ func fetchProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned
 	if err != nil {
 		return nil, errkit.WithStack(err)
 	}
-	cred, err := fetchCredential(ctx, cli, p.Credential)
+	var cred *Credential
+	switch {
+	case p.Location.Type == crv1alpha1.LocationTypeFilesystem:
+		// Filesystem locations are accessed through a mounted volume
+		cred = &Credential{}
+	case p.Credential.Type == crv1alpha1.CredentialTypeWorkloadIdentity:
+		cred, err = fetchWorkloadIdentityCredential(cli, p.Credential.WorkloadIdentity, p.Location.Type, p.Namespace)
+	default:
+		cred, err = fetchCredential(ctx, cli, p.Credential)
+	}
 	if err != nil {
 		return nil, errkit.WithStack(err)
 	}
+	var keys *EncryptionKeys
+	if p.Encryption != nil {
+		if keys, err = fetchEncryptionKeys(ctx, cli, p.Encryption); err != nil {
+			return nil, err
+		}
+	}
+	var sse *ServerSideEncryption
+	if p.ServerSideEncryption != nil {
+		if sse, err = fetchServerSideEncryption(ctx, cli, p.ServerSideEncryption); err != nil {
+			return nil, err
+		}
+	}
 	return &Profile{
-		Location:      p.Location,
-		Credential:    *cred,
-		SkipSSLVerify: p.SkipSSLVerify,
+		Location:             p.Location,
+		Credential:           *cred,
+		SkipSSLVerify:        p.SkipSSLVerify,
+		Encryption:           keys,
+		Compression:          p.Compression,
+		ObjectLock:           p.ObjectLock,
+		ServerSideEncryption: sse,
+		StorageClass:         p.StorageClass,
+		RateLimit:            p.RateLimit,
+		SecondaryLocations:   p.SecondaryLocations,
+		LocationPolicy:       p.LocationPolicy,
+	}, nil
+}
+
+func fetchEncryptionKeys(ctx context.Context, cli kubernetes.Interface, e *crv1alpha1.Encryption) (*EncryptionKeys, error) {
+	s, err := cli.CoreV1().Secrets(e.KeySecret.Namespace).Get(ctx, e.KeySecret.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to fetch encryption keys", "namespace", e.KeySecret.Namespace, "name", e.KeySecret.Name)
+	}
+	if _, ok := s.Data[e.KeyID]; !ok {
+		return nil, errkit.New(fmt.Sprintf("Key '%s' not found in secret '%s:%s'", e.KeyID, s.GetNamespace(), s.GetName()))
+	}
+	return &EncryptionKeys{
+		KeyID: e.KeyID,
+		Keys:  s.Data,
 	}, nil
 }
 
+func fetchServerSideEncryption(ctx context.Context, cli kubernetes.Interface, e *crv1alpha1.ServerSideEncryption) (*ServerSideEncryption, error) {
+	sse := &ServerSideEncryption{
+		Type:            e.Type,
+		KMSKeyID:        e.KMSKeyID,
+		EncryptionScope: e.EncryptionScope,
+	}
+	if e.Type != crv1alpha1.ServerSideEncryptionTypeCustomer {
+		return sse, nil
+	}
+	if e.CustomerKeySecret == nil {
+		return nil, errkit.New("Secret of sse-c key not specified")
+	}
+	s, err := cli.CoreV1().Secrets(e.CustomerKeySecret.Namespace).Get(ctx, e.CustomerKeySecret.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to fetch sse-c key", "namespace", e.CustomerKeySecret.Namespace, "name", e.CustomerKeySecret.Name)
+	}
+	k, ok := s.Data[e.CustomerKeyField]
+	if !ok {
+		return nil, errkit.New(fmt.Sprintf("Key '%s' not found in secret '%s:%s'", e.CustomerKeyField, s.GetNamespace(), s.GetName()))
+	}
+	// Keys are 32 bytes long, raw or base64 encoded
+	if len(k) != sseCustomerKeySize {
+		d, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(k)))
+		if err != nil || len(d) != sseCustomerKeySize {
+			return nil, errkit.New(fmt.Sprintf("Key '%s' in secret '%s:%s' must be %d bytes or their base64 encoding", e.CustomerKeyField, s.GetNamespace(), s.GetName(), sseCustomerKeySize))
+		}
+		k = d
+	}
+	sse.CustomerKey = k
+	return sse, nil
+}
+
 func fetchRepositoryServer(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, ref *crv1alpha1.ObjectReference) (*RepositoryServer, error) {
 	if ref == nil {
 		log.Debug().Print("Executing the action without a repository-server")
@@ -340,6 +593,9 @@ func getKopiaRepositoryCacheSize(rs *crv1alpha1.RepositoryServer) crv1alpha1.Cac
 }
 
 func fetchCredential(ctx context.Context, cli kubernetes.Interface, c crv1alpha1.Credential) (*Credential, error) {
+	if c.Source != nil {
+		return fetchSourceCredential(ctx, cli, c)
+	}
 	switch c.Type {
 	case crv1alpha1.CredentialTypeKeyPair:
 		return fetchKeyPairCredential(ctx, cli, c.KeyPair)
@@ -360,21 +616,115 @@ func fetchKeyPairCredential(ctx context.Context, cli kubernetes.Interface, c *cr
 	if err != nil {
 		return nil, errkit.WithStack(err)
 	}
-	if _, ok := s.Data[c.IDField]; !ok {
-		return nil, errkit.New(fmt.Sprintf("Key '%s' not found in secret '%s:%s'", c.IDField, s.GetNamespace(), s.GetName()))
+	return keyPairCredential(c, s.Data, fmt.Sprintf("secret '%s:%s'", s.GetNamespace(), s.GetName()))
+}
+
+func keyPairCredential(c *crv1alpha1.KeyPair, data map[string][]byte, origin string) (*Credential, error) {
+	if _, ok := data[c.IDField]; !ok {
+		return nil, errkit.New(fmt.Sprintf("Key '%s' not found in %s", c.IDField, origin))
 	}
-	if _, ok := s.Data[c.SecretField]; !ok {
-		return nil, errkit.New(fmt.Sprintf("Value '%s' not found in secret '%s:%s'", c.SecretField, s.GetNamespace(), s.GetName()))
+	if _, ok := data[c.SecretField]; !ok {
+		return nil, errkit.New(fmt.Sprintf("Value '%s' not found in %s", c.SecretField, origin))
 	}
 	return &Credential{
 		Type: CredentialTypeKeyPair,
 		KeyPair: &KeyPair{
-			ID:     string(s.Data[c.IDField]),
-			Secret: string(s.Data[c.SecretField]),
+			ID:     string(data[c.IDField]),
+			Secret: string(data[c.SecretField]),
 		},
 	}, nil
 }
 
+// fetchSourceCredential reads the credential data from an external credential
+// source. The source is read when the ActionSet is executed, so that rotated
+// credentials are picked up without updating the Profile.
+func fetchSourceCredential(ctx context.Context, cli kubernetes.Interface, c crv1alpha1.Credential) (*Credential, error) {
+	if c.Type != crv1alpha1.CredentialTypeKeyPair && c.Type != crv1alpha1.CredentialTypeSecret {
+		return nil, errkit.New(fmt.Sprintf("CredentialType '%s' does not support credential sources", c.Type))
+	}
+	if c.Type == crv1alpha1.CredentialTypeKeyPair && c.KeyPair == nil {
+		return nil, errkit.New("KVSecret cannot be nil")
+	}
+	src, err := secrets.NewSource(cli, c.Source)
+	if err != nil {
+		return nil, err
+	}
+	cred, err := sourceCredential(ctx, c, src)
+	if err != nil {
+		return nil, err
+	}
+	if ls, ok := src.(secrets.LeasedSource); ok {
+		if exp := ls.LeaseExpiration(); !exp.IsZero() {
+			cred.Renewer = &sourceRenewer{
+				spec:       c,
+				src:        ls,
+				cred:       cred,
+				expiration: exp,
+			}
+		}
+	}
+	return cred, nil
+}
+
+// sourceCredential reads a credential from a credential source.
+func sourceCredential(ctx context.Context, c crv1alpha1.Credential, src secrets.Source) (*Credential, error) {
+	data, err := src.Data(ctx)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to read credentials from credential source")
+	}
+	if c.Type == crv1alpha1.CredentialTypeKeyPair {
+		return keyPairCredential(c.KeyPair, data, "credential source")
+	}
+	s := &corev1.Secret{
+		Type: corev1.SecretType(c.Source.SecretType),
+		Data: data,
+	}
+	if c.Secret != nil {
+		s.Name = c.Secret.Name
+		s.Namespace = c.Secret.Namespace
+	}
+	if err = secrets.ValidateCredentials(s); err != nil {
+		return nil, err
+	}
+	return &Credential{
+		Type:   CredentialTypeSecret,
+		Secret: s,
+	}, nil
+}
+
+// credentialRenewalWindow is how long before their lease expires credentials
+// are read again from a credential source.
+const credentialRenewalWindow = 5 * time.Minute
+
+// sourceRenewer implements CredentialRenewer for credentials read from a
+// leased credential source.
+type sourceRenewer struct {
+	spec crv1alpha1.Credential
+	src  secrets.LeasedSource
+
+	mu         sync.Mutex
+	cred       *Credential
+	expiration time.Time
+}
+
+var _ CredentialRenewer = (*sourceRenewer)(nil)
+
+// Renew implements CredentialRenewer.
+func (r *sourceRenewer) Renew(ctx context.Context) (*Credential, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.expiration.IsZero() || time.Now().Add(credentialRenewalWindow).Before(r.expiration) {
+		return r.cred, r.expiration, nil
+	}
+	cred, err := sourceCredential(ctx, r.spec, r.src)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	cred.Renewer = r
+	r.cred, r.expiration = cred, r.src.LeaseExpiration()
+	return r.cred, r.expiration, nil
+}
+
 func fetchSecretCredential(ctx context.Context, cli kubernetes.Interface, sr *crv1alpha1.ObjectReference) (*Credential, error) {
 	if sr == nil {
 		return nil, errkit.New("Secret reference cannot be nil")
@@ -392,6 +742,23 @@ func fetchSecretCredential(ctx context.Context, cli kubernetes.Interface, sr *cr
 	}, nil
 }
 
+// fetchWorkloadIdentityCredential returns a credential whose ServiceAccount
+// tokens are requested when they are needed, so that long running actions
+// always use valid tokens.
+func fetchWorkloadIdentityCredential(cli kubernetes.Interface, wi *crv1alpha1.WorkloadIdentity, lt crv1alpha1.LocationType, namespace string) (*Credential, error) {
+	if err := secrets.ValidateWorkloadIdentity(wi, lt, namespace); err != nil {
+		return nil, err
+	}
+	sa := wi.ServiceAccount
+	return &Credential{
+		Type: CredentialTypeWorkloadIdentity,
+		WorkloadIdentity: &WorkloadIdentity{
+			WorkloadIdentity: *wi,
+			TokenSource:      secrets.NewServiceAccountTokenSource(cli, sa.Namespace, sa.Name, secrets.WorkloadIdentityAudience(wi, lt)),
+		},
+	}, nil
+}
+
 func secretFromSecretRef(ctx context.Context, cli kubernetes.Interface, ref corev1.SecretReference) (*corev1.Secret, error) {
 	secret, err := cli.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
 	if err != nil {
@@ -535,6 +902,144 @@ func fetchDeploymentParams(ctx context.Context, cli kubernetes.Interface, namesp
 	return dp, nil
 }
 
+func fetchDaemonSetParams(ctx context.Context, cli kubernetes.Interface, namespace, name string) (*DaemonSetParams, error) {
+	ds, err := cli.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errkit.WithStack(err)
+	}
+	pods, _, err := kube.FetchPods(cli, namespace, ds.UID)
+	if err != nil {
+		return nil, err
+	}
+	podNames, containers, pvcs := podParams(pods, kube.DaemonSetVolumes(cli, ds))
+	return &DaemonSetParams{
+		Name:                   name,
+		Namespace:              namespace,
+		Pods:                   podNames,
+		Containers:             containers,
+		PersistentVolumeClaims: pvcs,
+	}, nil
+}
+
+func fetchReplicaSetParams(ctx context.Context, cli kubernetes.Interface, namespace, name string) (*ReplicaSetParams, error) {
+	rs, err := cli.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errkit.WithStack(err)
+	}
+	pods, _, err := kube.FetchPods(cli, namespace, rs.UID)
+	if err != nil {
+		return nil, err
+	}
+	podNames, containers, pvcs := podParams(pods, kube.ReplicaSetVolumes(cli, rs))
+	return &ReplicaSetParams{
+		Name:                   name,
+		Namespace:              namespace,
+		Pods:                   podNames,
+		Containers:             containers,
+		PersistentVolumeClaims: pvcs,
+	}, nil
+}
+
+func fetchJobParams(ctx context.Context, cli kubernetes.Interface, namespace, name string) (*JobParams, error) {
+	job, err := cli.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errkit.WithStack(err)
+	}
+	pods, _, err := kube.FetchPods(cli, namespace, job.UID)
+	if err != nil {
+		return nil, err
+	}
+	podNames, containers, pvcs := podParams(pods, kube.JobVolumes(cli, job))
+	return &JobParams{
+		Name:                   name,
+		Namespace:              namespace,
+		Pods:                   podNames,
+		Containers:             containers,
+		PersistentVolumeClaims: pvcs,
+	}, nil
+}
+
+func fetchCronJobParams(ctx context.Context, cli kubernetes.Interface, namespace, name string) (*JobParams, error) {
+	cj, err := cli.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errkit.WithStack(err)
+	}
+	jobs, err := kube.FetchJobs(cli, namespace, cj.UID)
+	if err != nil {
+		return nil, err
+	}
+	var pods []corev1.Pod
+	for _, job := range jobs {
+		jobPods, _, err := kube.FetchPods(cli, namespace, job.UID)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, jobPods...)
+	}
+	podNames, containers, pvcs := podParams(pods, kube.CronJobVolumes(cli, cj))
+	return &JobParams{
+		Name:                   name,
+		Namespace:              namespace,
+		Pods:                   podNames,
+		Containers:             containers,
+		PersistentVolumeClaims: pvcs,
+	}, nil
+}
+
+// fetchWorkloadParams returns the params of an object that manages pods
+// through a label selector or a scale subresource. Nil is returned if the
+// object doesn't expose either.
+func fetchWorkloadParams(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, gvr schema.GroupVersionResource, u *unstructured.Unstructured) (*WorkloadParams, error) {
+	selector, err := kube.WorkloadSelector(ctx, dynCli, gvr, u)
+	if err != nil {
+		// Objects are also used by Blueprints that don't need pods, so we
+		// don't fail the action if the selector can't be determined.
+		log.Debug().WithContext(ctx).WithError(err).Print("Could not determine pod selector of object", field.M{"resource": gvr.String(), "namespace": u.GetNamespace(), "name": u.GetName()})
+		return nil, nil
+	}
+	if selector == nil {
+		return nil, nil
+	}
+	pods, _, err := kube.FetchPodsBySelector(ctx, cli, u.GetNamespace(), selector)
+	if err != nil {
+		return nil, err
+	}
+	wp := &WorkloadParams{
+		Name:                   u.GetName(),
+		Namespace:              u.GetNamespace(),
+		Group:                  gvr.Group,
+		Version:                gvr.Version,
+		Resource:               gvr.Resource,
+		Pods:                   []string{},
+		Containers:             [][]string{},
+		PersistentVolumeClaims: make(map[string]map[string]string),
+	}
+	for _, p := range pods {
+		wp.Pods = append(wp.Pods, p.Name)
+		wp.Containers = append(wp.Containers, containerNames(p))
+		if pvcToMountPath := volumes(p, kube.PodVolumes(&p)); len(pvcToMountPath) > 0 {
+			wp.PersistentVolumeClaims[p.Name] = pvcToMountPath
+		}
+	}
+	return wp, nil
+}
+
+// podParams returns the pod names, container names and [pod]->[PVC]->[mount path]
+// mapping for pods created from a single pod template.
+func podParams(pods []corev1.Pod, volToPvc map[string]string) ([]string, [][]string, map[string]map[string]string) {
+	podNames := []string{}
+	containers := [][]string{}
+	pvcs := make(map[string]map[string]string)
+	for _, p := range pods {
+		podNames = append(podNames, p.Name)
+		containers = append(containers, containerNames(p))
+		if pvcToMountPath := volumes(p, volToPvc); len(pvcToMountPath) > 0 {
+			pvcs[p.Name] = pvcToMountPath
+		}
+	}
+	return podNames, containers, pvcs
+}
+
 func containerNames(pod corev1.Pod) []string {
 	cs := make([]string, 0, len(pod.Status.ContainerStatuses))
 	for _, c := range pod.Status.ContainerStatuses {
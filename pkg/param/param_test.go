@@ -17,7 +17,10 @@ package param
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"text/template"
@@ -31,7 +34,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	fakedyncli "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
@@ -42,6 +47,7 @@ import (
 	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
 	"github.com/kanisterio/kanister/pkg/ksprig"
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/secrets"
 )
 
 // Hook up gocheck into the "go test" runner.
@@ -447,7 +453,7 @@ func (s *ParamsSuite) testNewTemplateParams(ctx context.Context, c *check.C, dyn
 	artsTpl["kindArtifact"] = crv1alpha1.Artifact{KeyValue: map[string]string{"my-key": template}}
 	artsTpl["objectNameArtifact"] = crv1alpha1.Artifact{KeyValue: map[string]string{"my-key": unstructuredTemplate}}
 
-	tp, err := New(ctx, s.cli, dynCli, crCli, osCli, as)
+	tp, err := New(ctx, s.cli, dynCli, crCli, osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 	c.Assert(tp.ConfigMaps["myCM"].Data, check.DeepEquals, map[string]string{"someKey": "some-value"})
 	c.Assert(tp.Options, check.DeepEquals, map[string]string{"podName": "some-pod"})
@@ -504,6 +510,135 @@ func (s *ParamsSuite) TestfetchKVSecretCredential(c *check.C) {
 	}
 }
 
+type CredentialSourceSuite struct{}
+
+var _ = check.Suite(&CredentialSourceSuite{})
+
+func (s *CredentialSourceSuite) TestFetchSourceCredential(c *check.C) {
+	dir := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(dir, secrets.AWSAccessKeyID), []byte("foo"), 0600), check.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dir, secrets.AWSSecretAccessKey), []byte("bar"), 0600), check.IsNil)
+	src := &crv1alpha1.CredentialSource{File: &crv1alpha1.FileCredentialSource{Path: dir}}
+	ctx := context.Background()
+	c.Assert(os.Setenv(secrets.CredentialFileRootEnv, dir), check.IsNil)
+	defer os.Unsetenv(secrets.CredentialFileRootEnv) //nolint:errcheck
+
+	cred, err := fetchCredential(ctx, fake.NewSimpleClientset(), crv1alpha1.Credential{
+		Type: crv1alpha1.CredentialTypeKeyPair,
+		KeyPair: &crv1alpha1.KeyPair{
+			IDField:     secrets.AWSAccessKeyID,
+			SecretField: secrets.AWSSecretAccessKey,
+		},
+		Source: src,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(cred, check.DeepEquals, &Credential{
+		Type:    CredentialTypeKeyPair,
+		KeyPair: &KeyPair{ID: "foo", Secret: "bar"},
+	})
+
+	_, err = fetchCredential(ctx, fake.NewSimpleClientset(), crv1alpha1.Credential{
+		Type: crv1alpha1.CredentialTypeKeyPair,
+		KeyPair: &crv1alpha1.KeyPair{
+			IDField:     "missing",
+			SecretField: secrets.AWSSecretAccessKey,
+		},
+		Source: src,
+	})
+	c.Assert(err, check.NotNil)
+
+	srcWithType := src.DeepCopy()
+	srcWithType.SecretType = secrets.AWSSecretType
+	cred, err = fetchCredential(ctx, fake.NewSimpleClientset(), crv1alpha1.Credential{
+		Type:   crv1alpha1.CredentialTypeSecret,
+		Source: srcWithType,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(cred.Type, check.Equals, CredentialTypeSecret)
+	c.Assert(string(cred.Secret.Data[secrets.AWSAccessKeyID]), check.Equals, "foo")
+
+	// The secret type is required to validate the data
+	_, err = fetchCredential(ctx, fake.NewSimpleClientset(), crv1alpha1.Credential{
+		Type:   crv1alpha1.CredentialTypeSecret,
+		Source: src,
+	})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *CredentialSourceSuite) TestFetchEncryptionKeys(c *check.C) {
+	ctx := context.Background()
+	cli := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "ns"},
+		Data: map[string][]byte{
+			"old": []byte("old-key"),
+			"new": []byte("new-key"),
+		},
+	})
+	keys, err := fetchEncryptionKeys(ctx, cli, &crv1alpha1.Encryption{
+		KeySecret: crv1alpha1.ObjectReference{Name: "keys", Namespace: "ns"},
+		KeyID:     "new",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(keys, check.DeepEquals, &EncryptionKeys{
+		KeyID: "new",
+		Keys:  map[string][]byte{"old": []byte("old-key"), "new": []byte("new-key")},
+	})
+
+	_, err = fetchEncryptionKeys(ctx, cli, &crv1alpha1.Encryption{
+		KeySecret: crv1alpha1.ObjectReference{Name: "keys", Namespace: "ns"},
+		KeyID:     "missing",
+	})
+	c.Assert(err, check.ErrorMatches, ".*Key 'missing' not found.*")
+
+	_, err = fetchEncryptionKeys(ctx, cli, &crv1alpha1.Encryption{
+		KeySecret: crv1alpha1.ObjectReference{Name: "missing", Namespace: "ns"},
+		KeyID:     "new",
+	})
+	c.Assert(err, check.NotNil)
+}
+
+func (s *CredentialSourceSuite) TestFetchServerSideEncryption(c *check.C) {
+	ctx := context.Background()
+	key := bytes.Repeat([]byte{1}, sseCustomerKeySize)
+	cli := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sse", Namespace: "ns"},
+		Data: map[string][]byte{
+			"raw":    key,
+			"base64": []byte(base64.StdEncoding.EncodeToString(key) + "\n"),
+			"short":  []byte("short"),
+		},
+	})
+	sse, err := fetchServerSideEncryption(ctx, cli, &crv1alpha1.ServerSideEncryption{
+		Type:     crv1alpha1.ServerSideEncryptionTypeKMS,
+		KMSKeyID: "alias/backups",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(sse, check.DeepEquals, &ServerSideEncryption{
+		Type:     crv1alpha1.ServerSideEncryptionTypeKMS,
+		KMSKeyID: "alias/backups",
+	})
+
+	for _, tc := range []struct {
+		field   string
+		checker check.Checker
+	}{
+		{field: "raw", checker: check.IsNil},
+		{field: "base64", checker: check.IsNil},
+		{field: "short", checker: check.NotNil},
+		{field: "missing", checker: check.NotNil},
+	} {
+		sse, err := fetchServerSideEncryption(ctx, cli, &crv1alpha1.ServerSideEncryption{
+			Type:              crv1alpha1.ServerSideEncryptionTypeCustomer,
+			CustomerKeySecret: &crv1alpha1.ObjectReference{Name: "sse", Namespace: "ns"},
+			CustomerKeyField:  tc.field,
+		})
+		c.Check(err, tc.checker, check.Commentf(tc.field))
+		if err == nil {
+			c.Check(sse.CustomerKey, check.DeepEquals, key)
+		}
+	}
+}
+
 func (s *ParamsSuite) TestProfile(c *check.C) {
 	ss := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -591,7 +726,7 @@ func (s *ParamsSuite) TestProfile(c *check.C) {
 
 	osCli := osfake.NewSimpleClientset()
 
-	tp, err := New(ctx, cli, dynCli, crCli, osCli, as.Spec.Actions[0])
+	tp, err := New(ctx, cli, dynCli, crCli, osCli, as.Spec.Actions[0], as.Namespace)
 	c.Assert(err, check.IsNil)
 	c.Assert(tp.Profile, check.NotNil)
 	c.Assert(tp.Profile, check.DeepEquals, &Profile{
@@ -646,7 +781,7 @@ func (s *ParamsSuite) TestParamsWithoutProfile(c *check.C) {
 			},
 		},
 	}
-	tp, err := New(ctx, s.cli, dynCli, crCli, osCli, as)
+	tp, err := New(ctx, s.cli, dynCli, crCli, osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 	c.Assert(tp, check.NotNil)
 }
@@ -716,7 +851,7 @@ func (s *ParamsSuite) TestPhaseParams(c *check.C) {
 			},
 		},
 	}
-	tp, err := New(ctx, s.cli, dynCli, crCli, osCli, as)
+	tp, err := New(ctx, s.cli, dynCli, crCli, osCli, as, s.namespace)
 	c.Assert(err, check.IsNil)
 	err = InitPhaseParams(ctx, s.cli, tp, "backup", nil)
 	c.Assert(err, check.IsNil)
@@ -834,3 +969,83 @@ func newDeploymentConfig() *osapps.DeploymentConfig {
 		},
 	}
 }
+
+type WorkloadParamsSuite struct{}
+
+var _ = check.Suite(&WorkloadParamsSuite{})
+
+func (s *WorkloadParamsSuite) TestFetchWorkloadParams(c *check.C) {
+	gvr := schema.GroupVersionResource{Group: "postgresql.cnpg.io", Version: "v1", Resource: "clusters"}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "postgresql.cnpg.io/v1",
+		"kind":       "Cluster",
+		"metadata":   map[string]interface{}{"name": "pg", "namespace": "ns"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"cnpg.io/cluster": "pg"},
+			},
+		},
+	}}
+	cli := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg-1", Namespace: "ns", Labels: map[string]string{"cnpg.io/cluster": "pg"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "postgres", VolumeMounts: []corev1.VolumeMount{{Name: "pgdata", MountPath: "/var/lib/postgresql/data"}}}},
+				Volumes:    []corev1.Volume{{Name: "pgdata", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pg-1"}}}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{Name: "postgres"}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	)
+	dynCli := fakedyncli.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "ClusterList"}, u)
+
+	wp, err := fetchWorkloadParams(context.Background(), cli, dynCli, gvr, u)
+	c.Assert(err, check.IsNil)
+	c.Assert(wp, check.DeepEquals, &WorkloadParams{
+		Name:       "pg",
+		Namespace:  "ns",
+		Group:      "postgresql.cnpg.io",
+		Version:    "v1",
+		Resource:   "clusters",
+		Pods:       []string{"pg-1"},
+		Containers: [][]string{{"postgres"}},
+		PersistentVolumeClaims: map[string]map[string]string{
+			"pg-1": {"pg-1": "/var/lib/postgresql/data"},
+		},
+	})
+
+	// Objects without a selector or scale subresource aren't workloads
+	delete(u.Object, "spec")
+	wp, err = fetchWorkloadParams(context.Background(), cli, dynCli, gvr, u)
+	c.Assert(err, check.IsNil)
+	c.Assert(wp, check.IsNil)
+}
+
+type FilesystemProfileSuite struct{}
+
+var _ = check.Suite(&FilesystemProfileSuite{})
+
+func (s *FilesystemProfileSuite) TestFetchProfile(c *check.C) {
+	ctx := context.Background()
+	location := crv1alpha1.Location{
+		Type:      crv1alpha1.LocationTypeFilesystem,
+		Bucket:    "backups",
+		ClaimName: "backup-pvc",
+	}
+	prof := &crv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "profName",
+			Namespace: "ns",
+		},
+		Location: location,
+	}
+	crCli := crfake.NewSimpleClientset(prof)
+
+	// Filesystem profiles do not need credentials
+	p, err := fetchProfile(ctx, fake.NewSimpleClientset(), crCli, &crv1alpha1.ObjectReference{Name: "profName", Namespace: "ns"})
+	c.Assert(err, check.IsNil)
+	c.Assert(p, check.DeepEquals, &Profile{Location: location})
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	"github.com/kanisterio/errkit"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+const (
+	// RateLimitOption is the ActionSet option that overrides the rate limit
+	// of the profile with a quantity of bytes per second, e.g. 10Mi. A rate
+	// of 0 disables the limit.
+	RateLimitOption = "rateLimit"
+	// RateLimitBurstOption is the ActionSet option that overrides the burst
+	// of the rate limit with a quantity of bytes.
+	RateLimitBurstOption = "rateLimitBurst"
+)
+
+// RateLimit returns the rate limit of data movement of an action, which is
+// the rate limit of its profile unless it is overridden by the options of
+// the ActionSet. It returns nil if data movement is not limited.
+func RateLimit(profile *Profile, options map[string]string) (*crv1alpha1.RateLimit, error) {
+	var rl crv1alpha1.RateLimit
+	if profile != nil && profile.RateLimit != nil {
+		rl = *profile.RateLimit
+	}
+	if v, ok := options[RateLimitOption]; ok {
+		bps, err := parseRateLimitOption(RateLimitOption, v)
+		if err != nil {
+			return nil, err
+		}
+		rl = crv1alpha1.RateLimit{BytesPerSecond: bps}
+	}
+	if v, ok := options[RateLimitBurstOption]; ok {
+		burst, err := parseRateLimitOption(RateLimitBurstOption, v)
+		if err != nil {
+			return nil, err
+		}
+		if rl.BytesPerSecond == 0 {
+			return nil, errkit.New("Option rateLimitBurst requires a rate limit", "option", RateLimitBurstOption)
+		}
+		rl.Burst = burst
+	}
+	if rl.BytesPerSecond == 0 {
+		return nil, nil
+	}
+	return &rl, nil
+}
+
+func parseRateLimitOption(option, value string) (int64, error) {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, errkit.Wrap(err, "Failed to parse option", "option", option, "value", value)
+	}
+	if q.Sign() < 0 {
+		return 0, errkit.New("Option must not be negative", "option", option, "value", value)
+	}
+	return q.Value(), nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type RateLimitSuite struct{}
+
+var _ = check.Suite(&RateLimitSuite{})
+
+func (s *RateLimitSuite) TestRateLimit(c *check.C) {
+	profile := &Profile{RateLimit: &crv1alpha1.RateLimit{BytesPerSecond: 1 << 20, Burst: 1 << 10}}
+	for _, tc := range []struct {
+		profile  *Profile
+		options  map[string]string
+		expected *crv1alpha1.RateLimit
+		err      bool
+	}{
+		{profile: nil, options: nil, expected: nil},
+		{profile: profile, options: nil, expected: profile.RateLimit},
+		{
+			profile:  profile,
+			options:  map[string]string{RateLimitOption: "10Mi"},
+			expected: &crv1alpha1.RateLimit{BytesPerSecond: 10 << 20},
+		},
+		{
+			profile:  profile,
+			options:  map[string]string{RateLimitBurstOption: "4Ki"},
+			expected: &crv1alpha1.RateLimit{BytesPerSecond: 1 << 20, Burst: 4 << 10},
+		},
+		{
+			profile:  nil,
+			options:  map[string]string{RateLimitOption: "1M", RateLimitBurstOption: "100k"},
+			expected: &crv1alpha1.RateLimit{BytesPerSecond: 1000000, Burst: 100000},
+		},
+		// A rate of 0 disables the limit of the profile
+		{profile: profile, options: map[string]string{RateLimitOption: "0"}, expected: nil},
+		{profile: nil, options: map[string]string{RateLimitBurstOption: "4Ki"}, err: true},
+		{profile: profile, options: map[string]string{RateLimitOption: "fast"}, err: true},
+		{profile: profile, options: map[string]string{RateLimitOption: "-1Mi"}, err: true},
+	} {
+		rl, err := RateLimit(tc.profile, tc.options)
+		if tc.err {
+			c.Check(err, check.NotNil, check.Commentf("%v", tc.options))
+			continue
+		}
+		c.Check(err, check.IsNil, check.Commentf("%v", tc.options))
+		c.Check(rl, check.DeepEquals, tc.expected, check.Commentf("%v", tc.options))
+	}
+}
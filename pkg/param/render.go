@@ -24,7 +24,6 @@ import (
 	"github.com/kanisterio/errkit"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
-	"github.com/kanisterio/kanister/pkg/ksprig"
 )
 
 const (
@@ -119,7 +118,7 @@ func RenderArtifacts(arts map[string]crv1alpha1.Artifact, tp TemplateParams) (ma
 }
 
 func renderStringArg(arg string, tp TemplateParams) (string, error) {
-	t, err := template.New("config").Option("missingkey=error").Funcs(ksprig.TxtFuncMap()).Parse(arg)
+	t, err := template.New("config").Option("missingkey=error").Funcs(funcMap(tp.lookup)).Parse(arg)
 	if err != nil {
 		return "", errkit.WithStack(err)
 	}
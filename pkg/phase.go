@@ -147,6 +147,14 @@ func GetDeferPhase(bp crv1alpha1.Blueprint, action, version string, tp param.Tem
 	}, nil
 }
 
+// CheckFuncVersion returns an error if the function `f` cannot be run with
+// the preferred `version`, i.e. if it is registered neither with it nor with
+// the default version that is used instead.
+func CheckFuncVersion(f, version string) error {
+	_, err := regFuncVersion(f, version)
+	return err
+}
+
 func regFuncVersion(f, version string) (semver.Version, error) {
 	funcMu.RLock()
 	defer funcMu.RUnlock()
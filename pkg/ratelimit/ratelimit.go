@@ -0,0 +1,143 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit throttles the bandwidth of data that Kanister moves to
+// and from locations.
+package ratelimit
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// Limiter throttles the bytes that are moved. *rate.Limiter implements it.
+type Limiter interface {
+	// WaitN blocks until n bytes can be moved. n must not exceed the burst.
+	WaitN(ctx context.Context, n int) error
+	// Burst is the number of bytes that can be moved at once.
+	Burst() int
+}
+
+// NewLimiter returns a token bucket that allows l.BytesPerSecond bytes per
+// second and bursts of up to l.Burst bytes. The burst defaults to one second
+// of data. It returns nil, which does not limit anything, if l is nil or its
+// rate is not positive.
+func NewLimiter(l *crv1alpha1.RateLimit) Limiter {
+	if l == nil || l.BytesPerSecond <= 0 {
+		return nil
+	}
+	burst := l.Burst
+	if burst <= 0 {
+		burst = l.BytesPerSecond
+	}
+	return rate.NewLimiter(rate.Limit(l.BytesPerSecond), int(burst))
+}
+
+// Reader returns a reader that reads from r no faster than allowed by lim.
+// Reads are not throttled if lim is nil.
+func Reader(ctx context.Context, r io.Reader, lim Limiter) io.Reader {
+	if lim == nil {
+		return r
+	}
+	return &reader{ctx: ctx, r: r, lim: lim}
+}
+
+// ReaderAt returns a reader that reads from r no faster than allowed by
+// lim, which may be shared by concurrent reads. Reads are not throttled if
+// lim is nil.
+func ReaderAt(ctx context.Context, r io.ReaderAt, lim Limiter) io.ReaderAt {
+	if lim == nil {
+		return r
+	}
+	return &readerAt{ctx: ctx, r: r, lim: lim}
+}
+
+// Writer returns a writer that writes to w no faster than allowed by lim.
+// Writes are not throttled if lim is nil.
+func Writer(ctx context.Context, w io.Writer, lim Limiter) io.Writer {
+	if lim == nil {
+		return w
+	}
+	return &writer{ctx: ctx, w: w, lim: lim}
+}
+
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+	lim Limiter
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	// Limiters cannot wait for more than their burst at once
+	if b := r.lim.Burst(); len(p) > b {
+		p = p[:b]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.lim.WaitN(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type readerAt struct {
+	ctx context.Context
+	r   io.ReaderAt
+	lim Limiter
+}
+
+// ReadAt reads p in chunks of at most the burst of the limiter, since
+// readers at offsets must fill p unless they fail.
+func (r *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	var read int
+	for read < len(p) {
+		chunk := min(len(p)-read, r.lim.Burst())
+		if err := r.lim.WaitN(r.ctx, chunk); err != nil {
+			return read, err
+		}
+		n, err := r.r.ReadAt(p[read:read+chunk], off+int64(read))
+		read += n
+		if err != nil {
+			return read, err
+		}
+	}
+	return read, nil
+}
+
+type writer struct {
+	ctx context.Context
+	w   io.Writer
+	lim Limiter
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	var written int
+	for written < len(p) {
+		chunk := min(len(p)-written, w.lim.Burst())
+		if err := w.lim.WaitN(w.ctx, chunk); err != nil {
+			return written, err
+		}
+		n, err := w.w.Write(p[written : written+chunk])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
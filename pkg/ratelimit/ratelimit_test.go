@@ -0,0 +1,114 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type RateLimitSuite struct{}
+
+var _ = check.Suite(&RateLimitSuite{})
+
+func (s *RateLimitSuite) TestNewLimiter(c *check.C) {
+	c.Assert(NewLimiter(nil), check.IsNil)
+	c.Assert(NewLimiter(&crv1alpha1.RateLimit{}), check.IsNil)
+	c.Assert(NewLimiter(&crv1alpha1.RateLimit{BytesPerSecond: -1}), check.IsNil)
+
+	lim := NewLimiter(&crv1alpha1.RateLimit{BytesPerSecond: 1024})
+	c.Assert(lim.Burst(), check.Equals, 1024)
+	lim = NewLimiter(&crv1alpha1.RateLimit{BytesPerSecond: 1024, Burst: 4096})
+	c.Assert(lim.Burst(), check.Equals, 4096)
+}
+
+func (s *RateLimitSuite) TestUnlimited(c *check.C) {
+	ctx := context.Background()
+	r := bytes.NewReader(nil)
+	c.Assert(Reader(ctx, r, nil), check.Equals, r)
+	c.Assert(ReaderAt(ctx, r, nil), check.Equals, r)
+	w := &bytes.Buffer{}
+	c.Assert(Writer(ctx, w, nil), check.Equals, w)
+}
+
+func (s *RateLimitSuite) TestThrottle(c *check.C) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("x"), 64<<10)
+
+	lim := &fakeLimiter{burst: 16 << 10}
+	out, err := io.ReadAll(Reader(ctx, bytes.NewReader(data), lim))
+	c.Assert(err, check.IsNil)
+	c.Assert(out, check.DeepEquals, data)
+	lim.check(c, len(data))
+
+	lim = &fakeLimiter{burst: 16 << 10}
+	out = make([]byte, len(data))
+	n, err := ReaderAt(ctx, bytes.NewReader(data), lim).ReadAt(out, 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, len(data))
+	c.Assert(out, check.DeepEquals, data)
+	lim.check(c, len(data))
+
+	lim = &fakeLimiter{burst: 16 << 10}
+	buf := &bytes.Buffer{}
+	n, err = Writer(ctx, buf, lim).Write(data)
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, len(data))
+	c.Assert(buf.Bytes(), check.DeepEquals, data)
+	lim.check(c, len(data))
+}
+
+func (s *RateLimitSuite) TestCancel(c *check.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &crv1alpha1.RateLimit{BytesPerSecond: 1, Burst: 1}
+	w := Writer(ctx, io.Discard, NewLimiter(l))
+	cancel()
+	_, err := w.Write([]byte("data"))
+	c.Assert(err, check.NotNil)
+}
+
+// fakeLimiter records the bytes that are waited for instead of waiting.
+type fakeLimiter struct {
+	burst  int
+	waited []int
+}
+
+func (l *fakeLimiter) WaitN(ctx context.Context, n int) error {
+	l.waited = append(l.waited, n)
+	return ctx.Err()
+}
+
+func (l *fakeLimiter) Burst() int {
+	return l.burst
+}
+
+// check asserts that size bytes were waited for in chunks of at most the
+// burst.
+func (l *fakeLimiter) check(c *check.C, size int) {
+	var total int
+	for _, n := range l.waited {
+		c.Assert(n <= l.burst, check.Equals, true)
+		total += n
+	}
+	c.Assert(total, check.Equals, size)
+}
@@ -58,3 +58,27 @@ func ActionSet(ctx context.Context, cli crclientv1alpha1.CrV1alpha1Interface, ns
 		return true, nil
 	})
 }
+
+// Profile attempts to reconcile the modifications made by `f` with the
+// Profile stored in the API server.
+func Profile(ctx context.Context, cli crclientv1alpha1.CrV1alpha1Interface, ns, name string, f func(*crv1alpha1.Profile) error) error {
+	return poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		p, err := cli.Profiles(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, errkit.WithStack(err)
+		}
+		if err = f(p); err != nil {
+			return false, err
+		}
+		_, err = cli.Profiles(p.GetNamespace()).Update(ctx, p, metav1.UpdateOptions{})
+		// If we get a version conflict, we backoff and try again.
+		if apierrors.IsConflict(err) {
+			return false, nil
+		}
+		if err != nil {
+			msg := fmt.Sprintf("Failed to update Profile %s", name)
+			return false, errkit.Wrap(err, msg)
+		}
+		return true, nil
+	})
+}
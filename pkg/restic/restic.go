@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -197,6 +198,9 @@ const (
 )
 
 func resticArgs(profile *param.Profile, repository, encryptionKey string) ([]string, error) {
+	if err := resticServerSideEncryption(profile.ServerSideEncryption); err != nil {
+		return nil, err
+	}
 	var cmd []string
 	var err error
 	switch profile.Location.Type {
@@ -206,13 +210,37 @@ func resticArgs(profile *param.Profile, repository, encryptionKey string) ([]str
 		cmd = resticGCSArgs(profile, repository)
 	case crv1alpha1.LocationTypeAzure:
 		cmd, err = resticAzureArgs(profile, repository)
+	case crv1alpha1.LocationTypeFilesystem:
+		cmd = resticFilesystemArgs(profile, repository)
 	default:
 		return nil, errkit.New(fmt.Sprintf("Unsupported type '%s' for the location", profile.Location.Type))
 	}
 	if err != nil {
 		return nil, errkit.Wrap(err, "Failed to get arguments")
 	}
-	return append(cmd, fmt.Sprintf("export %s=%s\n", ResticPassword, encryptionKey), ResticCommand), nil
+	cmd = append(cmd, fmt.Sprintf("export %s=%s\n", ResticPassword, encryptionKey), ResticCommand)
+	return append(cmd, resticRateLimitArgs(profile.RateLimit)...), nil
+}
+
+// resticServerSideEncryption returns an error if restic cannot write objects
+// that are encrypted as `sse` requires. Restic does not set server-side
+// encryption headers, so its objects are encrypted with the default
+// encryption of the bucket, which is sse-s3 unless it is configured otherwise.
+func resticServerSideEncryption(sse *param.ServerSideEncryption) error {
+	if sse == nil || (sse.Type == crv1alpha1.ServerSideEncryptionTypeS3 && sse.EncryptionScope == "") {
+		return nil
+	}
+	return errkit.New("Restic only supports sse-s3 server-side encryption of a location, use the default encryption of the bucket instead", "type", sse.Type)
+}
+
+// resticRateLimitArgs returns the flags that limit the bandwidth of restic.
+// Restic limits bandwidth in KiB/s and does not support bursts.
+func resticRateLimitArgs(l *crv1alpha1.RateLimit) []string {
+	if l == nil || l.BytesPerSecond <= 0 {
+		return nil
+	}
+	kibps := strconv.FormatInt((l.BytesPerSecond+1023)/1024, 10)
+	return []string{"--limit-upload", kibps, "--limit-download", kibps}
 }
 
 func resticS3Args(profile *param.Profile, repository string) ([]string, error) {
@@ -241,6 +269,11 @@ func resticS3CredentialArgs(creds param.Credential) ([]string, error) {
 		}, nil
 	case param.CredentialTypeSecret:
 		return resticS3CredentialSecretArgs(creds.Secret)
+	case param.CredentialTypeWorkloadIdentity:
+		return []string{
+			fmt.Sprintf("export %s=%s\n", location.AWSRoleARN, creds.WorkloadIdentity.RoleARN),
+			fmt.Sprintf("export %s=%s\n", location.AWSWebIdentityTokenFile, consts.WorkloadIdentityTokenFilePath),
+		}, nil
 	default:
 		return nil, errkit.New(fmt.Sprintf("Unsupported type '%s' for credentials", creds.Type))
 	}
@@ -262,13 +295,25 @@ func resticS3CredentialSecretArgs(secret *corev1.Secret) ([]string, error) {
 }
 
 func resticGCSArgs(profile *param.Profile, repository string) []string {
+	projectID := ""
+	if profile.Credential.Type == param.CredentialTypeWorkloadIdentity {
+		projectID = profile.Credential.WorkloadIdentity.ProjectID
+	} else {
+		projectID = profile.Credential.KeyPair.ID
+	}
 	return []string{
-		fmt.Sprintf("export %s=%s\n", location.GoogleProjectID, profile.Credential.KeyPair.ID),
+		fmt.Sprintf("export %s=%s\n", location.GoogleProjectID, projectID),
 		fmt.Sprintf("export %s=%s\n", location.GoogleCloudCreds, consts.GoogleCloudCredsFilePath),
 		fmt.Sprintf("export %s=gs:%s/\n", ResticRepository, strings.Replace(repository, "/", ":/", 1)),
 	}
 }
 
+func resticFilesystemArgs(profile *param.Profile, repository string) []string {
+	return []string{
+		fmt.Sprintf("export %s=%s\n", ResticRepository, path.Join(location.FilesystemRoot(profile.Location), repository)),
+	}
+}
+
 func resticAzureArgs(profile *param.Profile, repository string) ([]string, error) {
 	var storageAccountID, storageAccountKey string
 	switch profile.Credential.Type {
@@ -282,6 +327,16 @@ func resticAzureArgs(profile *param.Profile, repository string) ([]string, error
 		}
 		storageAccountID = creds.StorageAccount
 		storageAccountKey = creds.StorageKey
+	case param.CredentialTypeWorkloadIdentity:
+		// restic uses the Azure default credential chain if no key is set
+		wi := profile.Credential.WorkloadIdentity
+		return []string{
+			fmt.Sprintf("export %s=%s\n", location.AzureStorageAccount, wi.StorageAccount),
+			fmt.Sprintf("export %s=%s\n", location.AzureClientID, wi.ClientID),
+			fmt.Sprintf("export %s=%s\n", location.AzureTenantID, wi.TenantID),
+			fmt.Sprintf("export %s=%s\n", location.AzureFederatedTokenFile, consts.WorkloadIdentityTokenFilePath),
+			fmt.Sprintf("export %s=azure:%s/\n", ResticRepository, strings.Replace(repository, "/", ":/", 1)),
+		}, nil
 	}
 
 	return []string{
@@ -490,6 +545,12 @@ func DoesRepoExist(output string) bool {
 	return strings.Contains(output, "Is there a repository at the following location?")
 }
 
+// IsAccessDenied checks if the object store denied a request of a command,
+// e.g. to delete objects that are retained by an object lock, from its log
+func IsAccessDenied(output string) bool {
+	return strings.Contains(output, "Access Denied") || strings.Contains(output, "AccessDenied")
+}
+
 // SpaceFreedFromPruneLog gets the space freed from the prune log output
 // For reference, here is the logging command from restic codebase:
 //
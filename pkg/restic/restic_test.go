@@ -239,6 +239,67 @@ func (s *ResticDataSuite) TestResticArgs(c *check.C) {
 				"restic",
 			},
 		},
+		{
+			profile: &param.Profile{
+				Location: crv1alpha1.Location{
+					Type: crv1alpha1.LocationTypeAzure,
+				},
+				Credential: param.Credential{
+					Type: param.CredentialTypeWorkloadIdentity,
+					WorkloadIdentity: &param.WorkloadIdentity{
+						WorkloadIdentity: crv1alpha1.WorkloadIdentity{
+							TenantID:       "tenant",
+							ClientID:       "client",
+							StorageAccount: "account",
+						},
+					},
+				},
+			},
+			repo:     "bucket/repo",
+			password: "my-secret",
+			expected: []string{
+				"export AZURE_ACCOUNT_NAME=account\n",
+				"export AZURE_CLIENT_ID=client\n",
+				"export AZURE_TENANT_ID=tenant\n",
+				"export AZURE_FEDERATED_TOKEN_FILE=/tmp/kanister-workload-identity-token\n",
+				"export RESTIC_REPOSITORY=azure:bucket:/repo/\n",
+				"export RESTIC_PASSWORD=my-secret\n",
+				"restic",
+			},
+		},
+		{
+			profile: &param.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+			},
+			repo:     "bucket/repo",
+			password: "my-secret",
+			expected: []string{
+				"export RESTIC_REPOSITORY=/mnt/kanister-location/bucket/repo\n",
+				"export RESTIC_PASSWORD=my-secret\n",
+				"restic",
+			},
+		},
+		{
+			profile: &param.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				RateLimit: &crv1alpha1.RateLimit{BytesPerSecond: 1000, Burst: 4096},
+			},
+			repo:     "bucket/repo",
+			password: "my-secret",
+			expected: []string{
+				"export RESTIC_REPOSITORY=/mnt/kanister-location/bucket/repo\n",
+				"export RESTIC_PASSWORD=my-secret\n",
+				"restic",
+				"--limit-upload", "1",
+				"--limit-download", "1",
+			},
+		},
 	} {
 		args, err := resticArgs(tc.profile, tc.repo, tc.password)
 		c.Assert(err, check.IsNil)
@@ -246,6 +307,33 @@ func (s *ResticDataSuite) TestResticArgs(c *check.C) {
 	}
 }
 
+func (s *ResticDataSuite) TestResticArgsServerSideEncryption(c *check.C) {
+	profile := &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeS3Compliant,
+			Endpoint: "endpoint",
+		},
+		Credential: param.Credential{
+			Type: param.CredentialTypeKeyPair,
+			KeyPair: &param.KeyPair{
+				ID:     "id",
+				Secret: "secret",
+			},
+		},
+		ServerSideEncryption: &param.ServerSideEncryption{
+			Type:     crv1alpha1.ServerSideEncryptionTypeKMS,
+			KMSKeyID: "alias/backups",
+		},
+	}
+	_, err := resticArgs(profile, "repo", "my-secret")
+	c.Assert(err, check.ErrorMatches, ".*Restic only supports sse-s3 server-side encryption.*")
+
+	// Objects are encrypted with sse-s3 without headers
+	profile.ServerSideEncryption = &param.ServerSideEncryption{Type: crv1alpha1.ServerSideEncryptionTypeS3}
+	_, err = resticArgs(profile, "repo", "my-secret")
+	c.Assert(err, check.IsNil)
+}
+
 func (s *ResticDataSuite) TestResticArgsWithAWSRole(c *check.C) {
 	for _, tc := range []struct {
 		profile *param.Profile
@@ -348,6 +436,20 @@ s3:s3.amazonaws.com/abhdbhf/foodbar`, expected: false},
 	}
 }
 
+func (s *ResticDataSuite) TestIsAccessDenied(c *check.C) {
+	for _, tc := range []struct {
+		log      string
+		expected bool
+	}{
+		{log: `Fatal: wrong password or no key found`, expected: false},
+		{log: `Remove(<snapshot/0123abcd>) returned error, retrying after 552ms: Access Denied.`, expected: true},
+		{log: `Fatal: unable to remove <snapshot/0123abcd> from the repository: AccessDenied: Access Denied`, expected: true},
+	} {
+		output := IsAccessDenied(tc.log)
+		c.Assert(output, check.Equals, tc.expected)
+	}
+}
+
 func (s *ResticDataSuite) TestDoesRepoExist(c *check.C) {
 	for _, tc := range []struct {
 		log      string
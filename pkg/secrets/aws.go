@@ -93,16 +93,7 @@ func ValidateAWSCredentials(secret *corev1.Secret) error {
 // https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_use.html#id_roles_use_view-role-max-session.
 // The IAM role's max duration setting can be modified between 1h to 12h.
 func ExtractAWSCredentials(ctx context.Context, secret *corev1.Secret, assumeRoleDuration time.Duration) (*credentials.Value, error) {
-	if err := ValidateAWSCredentials(secret); err != nil {
-		return nil, err
-	}
-	config := map[string]string{
-		aws.AccessKeyID:        string(secret.Data[AWSAccessKeyID]),
-		aws.SecretAccessKey:    string(secret.Data[AWSSecretAccessKey]),
-		aws.ConfigRole:         string(secret.Data[ConfigRole]),
-		aws.AssumeRoleDuration: assumeRoleDuration.String(),
-	}
-	creds, err := aws.GetCredentials(ctx, config)
+	creds, err := AWSCredentials(ctx, secret, assumeRoleDuration)
 	if err != nil {
 		return nil, err
 	}
@@ -116,3 +107,20 @@ func ExtractAWSCredentials(ctx context.Context, secret *corev1.Secret, assumeRol
 	}
 	return &val, nil
 }
+
+// AWSCredentials returns the AWS credentials of the given secret. Unlike
+// ExtractAWSCredentials, the returned credentials assume the role again
+// whenever the previous session expires, so they can be used for operations
+// that take longer than assumeRoleDuration.
+func AWSCredentials(ctx context.Context, secret *corev1.Secret, assumeRoleDuration time.Duration) (*credentials.Credentials, error) {
+	if err := ValidateAWSCredentials(secret); err != nil {
+		return nil, err
+	}
+	config := map[string]string{
+		aws.AccessKeyID:        string(secret.Data[AWSAccessKeyID]),
+		aws.SecretAccessKey:    string(secret.Data[AWSSecretAccessKey]),
+		aws.ConfigRole:         string(secret.Data[ConfigRole]),
+		aws.AssumeRoleDuration: assumeRoleDuration.String(),
+	}
+	return aws.GetCredentials(ctx, config)
+}
@@ -45,6 +45,18 @@ const (
 	TypeKey          = "type"
 	// Location secret key to be used only for filestore location type
 	ClaimNameKey = "claimName"
+	// Location secret keys to be used only for s3 location types with
+	// object lock enabled
+	ObjectLockModeKey  = "objectLockMode"
+	RetentionPeriodKey = "retentionPeriod"
+	// Location secret keys of server-side encryption. The type and KMS key
+	// are used by s3 location types and the encryption scope by azure
+	ServerSideEncryptionKey = "serverSideEncryption"
+	KMSKeyIDKey             = "kmsKeyID"
+	EncryptionScopeKey      = "encryptionScope"
+	// Location secret key of the bandwidth limit of kopia, in bytes per
+	// second
+	RateLimitKey = "rateLimit"
 
 	// Kopia Repository Server secret keys
 	RepoPasswordKey  = "repo-password"
@@ -38,6 +38,8 @@ func ValidateCredentials(secret *corev1.Secret) error {
 		return ValidateAzureCredentials(secret)
 	case GCPSecretType:
 		return ValidateGCPCredentials(secret)
+	case SFTPSecretType:
+		return ValidateSFTPCredentials(secret)
 	case FilestoreSecretType:
 		// returning nil currently since we
 		// dont need credentials for file store
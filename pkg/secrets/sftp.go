@@ -0,0 +1,98 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"github.com/kanisterio/errkit"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kanisterio/kanister/pkg/objectstore"
+	secerrors "github.com/kanisterio/kanister/pkg/secrets/errors"
+)
+
+const (
+	// SFTPSecretType represents the secret type for SFTP credentials.
+	SFTPSecretType string = "secrets.kanister.io/sftp"
+
+	// SFTPUsername is the config map key for the SFTP user name
+	SFTPUsername string = "username"
+	// SFTPPassword is the config map key for the password of the SFTP user
+	SFTPPassword string = "password"
+	// SFTPPrivateKey is the config map key for the PEM encoded private key of
+	// the SFTP user
+	SFTPPrivateKey string = "private_key"
+	// SFTPPrivateKeyPassphrase is the config map key for the passphrase of an
+	// encrypted private key
+	SFTPPrivateKeyPassphrase string = "private_key_passphrase"
+	// SFTPHostKey is the config map key for the public keys of the SFTP
+	// server, in the format of authorized_keys or known_hosts files
+	SFTPHostKey string = "host_key"
+)
+
+// ValidateSFTPCredentials validates secret has all necessary information
+// for SFTP credentials. It also checks the secret doesn't have unnecessary
+// information.
+//
+// Required fields:
+// - username
+// - host_key
+// - password or private_key
+//
+// Optional field:
+// - private_key_passphrase
+func ValidateSFTPCredentials(secret *corev1.Secret) error {
+	if secret == nil {
+		return errkit.Wrap(secerrors.ErrValidate, secerrors.NilSecretErrorMessage)
+	}
+	if string(secret.Type) != SFTPSecretType {
+		return errkit.Wrap(secerrors.ErrValidate, secerrors.IncompatibleSecretTypeErrorMsg, SFTPSecretType, secret.Namespace, secret.Name)
+	}
+	for _, field := range []string{SFTPUsername, SFTPHostKey} {
+		if _, ok := secret.Data[field]; !ok {
+			return errkit.Wrap(secerrors.ErrValidate, secerrors.MissingRequiredFieldErrorMsg, field, secret.Namespace, secret.Name)
+		}
+	}
+	_, hasPassword := secret.Data[SFTPPassword]
+	_, hasKey := secret.Data[SFTPPrivateKey]
+	if !hasPassword && !hasKey {
+		return errkit.Wrap(secerrors.ErrValidate, secerrors.MissingRequiredFieldErrorMsg, SFTPPassword+" or "+SFTPPrivateKey, secret.Namespace, secret.Name)
+	}
+	for field := range secret.Data {
+		switch field {
+		case SFTPUsername, SFTPPassword, SFTPPrivateKey, SFTPPrivateKeyPassphrase, SFTPHostKey:
+		default:
+			return errkit.Wrap(secerrors.ErrValidate, secerrors.UnknownFieldErrorMsg, secret.Namespace, secret.Name)
+		}
+	}
+	return nil
+}
+
+// ExtractSFTPCredentials extracts SFTP credential values from the given
+// secret.
+//
+// If the type of the secret is not "secrets.kanister.io/sftp", it returns an error.
+// If the required fields are not available in the secret, it returns an error.
+func ExtractSFTPCredentials(secret *corev1.Secret) (*objectstore.SecretSFTP, error) {
+	if err := ValidateSFTPCredentials(secret); err != nil {
+		return nil, err
+	}
+	return &objectstore.SecretSFTP{
+		Username:   string(secret.Data[SFTPUsername]),
+		Password:   string(secret.Data[SFTPPassword]),
+		PrivateKey: string(secret.Data[SFTPPrivateKey]),
+		Passphrase: string(secret.Data[SFTPPrivateKeyPassphrase]),
+		HostKey:    string(secret.Data[SFTPHostKey]),
+	}, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kanisterio/kanister/pkg/objectstore"
+)
+
+type SFTPSecretSuite struct{}
+
+var _ = check.Suite(&SFTPSecretSuite{})
+
+func (s *SFTPSecretSuite) TestExtractSFTPCredentials(c *check.C) {
+	for i, tc := range []struct {
+		secret     *corev1.Secret
+		expected   *objectstore.SecretSFTP
+		errChecker check.Checker
+	}{
+		{
+			secret: &corev1.Secret{
+				Type: corev1.SecretType(SFTPSecretType),
+				Data: map[string][]byte{
+					SFTPUsername: []byte("user"),
+					SFTPPassword: []byte("password"),
+					SFTPHostKey:  []byte("ssh-ed25519 AAAA"),
+				},
+			},
+			expected: &objectstore.SecretSFTP{
+				Username: "user",
+				Password: "password",
+				HostKey:  "ssh-ed25519 AAAA",
+			},
+			errChecker: check.IsNil,
+		},
+		{
+			secret: &corev1.Secret{
+				Type: corev1.SecretType(SFTPSecretType),
+				Data: map[string][]byte{
+					SFTPUsername:             []byte("user"),
+					SFTPPrivateKey:           []byte("key"),
+					SFTPPrivateKeyPassphrase: []byte("passphrase"),
+					SFTPHostKey:              []byte("ssh-ed25519 AAAA"),
+				},
+			},
+			expected: &objectstore.SecretSFTP{
+				Username:   "user",
+				PrivateKey: "key",
+				Passphrase: "passphrase",
+				HostKey:    "ssh-ed25519 AAAA",
+			},
+			errChecker: check.IsNil,
+		},
+		{ // bad type
+			secret: &corev1.Secret{
+				Type: corev1.SecretType(AzureSecretType),
+				Data: map[string][]byte{
+					SFTPUsername: []byte("user"),
+					SFTPPassword: []byte("password"),
+					SFTPHostKey:  []byte("ssh-ed25519 AAAA"),
+				},
+			},
+			errChecker: check.NotNil,
+		},
+		{ // missing host key
+			secret: &corev1.Secret{
+				Type: corev1.SecretType(SFTPSecretType),
+				Data: map[string][]byte{
+					SFTPUsername: []byte("user"),
+					SFTPPassword: []byte("password"),
+				},
+			},
+			errChecker: check.NotNil,
+		},
+		{ // missing password and key
+			secret: &corev1.Secret{
+				Type: corev1.SecretType(SFTPSecretType),
+				Data: map[string][]byte{
+					SFTPUsername: []byte("user"),
+					SFTPHostKey:  []byte("ssh-ed25519 AAAA"),
+				},
+			},
+			errChecker: check.NotNil,
+		},
+		{ // additional field
+			secret: &corev1.Secret{
+				Type: corev1.SecretType(SFTPSecretType),
+				Data: map[string][]byte{
+					SFTPUsername: []byte("user"),
+					SFTPPassword: []byte("password"),
+					SFTPHostKey:  []byte("ssh-ed25519 AAAA"),
+					"bad field":  []byte("bad"),
+				},
+			},
+			errChecker: check.NotNil,
+		},
+	} {
+		sftpSecret, err := ExtractSFTPCredentials(tc.secret)
+		c.Check(sftpSecret, check.DeepEquals, tc.expected, check.Commentf("test number: %d", i))
+		c.Check(err, tc.errChecker)
+	}
+}
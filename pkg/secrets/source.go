@@ -0,0 +1,149 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kanisterio/errkit"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// CredentialFileRootEnv is the environment variable that sets the directory
+// that file credential sources must be in. File credential sources are
+// disabled if it is not set, so that Profiles cannot read arbitrary files of
+// the controller, like its service account token.
+const CredentialFileRootEnv = "KANISTER_CREDENTIAL_FILE_ROOT"
+
+// Source provides the data of a credential that is stored outside of
+// Kubernetes Secrets.
+type Source interface {
+	// Data returns the key/value data of the credential.
+	Data(ctx context.Context) (map[string][]byte, error)
+}
+
+// LeasedSource is a Source whose data is only valid for a limited time, such
+// as dynamic secrets leased from Vault.
+type LeasedSource interface {
+	Source
+	// LeaseExpiration returns when the data returned by the last call to
+	// Data expires. A zero time means that the data does not expire.
+	LeaseExpiration() time.Time
+}
+
+// NewSource returns the Source for the credential source specified in a
+// Profile. The credential data is only read when Source.Data is called.
+func NewSource(cli kubernetes.Interface, cs *crv1alpha1.CredentialSource) (Source, error) {
+	switch {
+	case cs == nil:
+		return nil, errkit.New("Credential source cannot be nil")
+	case cs.File != nil && cs.Vault != nil:
+		return nil, errkit.New("Only one of file or vault can be set in a credential source")
+	case cs.File != nil:
+		return NewFileSource(os.Getenv(CredentialFileRootEnv), cs.File.Path), nil
+	case cs.Vault != nil:
+		return NewVaultSource(cli, *cs.Vault), nil
+	default:
+		return nil, errkit.New("Credential source must set either file or vault")
+	}
+}
+
+// FileSource reads credentials from a directory, such as a volume mounted by
+// the Secrets Store CSI driver. Every regular file in the directory is a key
+// and its content is the value.
+type FileSource struct {
+	root string
+	path string
+}
+
+var _ Source = (*FileSource)(nil)
+
+// NewFileSource returns a Source that reads the files in the directory path,
+// which must be in the directory root. Relative paths are relative to root.
+func NewFileSource(root, path string) *FileSource {
+	return &FileSource{root: root, path: path}
+}
+
+// Data implements Source.
+func (f *FileSource) Data(ctx context.Context) (map[string][]byte, error) {
+	if f.root == "" {
+		return nil, errkit.New(fmt.Sprintf("File credential sources are disabled, %s is not set", CredentialFileRootEnv))
+	}
+	if f.path == "" {
+		return nil, errkit.New("Path of file credential source is empty")
+	}
+	root, err := filepath.EvalSymlinks(f.root)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to resolve credential file root", "root", f.root)
+	}
+	path := f.path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(f.root, path)
+	}
+	dir, err := resolveInRoot(root, path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to read credential directory", "path", f.path)
+	}
+	data := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		// Projected volumes store the files in hidden, timestamped
+		// directories and link them into the volume's root.
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		p, err := resolveInRoot(root, filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to stat credential file", "path", p)
+		}
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+		v, err := os.ReadFile(p)
+		if err != nil {
+			return nil, errkit.Wrap(err, "Failed to read credential file", "path", p)
+		}
+		data[e.Name()] = v
+	}
+	return data, nil
+}
+
+// resolveInRoot returns path with its symbolic links resolved, or an error if
+// it is not in the directory root, which must be resolved already.
+func resolveInRoot(root, path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to resolve credential path", "path", path)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errkit.New(fmt.Sprintf("Credential path %s is not in %s", path, root))
+	}
+	return resolved, nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type SourceSuite struct{}
+
+var _ = check.Suite(&SourceSuite{})
+
+func (s *SourceSuite) TestNewSource(c *check.C) {
+	for _, tc := range []struct {
+		cs      *crv1alpha1.CredentialSource
+		checker check.Checker
+	}{
+		{
+			cs:      nil,
+			checker: check.NotNil,
+		},
+		{
+			cs:      &crv1alpha1.CredentialSource{},
+			checker: check.NotNil,
+		},
+		{
+			cs: &crv1alpha1.CredentialSource{
+				File:  &crv1alpha1.FileCredentialSource{Path: "/creds"},
+				Vault: &crv1alpha1.VaultCredentialSource{},
+			},
+			checker: check.NotNil,
+		},
+		{
+			cs:      &crv1alpha1.CredentialSource{File: &crv1alpha1.FileCredentialSource{Path: "/creds"}},
+			checker: check.IsNil,
+		},
+		{
+			cs:      &crv1alpha1.CredentialSource{Vault: &crv1alpha1.VaultCredentialSource{}},
+			checker: check.IsNil,
+		},
+	} {
+		_, err := NewSource(fake.NewSimpleClientset(), tc.cs)
+		c.Check(err, tc.checker)
+	}
+}
+
+func (s *SourceSuite) TestFileSource(c *check.C) {
+	dir := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(dir, AWSAccessKeyID), []byte("id"), 0600), check.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(dir, AWSSecretAccessKey), []byte("secret"), 0600), check.IsNil)
+	// Hidden directories and files of projected volumes are skipped
+	c.Assert(os.Mkdir(filepath.Join(dir, "..data"), 0700), check.IsNil)
+	c.Assert(os.Mkdir(filepath.Join(dir, "subdir"), 0700), check.IsNil)
+
+	data, err := NewFileSource(dir, dir).Data(context.Background())
+	c.Assert(err, check.IsNil)
+	c.Assert(data, check.DeepEquals, map[string][]byte{
+		AWSAccessKeyID:     []byte("id"),
+		AWSSecretAccessKey: []byte("secret"),
+	})
+
+	_, err = NewFileSource(dir, filepath.Join(dir, "missing")).Data(context.Background())
+	c.Assert(err, check.NotNil)
+	_, err = NewFileSource(dir, "").Data(context.Background())
+	c.Assert(err, check.NotNil)
+	// File sources are disabled without a root
+	_, err = NewFileSource("", dir).Data(context.Background())
+	c.Assert(err, check.NotNil)
+}
+
+func (s *SourceSuite) TestFileSourceRoot(c *check.C) {
+	root := c.MkDir()
+	outside := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(outside, "token"), []byte("token"), 0600), check.IsNil)
+	creds := filepath.Join(root, "creds")
+	c.Assert(os.Mkdir(creds, 0700), check.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(creds, AWSAccessKeyID), []byte("id"), 0600), check.IsNil)
+	c.Assert(os.Symlink(outside, filepath.Join(root, "escape")), check.IsNil)
+	linked := filepath.Join(root, "linked")
+	c.Assert(os.Mkdir(linked, 0700), check.IsNil)
+	c.Assert(os.Symlink(filepath.Join(outside, "token"), filepath.Join(linked, "token")), check.IsNil)
+
+	for _, tc := range []struct {
+		path    string
+		checker check.Checker
+	}{
+		{path: creds, checker: check.IsNil},
+		{path: "creds", checker: check.IsNil},
+		{path: outside, checker: check.NotNil},
+		{path: "../" + filepath.Base(outside), checker: check.NotNil},
+		{path: filepath.Join(creds, "..", ".."), checker: check.NotNil},
+		// Symbolic links are resolved before the path is checked
+		{path: filepath.Join(root, "escape"), checker: check.NotNil},
+		{path: linked, checker: check.NotNil},
+	} {
+		_, err := NewFileSource(root, tc.path).Data(context.Background())
+		c.Check(err, tc.checker, check.Commentf("path: %s", tc.path))
+	}
+}
+
+func (s *SourceSuite) TestVaultSource(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(vaultTokenHeader) != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/kanister/s3":
+			if r.Header.Get(vaultNamespaceHeader) != "team" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"data":{"aws_access_key_id":"id","aws_secret_access_key":"secret"},"metadata":{"version":3}}}`))
+		case "/v1/kv/kanister/s3":
+			_, _ = w.Write([]byte(`{"lease_duration":3600,"data":{"aws_access_key_id":"id","retries":3}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "kanister"},
+		Data:       map[string][]byte{VaultTokenKey: []byte("vault-token\n")},
+	}
+	badTokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bad", Namespace: "kanister"},
+		Data:       map[string][]byte{VaultTokenKey: []byte("other")},
+	}
+	cli := fake.NewSimpleClientset(tokenSecret, badTokenSecret)
+	ref := crv1alpha1.ObjectReference{Name: "vault", Namespace: "kanister"}
+
+	for _, tc := range []struct {
+		cfg     crv1alpha1.VaultCredentialSource
+		data    map[string][]byte
+		leased  bool
+		checker check.Checker
+	}{
+		{
+			cfg:  crv1alpha1.VaultCredentialSource{Address: srv.URL, Namespace: "team", Path: "/kanister/s3", TokenSecret: ref},
+			data: map[string][]byte{AWSAccessKeyID: []byte("id"), AWSSecretAccessKey: []byte("secret")},
+		},
+		{
+			cfg:    crv1alpha1.VaultCredentialSource{Address: srv.URL, Mount: "kv", KVVersion: 1, Path: "kanister/s3", TokenSecret: ref},
+			data:   map[string][]byte{AWSAccessKeyID: []byte("id"), "retries": []byte("3")},
+			leased: true,
+		},
+		{
+			// Missing secret
+			cfg:     crv1alpha1.VaultCredentialSource{Address: srv.URL, Namespace: "team", Path: "kanister/gcs", TokenSecret: ref},
+			checker: check.NotNil,
+		},
+		{
+			// Invalid token
+			cfg:     crv1alpha1.VaultCredentialSource{Address: srv.URL, Namespace: "team", Path: "kanister/s3", TokenSecret: crv1alpha1.ObjectReference{Name: "bad", Namespace: "kanister"}},
+			checker: check.NotNil,
+		},
+		{
+			// Missing token secret
+			cfg:     crv1alpha1.VaultCredentialSource{Address: srv.URL, Path: "kanister/s3", TokenSecret: crv1alpha1.ObjectReference{Name: "missing", Namespace: "kanister"}},
+			checker: check.NotNil,
+		},
+		{
+			cfg:     crv1alpha1.VaultCredentialSource{Address: srv.URL, KVVersion: 3, Path: "kanister/s3", TokenSecret: ref},
+			checker: check.NotNil,
+		},
+		{
+			cfg:     crv1alpha1.VaultCredentialSource{Path: "kanister/s3", TokenSecret: ref},
+			checker: check.NotNil,
+		},
+	} {
+		src := NewVaultSource(cli, tc.cfg)
+		data, err := src.Data(context.Background())
+		if tc.checker != nil {
+			c.Assert(err, tc.checker)
+			continue
+		}
+		c.Assert(err, check.IsNil)
+		c.Assert(data, check.DeepEquals, tc.data)
+		exp := src.LeaseExpiration()
+		c.Assert(exp.IsZero(), check.Equals, !tc.leased)
+		if tc.leased {
+			c.Assert(time.Until(exp) > 59*time.Minute, check.Equals, true)
+		}
+	}
+}
+
+func (s *SourceSuite) TestVaultSourceCABundle(c *check.C) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"data":{"aws_access_key_id":"id"}}}`))
+	}))
+	defer srv.Close()
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+	cli := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "kanister"},
+		Data:       map[string][]byte{VaultTokenKey: []byte("vault-token")},
+	})
+	cfg := crv1alpha1.VaultCredentialSource{
+		Address:     srv.URL,
+		Path:        "kanister/s3",
+		TokenSecret: crv1alpha1.ObjectReference{Name: "vault", Namespace: "kanister"},
+	}
+
+	// The certificate of the server is not in the system trust store
+	_, err := NewVaultSource(cli, cfg).Data(context.Background())
+	c.Assert(err, check.NotNil)
+
+	cfg.CABundle = caBundle
+	data, err := NewVaultSource(cli, cfg).Data(context.Background())
+	c.Assert(err, check.IsNil)
+	c.Assert(data, check.DeepEquals, map[string][]byte{AWSAccessKeyID: []byte("id")})
+
+	cfg.CABundle = "not a certificate"
+	_, err = NewVaultSource(cli, cfg).Data(context.Background())
+	c.Assert(err, check.NotNil)
+}
@@ -0,0 +1,245 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kanisterio/errkit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+const (
+	// VaultTokenKey is the key of the Vault token in the Secret referenced by
+	// a Vault credential source.
+	VaultTokenKey = "token"
+
+	vaultDefaultMount     = "secret"
+	vaultDefaultKVVersion = 2
+	vaultTokenHeader      = "X-Vault-Token"
+	vaultNamespaceHeader  = "X-Vault-Namespace"
+	vaultRequestTimeout   = 30 * time.Second
+	// vaultMaxResponseSize limits the size of responses read from Vault.
+	vaultMaxResponseSize = 1 << 20
+)
+
+// VaultSource reads credentials from a secret of a HashiCorp Vault KV secrets
+// engine using the Vault HTTP API.
+type VaultSource struct {
+	cli        kubernetes.Interface
+	cfg        crv1alpha1.VaultCredentialSource
+	httpClient *http.Client
+	// clientErr is the error of creating httpClient, like an invalid CA
+	// bundle.
+	clientErr error
+
+	mu              sync.Mutex
+	leaseExpiration time.Time
+}
+
+var _ LeasedSource = (*VaultSource)(nil)
+
+// NewVaultSource returns a Source that reads the Vault secret. The Vault token
+// is read from the Kubernetes Secret referenced by the credential source.
+func NewVaultSource(cli kubernetes.Interface, cfg crv1alpha1.VaultCredentialSource) *VaultSource {
+	v := &VaultSource{
+		cli:        cli,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: vaultRequestTimeout},
+	}
+	if cfg.CABundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CABundle)) {
+			v.clientErr = errkit.New("Vault CA bundle has no valid PEM certificates")
+			return v
+		}
+		v.httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		}
+	}
+	return v
+}
+
+// Data implements Source.
+func (v *VaultSource) Data(ctx context.Context) (map[string][]byte, error) {
+	if v.clientErr != nil {
+		return nil, v.clientErr
+	}
+	u, err := v.secretURL()
+	if err != nil {
+		return nil, err
+	}
+	token, err := v.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create Vault request")
+	}
+	req.Header.Set(vaultTokenHeader, token)
+	if v.cfg.Namespace != "" {
+		req.Header.Set(vaultNamespaceHeader, v.cfg.Namespace)
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to read secret from Vault", "path", v.cfg.Path)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	body, err := io.ReadAll(io.LimitReader(resp.Body, vaultMaxResponseSize))
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to read Vault response", "path", v.cfg.Path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, vaultError(resp.StatusCode, body, v.cfg.Path)
+	}
+	data, lease, err := v.parseSecret(body)
+	if err != nil {
+		return nil, err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.leaseExpiration = time.Time{}
+	if lease > 0 {
+		v.leaseExpiration = time.Now().Add(lease)
+	}
+	return data, nil
+}
+
+// LeaseExpiration implements LeasedSource. Secrets of the KV secrets engine
+// are leased if a TTL is set.
+func (v *VaultSource) LeaseExpiration() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.leaseExpiration
+}
+
+func (v *VaultSource) secretURL() (string, error) {
+	if v.cfg.Address == "" {
+		return "", errkit.New("Vault address is empty")
+	}
+	if v.cfg.Path == "" {
+		return "", errkit.New("Vault secret path is empty")
+	}
+	base, err := url.Parse(v.cfg.Address)
+	if err != nil {
+		return "", errkit.Wrap(err, "Invalid Vault address", "address", v.cfg.Address)
+	}
+	mount := strings.Trim(v.cfg.Mount, "/")
+	if mount == "" {
+		mount = vaultDefaultMount
+	}
+	path := strings.Trim(v.cfg.Path, "/")
+	switch v.kvVersion() {
+	case 1:
+		base.Path = fmt.Sprintf("/v1/%s/%s", mount, path)
+	case 2:
+		base.Path = fmt.Sprintf("/v1/%s/data/%s", mount, path)
+	default:
+		return "", errkit.New(fmt.Sprintf("Unsupported Vault KV version %d", v.cfg.KVVersion))
+	}
+	return base.String(), nil
+}
+
+func (v *VaultSource) kvVersion() int {
+	if v.cfg.KVVersion == 0 {
+		return vaultDefaultKVVersion
+	}
+	return v.cfg.KVVersion
+}
+
+func (v *VaultSource) token(ctx context.Context) (string, error) {
+	ref := v.cfg.TokenSecret
+	if ref.Name == "" {
+		return "", errkit.New("Vault token secret not specified")
+	}
+	s, err := v.cli.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to fetch the Vault token secret")
+	}
+	t, ok := s.Data[VaultTokenKey]
+	if !ok || len(t) == 0 {
+		return "", errkit.New(fmt.Sprintf("Key '%s' not found in secret '%s:%s'", VaultTokenKey, s.GetNamespace(), s.GetName()))
+	}
+	return strings.TrimSpace(string(t)), nil
+}
+
+// parseSecret returns the key/value data of a KV secret and the duration of
+// its lease. Values that are not strings are returned as JSON.
+func (v *VaultSource) parseSecret(body []byte) (map[string][]byte, time.Duration, error) {
+	var resp struct {
+		LeaseDuration int64           `json:"lease_duration"`
+		Data          json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, 0, errkit.Wrap(err, "Failed to parse Vault response")
+	}
+	raw := resp.Data
+	if v.kvVersion() == 2 {
+		// KV version 2 nests the secret data with its metadata
+		var v2 struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &v2); err != nil {
+			return nil, 0, errkit.Wrap(err, "Failed to parse Vault response")
+		}
+		raw = v2.Data
+	}
+	var kv map[string]interface{}
+	if err := json.Unmarshal(raw, &kv); err != nil {
+		return nil, 0, errkit.Wrap(err, "Failed to parse Vault secret data")
+	}
+	if kv == nil {
+		return nil, 0, errkit.New(fmt.Sprintf("Vault secret '%s' has no data", v.cfg.Path))
+	}
+	data := make(map[string][]byte, len(kv))
+	for k, val := range kv {
+		if s, ok := val.(string); ok {
+			data[k] = []byte(s)
+			continue
+		}
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, 0, errkit.Wrap(err, "Failed to marshal Vault secret value", "key", k)
+		}
+		data[k] = b
+	}
+	return data, time.Duration(resp.LeaseDuration) * time.Second, nil
+}
+
+func vaultError(status int, body []byte, path string) error {
+	var resp struct {
+		Errors []string `json:"errors"`
+	}
+	msg := http.StatusText(status)
+	if err := json.Unmarshal(body, &resp); err == nil && len(resp.Errors) > 0 {
+		msg = strings.Join(resp.Errors, "; ")
+	}
+	return errkit.New(fmt.Sprintf("Failed to read secret '%s' from Vault: %d %s", path, status, msg))
+}
@@ -0,0 +1,426 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/kanisterio/errkit"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/aws"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+)
+
+const (
+	// WorkloadIdentityAWSAudience is the default audience of tokens exchanged
+	// with AWS STS.
+	WorkloadIdentityAWSAudience = "sts.amazonaws.com"
+	// WorkloadIdentityAzureAudience is the default audience of tokens exchanged
+	// with Azure AD.
+	WorkloadIdentityAzureAudience = "api://AzureADTokenExchange"
+
+	// WorkloadIdentityTokenExpiration is the requested lifetime of ServiceAccount
+	// tokens.
+	WorkloadIdentityTokenExpiration = time.Hour
+
+	gcpSTSTokenURL            = "https://sts.googleapis.com/v1/token"
+	gcpImpersonationURLFormat = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken"
+	jwtTokenType              = "urn:ietf:params:oauth:token-type:jwt"
+	azureStorageScope         = "https://storage.azure.com/.default"
+)
+
+// TokenSource provides ServiceAccount tokens that are exchanged for cloud
+// provider credentials.
+type TokenSource interface {
+	// Token returns a valid token.
+	Token(ctx context.Context) (string, error)
+}
+
+// serviceAccountTokenSource requests tokens using the TokenRequest API. Tokens
+// are reused until 80% of their lifetime has passed.
+type serviceAccountTokenSource struct {
+	cli       kubernetes.Interface
+	namespace string
+	name      string
+	audience  string
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+	now       func() time.Time
+}
+
+var _ TokenSource = (*serviceAccountTokenSource)(nil)
+
+// NewServiceAccountTokenSource returns a TokenSource that requests tokens of
+// the ServiceAccount for the given audience.
+func NewServiceAccountTokenSource(cli kubernetes.Interface, namespace, name, audience string) TokenSource {
+	return &serviceAccountTokenSource{
+		cli:       cli,
+		namespace: namespace,
+		name:      name,
+		audience:  audience,
+		now:       time.Now,
+	}
+}
+
+// Token implements TokenSource.
+func (s *serviceAccountTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.token != "" && now.Before(s.refreshAt) {
+		return s.token, nil
+	}
+	expiration := int64(WorkloadIdentityTokenExpiration.Seconds())
+	tr := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{s.audience},
+			ExpirationSeconds: &expiration,
+		},
+	}
+	tr, err := s.cli.CoreV1().ServiceAccounts(s.namespace).CreateToken(ctx, s.name, tr, metav1.CreateOptions{})
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to request token for service account", "namespace", s.namespace, "name", s.name)
+	}
+	if tr.Status.Token == "" {
+		return "", errkit.New(fmt.Sprintf("Empty token returned for service account '%s:%s'", s.namespace, s.name))
+	}
+	lifetime := tr.Status.ExpirationTimestamp.Sub(now)
+	s.token = tr.Status.Token
+	s.refreshAt = now.Add(lifetime * 4 / 5)
+	return s.token, nil
+}
+
+// fileTokenSource reads tokens from a file that is kept up to date by someone
+// else, such as a projected volume or the controller.
+type fileTokenSource struct {
+	path string
+}
+
+var _ TokenSource = (*fileTokenSource)(nil)
+
+// NewFileTokenSource returns a TokenSource that reads the token from a file.
+func NewFileTokenSource(path string) TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+// Token implements TokenSource.
+func (f *fileTokenSource) Token(ctx context.Context) (string, error) {
+	t, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to read token file", "path", f.path)
+	}
+	return strings.TrimSpace(string(t)), nil
+}
+
+// WorkloadIdentityAudience returns the audience of the ServiceAccount tokens
+// for a location type.
+func WorkloadIdentityAudience(wi *crv1alpha1.WorkloadIdentity, lt crv1alpha1.LocationType) string {
+	if wi.Audience != "" {
+		return wi.Audience
+	}
+	switch lt {
+	case crv1alpha1.LocationTypeS3Compliant:
+		return WorkloadIdentityAWSAudience
+	case crv1alpha1.LocationTypeAzure:
+		return WorkloadIdentityAzureAudience
+	default:
+		return ""
+	}
+}
+
+// ValidateWorkloadIdentity checks that the fields required for the location
+// type are set and that the ServiceAccount is in `namespace`, the namespace
+// of the Profile, so that Profiles cannot use the identity of ServiceAccounts
+// in other namespaces.
+func ValidateWorkloadIdentity(wi *crv1alpha1.WorkloadIdentity, lt crv1alpha1.LocationType, namespace string) error {
+	if wi == nil {
+		return errkit.New("Workload identity cannot be nil")
+	}
+	if wi.ServiceAccount.Name == "" || wi.ServiceAccount.Namespace == "" {
+		return errkit.New("Service account of workload identity not specified")
+	}
+	if wi.ServiceAccount.Namespace != namespace {
+		return errkit.New(fmt.Sprintf("Service account of workload identity must be in the namespace of the profile '%s'", namespace))
+	}
+	switch lt {
+	case crv1alpha1.LocationTypeS3Compliant:
+		if wi.RoleARN == "" {
+			return errkit.New("Role ARN is required for workload identity with S3 compliant locations")
+		}
+	case crv1alpha1.LocationTypeAzure:
+		if wi.TenantID == "" || wi.ClientID == "" || wi.StorageAccount == "" {
+			return errkit.New("Tenant ID, client ID and storage account are required for workload identity with Azure locations")
+		}
+	case crv1alpha1.LocationTypeGCS:
+		if wi.Audience == "" || wi.ProjectID == "" {
+			return errkit.New("Audience and project ID are required for workload identity with GCS locations")
+		}
+	default:
+		return errkit.New(fmt.Sprintf("Workload identity is not supported for location type '%s'", lt))
+	}
+	return nil
+}
+
+// WorkloadIdentityAWSCredentials returns AWS credentials of the role of the
+// workload identity. Whenever they expire, a new ServiceAccount token is
+// requested from ts and exchanged for new credentials.
+func WorkloadIdentityAWSCredentials(wi *crv1alpha1.WorkloadIdentity, ts TokenSource) (*credentials.Credentials, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, errkit.Wrap(err, "Failed to create session to initialize workload identity credentials")
+	}
+	return workloadIdentityAWSCredentials(sts.New(sess), wi, ts), nil
+}
+
+func workloadIdentityAWSCredentials(svc stsiface.STSAPI, wi *crv1alpha1.WorkloadIdentity, ts TokenSource) *credentials.Credentials {
+	p := stscreds.NewWebIdentityRoleProviderWithOptions(svc, wi.RoleARN, "", tokenFetcher{ts: ts})
+	p.Duration = aws.AssumeRoleDurationDefault
+	return credentials.NewCredentials(p)
+}
+
+var _ stscreds.TokenFetcher = tokenFetcher{}
+
+// tokenFetcher implements stscreds.TokenFetcher with a TokenSource.
+type tokenFetcher struct {
+	ts TokenSource
+}
+
+// FetchToken implements stscreds.TokenFetcher.
+func (t tokenFetcher) FetchToken(ctx credentials.Context) ([]byte, error) {
+	token, err := t.ts.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(token), nil
+}
+
+// WorkloadIdentityGCPCredentialsJSON returns a workload identity federation
+// credential configuration, as used with GOOGLE_APPLICATION_CREDENTIALS, that
+// reads the ServiceAccount token from tokenFile. The Google client libraries
+// re-read the file whenever they exchange the token.
+func WorkloadIdentityGCPCredentialsJSON(wi *crv1alpha1.WorkloadIdentity, tokenFile string) (string, error) {
+	cfg := map[string]interface{}{
+		"type":               "external_account",
+		"audience":           wi.Audience,
+		"subject_token_type": jwtTokenType,
+		"token_url":          gcpSTSTokenURL,
+		"credential_source": map[string]interface{}{
+			"file": tokenFile,
+		},
+	}
+	if wi.ServiceAccountEmail != "" {
+		cfg["service_account_impersonation_url"] = fmt.Sprintf(gcpImpersonationURLFormat, wi.ServiceAccountEmail)
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return "", errkit.Wrap(err, "Failed to marshal GCP credential configuration")
+	}
+	return string(b), nil
+}
+
+// WorkloadIdentitySecret returns object store credentials for a workload
+// identity and the time they expire. ServiceAccount tokens are requested from
+// ts or, if ts is nil, read from consts.WorkloadIdentityTokenFilePath as done
+// in data mover pods.
+func WorkloadIdentitySecret(ctx context.Context, pType objectstore.ProviderType, wi *crv1alpha1.WorkloadIdentity, ts TokenSource) (*objectstore.Secret, time.Time, error) {
+	tokenFile := consts.WorkloadIdentityTokenFilePath
+	if ts == nil {
+		ts = NewFileTokenSource(tokenFile)
+	}
+	switch pType {
+	case objectstore.ProviderTypeS3:
+		creds, err := WorkloadIdentityAWSCredentials(wi, ts)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		val, err := creds.GetWithContext(ctx)
+		if err != nil {
+			return nil, time.Time{}, errkit.Wrap(err, "Failed to get AWS credentials for workload identity")
+		}
+		exp, err := creds.ExpiresAt()
+		if err != nil {
+			return nil, time.Time{}, errkit.Wrap(err, "Failed to get expiration of AWS credentials for workload identity")
+		}
+		return &objectstore.Secret{
+			Type: objectstore.SecretTypeAwsAccessKey,
+			Aws: &objectstore.SecretAws{
+				AccessKeyID:     val.AccessKeyID,
+				SecretAccessKey: val.SecretAccessKey,
+				SessionToken:    val.SessionToken,
+			},
+		}, exp, nil
+	case objectstore.ProviderTypeGCS:
+		if _, ok := ts.(*fileTokenSource); !ok {
+			var err error
+			if tokenFile, err = tokenFiles.write(ctx, tokenFileName(wi), ts); err != nil {
+				return nil, time.Time{}, err
+			}
+		}
+		cfg, err := WorkloadIdentityGCPCredentialsJSON(wi, tokenFile)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		// The configuration does not expire since the token file is kept up
+		// to date
+		return &objectstore.Secret{
+			Type: objectstore.SecretTypeGcpServiceAccountKey,
+			Gcp: &objectstore.SecretGcp{
+				ProjectID:  wi.ProjectID,
+				ServiceKey: cfg,
+			},
+		}, time.Time{}, nil
+	case objectstore.ProviderTypeAzure:
+		return workloadIdentityAzureSecret(ctx, wi, ts, nil)
+	default:
+		return nil, time.Time{}, errkit.New(fmt.Sprintf("Workload identity is not supported for provider type '%s'", pType))
+	}
+}
+
+// workloadIdentityAzureSecret exchanges a ServiceAccount token for an Azure
+// AD access token of the storage account, using the token as the client
+// assertion of the application or managed identity with the federated
+// credential.
+func workloadIdentityAzureSecret(ctx context.Context, wi *crv1alpha1.WorkloadIdentity, ts TokenSource, opts *azidentity.ClientAssertionCredentialOptions) (*objectstore.Secret, time.Time, error) {
+	cred, err := azidentity.NewClientAssertionCredential(wi.TenantID, wi.ClientID, ts.Token, opts)
+	if err != nil {
+		return nil, time.Time{}, errkit.Wrap(err, "Failed to create Azure credential for workload identity")
+	}
+	tok, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureStorageScope}})
+	if err != nil {
+		return nil, time.Time{}, errkit.Wrap(err, "Failed to get Azure access token for workload identity")
+	}
+	return &objectstore.Secret{
+		Type: objectstore.SecretTypeAzStorageAccount,
+		Azure: &objectstore.SecretAzure{
+			StorageAccount:       wi.StorageAccount,
+			AccessToken:          tok.Token,
+			AccessTokenExpiresOn: tok.ExpiresOn,
+		},
+	}, tok.ExpiresOn, nil
+}
+
+// tokenFileRefreshInterval is how often token files are rewritten. Tokens
+// are requested long before they expire, so files always contain a token
+// that is valid for longer than the interval.
+const tokenFileRefreshInterval = 5 * time.Minute
+
+// tokenFiles are the token files referenced by the GCP credential
+// configurations of workload identities.
+var tokenFiles = &tokenFileWriter{
+	dir:      filepath.Join(os.TempDir(), "kanister-workload-identity"),
+	interval: tokenFileRefreshInterval,
+	files:    map[string]struct{}{},
+}
+
+// tokenFileName returns the name of the token file of a workload identity.
+// Tokens of the same ServiceAccount for different audiences are written to
+// different files.
+func tokenFileName(wi *crv1alpha1.WorkloadIdentity) string {
+	sum := sha256.Sum256([]byte(wi.Audience))
+	return fmt.Sprintf("%s-%s-%s", wi.ServiceAccount.Namespace, wi.ServiceAccount.Name, hex.EncodeToString(sum[:8]))
+}
+
+// tokenFileWriter writes tokens to files that can be referenced by GCP
+// credential configurations. The Google client libraries read the file
+// whenever they exchange the token, which happens long after the
+// configuration is created for clients that are used for a long time, so
+// every file is rewritten with a fresh token until the process exits.
+type tokenFileWriter struct {
+	dir      string
+	interval time.Duration
+
+	mu    sync.Mutex
+	files map[string]struct{}
+}
+
+// write writes a token from ts to the named file and starts refreshing the
+// file, unless it is already refreshed.
+func (w *tokenFileWriter) write(ctx context.Context, name string, ts TokenSource) (string, error) {
+	path := filepath.Join(w.dir, name)
+	if err := w.writeToken(ctx, path, ts); err != nil {
+		return "", err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.files[path]; !ok {
+		w.files[path] = struct{}{}
+		go w.refresh(path, ts)
+	}
+	return path, nil
+}
+
+func (w *tokenFileWriter) refresh(path string, ts TokenSource) {
+	ctx := context.Background()
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for range t.C {
+		if err := w.writeToken(ctx, path, ts); err != nil {
+			log.Error().WithError(err).Print("Failed to refresh workload identity token file", field.M{"path": path})
+		}
+	}
+}
+
+// writeToken replaces the file with a token from ts. The file is renamed so
+// that readers never see a partially written token.
+func (w *tokenFileWriter) writeToken(ctx context.Context, path string, ts TokenSource) error {
+	token, err := ts.Token(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(w.dir, 0700); err != nil {
+		return errkit.Wrap(err, "Failed to create token directory", "path", w.dir)
+	}
+	f, err := os.CreateTemp(w.dir, filepath.Base(path)+".*")
+	if err != nil {
+		return errkit.Wrap(err, "Failed to create token file", "path", path)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+	if _, err := f.WriteString(token); err != nil {
+		f.Close() //nolint:errcheck
+		return errkit.Wrap(err, "Failed to write token file", "path", path)
+	}
+	if err := f.Close(); err != nil {
+		return errkit.Wrap(err, "Failed to write token file", "path", path)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return errkit.Wrap(err, "Failed to write token file", "path", path)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"golang.org/x/oauth2/google"
+	"gopkg.in/check.v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/objectstore"
+)
+
+type WorkloadIdentitySuite struct{}
+
+var _ = check.Suite(&WorkloadIdentitySuite{})
+
+func (s *WorkloadIdentitySuite) TestServiceAccountTokenSource(c *check.C) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := 0
+	cli := fake.NewSimpleClientset()
+	cli.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ca := action.(k8stesting.CreateAction)
+		c.Assert(ca.GetSubresource(), check.Equals, "token")
+		tr := ca.GetObject().(*authenticationv1.TokenRequest)
+		c.Assert(tr.Spec.Audiences, check.DeepEquals, []string{WorkloadIdentityAWSAudience})
+		requests++
+		return true, &authenticationv1.TokenRequest{
+			Status: authenticationv1.TokenRequestStatus{
+				Token:               fmt.Sprintf("token-%d", requests),
+				ExpirationTimestamp: metav1.NewTime(now.Add(time.Hour)),
+			},
+		}, nil
+	})
+	ts := NewServiceAccountTokenSource(cli, "kanister", "backup", WorkloadIdentityAWSAudience).(*serviceAccountTokenSource)
+	ts.now = func() time.Time { return now }
+
+	for _, tc := range []struct {
+		elapsed time.Duration
+		token   string
+	}{
+		{elapsed: 0, token: "token-1"},
+		{elapsed: 30 * time.Minute, token: "token-1"},
+		// Tokens are refreshed after 80% of their lifetime
+		{elapsed: 50 * time.Minute, token: "token-2"},
+	} {
+		ts.now = func() time.Time { return now.Add(tc.elapsed) }
+		token, err := ts.Token(context.Background())
+		c.Assert(err, check.IsNil)
+		c.Assert(token, check.Equals, tc.token)
+	}
+	c.Assert(requests, check.Equals, 2)
+}
+
+func (s *WorkloadIdentitySuite) TestFileTokenSource(c *check.C) {
+	path := filepath.Join(c.MkDir(), "token")
+	c.Assert(os.WriteFile(path, []byte("token\n"), 0600), check.IsNil)
+	token, err := NewFileTokenSource(path).Token(context.Background())
+	c.Assert(err, check.IsNil)
+	c.Assert(token, check.Equals, "token")
+}
+
+func (s *WorkloadIdentitySuite) TestValidateWorkloadIdentity(c *check.C) {
+	sa := crv1alpha1.ObjectReference{Name: "backup", Namespace: "kanister"}
+	for _, tc := range []struct {
+		wi      *crv1alpha1.WorkloadIdentity
+		lt      crv1alpha1.LocationType
+		checker check.Checker
+	}{
+		{wi: nil, lt: crv1alpha1.LocationTypeS3Compliant, checker: check.NotNil},
+		{wi: &crv1alpha1.WorkloadIdentity{RoleARN: "arn"}, lt: crv1alpha1.LocationTypeS3Compliant, checker: check.NotNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: sa}, lt: crv1alpha1.LocationTypeS3Compliant, checker: check.NotNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: sa, RoleARN: "arn"}, lt: crv1alpha1.LocationTypeS3Compliant, checker: check.IsNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: crv1alpha1.ObjectReference{Name: "backup", Namespace: "default"}, RoleARN: "arn"}, lt: crv1alpha1.LocationTypeS3Compliant, checker: check.NotNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: sa, TenantID: "t", ClientID: "c"}, lt: crv1alpha1.LocationTypeAzure, checker: check.NotNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: sa, TenantID: "t", ClientID: "c", StorageAccount: "sa"}, lt: crv1alpha1.LocationTypeAzure, checker: check.IsNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: sa, ProjectID: "p"}, lt: crv1alpha1.LocationTypeGCS, checker: check.NotNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: sa, ProjectID: "p", Audience: "aud"}, lt: crv1alpha1.LocationTypeGCS, checker: check.IsNil},
+		{wi: &crv1alpha1.WorkloadIdentity{ServiceAccount: sa}, lt: crv1alpha1.LocationTypeKopia, checker: check.NotNil},
+	} {
+		c.Check(ValidateWorkloadIdentity(tc.wi, tc.lt, "kanister"), tc.checker)
+	}
+}
+
+func (s *WorkloadIdentitySuite) TestWorkloadIdentityGCPCredentials(c *check.C) {
+	wi := &crv1alpha1.WorkloadIdentity{
+		ServiceAccount:      crv1alpha1.ObjectReference{Name: "backup", Namespace: "kanister"},
+		Audience:            "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/k8s",
+		ServiceAccountEmail: "backup@project.iam.gserviceaccount.com",
+		ProjectID:           "project",
+	}
+	sec, exp, err := WorkloadIdentitySecret(context.Background(), objectstore.ProviderTypeGCS, wi, staticTokenSource("token"))
+	c.Assert(err, check.IsNil)
+	c.Assert(exp.IsZero(), check.Equals, true)
+	c.Assert(sec.Type, check.Equals, objectstore.SecretTypeGcpServiceAccountKey)
+	c.Assert(sec.Gcp.ProjectID, check.Equals, "project")
+
+	cfg := map[string]interface{}{}
+	c.Assert(json.Unmarshal([]byte(sec.Gcp.ServiceKey), &cfg), check.IsNil)
+	c.Assert(cfg["type"], check.Equals, "external_account")
+	c.Assert(cfg["service_account_impersonation_url"], check.Matches, ".*backup@project.iam.gserviceaccount.com:generateAccessToken")
+	tokenFile := cfg["credential_source"].(map[string]interface{})["file"].(string)
+	token, err := os.ReadFile(tokenFile)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(token), check.Equals, "token")
+
+	// The configuration is understood by the Google client libraries
+	_, err = google.CredentialsFromJSON(context.Background(), []byte(sec.Gcp.ServiceKey), "https://www.googleapis.com/auth/devstorage.read_write")
+	c.Assert(err, check.IsNil)
+
+	_, _, err = WorkloadIdentitySecret(context.Background(), objectstore.ProviderTypeSFTP, wi, staticTokenSource("token"))
+	c.Assert(err, check.NotNil)
+}
+
+func (s *WorkloadIdentitySuite) TestTokenFileRefresh(c *check.C) {
+	w := &tokenFileWriter{
+		dir:      c.MkDir(),
+		interval: 10 * time.Millisecond,
+		files:    map[string]struct{}{},
+	}
+	ts := &countingTokenSource{}
+	path, err := w.write(context.Background(), "kanister-backup", ts)
+	c.Assert(err, check.IsNil)
+	token, err := os.ReadFile(path)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(token), check.Equals, "token-1")
+
+	// The file is rewritten with new tokens in the background
+	for i := 0; i < 100 && string(token) == "token-1"; i++ {
+		time.Sleep(10 * time.Millisecond)
+		token, err = os.ReadFile(path)
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(string(token), check.Not(check.Equals), "token-1")
+	entries, err := os.ReadDir(w.dir)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+}
+
+func (s *WorkloadIdentitySuite) TestWorkloadIdentityAzureSecret(c *check.C) {
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tenant/v2.0/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"token_endpoint": "%[1]s/tenant/oauth2/v2.0/token", "authorization_endpoint": "%[1]s/tenant/oauth2/v2.0/authorize", "issuer": "%[1]s/tenant/v2.0"}`, srv.URL)
+		case "/tenant/oauth2/v2.0/token":
+			c.Check(r.ParseForm(), check.IsNil)
+			c.Check(r.Form.Get("client_id"), check.Equals, "client")
+			c.Check(r.Form.Get("client_assertion"), check.Equals, "token")
+			c.Check(r.Form.Get("scope"), check.Matches, ".*https://storage.azure.com/.default.*")
+			fmt.Fprint(w, `{"token_type": "Bearer", "expires_in": 3600, "access_token": "access-token"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	wi := &crv1alpha1.WorkloadIdentity{
+		ServiceAccount: crv1alpha1.ObjectReference{Name: "backup", Namespace: "kanister"},
+		TenantID:       "tenant",
+		ClientID:       "client",
+		StorageAccount: "account",
+	}
+	opts := &azidentity.ClientAssertionCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud:     cloud.Configuration{ActiveDirectoryAuthorityHost: srv.URL},
+			Transport: srv.Client(),
+		},
+		DisableInstanceDiscovery: true,
+	}
+	sec, exp, err := workloadIdentityAzureSecret(context.Background(), wi, staticTokenSource("token"), opts)
+	c.Assert(err, check.IsNil)
+	c.Assert(sec.Type, check.Equals, objectstore.SecretTypeAzStorageAccount)
+	c.Assert(sec.Azure.StorageAccount, check.Equals, "account")
+	c.Assert(sec.Azure.StorageKey, check.Equals, "")
+	c.Assert(sec.Azure.AccessToken, check.Equals, "access-token")
+	c.Assert(sec.Azure.AccessTokenExpiresOn, check.Equals, exp)
+	c.Assert(exp.After(time.Now().Add(50*time.Minute)), check.Equals, true)
+}
+
+func (s *WorkloadIdentitySuite) TestWorkloadIdentityAWSCredentialsRenewal(c *check.C) {
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Assert(r.ParseForm(), check.IsNil)
+		requests = append(requests, r.Form)
+		// The first credentials are already expired
+		exp := time.Now().Add(-time.Minute)
+		if len(requests) > 1 {
+			exp = time.Now().Add(time.Hour)
+		}
+		fmt.Fprintf(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>key-%d</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`, len(requests), exp.UTC().Format(time.RFC3339))
+	}))
+	defer srv.Close()
+
+	sess, err := session.NewSession(awssdk.NewConfig().
+		WithEndpoint(srv.URL).
+		WithRegion("us-east-1").
+		WithCredentials(credentials.AnonymousCredentials))
+	c.Assert(err, check.IsNil)
+	ts := &countingTokenSource{}
+	wi := &crv1alpha1.WorkloadIdentity{RoleARN: "arn:aws:iam::123456789012:role/kanister"}
+	creds := workloadIdentityAWSCredentials(sts.New(sess), wi, ts)
+
+	for _, key := range []string{"key-1", "key-2", "key-2"} {
+		val, err := creds.GetWithContext(context.Background())
+		c.Assert(err, check.IsNil)
+		c.Assert(val.AccessKeyID, check.Equals, key)
+	}
+	c.Assert(requests, check.HasLen, 2)
+	// Every exchange uses a new ServiceAccount token
+	for i, r := range requests {
+		c.Assert(r.Get("Action"), check.Equals, "AssumeRoleWithWebIdentity")
+		c.Assert(r.Get("RoleArn"), check.Equals, wi.RoleARN)
+		c.Assert(r.Get("WebIdentityToken"), check.Equals, fmt.Sprintf("token-%d", i+1))
+	}
+	exp, err := creds.ExpiresAt()
+	c.Assert(err, check.IsNil)
+	c.Assert(exp.After(time.Now()), check.Equals, true)
+}
+
+type countingTokenSource struct {
+	n int
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (string, error) {
+	s.n++
+	return fmt.Sprintf("token-%d", s.n), nil
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
@@ -15,16 +15,30 @@
 package validate
 
 import (
+	"fmt"
+
 	"github.com/kanisterio/errkit"
 )
 
-var errValidate = errkit.NewSentinelErr("Validation Failed")
+var (
+	errValidate   = errkit.NewSentinelErr("Validation Failed")
+	errNotChecked = errkit.NewSentinelErr("Not Checked")
+)
 
 func errorf(err error, format string, args ...interface{}) error {
-	return errkit.Wrap(err, format, args...)
+	if len(args) == 0 {
+		return errkit.Wrap(err, format)
+	}
+	return errkit.Wrap(err, fmt.Sprintf(format, args...))
 }
 
 // IsError returns true if the underlying cause was a validation error.
 func IsError(err error) bool {
 	return errkit.Is(err, errValidate)
 }
+
+// IsNotChecked returns true if a check did not check anything, e.g. because
+// the locations of a Profile cannot be accessed from where it is run.
+func IsNotChecked(err error) bool {
+	return errkit.Is(err, errNotChecked)
+}
@@ -18,9 +18,11 @@ package validate
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/kanisterio/errkit"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
@@ -70,6 +72,14 @@ func actionSpec(s crv1alpha1.ActionSpec) error {
 		fallthrough
 	case param.DeploymentConfigKind:
 		fallthrough
+	case param.DaemonSetKind:
+		fallthrough
+	case param.ReplicaSetKind:
+		fallthrough
+	case param.JobKind:
+		fallthrough
+	case param.CronJobKind:
+		fallthrough
 	case param.NamespaceKind:
 		// Known types
 	default:
@@ -143,24 +153,166 @@ func ProfileSchema(p *crv1alpha1.Profile) error {
 	if !supported(p.Location.Type) {
 		return errorf(errValidate, "unknown or unsupported location type '%s'", p.Location.Type)
 	}
-	if err := validateCredentialType(&p.Credential); err != nil {
+	switch p.Compression {
+	case "", crv1alpha1.CompressionTypeNone, crv1alpha1.CompressionTypeGzip, crv1alpha1.CompressionTypeZstd:
+	default:
+		return errorf(errValidate, "unknown or unsupported compression '%s'", p.Compression)
+	}
+	if err := validateObjectLock(p); err != nil {
+		return err
+	}
+	if err := validateServerSideEncryption(p); err != nil {
+		return err
+	}
+	if err := validateStorageClass(p); err != nil {
+		return err
+	}
+	if err := validateRateLimit(p.RateLimit); err != nil {
 		return err
 	}
-	if p.Location.Type == crv1alpha1.LocationTypeS3Compliant {
-		if p.Location.Bucket == "" {
+	if err := validateSecondaryLocations(p); err != nil {
+		return err
+	}
+	if p.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		// Filesystem locations are mounted and do not need credentials
+		if p.Location.ClaimName == "" {
+			return errorf(errValidate, "PersistentVolumeClaim of filesystem location not specified")
+		}
+		return nil
+	}
+	if err := validateCredentialType(&p.Credential, p.Location.Type, p.Namespace); err != nil {
+		return err
+	}
+	// The host key of the server is pinned in the secret
+	if p.Location.Type == crv1alpha1.LocationTypeSFTP && p.Credential.Type != crv1alpha1.CredentialTypeSecret {
+		return errorf(errValidate, "Unsupported credential type '%s' for SFTP location", p.Credential.Type)
+	}
+	for _, lp := range profileLocations(p) {
+		if err := validateLocation(lp.Location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateLocation(l crv1alpha1.Location) error {
+	if l.Type == crv1alpha1.LocationTypeSFTP && l.Endpoint == "" {
+		return errorf(errValidate, "Endpoint of SFTP location not specified")
+	}
+	if l.Type == crv1alpha1.LocationTypeS3Compliant {
+		if l.Bucket == "" {
 			return errorf(errValidate, "Bucket not specified")
 		}
 
-		if p.Location.Region == "" {
+		if l.Region == "" {
 			return errorf(errValidate, "Bucket region not specified")
 		}
 	}
 	return nil
 }
 
-func validateCredentialType(creds *crv1alpha1.Credential) error {
+func validateSecondaryLocations(p *crv1alpha1.Profile) error {
+	switch p.LocationPolicy {
+	case "", crv1alpha1.LocationPolicyFailover, crv1alpha1.LocationPolicyWriteAll:
+	default:
+		return errorf(errValidate, "unknown or unsupported location policy '%s'", p.LocationPolicy)
+	}
+	if len(p.SecondaryLocations) == 0 {
+		return nil
+	}
+	// Filesystem locations are mounted at a single path in the pods
+	if p.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		return errorf(errValidate, "secondary locations are not supported for location type '%s'", p.Location.Type)
+	}
+	for _, l := range p.SecondaryLocations {
+		// Secondary locations are accessed with the credential of the profile
+		if l.Type != p.Location.Type {
+			return errorf(errValidate, "secondary location type '%s' differs from location type '%s'", l.Type, p.Location.Type)
+		}
+	}
+	return nil
+}
+
+// profileLocations returns a copy of the profile for each of its locations,
+// in the order they are used.
+func profileLocations(p *crv1alpha1.Profile) []*crv1alpha1.Profile {
+	ps := []*crv1alpha1.Profile{p}
+	for _, l := range p.SecondaryLocations {
+		lp := p.DeepCopy()
+		lp.Location = l
+		lp.SecondaryLocations = nil
+		ps = append(ps, lp)
+	}
+	return ps
+}
+
+func validateObjectLock(p *crv1alpha1.Profile) error {
+	if p.ObjectLock == nil {
+		return nil
+	}
+	if p.Location.Type != crv1alpha1.LocationTypeS3Compliant {
+		return errorf(errValidate, "object lock is not supported for location type '%s'", p.Location.Type)
+	}
+	switch p.ObjectLock.Mode {
+	case crv1alpha1.ObjectLockModeGovernance, crv1alpha1.ObjectLockModeCompliance:
+	default:
+		return errorf(errValidate, "unknown or unsupported object lock mode '%s'", p.ObjectLock.Mode)
+	}
+	if p.ObjectLock.Retention.Duration <= 0 {
+		return errorf(errValidate, "object lock retention must be positive")
+	}
+	return nil
+}
+
+func validateServerSideEncryption(p *crv1alpha1.Profile) error {
+	sse := p.ServerSideEncryption
+	if sse == nil {
+		return nil
+	}
+	switch p.Location.Type {
+	case crv1alpha1.LocationTypeS3Compliant:
+		if sse.EncryptionScope != "" {
+			return errorf(errValidate, "encryption scope is not supported for location type '%s'", p.Location.Type)
+		}
+	case crv1alpha1.LocationTypeAzure:
+		if sse.Type != "" {
+			return errorf(errValidate, "server-side encryption type is not supported for location type '%s'", p.Location.Type)
+		}
+		if sse.EncryptionScope == "" {
+			return errorf(errValidate, "encryption scope not specified")
+		}
+		return nil
+	default:
+		return errorf(errValidate, "server-side encryption is not supported for location type '%s'", p.Location.Type)
+	}
+	switch sse.Type {
+	case crv1alpha1.ServerSideEncryptionTypeS3:
+	case crv1alpha1.ServerSideEncryptionTypeKMS:
+	case crv1alpha1.ServerSideEncryptionTypeCustomer:
+		if sse.CustomerKeySecret == nil || sse.CustomerKeySecret.Name == "" {
+			return errorf(errValidate, "secret of sse-c key not specified")
+		}
+		if sse.CustomerKeyField == "" {
+			return errorf(errValidate, "secret field of sse-c key not specified")
+		}
+	default:
+		return errorf(errValidate, "unknown or unsupported server-side encryption type '%s'", sse.Type)
+	}
+	if sse.KMSKeyID != "" && sse.Type != crv1alpha1.ServerSideEncryptionTypeKMS {
+		return errorf(errValidate, "KMS key is only supported with sse-kms")
+	}
+	return nil
+}
+
+func validateCredentialType(creds *crv1alpha1.Credential, lt crv1alpha1.LocationType, namespace string) error {
+	if creds.Source != nil {
+		return validateCredentialSource(creds)
+	}
 	switch creds.Type {
 	case crv1alpha1.CredentialTypeKeyPair:
+		if creds.KeyPair == nil {
+			return errorf(errValidate, "Key pair of credentials not specified")
+		}
 		if creds.KeyPair.Secret.Name == "" {
 			return errorf(errValidate, "Secret for bucket credentials not specified")
 		}
@@ -169,6 +321,9 @@ func validateCredentialType(creds *crv1alpha1.Credential) error {
 		}
 		return nil
 	case crv1alpha1.CredentialTypeSecret:
+		if creds.Secret == nil {
+			return errorf(errValidate, "Secret of credentials not specified")
+		}
 		if creds.Secret.Name == "" {
 			return errorf(errValidate, "Secret name is empty")
 		}
@@ -176,16 +331,132 @@ func validateCredentialType(creds *crv1alpha1.Credential) error {
 			return errorf(errValidate, "Secret namespace is empty")
 		}
 		return nil
+	case crv1alpha1.CredentialTypeWorkloadIdentity:
+		if err := secrets.ValidateWorkloadIdentity(creds.WorkloadIdentity, lt, namespace); err != nil {
+			return errorf(errValidate, "%s", err.Error())
+		}
+		return nil
 	default:
 		return errorf(errValidate, "Unsupported credential type '%s'", creds.Type)
 	}
 }
 
+func validateCredentialSource(creds *crv1alpha1.Credential) error {
+	src := creds.Source
+	switch {
+	case src.File != nil && src.Vault != nil:
+		return errorf(errValidate, "Only one of file or vault can be set in credential source")
+	case src.File != nil:
+		if src.File.Path == "" {
+			return errorf(errValidate, "Path of file credential source is empty")
+		}
+	case src.Vault != nil:
+		if src.Vault.Address == "" || src.Vault.Path == "" {
+			return errorf(errValidate, "Address or path of vault credential source is empty")
+		}
+		if src.Vault.TokenSecret.Name == "" {
+			return errorf(errValidate, "Token secret of vault credential source not specified")
+		}
+	default:
+		return errorf(errValidate, "Credential source must set either file or vault")
+	}
+	switch creds.Type {
+	case crv1alpha1.CredentialTypeKeyPair:
+		if creds.KeyPair == nil || creds.KeyPair.SecretField == "" || creds.KeyPair.IDField == "" {
+			return errorf(errValidate, "Secret field or id field empty")
+		}
+		return nil
+	case crv1alpha1.CredentialTypeSecret:
+		if src.SecretType == "" {
+			return errorf(errValidate, "Secret type of credential source is empty")
+		}
+		return nil
+	default:
+		return errorf(errValidate, "Unsupported credential type '%s' for credential source", creds.Type)
+	}
+}
+
+func validateStorageClass(p *crv1alpha1.Profile) error {
+	if p.StorageClass == "" {
+		return nil
+	}
+	var pType objectstore.ProviderType
+	switch p.Location.Type {
+	case crv1alpha1.LocationTypeS3Compliant:
+		pType = objectstore.ProviderTypeS3
+	case crv1alpha1.LocationTypeGCS:
+		pType = objectstore.ProviderTypeGCS
+	case crv1alpha1.LocationTypeAzure:
+		pType = objectstore.ProviderTypeAzure
+	default:
+		return errorf(errValidate, "storage class is not supported for location type '%s'", p.Location.Type)
+	}
+	if err := objectstore.ValidateStorageClass(pType, p.StorageClass); err != nil {
+		return errorf(errValidate, "unknown or unsupported storage class '%s' for location type '%s'", p.StorageClass, p.Location.Type)
+	}
+	return nil
+}
+
+func validateRateLimit(l *crv1alpha1.RateLimit) error {
+	if l == nil {
+		return nil
+	}
+	if l.BytesPerSecond <= 0 {
+		return errorf(errValidate, "rate limit must be positive, got %d bytes per second", l.BytesPerSecond)
+	}
+	if l.Burst < 0 {
+		return errorf(errValidate, "rate limit burst must not be negative, got %d bytes", l.Burst)
+	}
+	return nil
+}
+
 func supported(t crv1alpha1.LocationType) bool {
-	return t == crv1alpha1.LocationTypeS3Compliant || t == crv1alpha1.LocationTypeGCS || t == crv1alpha1.LocationTypeAzure
+	return t == crv1alpha1.LocationTypeS3Compliant || t == crv1alpha1.LocationTypeGCS || t == crv1alpha1.LocationTypeAzure || t == crv1alpha1.LocationTypeFilesystem || t == crv1alpha1.LocationTypeSFTP
 }
 
 func ProfileBucket(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
+	return checkLocations(p, func(lp *crv1alpha1.Profile) error {
+		return profileBucket(ctx, lp, cli)
+	})
+}
+
+// checkLocations runs check for the locations of the Profile that are
+// accessed from the controller. Filesystem locations are only mounted in the
+// pods that access them, so they are not checked. It returns an error for
+// which IsNotChecked is true if no location was checked.
+func checkLocations(p *crv1alpha1.Profile, check func(*crv1alpha1.Profile) error) error {
+	checked := false
+	for _, lp := range profileLocations(p) {
+		if lp.Location.Type == crv1alpha1.LocationTypeFilesystem {
+			continue
+		}
+		checked = true
+		if err := check(lp); err != nil {
+			return err
+		}
+	}
+	if !checked {
+		return errorf(errNotChecked, "filesystem locations are only accessed from the pods that mount them")
+	}
+	return nil
+}
+
+// FilesystemClaims checks that the PersistentVolumeClaims of the filesystem
+// locations of the Profile exist in `namespace`, where the pods that access
+// them are run. Profiles can be used by actions in any namespace.
+func FilesystemClaims(ctx context.Context, p *crv1alpha1.Profile, namespace string, cli kubernetes.Interface) error {
+	for _, lp := range profileLocations(p) {
+		if lp.Location.Type != crv1alpha1.LocationTypeFilesystem {
+			continue
+		}
+		if _, err := cli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, lp.Location.ClaimName, metav1.GetOptions{}); err != nil {
+			return errorf(err, "failed to get PersistentVolumeClaim '%s/%s' of filesystem location", namespace, lp.Location.ClaimName)
+		}
+	}
+	return nil
+}
+
+func profileBucket(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
 	var pType objectstore.ProviderType
 	bucketName := p.Location.Bucket
 
@@ -196,6 +467,8 @@ func ProfileBucket(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.In
 		pType = objectstore.ProviderTypeGCS
 	case crv1alpha1.LocationTypeAzure:
 		pType = objectstore.ProviderTypeAzure
+	case crv1alpha1.LocationTypeSFTP:
+		pType = objectstore.ProviderTypeSFTP
 	default:
 		return errorf(errValidate, "unknown or unsupported location type '%s'", p.Location.Type)
 	}
@@ -220,7 +493,15 @@ func ProfileBucket(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.In
 	return err
 }
 
+// ReadAccess checks that the objects under the prefix of the locations of
+// the Profile can be listed.
 func ReadAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
+	return checkLocations(p, func(lp *crv1alpha1.Profile) error {
+		return readAccess(ctx, lp, cli)
+	})
+}
+
+func readAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
 	var pType objectstore.ProviderType
 	var secret *objectstore.Secret
 	var err error
@@ -231,6 +512,8 @@ func ReadAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Inter
 		pType = objectstore.ProviderTypeGCS
 	case crv1alpha1.LocationTypeAzure:
 		pType = objectstore.ProviderTypeAzure
+	case crv1alpha1.LocationTypeSFTP:
+		pType = objectstore.ProviderTypeSFTP
 	default:
 		return errorf(errValidate, "unknown or unsupported location type '%s'", p.Location.Type)
 	}
@@ -252,13 +535,28 @@ func ReadAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Inter
 	if err != nil {
 		return err
 	}
-	if _, err := bucket.ListDirectories(ctx); err != nil {
-		return errorf(err, "failed to list directories in bucket '%s'", p.Location.Bucket)
+	// A single page is enough to check that objects can be listed
+	it := bucket.IterateObjects(ctx, objectstore.ListOptions{Prefix: p.Location.Prefix, PageSize: 1})
+	it.Next(ctx)
+	if err := it.Err(); err != nil {
+		return errorf(err, "failed to list objects in bucket '%s'", p.Location.Bucket)
 	}
 	return nil
 }
 
+// WriteAccess checks that an object can be written to and deleted from the
+// prefix of the locations of the Profile. Profiles with an object lock are
+// not checked, since the object would be retained.
 func WriteAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
+	if p.ObjectLock != nil {
+		return errorf(errNotChecked, "objects written to locations with an object lock are retained")
+	}
+	return checkLocations(p, func(lp *crv1alpha1.Profile) error {
+		return writeAccess(ctx, lp, cli)
+	})
+}
+
+func writeAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
 	var pType objectstore.ProviderType
 	var secret *objectstore.Secret
 	var err error
@@ -269,6 +567,8 @@ func WriteAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Inte
 		pType = objectstore.ProviderTypeGCS
 	case crv1alpha1.LocationTypeAzure:
 		pType = objectstore.ProviderTypeAzure
+	case crv1alpha1.LocationTypeSFTP:
+		pType = objectstore.ProviderTypeSFTP
 	default:
 		return errorf(errValidate, "unknown or unsupported location type '%s'", p.Location.Type)
 	}
@@ -276,7 +576,7 @@ func WriteAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Inte
 	if err != nil {
 		return err
 	}
-	const objName = "sample"
+	objName := path.Join(p.Location.Prefix, "sample")
 
 	pc := objectstore.ProviderConfig{
 		Type:          pType,
@@ -301,16 +601,95 @@ func WriteAccess(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Inte
 	return nil
 }
 
+// ProfileCredential checks that the credential of the Profile can be read
+// and turned into the secret of its object store.
+func ProfileCredential(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
+	var pType objectstore.ProviderType
+	switch p.Location.Type {
+	case crv1alpha1.LocationTypeFilesystem:
+		// Filesystem locations are mounted and do not need credentials
+		return nil
+	case crv1alpha1.LocationTypeS3Compliant:
+		pType = objectstore.ProviderTypeS3
+	case crv1alpha1.LocationTypeGCS:
+		pType = objectstore.ProviderTypeGCS
+	case crv1alpha1.LocationTypeAzure:
+		pType = objectstore.ProviderTypeAzure
+	case crv1alpha1.LocationTypeSFTP:
+		pType = objectstore.ProviderTypeSFTP
+	default:
+		return errorf(errValidate, "unknown or unsupported location type '%s'", p.Location.Type)
+	}
+	_, err := osSecretFromProfile(ctx, pType, p, cli)
+	return err
+}
+
+// locationSecretTypes are the types of the Secrets that hold the
+// credentials of each location type.
+var locationSecretTypes = map[crv1alpha1.LocationType]string{
+	crv1alpha1.LocationTypeS3Compliant: secrets.AWSSecretType,
+	crv1alpha1.LocationTypeGCS:         secrets.GCPSecretType,
+	crv1alpha1.LocationTypeAzure:       secrets.AzureSecretType,
+	crv1alpha1.LocationTypeSFTP:        secrets.SFTPSecretType,
+}
+
+// ProfileSecrets checks that the Secrets referenced by the credential of the
+// Profile exist and have the keys its location type needs. Credentials
+// leased from a credential source are not checked.
+func ProfileSecrets(ctx context.Context, p *crv1alpha1.Profile, cli kubernetes.Interface) error {
+	if p.Credential.Source != nil {
+		return nil
+	}
+	switch p.Credential.Type {
+	case crv1alpha1.CredentialTypeKeyPair:
+		kp := p.Credential.KeyPair
+		if kp == nil {
+			return errorf(errValidate, "Key pair of credentials not specified")
+		}
+		s, err := credentialSecret(ctx, cli, p.Credential, &kp.Secret)
+		if err != nil {
+			return err
+		}
+		for _, key := range []string{kp.IDField, kp.SecretField} {
+			if _, ok := s.Data[key]; !ok {
+				return errorf(errValidate, "Secret '%s:%s' has no key '%s'", kp.Secret.Namespace, kp.Secret.Name, key)
+			}
+		}
+	case crv1alpha1.CredentialTypeSecret:
+		s, err := credentialSecret(ctx, cli, p.Credential, p.Credential.Secret)
+		if err != nil {
+			return err
+		}
+		if t, ok := locationSecretTypes[p.Location.Type]; ok && string(s.Type) != t {
+			return errorf(errValidate, "Secret '%s:%s' of type '%s' cannot be used for location type '%s', which needs type '%s'", s.Namespace, s.Name, s.Type, p.Location.Type, t)
+		}
+		if err := secrets.ValidateCredentials(s); err != nil {
+			return errorf(err, "Invalid secret '%s:%s'", s.Namespace, s.Name)
+		}
+	}
+	return nil
+}
+
 func osSecretFromProfile(ctx context.Context, pType objectstore.ProviderType, p *crv1alpha1.Profile, cli kubernetes.Interface) (*objectstore.Secret, error) {
 	var key, value []byte
 	var ok bool
 	secret := &objectstore.Secret{}
 
+	if p.Credential.Type == crv1alpha1.CredentialTypeWorkloadIdentity {
+		wi := p.Credential.WorkloadIdentity
+		if err := secrets.ValidateWorkloadIdentity(wi, p.Location.Type, p.Namespace); err != nil {
+			return nil, errorf(errValidate, "Invalid credentials %s", err.Error())
+		}
+		ts := secrets.NewServiceAccountTokenSource(cli, wi.ServiceAccount.Namespace, wi.ServiceAccount.Name, secrets.WorkloadIdentityAudience(wi, p.Location.Type))
+		secret, _, err := secrets.WorkloadIdentitySecret(ctx, pType, wi, ts)
+		return secret, err
+	}
+
 	// Secret Credential type code path
 	if p.Credential.Type == crv1alpha1.CredentialTypeSecret {
-		s, err := cli.CoreV1().Secrets(p.Credential.Secret.Namespace).Get(ctx, p.Credential.Secret.Name, metav1.GetOptions{})
+		s, err := credentialSecret(ctx, cli, p.Credential, p.Credential.Secret)
 		if err != nil {
-			return nil, errorf(err, "Could not fetch the secret specified in credential")
+			return nil, err
 		}
 		switch pType {
 		case objectstore.ProviderTypeS3:
@@ -331,6 +710,13 @@ func osSecretFromProfile(ctx context.Context, pType objectstore.ProviderType, p
 			}
 			secret.Type = objectstore.SecretTypeAzStorageAccount
 			secret.Azure = azureSecret
+		case objectstore.ProviderTypeSFTP:
+			sftpSecret, err := secrets.ExtractSFTPCredentials(s)
+			if err != nil {
+				return nil, err
+			}
+			secret.Type = objectstore.SecretTypeSFTP
+			secret.SFTP = sftpSecret
 		}
 		return secret, nil
 	}
@@ -340,9 +726,9 @@ func osSecretFromProfile(ctx context.Context, pType objectstore.ProviderType, p
 	if kp == nil {
 		return nil, errorf(errValidate, "Invalid credentials kp cannot be nil")
 	}
-	s, err := cli.CoreV1().Secrets(kp.Secret.Namespace).Get(ctx, kp.Secret.Name, metav1.GetOptions{})
+	s, err := credentialSecret(ctx, cli, p.Credential, &kp.Secret)
 	if err != nil {
-		return nil, errorf(err, "Could not fetch the secret specified in credential")
+		return nil, err
 	}
 	if key, ok = s.Data[kp.IDField]; !ok {
 		return nil, errorf(errValidate, "Key '%s' not found in secret '%s:%s'", kp.IDField, s.GetNamespace(), s.GetName())
@@ -376,6 +762,30 @@ func osSecretFromProfile(ctx context.Context, pType objectstore.ProviderType, p
 	return secret, nil
 }
 
+// credentialSecret returns the secret referenced by a credential, or a secret
+// holding the data of the credential source if one is set.
+func credentialSecret(ctx context.Context, cli kubernetes.Interface, creds crv1alpha1.Credential, ref *crv1alpha1.ObjectReference) (*corev1.Secret, error) {
+	if creds.Source != nil {
+		src, err := secrets.NewSource(cli, creds.Source)
+		if err != nil {
+			return nil, errorf(err, "Invalid credential source")
+		}
+		data, err := src.Data(ctx)
+		if err != nil {
+			return nil, errorf(err, "Could not read the credential source")
+		}
+		return &corev1.Secret{Type: corev1.SecretType(creds.Source.SecretType), Data: data}, nil
+	}
+	if ref == nil {
+		return nil, errorf(errValidate, "Secret reference cannot be nil")
+	}
+	s, err := cli.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errorf(err, "Could not fetch the secret specified in credential")
+	}
+	return s, nil
+}
+
 func ValidateLabels(labels map[string]string) error {
 	for k, v := range labels {
 		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
@@ -16,7 +16,10 @@ package validate
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"gopkg.in/check.v1"
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +29,7 @@ import (
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/objectstore"
+	"github.com/kanisterio/kanister/pkg/objectstore/sftptest"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/secrets"
 )
@@ -606,6 +610,494 @@ func (s *ValidateSuite) TestProfileSchema(c *check.C) {
 			},
 			checker: check.NotNil,
 		},
+		// Filesystem location without credentials
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+			},
+			checker: check.IsNil,
+		},
+		// Missing claim name
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type: crv1alpha1.LocationTypeFilesystem,
+				},
+			},
+			checker: check.NotNil,
+		},
+		// SFTP location
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeSFTP,
+					Endpoint: "sftp.example.com",
+					Bucket:   "backups",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: check.IsNil,
+		},
+		// SFTP location without endpoint
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeSFTP,
+					Bucket: "backups",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: check.NotNil,
+		},
+		// SFTP location with a key pair, which cannot pin the host key
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:     crv1alpha1.LocationTypeSFTP,
+					Endpoint: "sftp.example.com",
+					Bucket:   "backups",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeKeyPair,
+					KeyPair: &crv1alpha1.KeyPair{
+						IDField:     "id",
+						SecretField: "secret",
+						Secret: crv1alpha1.ObjectReference{
+							Name:      "secret-name",
+							Namespace: "secret-namespace",
+						},
+					},
+				},
+			},
+			checker: check.NotNil,
+		},
+		// Compressed artifacts
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				Compression: crv1alpha1.CompressionTypeZstd,
+			},
+			checker: check.IsNil,
+		},
+		// Object lock
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ObjectLock: &crv1alpha1.ObjectLock{
+					Mode:      crv1alpha1.ObjectLockModeCompliance,
+					Retention: metav1.Duration{Duration: 30 * 24 * time.Hour},
+				},
+			},
+			checker: check.IsNil,
+		},
+		// Object lock without retention
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ObjectLock: &crv1alpha1.ObjectLock{
+					Mode: crv1alpha1.ObjectLockModeGovernance,
+				},
+			},
+			checker: check.NotNil,
+		},
+		// Object lock on a location that does not support it
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				ObjectLock: &crv1alpha1.ObjectLock{
+					Mode:      crv1alpha1.ObjectLockModeGovernance,
+					Retention: metav1.Duration{Duration: time.Hour},
+				},
+			},
+			checker: check.NotNil,
+		},
+		// SSE-KMS with a key
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ServerSideEncryption: &crv1alpha1.ServerSideEncryption{
+					Type:     crv1alpha1.ServerSideEncryptionTypeKMS,
+					KMSKeyID: "alias/backups",
+				},
+			},
+			checker: check.IsNil,
+		},
+		// SSE-C
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ServerSideEncryption: &crv1alpha1.ServerSideEncryption{
+					Type: crv1alpha1.ServerSideEncryptionTypeCustomer,
+					CustomerKeySecret: &crv1alpha1.ObjectReference{
+						Name:      "sse-key",
+						Namespace: "secret-namespace",
+					},
+					CustomerKeyField: "key",
+				},
+			},
+			checker: check.IsNil,
+		},
+		// SSE-C without a key
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ServerSideEncryption: &crv1alpha1.ServerSideEncryption{
+					Type: crv1alpha1.ServerSideEncryptionTypeCustomer,
+				},
+			},
+			checker: check.NotNil,
+		},
+		// KMS key without SSE-KMS
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ServerSideEncryption: &crv1alpha1.ServerSideEncryption{
+					Type:     crv1alpha1.ServerSideEncryptionTypeS3,
+					KMSKeyID: "alias/backups",
+				},
+			},
+			checker: check.NotNil,
+		},
+		// Encryption scope
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeAzure,
+					Bucket: "bucket-name",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ServerSideEncryption: &crv1alpha1.ServerSideEncryption{
+					EncryptionScope: "backups",
+				},
+			},
+			checker: check.IsNil,
+		},
+		// S3 server-side encryption on an Azure location
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeAzure,
+					Bucket: "bucket-name",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ServerSideEncryption: &crv1alpha1.ServerSideEncryption{
+					Type: crv1alpha1.ServerSideEncryptionTypeS3,
+				},
+			},
+			checker: check.NotNil,
+		},
+		// Encryption scope on an S3 location
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				ServerSideEncryption: &crv1alpha1.ServerSideEncryption{
+					EncryptionScope: "backups",
+				},
+			},
+			checker: check.NotNil,
+		},
+		// Unsupported compression
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				Compression: "lz4",
+			},
+			checker: check.NotNil,
+		},
+		// Storage class of the location type
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				StorageClass: "DEEP_ARCHIVE",
+			},
+			checker: check.IsNil,
+		},
+		// Storage class of another location type
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				StorageClass: "Archive",
+			},
+			checker: check.NotNil,
+		},
+		// Storage class of filesystem location
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				StorageClass: "STANDARD",
+			},
+			checker: check.NotNil,
+		},
+		// Rate limit
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				RateLimit: &crv1alpha1.RateLimit{BytesPerSecond: 10 << 20, Burst: 1 << 20},
+			},
+			checker: check.IsNil,
+		},
+		// Rate limit without rate
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				RateLimit: &crv1alpha1.RateLimit{Burst: 1 << 20},
+			},
+			checker: check.NotNil,
+		},
+		// Negative rate limit burst
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				RateLimit: &crv1alpha1.RateLimit{BytesPerSecond: 10 << 20, Burst: -1},
+			},
+			checker: check.NotNil,
+		},
+		// Secondary locations
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				SecondaryLocations: []crv1alpha1.Location{
+					{Type: crv1alpha1.LocationTypeS3Compliant, Bucket: "bucket-name", Region: "other-region"},
+				},
+				LocationPolicy: crv1alpha1.LocationPolicyWriteAll,
+			},
+			checker: check.IsNil,
+		},
+		// Secondary location of another type
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				SecondaryLocations: []crv1alpha1.Location{
+					{Type: crv1alpha1.LocationTypeGCS, Bucket: "bucket-name"},
+				},
+			},
+			checker: check.NotNil,
+		},
+		// Secondary location without region
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				SecondaryLocations: []crv1alpha1.Location{
+					{Type: crv1alpha1.LocationTypeS3Compliant, Bucket: "bucket-name"},
+				},
+			},
+			checker: check.NotNil,
+		},
+		// Unknown location policy
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:   crv1alpha1.LocationTypeS3Compliant,
+					Bucket: "bucket-name",
+					Region: "region",
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+				SecondaryLocations: []crv1alpha1.Location{
+					{Type: crv1alpha1.LocationTypeS3Compliant, Bucket: "bucket-name", Region: "other-region"},
+				},
+				LocationPolicy: "readAll",
+			},
+			checker: check.NotNil,
+		},
+		// Secondary filesystem location
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type:      crv1alpha1.LocationTypeFilesystem,
+					ClaimName: "backup-pvc",
+				},
+				SecondaryLocations: []crv1alpha1.Location{
+					{Type: crv1alpha1.LocationTypeFilesystem, ClaimName: "other-pvc"},
+				},
+			},
+			checker: check.NotNil,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -614,6 +1106,84 @@ func (s *ValidateSuite) TestProfileSchema(c *check.C) {
 	}
 }
 
+func (s *ValidateSuite) TestFilesystemProfile(c *check.C) {
+	ctx := context.Background()
+	p := &crv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns"},
+		Location: crv1alpha1.Location{
+			Type:      crv1alpha1.LocationTypeFilesystem,
+			ClaimName: "backup-pvc",
+		},
+	}
+	// Claims are checked in the namespace of the action, not of the Profile
+	cli := fake.NewSimpleClientset(&corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-pvc", Namespace: "app"},
+	})
+	// Filesystems are only mounted in pods, so they cannot be accessed here
+	for _, fn := range []func(context.Context, *crv1alpha1.Profile, kubernetes.Interface) error{ProfileBucket, ReadAccess, WriteAccess} {
+		c.Assert(IsNotChecked(fn(ctx, p, cli)), check.Equals, true)
+	}
+	c.Assert(FilesystemClaims(ctx, p, "app", cli), check.IsNil)
+	err := FilesystemClaims(ctx, p, "ns", cli)
+	c.Assert(err, check.ErrorMatches, ".*PersistentVolumeClaim 'ns/backup-pvc'.*")
+}
+
+func (s *ValidateSuite) TestSFTPProfile(c *check.C) {
+	ctx := context.Background()
+	root := c.MkDir()
+	c.Assert(os.Mkdir(filepath.Join(root, "backups"), 0o755), check.IsNil)
+	server, err := sftptest.NewServer(root)
+	c.Assert(err, check.IsNil)
+	defer server.Close() //nolint:errcheck
+
+	p := &crv1alpha1.Profile{
+		Location: crv1alpha1.Location{
+			Type:     crv1alpha1.LocationTypeSFTP,
+			Endpoint: server.Addr,
+			Bucket:   "backups",
+		},
+		Credential: crv1alpha1.Credential{
+			Type: crv1alpha1.CredentialTypeSecret,
+			Secret: &crv1alpha1.ObjectReference{
+				Name:      "secname",
+				Namespace: "secnamespace",
+			},
+		},
+	}
+	cli := fake.NewSimpleClientset(&corev1.Secret{
+		Type: corev1.SecretType(secrets.SFTPSecretType),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secname",
+			Namespace: "secnamespace",
+		},
+		Data: map[string][]byte{
+			secrets.SFTPUsername:   []byte(sftptest.Username),
+			secrets.SFTPPrivateKey: []byte(server.PrivateKey),
+			secrets.SFTPHostKey:    []byte(server.HostKey),
+		},
+	})
+	c.Assert(ProfileSchema(p), check.IsNil)
+	c.Assert(ProfileBucket(ctx, p, cli), check.IsNil)
+	c.Assert(ReadAccess(ctx, p, cli), check.IsNil)
+	c.Assert(WriteAccess(ctx, p, cli), check.IsNil)
+
+	// Access is checked under the prefix
+	p.Location.Prefix = "kanister"
+	c.Assert(ReadAccess(ctx, p, cli), check.IsNil)
+	c.Assert(WriteAccess(ctx, p, cli), check.IsNil)
+	entries, err := os.ReadDir(filepath.Join(root, "backups"))
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0].Name(), check.Equals, "kanister")
+
+	// Objects written to locked locations would be retained
+	p.ObjectLock = &crv1alpha1.ObjectLock{Mode: crv1alpha1.ObjectLockModeGovernance, Retention: metav1.Duration{Duration: time.Hour}}
+	c.Assert(IsNotChecked(WriteAccess(ctx, p, cli)), check.Equals, true)
+
+	p.Location.Bucket = "missing"
+	c.Assert(ProfileBucket(ctx, p, cli), check.NotNil)
+}
+
 func (s *ValidateSuite) TestOsSecretFromProfile(c *check.C) {
 	ctx := context.Background()
 	for i, tc := range []struct {
@@ -805,3 +1375,92 @@ func (s *ValidateSuite) TestOsSecretFromProfile(c *check.C) {
 		c.Check(err, tc.errChecker)
 	}
 }
+
+func (s *ValidateSuite) TestProfileSecrets(c *check.C) {
+	ctx := context.Background()
+	ref := crv1alpha1.ObjectReference{Name: "secname", Namespace: "secnamespace"}
+	awsSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			Type:       corev1.SecretType(secrets.AWSSecretType),
+			ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace},
+			Data:       data,
+		}
+	}
+	awsData := map[string][]byte{
+		secrets.AWSAccessKeyID:     []byte("id"),
+		secrets.AWSSecretAccessKey: []byte("secret"),
+	}
+	keyPair := crv1alpha1.Credential{
+		Type: crv1alpha1.CredentialTypeKeyPair,
+		KeyPair: &crv1alpha1.KeyPair{
+			IDField:     secrets.AWSAccessKeyID,
+			SecretField: secrets.AWSSecretAccessKey,
+			Secret:      ref,
+		},
+	}
+	secret := crv1alpha1.Credential{
+		Type:   crv1alpha1.CredentialTypeSecret,
+		Secret: &ref,
+	}
+	for i, tc := range []struct {
+		lt         crv1alpha1.LocationType
+		cred       crv1alpha1.Credential
+		cli        kubernetes.Interface
+		errChecker check.Checker
+	}{
+		{ // key pair
+			lt:         crv1alpha1.LocationTypeS3Compliant,
+			cred:       keyPair,
+			cli:        fake.NewSimpleClientset(awsSecret(awsData)),
+			errChecker: check.IsNil,
+		},
+		{ // missing key pair secret
+			lt:         crv1alpha1.LocationTypeS3Compliant,
+			cred:       keyPair,
+			cli:        fake.NewSimpleClientset(),
+			errChecker: check.NotNil,
+		},
+		{ // missing key pair field
+			lt:   crv1alpha1.LocationTypeS3Compliant,
+			cred: keyPair,
+			cli: fake.NewSimpleClientset(awsSecret(map[string][]byte{
+				secrets.AWSAccessKeyID: []byte("id"),
+			})),
+			errChecker: check.NotNil,
+		},
+		{ // secret
+			lt:         crv1alpha1.LocationTypeS3Compliant,
+			cred:       secret,
+			cli:        fake.NewSimpleClientset(awsSecret(awsData)),
+			errChecker: check.IsNil,
+		},
+		{ // secret of another location type
+			lt:         crv1alpha1.LocationTypeAzure,
+			cred:       secret,
+			cli:        fake.NewSimpleClientset(awsSecret(awsData)),
+			errChecker: check.NotNil,
+		},
+		{ // secret with unknown key
+			lt:   crv1alpha1.LocationTypeS3Compliant,
+			cred: secret,
+			cli: fake.NewSimpleClientset(awsSecret(map[string][]byte{
+				secrets.AWSAccessKeyID: []byte("id"),
+				"unknown":              []byte("value"),
+			})),
+			errChecker: check.NotNil,
+		},
+		{ // missing secret
+			lt:         crv1alpha1.LocationTypeS3Compliant,
+			cred:       secret,
+			cli:        fake.NewSimpleClientset(),
+			errChecker: check.NotNil,
+		},
+	} {
+		p := &crv1alpha1.Profile{
+			Location:   crv1alpha1.Location{Type: tc.lt, Bucket: "bucket"},
+			Credential: tc.cred,
+		}
+		err := ProfileSecrets(ctx, p, tc.cli)
+		c.Check(err, tc.errChecker, check.Commentf("test number: %d", i))
+	}
+}
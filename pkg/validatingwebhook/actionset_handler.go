@@ -0,0 +1,118 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatingwebhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kanisterio/errkit"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+// ActionSetValidator denies malformed ActionSets and changes to the spec of
+// ActionSets that are running. If Client is set, it also checks that the
+// Blueprints and actions that the ActionSet references exist and that their
+// functions are registered with the preferred version of the action.
+type ActionSetValidator struct {
+	decoder *admission.Decoder
+	Client  versioned.Interface
+}
+
+func (av *ActionSetValidator) Handle(ctx context.Context, r admission.Request) admission.Response {
+	as := &crv1alpha1.ActionSet{}
+	if err := (*av.decoder).Decode(r, as); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if r.Operation == admissionv1.Update {
+		old := &crv1alpha1.ActionSet{}
+		if err := (*av.decoder).DecodeRaw(r.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Updates of the status by the controller are always allowed
+		if equality.Semantic.DeepEqual(old.Spec, as.Spec) {
+			return admission.Allowed("")
+		}
+		if old.Status != nil && old.Status.State == crv1alpha1.StateRunning {
+			return admission.Denied(fmt.Sprintf("Invalid actionset, spec of ActionSet %s cannot be changed while it is running\n", as.GetName()))
+		}
+	}
+
+	if err := av.validateActionSet(ctx, as); err != nil {
+		return admission.Denied(fmt.Sprintf("Invalid actionset, %s\n", err.Error()))
+	}
+
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (av *ActionSetValidator) InjectDecoder(d *admission.Decoder) error {
+	av.decoder = d
+	return nil
+}
+
+func (av *ActionSetValidator) validateActionSet(ctx context.Context, as *crv1alpha1.ActionSet) error {
+	if err := validate.ActionSet(as); err != nil {
+		return err
+	}
+	for _, a := range as.Spec.Actions {
+		if err := validate.ValidateLabels(a.PodLabels); err != nil {
+			return errkit.Wrap(err, fmt.Sprintf("Invalid podLabels of action %s", a.Name))
+		}
+		if err := validate.ValidateAnnotations(a.PodAnnotations); err != nil {
+			return errkit.Wrap(err, fmt.Sprintf("Invalid podAnnotations of action %s", a.Name))
+		}
+		if av.Client == nil {
+			continue
+		}
+		if err := av.validateBlueprintAction(ctx, as.GetNamespace(), a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBlueprintAction checks that the Blueprint action of `a` exists
+// and that its phases can be run with the preferred version of `a`.
+func (av *ActionSetValidator) validateBlueprintAction(ctx context.Context, namespace string, a crv1alpha1.ActionSpec) error {
+	bp, err := av.Client.CrV1alpha1().Blueprints(namespace).Get(ctx, a.Blueprint, metav1.GetOptions{})
+	if err != nil {
+		return errkit.Wrap(err, fmt.Sprintf("Failed to get Blueprint %s of action %s", a.Blueprint, a.Name))
+	}
+	bpa, ok := bp.Actions[a.Name]
+	if !ok {
+		return errkit.New(fmt.Sprintf("Action %s not found in Blueprint %s", a.Name, a.Blueprint))
+	}
+	phases := bpa.Phases
+	if bpa.DeferPhase != nil {
+		phases = append(phases[:len(phases):len(phases)], *bpa.DeferPhase)
+	}
+	for _, p := range phases {
+		if err := kanister.CheckFuncVersion(p.Func, a.PreferredVersion); err != nil {
+			return errkit.Wrap(err, fmt.Sprintf("Invalid phase %s of action %s", p.Name, a.Name))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatingwebhook
+
+import (
+	"context"
+	"strings"
+
+	"gopkg.in/check.v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
+	"github.com/kanisterio/kanister/pkg/testutil"
+)
+
+type ActionSetValidatorSuite struct{}
+
+var _ = check.Suite(&ActionSetValidatorSuite{})
+
+func (s *ActionSetValidatorSuite) TestHandle(c *check.C) {
+	bp := &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp", Namespace: "kanister"},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Phases: []crv1alpha1.BlueprintPhase{{Name: "backup", Func: testutil.ArgFuncName}},
+			},
+			"versioned": {
+				Phases: []crv1alpha1.BlueprintPhase{{Name: "backup", Func: testutil.VersionMismatchFuncName}},
+			},
+		},
+	}
+	newActionSet := func(state crv1alpha1.State) *crv1alpha1.ActionSet {
+		as := &crv1alpha1.ActionSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "as", Namespace: "kanister"},
+			Spec: &crv1alpha1.ActionSetSpec{
+				Actions: []crv1alpha1.ActionSpec{{
+					Name:      "backup",
+					Blueprint: "bp",
+					Object:    crv1alpha1.ObjectReference{Kind: "Deployment", Name: "app", Namespace: "app"},
+				}},
+			},
+		}
+		if state != "" {
+			as.Status = &crv1alpha1.ActionSetStatus{
+				State:   state,
+				Actions: []crv1alpha1.ActionStatus{{Name: "backup", Blueprint: "bp"}},
+			}
+		}
+		return as
+	}
+	for _, tc := range []struct {
+		name     string
+		noClient bool
+		op       admissionv1.Operation
+		old      *crv1alpha1.ActionSet
+		as       *crv1alpha1.ActionSet
+		update   func(*crv1alpha1.ActionSet)
+		allowed  bool
+		reason   string
+	}{
+		{
+			name:    "create",
+			op:      admissionv1.Create,
+			allowed: true,
+		},
+		{
+			name:    "create with preferred version",
+			op:      admissionv1.Create,
+			update:  func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].PreferredVersion = testutil.TestVersion },
+			allowed: true,
+		},
+		{
+			name:   "missing blueprint",
+			op:     admissionv1.Create,
+			update: func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].Blueprint = "missing" },
+			reason: "Invalid actionset, Failed to get Blueprint missing of action backup.*",
+		},
+		{
+			name:     "blueprints are not checked without a client",
+			noClient: true,
+			op:       admissionv1.Create,
+			update:   func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].Blueprint = "missing" },
+			allowed:  true,
+		},
+		{
+			name:   "missing action",
+			op:     admissionv1.Create,
+			update: func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].Name = "restore" },
+			reason: "Invalid actionset, Action restore not found in Blueprint bp",
+		},
+		{
+			name:   "function not registered with version",
+			op:     admissionv1.Create,
+			update: func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].Name = "versioned" },
+			reason: "Invalid actionset, Invalid phase backup of action versioned: Requested function {VerMisFunc} has not been registered with version {}",
+		},
+		{
+			name: "unknown version",
+			op:   admissionv1.Create,
+			update: func(as *crv1alpha1.ActionSet) {
+				as.Spec.Actions[0].Name = "versioned"
+				as.Spec.Actions[0].PreferredVersion = "v2.0.0"
+			},
+			reason: "Invalid actionset, .*has not been registered with versions {v2.0.0} or {v0.0.0}",
+		},
+		{
+			name:   "invalid version",
+			op:     admissionv1.Create,
+			update: func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].PreferredVersion = "latest" },
+			reason: "Invalid actionset, .*Failed to parse function version {latest}.*",
+		},
+		{
+			name:   "bad labels",
+			op:     admissionv1.Create,
+			update: func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].PodLabels = map[string]string{"bad key": "value"} },
+			reason: "Invalid actionset, Invalid podLabels of action backup.*",
+		},
+		{
+			name:   "unknown object kind",
+			op:     admissionv1.Create,
+			update: func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].Object.Kind = "Widget" },
+			reason: "Invalid actionset, .*Not a known object Kind Widget.*",
+		},
+		{
+			name:    "status update of a running actionset",
+			op:      admissionv1.Update,
+			old:     newActionSet(crv1alpha1.StateRunning),
+			as:      newActionSet(crv1alpha1.StateComplete),
+			allowed: true,
+		},
+		{
+			name:   "spec update of a running actionset",
+			op:     admissionv1.Update,
+			old:    newActionSet(crv1alpha1.StateRunning),
+			as:     newActionSet(crv1alpha1.StateRunning),
+			update: func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].Object.Name = "other" },
+			reason: "Invalid actionset, spec of ActionSet as cannot be changed while it is running",
+		},
+		{
+			name:    "spec update of a pending actionset",
+			op:      admissionv1.Update,
+			old:     newActionSet(crv1alpha1.StatePending),
+			as:      newActionSet(crv1alpha1.StatePending),
+			update:  func(as *crv1alpha1.ActionSet) { as.Spec.Actions[0].Object.Name = "other" },
+			allowed: true,
+		},
+	} {
+		comment := check.Commentf(tc.name)
+		as := tc.as
+		if as == nil {
+			as = newActionSet("")
+		}
+		if tc.update != nil {
+			tc.update(as)
+		}
+		av := &ActionSetValidator{}
+		if !tc.noClient {
+			av.Client = fake.NewSimpleClientset(bp)
+		}
+		c.Assert(av.InjectDecoder(newDecoder(c)), check.IsNil)
+		var old runtime.Object
+		if tc.old != nil {
+			old = tc.old
+		}
+		resp := av.Handle(context.Background(), newRequest(c, tc.op, as, old))
+		c.Assert(resp.Allowed, check.Equals, tc.allowed, comment)
+		if !tc.allowed {
+			c.Assert(strings.TrimSpace(resp.Result.Message), check.Matches, tc.reason, comment)
+		}
+	}
+}
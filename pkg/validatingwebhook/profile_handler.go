@@ -0,0 +1,93 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatingwebhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kanisterio/errkit"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+// ProfileValidator denies Profiles whose location and credentials do not
+// fit together. If Client is set, it also checks that the Secrets that the
+// credentials reference exist and have the keys the location needs.
+type ProfileValidator struct {
+	decoder *admission.Decoder
+	Client  kubernetes.Interface
+}
+
+func (pv *ProfileValidator) Handle(ctx context.Context, r admission.Request) admission.Response {
+	p := &crv1alpha1.Profile{}
+	if err := (*pv.decoder).Decode(r, p); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if r.Operation == admissionv1.Update {
+		old := &crv1alpha1.Profile{}
+		if err := (*pv.decoder).DecodeRaw(r.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Updates of the status by the controller are always allowed
+		if profileSpecEqual(old, p) {
+			return admission.Allowed("")
+		}
+	}
+
+	if err := pv.validateProfile(ctx, p); err != nil {
+		return admission.Denied(fmt.Sprintf("Invalid profile, %s\n", err.Error()))
+	}
+
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (pv *ProfileValidator) InjectDecoder(d *admission.Decoder) error {
+	pv.decoder = d
+	return nil
+}
+
+func (pv *ProfileValidator) validateProfile(ctx context.Context, p *crv1alpha1.Profile) error {
+	if p.Location.Type == crv1alpha1.LocationTypeKopia {
+		if p.Credential.Type != crv1alpha1.CredentialTypeKopia || p.Credential.KopiaServerSecret == nil {
+			return errkit.New(fmt.Sprintf("Location type '%s' requires credentials of type '%s'", crv1alpha1.LocationTypeKopia, crv1alpha1.CredentialTypeKopia))
+		}
+		return nil
+	}
+	if err := validate.ProfileSchema(p); err != nil {
+		return err
+	}
+	if pv.Client == nil || p.Location.Type == crv1alpha1.LocationTypeFilesystem {
+		return nil
+	}
+	return validate.ProfileSecrets(ctx, p, pv.Client)
+}
+
+// profileSpecEqual reports whether the two Profiles differ only in their
+// metadata and status.
+func profileSpecEqual(a, b *crv1alpha1.Profile) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	a.TypeMeta, a.ObjectMeta = b.TypeMeta, b.ObjectMeta
+	a.Status, b.Status = nil, nil
+	return equality.Semantic.DeepEqual(a, b)
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatingwebhook
+
+import (
+	"context"
+	"strings"
+
+	"gopkg.in/check.v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type ProfileValidatorSuite struct{}
+
+var _ = check.Suite(&ProfileValidatorSuite{})
+
+func newS3Profile() *crv1alpha1.Profile {
+	return &crv1alpha1.Profile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "kanister"},
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Bucket: "backups",
+			Region: "us-west-2",
+		},
+		Credential: crv1alpha1.Credential{
+			Type: crv1alpha1.CredentialTypeKeyPair,
+			KeyPair: &crv1alpha1.KeyPair{
+				IDField:     "id",
+				SecretField: "secret",
+				Secret:      crv1alpha1.ObjectReference{Name: "creds", Namespace: "kanister"},
+			},
+		},
+	}
+}
+
+func (s *ProfileValidatorSuite) TestHandle(c *check.C) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "kanister"},
+		Data:       map[string][]byte{"id": []byte("id"), "secret": []byte("secret")},
+	}
+	unhealthy := &crv1alpha1.ProfileStatus{
+		Conditions: []metav1.Condition{{Type: crv1alpha1.ProfileCredentialsValid, Status: metav1.ConditionFalse}},
+	}
+	for _, tc := range []struct {
+		name     string
+		noClient bool
+		op       admissionv1.Operation
+		old      func(*crv1alpha1.Profile)
+		update   func(*crv1alpha1.Profile)
+		allowed  bool
+		reason   string
+	}{
+		{
+			name:    "create",
+			op:      admissionv1.Create,
+			allowed: true,
+		},
+		{
+			name:   "missing secret",
+			op:     admissionv1.Create,
+			update: func(p *crv1alpha1.Profile) { p.Credential.KeyPair.Secret.Name = "missing" },
+			reason: "Invalid profile, .*missing.*",
+		},
+		{
+			name:     "secrets are not checked without a client",
+			noClient: true,
+			op:       admissionv1.Create,
+			update:   func(p *crv1alpha1.Profile) { p.Credential.KeyPair.Secret.Name = "missing" },
+			allowed:  true,
+		},
+		{
+			name:   "missing key",
+			op:     admissionv1.Create,
+			update: func(p *crv1alpha1.Profile) { p.Credential.KeyPair.SecretField = "password" },
+			reason: "Invalid profile, Secret 'kanister:creds' has no key 'password'.*",
+		},
+		{
+			name:   "unsupported location type",
+			op:     admissionv1.Create,
+			update: func(p *crv1alpha1.Profile) { p.Location.Type = "ftp" },
+			reason: "Invalid profile, unknown or unsupported location type 'ftp'.*",
+		},
+		{
+			name: "filesystem",
+			op:   admissionv1.Create,
+			update: func(p *crv1alpha1.Profile) {
+				p.Location = crv1alpha1.Location{Type: crv1alpha1.LocationTypeFilesystem, ClaimName: "backups"}
+				p.Credential = crv1alpha1.Credential{}
+			},
+			allowed: true,
+		},
+		{
+			name: "kopia",
+			op:   admissionv1.Create,
+			update: func(p *crv1alpha1.Profile) {
+				p.Location = crv1alpha1.Location{Type: crv1alpha1.LocationTypeKopia}
+				p.Credential = crv1alpha1.Credential{
+					Type:              crv1alpha1.CredentialTypeKopia,
+					KopiaServerSecret: &crv1alpha1.KopiaServerSecret{Username: "user"},
+				}
+			},
+			allowed: true,
+		},
+		{
+			name:   "kopia without kopia credentials",
+			op:     admissionv1.Create,
+			update: func(p *crv1alpha1.Profile) { p.Location = crv1alpha1.Location{Type: crv1alpha1.LocationTypeKopia} },
+			reason: "Invalid profile, Location type 'kopia' requires credentials of type 'kopia'",
+		},
+		{
+			name: "status update of an invalid profile",
+			op:   admissionv1.Update,
+			old:  func(p *crv1alpha1.Profile) { p.Credential.KeyPair.Secret.Name = "missing" },
+			update: func(p *crv1alpha1.Profile) {
+				p.Credential.KeyPair.Secret.Name = "missing"
+				p.Status = unhealthy
+				p.Labels = map[string]string{"checked": "true"}
+			},
+			allowed: true,
+		},
+		{
+			name:   "spec update of an invalid profile",
+			op:     admissionv1.Update,
+			old:    func(p *crv1alpha1.Profile) { p.Credential.KeyPair.Secret.Name = "missing" },
+			update: func(p *crv1alpha1.Profile) { p.Credential.KeyPair.Secret.Name = "rotated" },
+			reason: "Invalid profile, .*rotated.*",
+		},
+	} {
+		comment := check.Commentf(tc.name)
+		p := newS3Profile()
+		if tc.update != nil {
+			tc.update(p)
+		}
+		pv := &ProfileValidator{}
+		if !tc.noClient {
+			pv.Client = fake.NewSimpleClientset(secret)
+		}
+		c.Assert(pv.InjectDecoder(newDecoder(c)), check.IsNil)
+		var old runtime.Object
+		if tc.old != nil {
+			op := newS3Profile()
+			tc.old(op)
+			old = op
+		}
+		resp := pv.Handle(context.Background(), newRequest(c, tc.op, p, old))
+		c.Assert(resp.Allowed, check.Equals, tc.allowed, comment)
+		if !tc.allowed {
+			c.Assert(strings.TrimSpace(resp.Result.Message), check.Matches, tc.reason, comment)
+		}
+	}
+}
+
+func (s *ProfileValidatorSuite) TestProfileSpecEqual(c *check.C) {
+	a := newS3Profile()
+	b := newS3Profile()
+	c.Assert(profileSpecEqual(a, b), check.Equals, true)
+
+	// Metadata and status are not part of the spec
+	b.ResourceVersion = "2"
+	b.Labels = map[string]string{"app": "backup"}
+	b.Status = &crv1alpha1.ProfileStatus{LastChecked: &metav1.Time{}}
+	c.Assert(profileSpecEqual(a, b), check.Equals, true)
+	// The Profiles are not modified
+	c.Assert(b.ResourceVersion, check.Equals, "2")
+	c.Assert(b.Status, check.NotNil)
+	c.Assert(a.Status, check.IsNil)
+
+	for _, update := range []func(*crv1alpha1.Profile){
+		func(p *crv1alpha1.Profile) { p.Location.Bucket = "other" },
+		func(p *crv1alpha1.Profile) { p.Credential.KeyPair.Secret.Name = "other" },
+		func(p *crv1alpha1.Profile) { p.SkipSSLVerify = true },
+		func(p *crv1alpha1.Profile) { p.RateLimit = &crv1alpha1.RateLimit{BytesPerSecond: 1} },
+	} {
+		b := newS3Profile()
+		update(b)
+		c.Assert(profileSpecEqual(a, b), check.Equals, false)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatingwebhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/check.v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+// newDecoder returns a decoder of Kanister custom resources.
+func newDecoder(c *check.C) *admission.Decoder {
+	scheme := runtime.NewScheme()
+	c.Assert(crv1alpha1.AddToScheme(scheme), check.IsNil)
+	d := admission.NewDecoder(scheme)
+	return &d
+}
+
+// newRequest returns an admission request of `op` for `obj`. `old` is the
+// object before an update.
+func newRequest(c *check.C, op admissionv1.Operation, obj, old runtime.Object) admission.Request {
+	r := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: op}}
+	raw, err := json.Marshal(obj)
+	c.Assert(err, check.IsNil)
+	r.Object = runtime.RawExtension{Raw: raw}
+	if old != nil {
+		raw, err = json.Marshal(old)
+		c.Assert(err, check.IsNil)
+		r.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return r
+}